@@ -14,9 +14,10 @@ import (
 	"net/url"
 	"os"
 	"strings"
+	"sync/atomic"
 	"time"
+	"unsafe"
 
-	"github.com/cenkalti/backoff/v4"
 	"golang.org/x/net/http/httpproxy"
 )
 
@@ -61,12 +62,17 @@ func init() {
 	}
 }
 
-// Client
+// Client is safe for concurrent use. Its setters replace the configuration
+// copy-on-write, ServiceInfo should not be modified in place once requests
+// are running.
 type Client struct {
 	Client        *http.Client
 	ServiceInfo   *ServiceInfo
 	ApiInfoList   map[string]*ApiInfo
 	CustomTimeout time.Duration
+
+	// options points to the current *clientOptions snapshot.
+	options unsafe.Pointer
 }
 
 // NewClient
@@ -77,19 +83,11 @@ func NewClient(info *ServiceInfo, apiInfoList map[string]*ApiInfo) *Client {
 		client.ServiceInfo.Scheme = defaultScheme
 	}
 
-	if os.Getenv(accessKey) != "" && os.Getenv(secretKey) != "" {
-		client.ServiceInfo.Credentials.AccessKeyID = os.Getenv(accessKey)
-		client.ServiceInfo.Credentials.SecretAccessKey = os.Getenv(secretKey)
-	} else if _, err := os.Stat(os.Getenv("HOME") + "/.volc/config"); err == nil {
-		if content, err := ioutil.ReadFile(os.Getenv("HOME") + "/.volc/config"); err == nil {
-			m := make(map[string]string)
-			json.Unmarshal(content, &m)
-			if accessKey, ok := m["ak"]; ok {
-				client.ServiceInfo.Credentials.AccessKeyID = accessKey
-			}
-			if secretKey, ok := m["sk"]; ok {
-				client.ServiceInfo.Credentials.SecretAccessKey = secretKey
-			}
+	if cred, err := NewDefaultCredentialsProvider().Retrieve(); err == nil {
+		client.ServiceInfo.Credentials.AccessKeyID = cred.AccessKeyID
+		client.ServiceInfo.Credentials.SecretAccessKey = cred.SecretAccessKey
+		if cred.SessionToken != "" {
+			client.ServiceInfo.Credentials.SessionToken = cred.SessionToken
 		}
 	}
 	return client
@@ -123,76 +121,156 @@ func (cred Credentials) Clone() Credentials {
 // SetRetrySettings
 func (client *Client) SetRetrySettings(retrySettings *RetrySettings) {
 	if retrySettings != nil {
-		client.ServiceInfo.Retry = *retrySettings
+		client.UpdateServiceInfo(func(info *ServiceInfo) {
+			info.Retry = *retrySettings
+		})
 	}
 }
 
 // SetAccessKey
 func (client *Client) SetAccessKey(ak string) {
 	if ak != "" {
-		client.ServiceInfo.Credentials.AccessKeyID = ak
+		client.UpdateServiceInfo(func(info *ServiceInfo) {
+			info.Credentials.AccessKeyID = ak
+		})
 	}
 }
 
 // SetSecretKey
 func (client *Client) SetSecretKey(sk string) {
 	if sk != "" {
-		client.ServiceInfo.Credentials.SecretAccessKey = sk
+		client.UpdateServiceInfo(func(info *ServiceInfo) {
+			info.Credentials.SecretAccessKey = sk
+		})
 	}
 }
 
 // SetSessionToken
 func (client *Client) SetSessionToken(token string) {
 	if token != "" {
-		client.ServiceInfo.Credentials.SessionToken = token
+		client.UpdateServiceInfo(func(info *ServiceInfo) {
+			info.Credentials.SessionToken = token
+		})
 	}
 }
 
 // SetHost
 func (client *Client) SetHost(host string) {
 	if host != "" {
-		client.ServiceInfo.Host = host
+		client.UpdateServiceInfo(func(info *ServiceInfo) {
+			info.Host = host
+		})
 	}
 }
 
 func (client *Client) SetScheme(scheme string) {
 	if scheme != "" {
-		client.ServiceInfo.Scheme = scheme
+		client.UpdateServiceInfo(func(info *ServiceInfo) {
+			info.Scheme = scheme
+		})
+	}
+}
+
+// SetRegion
+func (client *Client) SetRegion(region string) {
+	if region != "" {
+		client.UpdateServiceInfo(func(info *ServiceInfo) {
+			info.Credentials.Region = region
+		})
 	}
 }
 
 // SetCredential
 func (client *Client) SetCredential(c Credentials) {
-	if c.AccessKeyID != "" {
-		client.ServiceInfo.Credentials.AccessKeyID = c.AccessKeyID
-	}
+	client.UpdateServiceInfo(func(info *ServiceInfo) {
+		if c.AccessKeyID != "" {
+			info.Credentials.AccessKeyID = c.AccessKeyID
+		}
 
-	if c.SecretAccessKey != "" {
-		client.ServiceInfo.Credentials.SecretAccessKey = c.SecretAccessKey
-	}
+		if c.SecretAccessKey != "" {
+			info.Credentials.SecretAccessKey = c.SecretAccessKey
+		}
 
-	if c.Region != "" {
-		client.ServiceInfo.Credentials.Region = c.Region
-	}
+		if c.Region != "" {
+			info.Credentials.Region = c.Region
+		}
 
-	if c.SessionToken != "" {
-		client.ServiceInfo.Credentials.SessionToken = c.SessionToken
-	}
+		if c.SessionToken != "" {
+			info.Credentials.SessionToken = c.SessionToken
+		}
 
-	if c.Service != "" {
-		client.ServiceInfo.Credentials.Service = c.Service
+		if c.Service != "" {
+			info.Credentials.Service = c.Service
+		}
+	})
+}
+
+// SetCredentialsProvider makes the client fetch credentials from p before
+// signing each request, so rotated keys are used without calling SetAccessKey.
+// The provider takes precedence over the keys in ServiceInfo.Credentials.
+func (client *Client) SetCredentialsProvider(p CredentialsProvider) {
+	client.updateOptions(func(opts *clientOptions) {
+		opts.credentialsProvider = p
+	})
+}
+
+// Credentials returns the credentials the client signs requests with, those
+// retrieved from the provider set by SetCredentialsProvider if any. Code
+// signing its own requests should use them rather than
+// ServiceInfo.Credentials.
+func (client *Client) Credentials() (Credentials, error) {
+	return client.credentials(client.GetServiceInfo(), client.getOptions().credentialsProvider)
+}
+
+// credentials returns the credentials used to sign the next request. Service
+// and Region always come from info unless the provider sets them.
+func (client *Client) credentials(info *ServiceInfo, provider CredentialsProvider) (Credentials, error) {
+	cred := info.Credentials.Clone()
+	if provider == nil {
+		return cred, nil
 	}
+	provided, err := provider.Retrieve()
+	if err != nil {
+		return cred, fmt.Errorf("fail to retrieve credentials, %v", err)
+	}
+	cred.AccessKeyID = provided.AccessKeyID
+	cred.SecretAccessKey = provided.SecretAccessKey
+	cred.SessionToken = provided.SessionToken
+	if provided.Service != "" {
+		cred.Service = provided.Service
+	}
+	if provided.Region != "" {
+		cred.Region = provided.Region
+	}
+	return cred, nil
+}
+
+// SetRetryPolicy replaces the constant interval retry of RetrySettings. Once a
+// policy is set it decides for every api, whatever its AutoRetry setting.
+func (client *Client) SetRetryPolicy(policy RetryPolicy) {
+	client.updateOptions(func(opts *clientOptions) {
+		opts.retryPolicy = policy
+	})
+}
+
+// SetRetryBudget limits the retries of all requests made by the client.
+func (client *Client) SetRetryBudget(budget *RetryBudget) {
+	client.updateOptions(func(opts *clientOptions) {
+		opts.retryBudget = budget
+	})
 }
 
 func (client *Client) SetTimeout(timeout time.Duration) {
 	if timeout > 0 {
-		client.ServiceInfo.Timeout = timeout
+		client.UpdateServiceInfo(func(info *ServiceInfo) {
+			info.Timeout = timeout
+		})
 	}
 }
 
 func (client *Client) SetCustomTimeout(timeout time.Duration) {
 	if timeout > 0 {
-		client.CustomTimeout = timeout
+		atomic.StoreInt64((*int64)(&client.CustomTimeout), int64(timeout))
 	}
 }
 
@@ -206,9 +284,10 @@ func (client *Client) GetSignUrl(api string, query url.Values) (string, error) {
 
 	query = mergeQuery(query, apiInfo.Query)
 
+	info := client.GetServiceInfo()
 	u := url.URL{
-		Scheme:   client.ServiceInfo.Scheme,
-		Host:     client.ServiceInfo.Host,
+		Scheme:   info.Scheme,
+		Host:     info.Host,
 		Path:     apiInfo.Path,
 		RawQuery: query.Encode(),
 	}
@@ -218,7 +297,11 @@ func (client *Client) GetSignUrl(api string, query url.Values) (string, error) {
 		return "", errors.New("Failed to build request")
 	}
 
-	return client.ServiceInfo.Credentials.SignUrl(req), nil
+	cred, err := client.credentials(info, client.getOptions().credentialsProvider)
+	if err != nil {
+		return "", err
+	}
+	return cred.SignUrl(req), nil
 }
 
 // SignSts2
@@ -238,7 +321,11 @@ func (client *Client) SignSts2(inlinePolicy *Policy, expire time.Duration) (*Sec
 	sts.CurrentTime = now.Format(time.RFC3339)
 	sts.ExpiredTime = expireTime.Format(time.RFC3339)
 
-	innerToken, err := createInnerToken(client.ServiceInfo.Credentials, sts, inlinePolicy, expireTime.Unix())
+	cred, err := client.Credentials()
+	if err != nil {
+		return nil, err
+	}
+	innerToken, err := createInnerToken(cred, sts, inlinePolicy, expireTime.Unix())
 	if err != nil {
 		return nil, err
 	}
@@ -302,9 +389,9 @@ func (client *Client) CtxMultiPart(ctx context.Context, api string, query url.Va
 	return client.request(ctx, api, query, body.Bytes(), writer.FormDataContentType())
 }
 
-func (client *Client) makeRequest(inputContext context.Context, api string, req *http.Request, timeout time.Duration) ([]byte, int, error, bool) {
-	req = client.ServiceInfo.Credentials.Sign(req)
-
+// makeRequest sends one attempt. The returned attempt carries the status code
+// and error, with Transport set when the request never got a response.
+func (client *Client) makeRequest(inputContext context.Context, api string, req *http.Request, timeout time.Duration, info *ServiceInfo, opts *clientOptions) ([]byte, *RetryAttempt) {
 	ctx := inputContext
 	if ctx == nil {
 		ctx = context.Background()
@@ -312,30 +399,57 @@ func (client *Client) makeRequest(inputContext context.Context, api string, req
 
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
-	req = req.WithContext(ctx)
 
-	resp, err := client.Client.Do(req)
-	if err != nil {
-		// should retry when client sends request error.
-		return []byte(""), 500, err, true
+	resp, attempt := client.roundTrip(ctx, api, req, "", info, opts)
+	if attempt.Err != nil {
+		return []byte(""), attempt
 	}
 	defer resp.Body.Close()
 
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return []byte(""), resp.StatusCode, err, false
+		attempt.Err = err
+		return []byte(""), attempt
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode > 299 {
-		needRetry := false
-		// should retry when server returns 5xx error.
-		if resp.StatusCode >= http.StatusInternalServerError {
-			needRetry = true
-		}
-		return body, resp.StatusCode, fmt.Errorf("api %s http code %d body %s", api, resp.StatusCode, string(body)), needRetry
+		attempt.Err = newAPIError(api, resp.StatusCode, body)
 	}
+	return body, attempt
+}
 
-	return body, resp.StatusCode, nil, false
+// roundTrip signs req and sends it through the middlewares. On success the
+// caller owns the response body; the attempt's Err is left nil even for non-2xx
+// responses so that the caller can read the body first.
+func (client *Client) roundTrip(ctx context.Context, api string, req *http.Request, payloadHash string, info *ServiceInfo, opts *clientOptions) (*http.Response, *RetryAttempt) {
+	attempt := &RetryAttempt{Api: api}
+	cred, err := client.credentials(info, opts.credentialsProvider)
+	if err != nil {
+		attempt.Err, attempt.Permanent = err, true
+		return nil, attempt
+	}
+
+	req = req.WithContext(withRequestAPI(ctx, api))
+	roundTrip := wrapRoundTrip(opts.middlewares, func(req *http.Request) (*http.Response, error) {
+		return client.Client.Do(cred.SignWithPayloadHash(req, payloadHash))
+	})
+	resp, err := roundTrip(req)
+	if err != nil {
+		// should retry when client sends request error.
+		attempt.Err, attempt.Transport = err, true
+		return nil, attempt
+	}
+	attempt.StatusCode, attempt.Header = resp.StatusCode, resp.Header
+	return resp, attempt
+}
+
+// legacyStatusCode returns the status code of attempt as returned by Query and
+// the other request methods, 500 when no response was received.
+func legacyStatusCode(attempt *RetryAttempt) int {
+	if attempt.StatusCode == 0 {
+		return http.StatusInternalServerError
+	}
+	return attempt.StatusCode
 }
 
 func (client *Client) request(ctx context.Context, api string, query url.Values, body []byte, ct string) ([]byte, int, error) {
@@ -348,14 +462,16 @@ func (client *Client) request(ctx context.Context, api string, query url.Values,
 }
 
 func (client *Client) requestThumb(ctx context.Context, api string, apiInfo *ApiInfo, query url.Values, body []byte, ct string) ([]byte, int, error) {
-	timeout := getTimeout(client.ServiceInfo.Timeout, apiInfo.Timeout, client.CustomTimeout)
-	header := mergeHeader(client.ServiceInfo.Header, apiInfo.Header)
+	// Take one snapshot so that a concurrent setter never mixes two configurations.
+	info, opts := client.GetServiceInfo(), client.getOptions()
+	timeout := getTimeout(info.Timeout, apiInfo.Timeout, client.getCustomTimeout())
+	header := mergeHeader(info.Header, apiInfo.Header)
 	query = mergeQuery(query, apiInfo.Query)
-	retrySettings := getRetrySetting(&client.ServiceInfo.Retry, &apiInfo.Retry)
+	retrySettings := getRetrySetting(&info.Retry, &apiInfo.Retry)
 
 	u := url.URL{
-		Scheme:   client.ServiceInfo.Scheme,
-		Host:     client.ServiceInfo.Host,
+		Scheme:   info.Scheme,
+		Host:     info.Host,
 		Path:     apiInfo.Path,
 		RawQuery: query.Encode(),
 	}
@@ -372,24 +488,26 @@ func (client *Client) requestThumb(ctx context.Context, api string, apiInfo *Api
 	// Because service info could be changed by SetRegion, so set UA header for every request here.
 	req.Header.Set("User-Agent", strings.Join([]string{SDKName, SDKVersion}, "/"))
 
+	policy := opts.retryPolicy
+	if policy == nil {
+		policy = &constantRetryPolicy{times: *retrySettings.RetryTimes, interval: *retrySettings.RetryInterval}
+	}
+
 	var resp []byte
 	var code int
 
-	err = backoff.Retry(func() error {
-		_, err = requestBody.Seek(0, io.SeekStart)
-		if err != nil {
+	err = DoWithRetry(ctx, policy, opts.retryBudget, func() *RetryAttempt {
+		if _, err := requestBody.Seek(0, io.SeekStart); err != nil {
 			// if seek failed, stop retry.
-			return backoff.Permanent(err)
+			code = 500
+			return &RetryAttempt{Api: api, Err: err, Permanent: true}
 		}
 		req.Body = ioutil.NopCloser(requestBody)
-		var needRetry bool
-		resp, code, err, needRetry = client.makeRequest(ctx, api, req, timeout)
-		if needRetry {
-			return err
-		} else {
-			return backoff.Permanent(err)
-		}
-	}, backoff.WithMaxRetries(backoff.NewConstantBackOff(*retrySettings.RetryInterval), *retrySettings.RetryTimes))
+		var attempt *RetryAttempt
+		resp, attempt = client.makeRequest(ctx, api, req, timeout, info, opts)
+		code = legacyStatusCode(attempt)
+		return attempt
+	})
 	return resp, code, err
 }
 
@@ -0,0 +1,67 @@
+package base
+
+import (
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// clientOptions holds the settings of a Client that have no exported field.
+// It is replaced as a whole, never modified in place.
+type clientOptions struct {
+	credentialsProvider CredentialsProvider
+	middlewares         []Middleware
+	retryPolicy         RetryPolicy
+	retryBudget         *RetryBudget
+}
+
+// GetServiceInfo returns the current configuration of the client. The result
+// is a snapshot shared with in-flight requests and must not be modified, use
+// UpdateServiceInfo instead.
+func (client *Client) GetServiceInfo() *ServiceInfo {
+	return (*ServiceInfo)(atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&client.ServiceInfo))))
+}
+
+// UpdateServiceInfo applies fn to a copy of the current configuration and then
+// publishes the copy, so concurrent requests see either the old configuration
+// or the new one in full. Use it to change several settings at once, e.g.
+// region and host together. fn may run more than once when updates race, so
+// it must not have side effects beyond modifying info.
+func (client *Client) UpdateServiceInfo(fn func(info *ServiceInfo)) {
+	addr := (*unsafe.Pointer)(unsafe.Pointer(&client.ServiceInfo))
+	for {
+		current := atomic.LoadPointer(addr)
+		info := (*ServiceInfo)(current).Clone()
+		info.Retry = (*ServiceInfo)(current).Retry
+		fn(info)
+		if atomic.CompareAndSwapPointer(addr, current, unsafe.Pointer(info)) {
+			return
+		}
+	}
+}
+
+func (client *Client) getOptions() *clientOptions {
+	if opts := (*clientOptions)(atomic.LoadPointer(&client.options)); opts != nil {
+		return opts
+	}
+	return &clientOptions{}
+}
+
+func (client *Client) updateOptions(fn func(opts *clientOptions)) {
+	for {
+		current := atomic.LoadPointer(&client.options)
+		opts := &clientOptions{}
+		if current != nil {
+			*opts = *(*clientOptions)(current)
+			opts.middlewares = append([]Middleware(nil), opts.middlewares...)
+		}
+		fn(opts)
+		if atomic.CompareAndSwapPointer(&client.options, current, unsafe.Pointer(opts)) {
+			return
+		}
+	}
+}
+
+func (client *Client) getCustomTimeout() time.Duration {
+	return time.Duration(atomic.LoadInt64((*int64)(&client.CustomTimeout)))
+}
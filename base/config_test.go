@@ -0,0 +1,109 @@
+package base
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Run with -race: setters and requests share the client concurrently.
+func TestClient_ConcurrentConfig(t *testing.T) {
+	var mismatch int32
+	newRegionServer := func(region string) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !strings.Contains(r.Header.Get("Authorization"), "/"+region+"/iam/request") {
+				atomic.AddInt32(&mismatch, 1)
+			}
+			w.Write([]byte(`{}`))
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+	east, west := newRegionServer("region-east"), newRegionServer("region-west")
+	eastURL, _ := url.Parse(east.URL)
+	westURL, _ := url.Parse(west.URL)
+
+	client := NewClient(&ServiceInfo{
+		Timeout:     5 * time.Second,
+		Scheme:      "http",
+		Host:        eastURL.Host,
+		Credentials: Credentials{Region: "region-east", Service: "iam"},
+	}, apiList)
+
+	stop := make(chan struct{})
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			client.SetAccessKey("ak")
+			client.SetSecretKey("sk")
+			client.SetTimeout(5 * time.Second)
+			client.SetCustomTimeout(5 * time.Second)
+			client.SetRetrySettings(&RetrySettings{})
+			client.SetCredentialsProvider(nil)
+			client.UpdateServiceInfo(func(info *ServiceInfo) {
+				if i%2 == 0 {
+					info.Host, info.Credentials.Region = westURL.Host, "region-west"
+				} else {
+					info.Host, info.Credentials.Region = eastURL.Host, "region-east"
+				}
+			})
+		}
+	}()
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				if _, _, err := client.Query("ListUsers", nil); err != nil {
+					t.Error(err)
+					return
+				}
+				client.GetSignUrl("ListUsers", nil)
+			}
+		}()
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	close(stop)
+	wg.Wait()
+
+	if mismatch != 0 {
+		t.Fatalf("%d requests mixed host and region of different configurations", mismatch)
+	}
+}
+
+func TestClient_UpdateServiceInfo(t *testing.T) {
+	client := NewClient(serviceInfo, apiList)
+	before := client.GetServiceInfo()
+	retryTimes := uint64(3)
+	client.SetRetrySettings(&RetrySettings{AutoRetry: true, RetryTimes: &retryTimes})
+	client.SetRegion("cn-beijing")
+	client.SetHost("example.com")
+
+	after := client.GetServiceInfo()
+	if before == after {
+		t.Fatal("expect a new snapshot after update")
+	}
+	if before.Credentials.Region != RegionCnNorth1 || before.Host != "open.volcengineapi.com" {
+		t.Fatalf("previous snapshot was modified: %+v", before)
+	}
+	if after.Credentials.Region != "cn-beijing" || after.Host != "example.com" || *after.Retry.RetryTimes != 3 {
+		t.Fatalf("unexpected snapshot %+v", after)
+	}
+	if client.ServiceInfo != after {
+		t.Fatal("expect ServiceInfo to point to the latest snapshot")
+	}
+}
@@ -0,0 +1,261 @@
+package base
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	sessionToken = "VOLC_SESSIONTOKEN"
+	profileName  = "VOLC_PROFILE"
+
+	defaultProfile = "default"
+)
+
+// ErrNoValidCredentials is returned by providers that have nothing to offer,
+// a ChainCredentialsProvider moves on to the next provider when it sees it.
+var ErrNoValidCredentials = errors.New("no valid credentials")
+
+// CredentialsProvider supplies the credentials used to sign each request.
+// Retrieve is called once per request, so implementations that talk to a
+// remote service should cache their result. Implementations must be safe for
+// concurrent use.
+type CredentialsProvider interface {
+	Retrieve() (Credentials, error)
+}
+
+// CredentialsProviderFunc adapts an ordinary function to a CredentialsProvider.
+type CredentialsProviderFunc func() (Credentials, error)
+
+func (f CredentialsProviderFunc) Retrieve() (Credentials, error) {
+	return f()
+}
+
+// StaticCredentialsProvider always returns the same credentials.
+type StaticCredentialsProvider struct {
+	Value Credentials
+}
+
+func NewStaticCredentialsProvider(ak, sk, token string) *StaticCredentialsProvider {
+	return &StaticCredentialsProvider{Value: Credentials{
+		AccessKeyID:     ak,
+		SecretAccessKey: sk,
+		SessionToken:    token,
+	}}
+}
+
+func (p *StaticCredentialsProvider) Retrieve() (Credentials, error) {
+	if p.Value.AccessKeyID == "" || p.Value.SecretAccessKey == "" {
+		return Credentials{}, ErrNoValidCredentials
+	}
+	return p.Value.Clone(), nil
+}
+
+// EnvCredentialsProvider reads VOLC_ACCESSKEY, VOLC_SECRETKEY and the optional
+// VOLC_SESSIONTOKEN from the environment on every call.
+type EnvCredentialsProvider struct{}
+
+func NewEnvCredentialsProvider() *EnvCredentialsProvider {
+	return &EnvCredentialsProvider{}
+}
+
+func (p *EnvCredentialsProvider) Retrieve() (Credentials, error) {
+	ak, sk := os.Getenv(accessKey), os.Getenv(secretKey)
+	if ak == "" || sk == "" {
+		return Credentials{}, ErrNoValidCredentials
+	}
+	return Credentials{
+		AccessKeyID:     ak,
+		SecretAccessKey: sk,
+		SessionToken:    os.Getenv(sessionToken),
+	}, nil
+}
+
+// SharedCredentialsProvider loads credentials from a shared config file,
+// ~/.volc/config by default. The file is either JSON or INI:
+//
+//	{"ak": "...", "sk": "..."}
+//	{"default": {"ak": "...", "sk": "..."}, "prod": {"ak": "...", "sk": "...", "token": "..."}}
+//
+//	[default]
+//	ak = ...
+//	sk = ...
+//
+// The file is re-read whenever its modification time changes, so keys written
+// by an external rotator are picked up without restarting the process.
+type SharedCredentialsProvider struct {
+	// Filename defaults to ~/.volc/config when empty.
+	Filename string
+	// Profile defaults to $VOLC_PROFILE, then "default" when empty.
+	Profile string
+
+	lock    sync.Mutex
+	modTime time.Time
+	cached  Credentials
+	err     error
+}
+
+func NewSharedCredentialsProvider(filename, profile string) *SharedCredentialsProvider {
+	return &SharedCredentialsProvider{Filename: filename, Profile: profile}
+}
+
+func (p *SharedCredentialsProvider) filename() string {
+	if p.Filename != "" {
+		return p.Filename
+	}
+	return filepath.Join(os.Getenv("HOME"), ".volc", "config")
+}
+
+func (p *SharedCredentialsProvider) profile() string {
+	if p.Profile != "" {
+		return p.Profile
+	}
+	if env := os.Getenv(profileName); env != "" {
+		return env
+	}
+	return defaultProfile
+}
+
+func (p *SharedCredentialsProvider) Retrieve() (Credentials, error) {
+	filename := p.filename()
+	info, err := os.Stat(filename)
+	if err != nil {
+		return Credentials{}, ErrNoValidCredentials
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if !info.ModTime().Equal(p.modTime) || p.modTime.IsZero() {
+		p.cached, p.err = loadSharedCredentials(filename, p.profile())
+		p.modTime = info.ModTime()
+	}
+	if p.err != nil {
+		return Credentials{}, p.err
+	}
+	return p.cached.Clone(), nil
+}
+
+func loadSharedCredentials(filename, profile string) (Credentials, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	var profiles map[string]map[string]string
+	content = bytes.TrimSpace(content)
+	if len(content) > 0 && content[0] == '{' {
+		profiles, err = parseJSONProfiles(content)
+	} else {
+		profiles, err = parseINIProfiles(content)
+	}
+	if err != nil {
+		return Credentials{}, fmt.Errorf("fail to parse %s, %v", filename, err)
+	}
+
+	section, ok := profiles[profile]
+	if !ok || section["ak"] == "" || section["sk"] == "" {
+		return Credentials{}, ErrNoValidCredentials
+	}
+	return Credentials{
+		AccessKeyID:     section["ak"],
+		SecretAccessKey: section["sk"],
+		SessionToken:    section["token"],
+	}, nil
+}
+
+// parseJSONProfiles accepts both the legacy flat {"ak","sk"} layout, which is
+// treated as the default profile, and a map of named profiles.
+func parseJSONProfiles(content []byte) (map[string]map[string]string, error) {
+	raw := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(content, &raw); err != nil {
+		return nil, err
+	}
+
+	profiles := make(map[string]map[string]string)
+	flat := make(map[string]string)
+	for k, v := range raw {
+		var s string
+		if err := json.Unmarshal(v, &s); err == nil {
+			flat[k] = s
+			continue
+		}
+		section := make(map[string]string)
+		if err := json.Unmarshal(v, &section); err != nil {
+			return nil, err
+		}
+		profiles[k] = section
+	}
+	if len(flat) > 0 {
+		if _, ok := profiles[defaultProfile]; !ok {
+			profiles[defaultProfile] = flat
+		}
+	}
+	return profiles, nil
+}
+
+func parseINIProfiles(content []byte) (map[string]map[string]string, error) {
+	profiles := make(map[string]map[string]string)
+	section := defaultProfile
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || text[0] == '#' || text[0] == ';' {
+			continue
+		}
+		if text[0] == '[' && text[len(text)-1] == ']' {
+			section = strings.TrimSpace(text[1 : len(text)-1])
+			section = strings.TrimSpace(strings.TrimPrefix(section, "profile "))
+			continue
+		}
+		idx := strings.IndexByte(text, '=')
+		if idx < 0 {
+			return nil, fmt.Errorf("line %d: missing '='", line)
+		}
+		if profiles[section] == nil {
+			profiles[section] = make(map[string]string)
+		}
+		key := strings.ToLower(strings.TrimSpace(text[:idx]))
+		profiles[section][key] = strings.TrimSpace(text[idx+1:])
+	}
+	return profiles, scanner.Err()
+}
+
+// ChainCredentialsProvider tries each provider in order and returns the first
+// credentials found. Providers answering ErrNoValidCredentials are skipped,
+// any other error stops the chain.
+type ChainCredentialsProvider struct {
+	Providers []CredentialsProvider
+}
+
+func NewChainCredentialsProvider(providers ...CredentialsProvider) *ChainCredentialsProvider {
+	return &ChainCredentialsProvider{Providers: providers}
+}
+
+// NewDefaultCredentialsProvider returns the environment, then the shared config
+// file, the same lookup order NewClient has always used.
+func NewDefaultCredentialsProvider() *ChainCredentialsProvider {
+	return NewChainCredentialsProvider(NewEnvCredentialsProvider(), NewSharedCredentialsProvider("", ""))
+}
+
+func (p *ChainCredentialsProvider) Retrieve() (Credentials, error) {
+	for _, provider := range p.Providers {
+		if provider == nil {
+			continue
+		}
+		cred, err := provider.Retrieve()
+		if err == ErrNoValidCredentials {
+			continue
+		}
+		return cred, err
+	}
+	return Credentials{}, ErrNoValidCredentials
+}
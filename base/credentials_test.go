@@ -0,0 +1,179 @@
+package base
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestServer(t *testing.T, handler http.HandlerFunc) (*httptest.Server, *Client) {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	u, _ := url.Parse(server.URL)
+	client := NewClient(&ServiceInfo{
+		Timeout:     5 * time.Second,
+		Scheme:      u.Scheme,
+		Host:        u.Host,
+		Credentials: Credentials{Region: RegionCnNorth1, Service: "iam"},
+	}, apiList)
+	return server, client
+}
+
+func TestClient_CredentialsProvider(t *testing.T) {
+	var gotAuth, gotToken string
+	_, client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotToken = r.Header.Get("X-Security-Token")
+		w.Write([]byte(`{}`))
+	})
+	client.SetAccessKey("static-ak")
+	client.SetSecretKey("static-sk")
+
+	if _, _, err := client.Query("ListUsers", nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(gotAuth, "Credential=static-ak/") {
+		t.Fatalf("expect static ak, got %s", gotAuth)
+	}
+
+	key := "rotated-ak-1"
+	client.SetCredentialsProvider(CredentialsProviderFunc(func() (Credentials, error) {
+		return Credentials{AccessKeyID: key, SecretAccessKey: "sk", SessionToken: "token"}, nil
+	}))
+	if _, _, err := client.Query("ListUsers", nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(gotAuth, "Credential=rotated-ak-1/") || gotToken != "token" {
+		t.Fatalf("expect provider credentials, got %s %s", gotAuth, gotToken)
+	}
+
+	key = "rotated-ak-2"
+	if _, _, err := client.Query("ListUsers", nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(gotAuth, "Credential=rotated-ak-2/") || !strings.Contains(gotAuth, "/cn-north-1/iam/request") {
+		t.Fatalf("expect rotated credentials with service scope, got %s", gotAuth)
+	}
+	cred, err := client.Credentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cred.AccessKeyID != "rotated-ak-2" || cred.Region != RegionCnNorth1 || cred.Service != "iam" {
+		t.Fatalf("expect provider credentials with service scope, got %+v", cred)
+	}
+
+	client.SetCredentialsProvider(NewChainCredentialsProvider())
+	if _, _, err := client.Query("ListUsers", nil); err == nil {
+		t.Fatal("expect error from empty provider chain")
+	}
+	if _, err := client.Credentials(); err == nil {
+		t.Fatal("expect Credentials to fail with the empty provider chain")
+	}
+}
+
+func TestEnvCredentialsProvider(t *testing.T) {
+	t.Setenv(accessKey, "")
+	t.Setenv(secretKey, "")
+	if _, err := NewEnvCredentialsProvider().Retrieve(); err != ErrNoValidCredentials {
+		t.Fatalf("expect ErrNoValidCredentials, got %v", err)
+	}
+
+	t.Setenv(accessKey, "env-ak")
+	t.Setenv(secretKey, "env-sk")
+	t.Setenv(sessionToken, "env-token")
+	cred, err := NewEnvCredentialsProvider().Retrieve()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cred.AccessKeyID != "env-ak" || cred.SecretAccessKey != "env-sk" || cred.SessionToken != "env-token" {
+		t.Fatalf("unexpected credentials %+v", cred)
+	}
+}
+
+func TestSharedCredentialsProvider(t *testing.T) {
+	dir := t.TempDir()
+	cases := []struct {
+		name    string
+		content string
+		profile string
+		ak      string
+		token   string
+	}{
+		{"legacy json", `{"ak": "json-ak", "sk": "json-sk"}`, "", "json-ak", ""},
+		{"json profiles", `{"default": {"ak": "d-ak", "sk": "d-sk"}, "prod": {"ak": "p-ak", "sk": "p-sk", "token": "p-token"}}`, "prod", "p-ak", "p-token"},
+		{"ini", "# comment\n[default]\nak = i-ak\nsk = i-sk\n\n[profile staging]\nak=s-ak\nsk=s-sk\n", "staging", "s-ak", ""},
+		{"ini default", "[default]\nak = i-ak\nsk = i-sk\n", "", "i-ak", ""},
+	}
+	for i, c := range cases {
+		filename := filepath.Join(dir, c.name)
+		if err := ioutil.WriteFile(filename, []byte(c.content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		cred, err := NewSharedCredentialsProvider(filename, c.profile).Retrieve()
+		if err != nil {
+			t.Fatalf("case %d %s: %v", i, c.name, err)
+		}
+		if cred.AccessKeyID != c.ak || cred.SessionToken != c.token {
+			t.Fatalf("case %d %s: unexpected credentials %+v", i, c.name, cred)
+		}
+	}
+
+	if _, err := NewSharedCredentialsProvider(filepath.Join(dir, "missing"), "").Retrieve(); err != ErrNoValidCredentials {
+		t.Fatalf("expect ErrNoValidCredentials, got %v", err)
+	}
+	if _, err := NewSharedCredentialsProvider(filepath.Join(dir, "ini"), "unknown").Retrieve(); err != ErrNoValidCredentials {
+		t.Fatalf("expect ErrNoValidCredentials, got %v", err)
+	}
+}
+
+func TestSharedCredentialsProvider_Reload(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config")
+	if err := ioutil.WriteFile(filename, []byte(`{"ak": "old-ak", "sk": "sk"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	p := NewSharedCredentialsProvider(filename, "")
+	if cred, _ := p.Retrieve(); cred.AccessKeyID != "old-ak" {
+		t.Fatalf("unexpected credentials %+v", cred)
+	}
+
+	if err := ioutil.WriteFile(filename, []byte(`{"ak": "new-ak", "sk": "sk"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(filename, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if cred, _ := p.Retrieve(); cred.AccessKeyID != "new-ak" {
+		t.Fatalf("expect reloaded credentials, got %+v", cred)
+	}
+}
+
+func TestChainCredentialsProvider(t *testing.T) {
+	t.Setenv(accessKey, "")
+	t.Setenv(secretKey, "")
+	chain := NewChainCredentialsProvider(
+		NewEnvCredentialsProvider(),
+		NewStaticCredentialsProvider("", "", ""),
+		NewStaticCredentialsProvider("static-ak", "static-sk", ""),
+	)
+	cred, err := chain.Retrieve()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cred.AccessKeyID != "static-ak" {
+		t.Fatalf("unexpected credentials %+v", cred)
+	}
+
+	t.Setenv(accessKey, "env-ak")
+	t.Setenv(secretKey, "env-sk")
+	if cred, _ = chain.Retrieve(); cred.AccessKeyID != "env-ak" {
+		t.Fatalf("expect env credentials first, got %+v", cred)
+	}
+}
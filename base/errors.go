@@ -0,0 +1,121 @@
+package base
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// throttlingCodes are ResponseMetadata error codes returned when a caller is
+// being rate limited.
+var throttlingCodes = map[string]bool{
+	"FlowLimitExceeded":    true,
+	"RequestLimitExceeded": true,
+	"Throttling":           true,
+	"ThrottlingException":  true,
+	"TooManyRequests":      true,
+	"QPSLimitExceeded":     true,
+	"ExceedQPSLimit":       true,
+}
+
+// APIError is returned for non-2xx responses and for responses whose
+// ResponseMetadata carries an error. Use errors.As to get at it:
+//
+//	var apiErr *base.APIError
+//	if errors.As(err, &apiErr) && apiErr.Code == "InvalidParameter" { ... }
+type APIError struct {
+	HTTPCode  int
+	RequestId string
+	Service   string
+	Action    string
+	Code      string
+	CodeN     int
+	Message   string
+	// Body is the raw response body.
+	Body []byte
+
+	msg string
+}
+
+func (e *APIError) Error() string {
+	if e.msg != "" {
+		return e.msg
+	}
+	return fmt.Sprintf("request %s error %s: %s", e.RequestId, e.Code, e.Message)
+}
+
+// IsThrottling reports whether the request was rejected by rate limiting.
+func (e *APIError) IsThrottling() bool {
+	return e.HTTPCode == http.StatusTooManyRequests || throttlingCodes[e.Code]
+}
+
+// IsNotFound reports whether the requested resource does not exist.
+func (e *APIError) IsNotFound() bool {
+	return e.HTTPCode == http.StatusNotFound || strings.Contains(e.Code, "NotFound")
+}
+
+// IsServerError reports whether the server failed to handle a valid request.
+func (e *APIError) IsServerError() bool {
+	return e.HTTPCode >= http.StatusInternalServerError
+}
+
+// AsAPIError returns the *APIError wrapped in err, if any.
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	return nil, false
+}
+
+// IsThrottling reports whether err is an *APIError caused by rate limiting.
+func IsThrottling(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	return ok && apiErr.IsThrottling()
+}
+
+// IsNotFound reports whether err is an *APIError for a missing resource.
+func IsNotFound(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	return ok && apiErr.IsNotFound()
+}
+
+// ErrorCode returns the ResponseMetadata error code of err, or "" when err is
+// not an *APIError.
+func ErrorCode(err error) string {
+	if apiErr, ok := AsAPIError(err); ok {
+		return apiErr.Code
+	}
+	return ""
+}
+
+// newAPIError builds the error for a non-2xx response, keeping the message
+// format callers have historically seen.
+func newAPIError(api string, httpCode int, body []byte) *APIError {
+	e := &APIError{
+		HTTPCode: httpCode,
+		Action:   api,
+		Body:     body,
+		msg:      fmt.Sprintf("api %s http code %d body %s", api, httpCode, string(body)),
+	}
+	resp := new(CommonResponse)
+	if err := json.Unmarshal(body, resp); err == nil {
+		e.fill(&resp.ResponseMetadata)
+	}
+	return e
+}
+
+func (e *APIError) fill(meta *ResponseMetadata) {
+	e.RequestId = meta.RequestId
+	e.Service = meta.Service
+	if meta.Action != "" {
+		e.Action = meta.Action
+	}
+	if meta.Error != nil {
+		e.Code = meta.Error.Code
+		e.CodeN = meta.Error.CodeN
+		e.Message = meta.Error.Message
+	}
+}
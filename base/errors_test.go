@@ -0,0 +1,64 @@
+package base
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestClient_APIError(t *testing.T) {
+	cases := []struct {
+		status    int
+		body      string
+		code      string
+		throttled bool
+		notFound  bool
+	}{
+		{http.StatusTooManyRequests, `{"ResponseMetadata":{"RequestId":"req-1","Action":"ListUsers","Service":"iam","Error":{"CodeN":100018,"Code":"FlowLimitExceeded","Message":"flow limit"}}}`, "FlowLimitExceeded", true, false},
+		{http.StatusBadRequest, `{"ResponseMetadata":{"RequestId":"req-2","Error":{"CodeN":100019,"Code":"RequestLimitExceeded","Message":"limit"}}}`, "RequestLimitExceeded", true, false},
+		{http.StatusNotFound, `{"ResponseMetadata":{"RequestId":"req-3","Error":{"CodeN":109001,"Code":"UserNotFound","Message":"user not found"}}}`, "UserNotFound", false, true},
+		{http.StatusBadGateway, `bad gateway`, "", false, false},
+	}
+
+	for i, c := range cases {
+		_, client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(c.status)
+			w.Write([]byte(c.body))
+		})
+		_, code, err := client.Query("ListUsers", nil)
+		if code != c.status {
+			t.Fatalf("case %d: unexpected status %d", i, code)
+		}
+
+		var apiErr *APIError
+		if !errors.As(fmt.Errorf("wrapped: %w", err), &apiErr) {
+			t.Fatalf("case %d: expect *APIError, got %T", i, err)
+		}
+		if apiErr.HTTPCode != c.status || apiErr.Code != c.code || apiErr.Action != "ListUsers" {
+			t.Fatalf("case %d: unexpected error %+v", i, apiErr)
+		}
+		if IsThrottling(err) != c.throttled || IsNotFound(err) != c.notFound || ErrorCode(err) != c.code {
+			t.Fatalf("case %d: unexpected classification of %v", i, err)
+		}
+		if want := fmt.Sprintf("api ListUsers http code %d body %s", c.status, c.body); err.Error() != want {
+			t.Fatalf("case %d: unexpected message %q", i, err.Error())
+		}
+	}
+}
+
+func TestUnmarshalResultInto_APIError(t *testing.T) {
+	data := []byte(`{"ResponseMetadata":{"RequestId":"req-1","Service":"iam","Error":{"CodeN":100009,"Code":"InvalidAccessKey","Message":"invalid ak"}}}`)
+	err := UnmarshalResultInto(data, &struct{}{})
+
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		t.Fatalf("expect *APIError, got %T", err)
+	}
+	if apiErr.RequestId != "req-1" || apiErr.Service != "iam" || apiErr.CodeN != 100009 || apiErr.Message != "invalid ak" {
+		t.Fatalf("unexpected error %+v", apiErr)
+	}
+	if err.Error() != "request req-1 error invalid ak" {
+		t.Fatalf("unexpected message %q", err.Error())
+	}
+}
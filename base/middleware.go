@@ -0,0 +1,47 @@
+package base
+
+import (
+	"context"
+	"net/http"
+)
+
+// RoundTripFunc sends a single HTTP attempt and returns its response.
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps the round trip of every attempt a Client makes, retries
+// included. Middlewares run before the request is signed, so headers they add
+// with an "X-" prefix are covered by the signature. A middleware may answer
+// without calling next, e.g. to inject faults; 5xx responses it returns are
+// retried like real ones.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+type apiContextKey struct{}
+
+// RequestAPI returns the api name of a request passing through a Middleware.
+func RequestAPI(req *http.Request) string {
+	api, _ := req.Context().Value(apiContextKey{}).(string)
+	return api
+}
+
+func withRequestAPI(ctx context.Context, api string) context.Context {
+	return context.WithValue(ctx, apiContextKey{}, api)
+}
+
+// Use appends middlewares to the client. The first middleware added is the
+// outermost one and sees the request first.
+func (client *Client) Use(middlewares ...Middleware) {
+	client.updateOptions(func(opts *clientOptions) {
+		for _, m := range middlewares {
+			if m != nil {
+				opts.middlewares = append(opts.middlewares, m)
+			}
+		}
+	})
+}
+
+func wrapRoundTrip(middlewares []Middleware, final RoundTripFunc) RoundTripFunc {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		final = middlewares[i](final)
+	}
+	return final
+}
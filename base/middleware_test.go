@@ -0,0 +1,99 @@
+package base
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_Middleware(t *testing.T) {
+	var gotTrace, gotSigned string
+	_, client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		gotTrace = r.Header.Get("X-Trace-Id")
+		gotSigned = r.Header.Get("Authorization")
+		w.Write([]byte(`{"Result":{}}`))
+	})
+	client.SetAccessKey("ak")
+	client.SetSecretKey("sk")
+
+	var order []string
+	var apis []string
+	var codes []int
+	client.Use(
+		func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				order = append(order, "outer")
+				apis = append(apis, RequestAPI(req))
+				resp, err := next(req)
+				if err == nil {
+					codes = append(codes, resp.StatusCode)
+				}
+				return resp, err
+			}
+		},
+		func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				order = append(order, "inner")
+				req.Header.Set("X-Trace-Id", "trace-1")
+				return next(req)
+			}
+		},
+	)
+
+	if _, _, err := client.Query("ListUsers", nil); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(order, ",") != "outer,inner" {
+		t.Fatalf("unexpected middleware order %v", order)
+	}
+	if apis[0] != "ListUsers" || codes[0] != http.StatusOK {
+		t.Fatalf("unexpected api %v or code %v", apis, codes)
+	}
+	if gotTrace != "trace-1" || !strings.Contains(gotSigned, "x-trace-id") {
+		t.Fatalf("expect injected header to be sent and signed, got %q %q", gotTrace, gotSigned)
+	}
+}
+
+func TestClient_MiddlewareFaultInjection(t *testing.T) {
+	var served int
+	_, client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		served++
+		w.Write([]byte(`{"Result":{}}`))
+	})
+	retryTimes := uint64(2)
+	retryInterval := 10 * time.Millisecond
+	client.SetRetrySettings(&RetrySettings{AutoRetry: true, RetryTimes: &retryTimes, RetryInterval: &retryInterval})
+	client.ApiInfoList = map[string]*ApiInfo{
+		"ListUsers": {Method: http.MethodGet, Path: "/", Retry: RetrySettings{AutoRetry: true}},
+	}
+
+	var attempts int
+	client.Use(func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			attempts++
+			if attempts == 1 {
+				return &http.Response{
+					StatusCode: http.StatusServiceUnavailable,
+					Header:     http.Header{},
+					Body:       ioutil.NopCloser(bytes.NewReader([]byte("injected"))),
+					Request:    req,
+				}, nil
+			}
+			return next(req)
+		}
+	})
+
+	body, code, err := client.Query("ListUsers", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code != http.StatusOK || string(body) != `{"Result":{}}` {
+		t.Fatalf("unexpected response %d %s", code, body)
+	}
+	if attempts != 2 || served != 1 {
+		t.Fatalf("expect injected failure to be retried, attempts %d served %d", attempts, served)
+	}
+}
@@ -125,12 +125,14 @@ type RetrySettings struct {
 type RequestParam struct {
 	IsSignUrl bool
 	Body      []byte
-	Method    string
-	Date      time.Time
-	Path      string
-	Host      string
-	QueryList url.Values
-	Headers   http.Header
+	// PayloadHash, when set, is used instead of hashing Body.
+	PayloadHash string
+	Method      string
+	Date        time.Time
+	Path        string
+	Host        string
+	QueryList   url.Values
+	Headers     http.Header
 }
 
 type SignRequest struct {
@@ -0,0 +1,108 @@
+package base
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNoMorePages is returned by NextPage once the last page has been fetched.
+var ErrNoMorePages = errors.New("no more pages")
+
+// PageFunc fetches one page of a list API. position is the 1-based page number
+// for page number APIs and the 0-based offset for offset APIs. It returns the
+// number of items on the page and the total reported by the service, or a
+// negative total when the service does not report one.
+type PageFunc func(ctx context.Context, position, size int) (count int, total int, err error)
+
+// Paginator walks a PageNumber/PageSize or Offset/Limit list API lazily, one
+// page per NextPage call. It only tracks positions, the PageFunc keeps the
+// items; services wrap it into typed paginators:
+//
+//	p := base.NewPageNumberPaginator(100, func(ctx context.Context, page, size int) (int, int, error) {
+//		resp, err := client.DescribeTopics(&tls.DescribeTopicsRequest{PageNumber: page, PageSize: size})
+//		...
+//		topics = resp.Topics
+//		return len(resp.Topics), resp.Total, nil
+//	})
+//	for p.HasMorePages() {
+//		if err := p.NextPage(ctx); err != nil { ... }
+//		// use topics
+//	}
+type Paginator struct {
+	fetch    PageFunc
+	pageSize int
+	byOffset bool
+
+	position int
+	fetched  int
+	done     bool
+}
+
+// NewPageNumberPaginator pages with 1-based page numbers.
+func NewPageNumberPaginator(pageSize int, fetch PageFunc) *Paginator {
+	return &Paginator{fetch: fetch, pageSize: pageSize, position: 1}
+}
+
+// NewOffsetPaginator pages with 0-based offsets.
+func NewOffsetPaginator(limit int, fetch PageFunc) *Paginator {
+	return &Paginator{fetch: fetch, pageSize: limit, byOffset: true}
+}
+
+// HasMorePages reports whether NextPage may return more items.
+func (p *Paginator) HasMorePages() bool {
+	return !p.done
+}
+
+// Fetched returns how many items all pages so far have returned.
+func (p *Paginator) Fetched() int {
+	return p.fetched
+}
+
+// NextPage fetches the next page. A failed page is fetched again by the next
+// call.
+func (p *Paginator) NextPage(ctx context.Context) error {
+	if p.done {
+		return ErrNoMorePages
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	count, total, err := p.fetch(ctx, p.position, p.pageSize)
+	if err != nil {
+		return err
+	}
+
+	p.fetched += count
+	if p.byOffset {
+		p.position += count
+	} else {
+		p.position++
+	}
+	if count == 0 || (total >= 0 && p.fetched >= total) || (total < 0 && p.pageSize > 0 && count < p.pageSize) {
+		p.done = true
+	}
+	return nil
+}
+
+// Collect fetches pages until there are none left or max items were fetched,
+// all pages when max <= 0. After each page add is called with how many of its
+// first items to keep, so that typed paginators append them to their result.
+func (p *Paginator) Collect(ctx context.Context, max int, add func(keep int)) error {
+	start := p.fetched
+	for p.HasMorePages() && (max <= 0 || p.fetched-start < max) {
+		before := p.fetched
+		if err := p.NextPage(ctx); err != nil {
+			return err
+		}
+		keep := p.fetched - before
+		if max > 0 && p.fetched-start > max {
+			keep -= p.fetched - start - max
+		}
+		add(keep)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package base
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestPaginator(t *testing.T) {
+	items := make([]int, 23)
+	cases := []struct {
+		name      string
+		paginator func(positions *[]int) *Paginator
+		positions []int
+	}{
+		{"page number", func(positions *[]int) *Paginator {
+			return NewPageNumberPaginator(10, func(ctx context.Context, page, size int) (int, int, error) {
+				*positions = append(*positions, page)
+				return len(pageOf(items, (page-1)*size, size)), len(items), nil
+			})
+		}, []int{1, 2, 3}},
+		{"offset", func(positions *[]int) *Paginator {
+			return NewOffsetPaginator(10, func(ctx context.Context, offset, limit int) (int, int, error) {
+				*positions = append(*positions, offset)
+				return len(pageOf(items, offset, limit)), len(items), nil
+			})
+		}, []int{0, 10, 20}},
+		{"unknown total", func(positions *[]int) *Paginator {
+			return NewOffsetPaginator(10, func(ctx context.Context, offset, limit int) (int, int, error) {
+				*positions = append(*positions, offset)
+				return len(pageOf(items, offset, limit)), -1, nil
+			})
+		}, []int{0, 10, 20}},
+	}
+
+	for _, c := range cases {
+		var positions []int
+		p := c.paginator(&positions)
+		for p.HasMorePages() {
+			if err := p.NextPage(context.Background()); err != nil {
+				t.Fatalf("%s: %v", c.name, err)
+			}
+		}
+		if p.Fetched() != len(items) || len(positions) != len(c.positions) {
+			t.Fatalf("%s: fetched %d items at %v", c.name, p.Fetched(), positions)
+		}
+		for i := range positions {
+			if positions[i] != c.positions[i] {
+				t.Fatalf("%s: unexpected positions %v", c.name, positions)
+			}
+		}
+		if err := p.NextPage(context.Background()); err != ErrNoMorePages {
+			t.Fatalf("%s: expect ErrNoMorePages, got %v", c.name, err)
+		}
+	}
+}
+
+func TestPaginator_Errors(t *testing.T) {
+	fail := true
+	p := NewPageNumberPaginator(10, func(ctx context.Context, page, size int) (int, int, error) {
+		if fail {
+			return 0, 0, errors.New("boom")
+		}
+		if page != 1 {
+			t.Fatalf("expect failed page to be fetched again, got page %d", page)
+		}
+		return 5, 5, nil
+	})
+	if err := p.NextPage(context.Background()); err == nil || !p.HasMorePages() {
+		t.Fatal("expect error and more pages")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := p.NextPage(ctx); err != context.Canceled {
+		t.Fatalf("expect context.Canceled, got %v", err)
+	}
+
+	fail = false
+	if err := p.NextPage(context.Background()); err != nil || p.HasMorePages() {
+		t.Fatalf("expect last page, got %v", err)
+	}
+}
+
+func TestPaginator_Collect(t *testing.T) {
+	items := make([]int, 23)
+	for i := range items {
+		items[i] = i
+	}
+	for max, expect := range map[int]int{0: 23, 15: 15, 20: 20, 30: 23} {
+		var page, all []int
+		p := NewOffsetPaginator(10, func(ctx context.Context, offset, limit int) (int, int, error) {
+			page = pageOf(items, offset, limit)
+			return len(page), len(items), nil
+		})
+		err := p.Collect(context.Background(), max, func(keep int) {
+			all = append(all, page[:keep]...)
+		})
+		if err != nil || len(all) != expect || all[len(all)-1] != expect-1 {
+			t.Fatalf("max %d: collected %v, err %v", max, all, err)
+		}
+	}
+}
+
+func pageOf(items []int, offset, size int) []int {
+	if offset >= len(items) {
+		return nil
+	}
+	end := offset + size
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[offset:end]
+}
@@ -0,0 +1,239 @@
+package base
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RetryAttempt describes a failed attempt handed to a RetryPolicy.
+type RetryAttempt struct {
+	Api string
+	// Attempt is the 1-based number of the attempt that failed.
+	Attempt int
+	// StatusCode is 0 when no response was received.
+	StatusCode int
+	// Header is the response header, nil when no response was received.
+	Header http.Header
+	Err    error
+	// Transport is set when the request failed before a response arrived.
+	Transport bool
+	// Permanent is set when trying again cannot help, such as when the
+	// credentials could not be retrieved. DoWithRetry never retries it.
+	Permanent bool
+}
+
+// RetryPolicy decides, after every failed attempt, whether to try again and
+// how long to wait before doing so.
+type RetryPolicy interface {
+	ShouldRetry(attempt *RetryAttempt) (time.Duration, bool)
+}
+
+// RetryPolicyFunc adapts an ordinary function to a RetryPolicy.
+type RetryPolicyFunc func(attempt *RetryAttempt) (time.Duration, bool)
+
+func (f RetryPolicyFunc) ShouldRetry(attempt *RetryAttempt) (time.Duration, bool) {
+	return f(attempt)
+}
+
+// ExponentialRetryPolicy retries transport errors, 429, 5xx and throttling
+// error codes with exponential backoff and jitter. A Retry-After header on the
+// response is honored when it asks for a longer wait, up to MaxDelay.
+type ExponentialRetryPolicy struct {
+	// MaxAttempts counts the first attempt, so 3 means up to 2 retries.
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	// RetryableStatusCodes replaces the default 429, 500, 502, 503 and 504.
+	RetryableStatusCodes []int
+}
+
+func NewExponentialRetryPolicy(maxAttempts int, baseDelay, maxDelay time.Duration) *ExponentialRetryPolicy {
+	return &ExponentialRetryPolicy{
+		MaxAttempts: maxAttempts,
+		BaseDelay:   baseDelay,
+		MaxDelay:    maxDelay,
+	}
+}
+
+var defaultRetryableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+func (p *ExponentialRetryPolicy) ShouldRetry(attempt *RetryAttempt) (time.Duration, bool) {
+	if attempt.Attempt >= p.MaxAttempts || !p.retryable(attempt) {
+		return 0, false
+	}
+
+	delay := ExponentialBackoff(attempt.Attempt, p.BaseDelay, p.MaxDelay)
+	if retryAfter := ParseRetryAfter(attempt.Header); retryAfter > delay {
+		delay = retryAfter
+		if p.MaxDelay > 0 && delay > p.MaxDelay {
+			delay = p.MaxDelay
+		}
+	}
+	return delay, true
+}
+
+func (p *ExponentialRetryPolicy) retryable(attempt *RetryAttempt) bool {
+	if attempt.Transport {
+		return true
+	}
+	codes := p.RetryableStatusCodes
+	if codes == nil {
+		codes = defaultRetryableStatusCodes
+	}
+	for _, code := range codes {
+		if attempt.StatusCode == code {
+			return true
+		}
+	}
+	return IsThrottling(attempt.Err)
+}
+
+// ExponentialBackoff returns the delay before retrying after the given attempt:
+// base*2^(attempt-1) capped at max, of which the upper half is randomized.
+func ExponentialBackoff(attempt int, base, max time.Duration) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+	delay := base
+	for i := 1; i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+	if max > 0 && delay > max {
+		delay = max
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+// ParseRetryAfter reads a Retry-After header given in seconds or as an HTTP
+// date. It returns 0 when the header is absent or malformed.
+func ParseRetryAfter(header http.Header) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := time.Until(date); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// constantRetryPolicy reproduces RetrySettings: a fixed number of retries at a
+// fixed interval for transport errors and 5xx responses.
+type constantRetryPolicy struct {
+	times    uint64
+	interval time.Duration
+}
+
+func (p *constantRetryPolicy) ShouldRetry(attempt *RetryAttempt) (time.Duration, bool) {
+	if uint64(attempt.Attempt) > p.times {
+		return 0, false
+	}
+	if attempt.Transport || attempt.StatusCode >= http.StatusInternalServerError {
+		return p.interval, true
+	}
+	return 0, false
+}
+
+// RetryBudget is a token bucket limiting retries across all requests of a
+// client, so that a failing backend is not hit with a multiple of the normal
+// load. Every retry takes a token and every success returns Ratio of one;
+// retries stop while the bucket is at or below half of Max. The bucket is
+// full on first use, NewRetryBudget and a struct literal with Max set are
+// equivalent; a zero Max allows no retries.
+type RetryBudget struct {
+	Max   float64
+	Ratio float64
+
+	lock   sync.Mutex
+	tokens float64
+	filled bool
+}
+
+func NewRetryBudget(max int, ratio float64) *RetryBudget {
+	return &RetryBudget{Max: float64(max), Ratio: ratio}
+}
+
+// fill fills the bucket on first use, b.lock must be held.
+func (b *RetryBudget) fill() {
+	if !b.filled {
+		b.tokens = b.Max
+		b.filled = true
+	}
+}
+
+// Allow takes a token for a retry, it returns false when the budget is spent.
+func (b *RetryBudget) Allow() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.fill()
+	if b.tokens <= b.Max/2 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Succeed records a successful attempt.
+func (b *RetryBudget) Succeed() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.fill()
+	b.tokens += b.Ratio
+	if b.tokens > b.Max {
+		b.tokens = b.Max
+	}
+}
+
+// DoWithRetry runs op until it succeeds, the policy gives up, the budget is
+// spent or ctx is done, and returns the error of the last attempt. op fills in
+// everything but Attempt; a nil Err means success. budget may be nil.
+func DoWithRetry(ctx context.Context, policy RetryPolicy, budget *RetryBudget, op func() *RetryAttempt) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	for n := 1; ; n++ {
+		attempt := op()
+		attempt.Attempt = n
+		if attempt.Err == nil {
+			if budget != nil {
+				budget.Succeed()
+			}
+			return nil
+		}
+
+		if attempt.Permanent {
+			return attempt.Err
+		}
+		delay, retry := policy.ShouldRetry(attempt)
+		if !retry || ctx.Err() != nil || (budget != nil && !budget.Allow()) {
+			return attempt.Err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return attempt.Err
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package base
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestClient_RetryPolicy(t *testing.T) {
+	cases := []struct {
+		name     string
+		failures []int
+		body     string
+		attempts int
+		ok       bool
+	}{
+		{"throttled then ok", []int{http.StatusTooManyRequests, http.StatusServiceUnavailable}, `{}`, 3, true},
+		{"throttling code", []int{http.StatusBadRequest}, `{"ResponseMetadata":{"Error":{"CodeN":100018,"Code":"FlowLimitExceeded"}}}`, 2, true},
+		{"client error", []int{http.StatusBadRequest}, `{"ResponseMetadata":{"Error":{"CodeN":100001,"Code":"MissingParameter"}}}`, 1, false},
+		{"exhausted", []int{500, 500, 500, 500}, `{}`, 3, false},
+	}
+
+	for _, c := range cases {
+		var served int
+		_, client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			served++
+			if served <= len(c.failures) {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(c.failures[served-1])
+				w.Write([]byte(c.body))
+				return
+			}
+			w.Write([]byte(`{}`))
+		})
+		client.SetRetryPolicy(NewExponentialRetryPolicy(3, time.Millisecond, 10*time.Millisecond))
+
+		_, _, err := client.Query("ListUsers", nil)
+		if (err == nil) != c.ok || served != c.attempts {
+			t.Fatalf("%s: expect ok=%v after %d attempts, got err=%v after %d", c.name, c.ok, c.attempts, err, served)
+		}
+	}
+}
+
+func TestClient_RetrySettings(t *testing.T) {
+	var served int
+	_, client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		served++
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	// Without a policy, RetrySettings only retry transport errors and 5xx.
+	retryTimes, retryInterval := uint64(2), time.Millisecond
+	client.SetRetrySettings(&RetrySettings{AutoRetry: true, RetryTimes: &retryTimes, RetryInterval: &retryInterval})
+	client.ApiInfoList = map[string]*ApiInfo{"ListUsers": {Method: http.MethodGet, Path: "/", Retry: RetrySettings{AutoRetry: true}}}
+	if _, code, _ := client.Query("ListUsers", nil); code != http.StatusTooManyRequests || served != 1 {
+		t.Fatalf("expect no retry for 429, got code %d after %d attempts", code, served)
+	}
+}
+
+func TestClient_RetryAttemptWithoutResponse(t *testing.T) {
+	server, client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	})
+	var attempts []RetryAttempt
+	client.SetRetryPolicy(RetryPolicyFunc(func(attempt *RetryAttempt) (time.Duration, bool) {
+		attempts = append(attempts, *attempt)
+		return 0, attempt.Attempt < 2
+	}))
+
+	// Credentials which cannot be retrieved are never retried.
+	client.SetCredentialsProvider(NewChainCredentialsProvider())
+	if _, code, err := client.Query("ListUsers", nil); err == nil || code != http.StatusInternalServerError || len(attempts) != 0 {
+		t.Fatalf("expect one failed attempt with code 500, got code %d, err %v, policy calls %+v", code, err, attempts)
+	}
+
+	// A transport error reaches the policy without a status code.
+	client.SetCredentialsProvider(nil)
+	server.Close()
+	if _, code, err := client.Query("ListUsers", nil); err == nil || code != http.StatusInternalServerError {
+		t.Fatalf("expect code 500 for a transport error, got code %d, err %v", code, err)
+	}
+	if len(attempts) != 2 || attempts[0].StatusCode != 0 || !attempts[0].Transport {
+		t.Fatalf("expect 2 transport attempts without status code, got %+v", attempts)
+	}
+}
+
+func TestRetryBudget(t *testing.T) {
+	var served int
+	_, client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		served++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	client.SetRetryPolicy(NewExponentialRetryPolicy(10, time.Millisecond, time.Millisecond))
+	client.SetRetryBudget(NewRetryBudget(4, 0.5))
+
+	// Two retries drain the budget down to half, then retrying stops.
+	client.Query("ListUsers", nil)
+	if served != 3 {
+		t.Fatalf("expect 3 attempts, got %d", served)
+	}
+	served = 0
+	client.Query("ListUsers", nil)
+	if served != 1 {
+		t.Fatalf("expect no retry with a spent budget, got %d attempts", served)
+	}
+
+	// A struct literal starts full like NewRetryBudget.
+	client.SetRetryBudget(&RetryBudget{Max: 4, Ratio: 0.5})
+	served = 0
+	client.Query("ListUsers", nil)
+	if served != 3 {
+		t.Fatalf("expect 3 attempts with a RetryBudget literal, got %d", served)
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	for attempt := 1; attempt <= 10; attempt++ {
+		d := ExponentialBackoff(attempt, 100*time.Millisecond, time.Second)
+		ceiling := 100 * time.Millisecond << uint(attempt-1)
+		if ceiling > time.Second {
+			ceiling = time.Second
+		}
+		if d < ceiling/2 || d > ceiling {
+			t.Fatalf("attempt %d: delay %v out of [%v, %v]", attempt, d, ceiling/2, ceiling)
+		}
+	}
+}
+
+func TestExponentialRetryPolicy_RetryAfter(t *testing.T) {
+	policy := NewExponentialRetryPolicy(3, time.Millisecond, time.Second)
+	attempt := &RetryAttempt{Attempt: 1, StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": []string{"2"}}}
+	if d, ok := policy.ShouldRetry(attempt); !ok || d != time.Second {
+		t.Fatalf("expect Retry-After capped at MaxDelay, got %v %v", d, ok)
+	}
+	policy.MaxDelay = 0
+	if d, ok := policy.ShouldRetry(attempt); !ok || d != 2*time.Second {
+		t.Fatalf("expect Retry-After without MaxDelay, got %v %v", d, ok)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if d := ParseRetryAfter(http.Header{"Retry-After": []string{"3"}}); d != 3*time.Second {
+		t.Fatalf("unexpected delay %v", d)
+	}
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if d := ParseRetryAfter(http.Header{"Retry-After": []string{date}}); d < 58*time.Second || d > time.Minute {
+		t.Fatalf("unexpected delay %v", d)
+	}
+	if d := ParseRetryAfter(http.Header{"Retry-After": []string{"soon"}}); d != 0 {
+		t.Fatalf("unexpected delay %v", d)
+	}
+	if d := ParseRetryAfter(nil); d != 0 {
+		t.Fatalf("unexpected delay %v", d)
+	}
+}
@@ -17,21 +17,33 @@ import (
 )
 
 func (c Credentials) Sign(request *http.Request) *http.Request {
+	return c.SignWithPayloadHash(request, "")
+}
+
+// SignWithPayloadHash signs request without reading its body when payloadHash
+// is given, either the hex SHA256 of the body or UnsignedPayload. This keeps
+// streamed bodies intact. An empty payloadHash behaves like Sign.
+func (c Credentials) SignWithPayloadHash(request *http.Request, payloadHash string) *http.Request {
 	query := request.URL.Query()
 	request.URL.RawQuery = query.Encode()
 
 	if request.URL.Path == "" {
 		request.URL.Path += "/"
 	}
+	var body []byte
+	if payloadHash == "" {
+		body = readAndReplaceBody(request)
+	}
 	requestParam := RequestParam{
-		IsSignUrl: false,
-		Body:      readAndReplaceBody(request),
-		Host:      request.Host,
-		Path:      request.URL.Path,
-		Method:    request.Method,
-		Date:      now(),
-		QueryList: query,
-		Headers:   request.Header,
+		IsSignUrl:   false,
+		Body:        body,
+		PayloadHash: payloadHash,
+		Host:        request.Host,
+		Path:        request.URL.Path,
+		Method:      request.Method,
+		Date:        now(),
+		QueryList:   query,
+		Headers:     request.Header,
 	}
 	signRequest := GetSignRequest(requestParam, c)
 
@@ -119,7 +131,9 @@ func GetSignRequest(requestParam RequestParam, credentials Credentials) SignRequ
 		}
 		requestSignMap["X-Date"], requestSignMap["Host"], requestSignMap["Content-Type"] = []string{formatDate}, []string{requestParam.Host}, []string{signRequest.ContentType}
 
-		if len(requestParam.Body) == 0 {
+		if requestParam.PayloadHash != "" {
+			bodyHash = requestParam.PayloadHash
+		} else if len(requestParam.Body) == 0 {
 			bodyHash = hashSHA256([]byte{})
 		} else {
 			bodyHash = hashSHA256(requestParam.Body)
@@ -0,0 +1,191 @@
+// Package sse decodes server-sent events, the text/event-stream format used
+// by the streaming APIs of the SDK.
+//
+// A Decoder reads the events of one response body. A Reader reads the events
+// of a stream across connections, reconnecting with the Last-Event-ID of the
+// last event it received.
+package sse
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+)
+
+// DefaultMaxEventSize is the largest event read when no size is given.
+const DefaultMaxEventSize = 4 << 20
+
+// ErrEventTooLarge is returned once an event is bigger than the max event
+// size, the stream can not be read further.
+var ErrEventTooLarge = errors.New("sse: event too large")
+
+// Event is a server-sent event.
+type Event struct {
+	// ID is the last event id of the stream when the event was received. It
+	// is kept from event to event until the server sends another one.
+	ID string
+	// Type is the event field, empty for the default "message" type.
+	Type string
+	// Data is the data of the event, its lines joined by "\n".
+	Data []byte
+}
+
+// Decoder reads events from a text/event-stream body. Lines end with "\r\n",
+// "\n" or "\r", comments and unknown fields are ignored and events without
+// data are not returned.
+type Decoder struct {
+	reader       *bufio.Reader
+	maxEventSize int
+	err          error
+
+	// skipLF is set after a "\r", whose following "\n" ends the same line.
+	skipLF bool
+	line   []byte
+	// size is the number of bytes read for the current event.
+	size int
+
+	eventType   string
+	data        bytes.Buffer
+	hasData     bool
+	lastEventID string
+	retry       time.Duration
+}
+
+// NewDecoder returns a decoder reading events of at most maxEventSize bytes,
+// DefaultMaxEventSize if maxEventSize <= 0, from r.
+func NewDecoder(r io.Reader, maxEventSize int) *Decoder {
+	if maxEventSize <= 0 {
+		maxEventSize = DefaultMaxEventSize
+	}
+
+	return &Decoder{reader: bufio.NewReader(r), maxEventSize: maxEventSize}
+}
+
+// LastEventID returns the last event id the server sent, to send in the
+// Last-Event-ID header when reconnecting.
+func (d *Decoder) LastEventID() string {
+	return d.lastEventID
+}
+
+// Retry returns the reconnection time the server asked for with the retry
+// field, 0 if it did not.
+func (d *Decoder) Retry() time.Duration {
+	return d.retry
+}
+
+// Next returns the next event. It returns io.EOF at the end of the body, an
+// event which the body ends before its blank line is still returned first.
+// Errors are final, later calls return the same error.
+func (d *Decoder) Next() (*Event, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	for {
+		line, err := d.readLine()
+		if err != nil {
+			if err == io.EOF && d.hasData {
+				d.err = err
+				return d.dispatch(), nil
+			}
+			d.err = err
+			return nil, err
+		}
+
+		if len(line) == 0 {
+			if d.hasData {
+				return d.dispatch(), nil
+			}
+			d.reset()
+			continue
+		}
+		d.field(line)
+	}
+}
+
+// readLine returns the next line without its end of line.
+func (d *Decoder) readLine() ([]byte, error) {
+	d.line = d.line[:0]
+	for {
+		b, err := d.reader.ReadByte()
+		if err != nil {
+			if err == io.EOF && len(d.line) > 0 {
+				return d.line, nil
+			}
+			return nil, err
+		}
+
+		skipLF := d.skipLF
+		d.skipLF = false
+		switch b {
+		case '\n':
+			if skipLF {
+				continue
+			}
+			return d.line, nil
+		case '\r':
+			d.skipLF = true
+			return d.line, nil
+		}
+
+		d.size++
+		if d.size > d.maxEventSize {
+			return nil, ErrEventTooLarge
+		}
+		d.line = append(d.line, b)
+	}
+}
+
+func (d *Decoder) field(line []byte) {
+	if line[0] == ':' {
+		return
+	}
+
+	name, value := line, []byte(nil)
+	if i := bytes.IndexByte(line, ':'); i >= 0 {
+		name, value = line[:i], line[i+1:]
+		if len(value) > 0 && value[0] == ' ' {
+			value = value[1:]
+		}
+	}
+
+	switch string(name) {
+	case "event":
+		d.eventType = string(value)
+	case "data":
+		if d.hasData {
+			d.data.WriteByte('\n')
+		}
+		d.data.Write(value)
+		d.hasData = true
+	case "id":
+		if bytes.IndexByte(value, 0) < 0 {
+			d.lastEventID = string(value)
+		}
+	case "retry":
+		if ms, err := strconv.ParseUint(string(value), 10, 32); err == nil {
+			d.retry = time.Duration(ms) * time.Millisecond
+		}
+	}
+}
+
+func (d *Decoder) dispatch() *Event {
+	event := &Event{
+		ID:   d.lastEventID,
+		Type: d.eventType,
+		Data: append(make([]byte, 0, d.data.Len()), d.data.Bytes()...),
+	}
+	d.reset()
+
+	return event
+}
+
+func (d *Decoder) reset() {
+	d.eventType = ""
+	d.data.Reset()
+	d.hasData = false
+	d.size = 0
+}
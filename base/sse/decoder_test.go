@@ -0,0 +1,59 @@
+package sse
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecoder(t *testing.T) {
+	stream := ": comment\n" +
+		"data: first\n\n" +
+		"event: update\r\nid: 1\r\ndata:two\r\ndata:  lines\r\n\r\n" +
+		"retry: 2500\rdata\r\r" +
+		"id\nunknown: field\n\n" +
+		"retry: soon\nid: 2\ndata: {\"end\": true}"
+
+	var got []Event
+	d := NewDecoder(strings.NewReader(stream), 0)
+	for {
+		event, err := d.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, *event)
+	}
+
+	want := []Event{
+		{Data: []byte("first")},
+		{ID: "1", Type: "update", Data: []byte("two\n lines")},
+		{ID: "1", Data: []byte("")},
+		{ID: "2", Data: []byte(`{"end": true}`)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got events %q, want %q", got, want)
+	}
+	if d.LastEventID() != "2" || d.Retry() != 2500*time.Millisecond {
+		t.Fatalf("got last event id %q and retry %v", d.LastEventID(), d.Retry())
+	}
+	if _, err := d.Next(); err != io.EOF {
+		t.Fatalf("got %v after the end", err)
+	}
+}
+
+func TestDecoderMaxEventSize(t *testing.T) {
+	d := NewDecoder(strings.NewReader("data: 12345\n\ndata: 123456789\n\ndata: 1\n\n"), 12)
+	if event, err := d.Next(); err != nil || string(event.Data) != "12345" {
+		t.Fatalf("got event %v, error %v", event, err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := d.Next(); err != ErrEventTooLarge {
+			t.Fatalf("got %v, want ErrEventTooLarge", err)
+		}
+	}
+}
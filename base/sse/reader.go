@@ -0,0 +1,225 @@
+package sse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultReconnectDelay is the time waited before reconnecting when neither
+// the options nor the server give one.
+const DefaultReconnectDelay = time.Second
+
+// ConnectFunc opens the stream, resuming after lastEventID when it is not
+// empty. The body is closed by the Reader.
+type ConnectFunc func(ctx context.Context, lastEventID string) (io.ReadCloser, error)
+
+// ReaderOptions configures a Reader.
+type ReaderOptions struct {
+	// MaxEventSize is the largest event read, DefaultMaxEventSize if <= 0.
+	MaxEventSize int
+	// MaxReconnects is the number of reconnections tried in a row when the
+	// stream breaks, 0 never reconnects. The count starts again after each
+	// event received.
+	MaxReconnects int
+	// ReconnectDelay is the time waited before reconnecting,
+	// DefaultReconnectDelay if <= 0. A retry field sent by the server
+	// replaces it.
+	ReconnectDelay time.Duration
+}
+
+// Reader reads the events of a stream, reconnecting with the Last-Event-ID
+// of the last event received when the connection breaks. It stops when ctx
+// is done or Close is called, closing the connection in use.
+type Reader struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	connect ConnectFunc
+	opts    ReaderOptions
+	delay   time.Duration
+
+	conn      *conn
+	decoder   *Decoder
+	connected bool
+
+	lastEventID string
+	reconnects  int
+	err         error
+}
+
+// NewReader returns a reader of the stream opened by connect. It connects
+// on the first call to Next.
+func NewReader(ctx context.Context, connect ConnectFunc, opts *ReaderOptions) *Reader {
+	r := &Reader{connect: connect}
+	if opts != nil {
+		r.opts = *opts
+	}
+	r.delay = r.opts.ReconnectDelay
+	if r.delay <= 0 {
+		r.delay = DefaultReconnectDelay
+	}
+	r.ctx, r.cancel = context.WithCancel(ctx)
+
+	return r
+}
+
+// LastEventID returns the id of the last event received.
+func (r *Reader) LastEventID() string {
+	return r.lastEventID
+}
+
+// Next returns the next event. It returns io.EOF when the stream ends and
+// can not be reconnected, and the error of ctx once it is done, which is
+// context.Canceled after Close. Errors are final.
+func (r *Reader) Next() (*Event, error) {
+	for r.err == nil {
+		if r.decoder == nil {
+			if err := r.dial(); err != nil {
+				if !r.connected || r.ctx.Err() != nil {
+					r.fail(err)
+					continue
+				}
+				r.reconnect(err)
+				continue
+			}
+		}
+
+		event, err := r.decoder.Next()
+		if err == nil {
+			r.reconnects = 0
+			r.lastEventID = event.ID
+			return event, nil
+		}
+		r.hangUp()
+		if r.ctx.Err() != nil || err == ErrEventTooLarge {
+			r.fail(err)
+			continue
+		}
+		r.reconnect(err)
+	}
+
+	return nil, r.err
+}
+
+// Close stops the reader and closes its connection. It may be called while
+// Next is blocked.
+func (r *Reader) Close() error {
+	r.cancel()
+	return nil
+}
+
+func (r *Reader) dial() error {
+	body, err := r.connect(r.ctx, r.lastEventID)
+	if err != nil {
+		return err
+	}
+	r.connected = true
+	r.conn = &conn{body: body, hungUp: make(chan struct{})}
+	r.decoder = NewDecoder(body, r.opts.MaxEventSize)
+	r.decoder.lastEventID = r.lastEventID
+	go r.conn.watch(r.ctx)
+
+	return nil
+}
+
+func (r *Reader) hangUp() {
+	if retry := r.decoder.Retry(); retry > 0 {
+		r.delay = retry
+	}
+	close(r.conn.hungUp)
+	r.conn.close()
+	r.conn = nil
+	r.decoder = nil
+}
+
+// reconnect waits before the next connection, or fails with err when no
+// more reconnections are allowed.
+func (r *Reader) reconnect(err error) {
+	if r.reconnects >= r.opts.MaxReconnects {
+		r.fail(err)
+		return
+	}
+	r.reconnects++
+
+	timer := time.NewTimer(r.delay)
+	defer timer.Stop()
+	select {
+	case <-r.ctx.Done():
+		r.fail(r.ctx.Err())
+	case <-timer.C:
+	}
+}
+
+func (r *Reader) fail(err error) {
+	if ctxErr := r.ctx.Err(); ctxErr != nil {
+		err = ctxErr
+	}
+	r.err = err
+	r.cancel()
+}
+
+// conn is the body of one connection.
+type conn struct {
+	body   io.ReadCloser
+	once   sync.Once
+	hungUp chan struct{}
+}
+
+// watch closes the body when ctx is done to unblock a pending read.
+func (c *conn) watch(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+		c.close()
+	case <-c.hungUp:
+	}
+}
+
+func (c *conn) close() {
+	c.once.Do(func() { c.body.Close() })
+}
+
+// HTTPConnect returns a ConnectFunc sending req with client, adding the
+// Accept and Last-Event-ID headers. A request with a body must have GetBody
+// set to be sent again. A response other than 200 OK is an error.
+func HTTPConnect(client *http.Client, req *http.Request) ConnectFunc {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	sent := false
+	return func(ctx context.Context, lastEventID string) (io.ReadCloser, error) {
+		r := req.Clone(ctx)
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r.Body = body
+		} else if sent && req.Body != nil && req.Body != http.NoBody {
+			return nil, errors.New("sse: request body can not be sent again")
+		}
+		sent = true
+		r.Header.Set("Accept", "text/event-stream")
+		r.Header.Set("Cache-Control", "no-cache")
+		if lastEventID != "" {
+			r.Header.Set("Last-Event-ID", lastEventID)
+		}
+
+		resp, err := client.Do(r)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			defer resp.Body.Close()
+			msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+			return nil, fmt.Errorf("sse: %s: %s", resp.Status, msg)
+		}
+
+		return resp.Body, nil
+	}
+}
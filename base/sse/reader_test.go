@@ -0,0 +1,96 @@
+package sse
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReaderReconnect(t *testing.T) {
+	var mu sync.Mutex
+	var lastEventIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		lastEventIDs = append(lastEventIDs, r.Header.Get("Last-Event-ID"))
+		n := len(lastEventIDs)
+		mu.Unlock()
+		if r.Header.Get("Accept") != "text/event-stream" {
+			http.Error(w, "not an event stream request", http.StatusBadRequest)
+			return
+		}
+		switch n {
+		case 1:
+			fmt.Fprint(w, "retry: 1\nid: 1\ndata: a\n\nid: 2\ndata: b\n\n")
+		case 2:
+			fmt.Fprint(w, "id: 3\ndata: c\n\n")
+		default:
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	r := NewReader(context.Background(), HTTPConnect(nil, req), &ReaderOptions{MaxReconnects: 2})
+	defer r.Close()
+
+	var data []string
+	for {
+		event, err := r.Next()
+		if err != nil {
+			if !strings.Contains(err.Error(), "503") {
+				t.Fatalf("got error %v, want the 503 of the last reconnection", err)
+			}
+			break
+		}
+		data = append(data, event.ID+":"+string(event.Data))
+	}
+
+	if got := strings.Join(data, " "); got != "1:a 2:b 3:c" {
+		t.Fatalf("got events %s", got)
+	}
+	if got := strings.Join(lastEventIDs, ","); got != ",2,3,3" {
+		t.Fatalf("got Last-Event-ID headers %s", got)
+	}
+	if r.LastEventID() != "3" {
+		t.Fatalf("got last event id %q", r.LastEventID())
+	}
+}
+
+func TestReaderCancel(t *testing.T) {
+	body, writer := io.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+	r := NewReader(ctx, func(ctx context.Context, lastEventID string) (io.ReadCloser, error) {
+		return body, nil
+	}, &ReaderOptions{MaxReconnects: 1})
+
+	go fmt.Fprint(writer, "data: a\n\n")
+	if event, err := r.Next(); err != nil || string(event.Data) != "a" {
+		t.Fatalf("got event %v, error %v", event, err)
+	}
+
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if _, err := r.Next(); err != context.Canceled {
+		t.Fatalf("got %v, want context.Canceled", err)
+	}
+	if _, err := writer.Write([]byte("data: b\n\n")); err != io.ErrClosedPipe {
+		t.Fatalf("the body was not closed, writing got %v", err)
+	}
+}
+
+func TestReaderClose(t *testing.T) {
+	r := NewReader(context.Background(), func(ctx context.Context, lastEventID string) (io.ReadCloser, error) {
+		body, _ := io.Pipe()
+		return body, nil
+	}, nil)
+
+	time.AfterFunc(10*time.Millisecond, func() { r.Close() })
+	if _, err := r.Next(); err != context.Canceled {
+		t.Fatalf("got %v after Close, want context.Canceled", err)
+	}
+}
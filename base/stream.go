@@ -0,0 +1,239 @@
+package base
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// UnsignedPayload is signed in place of the body hash when a streamed body can
+// not be read twice. The service has to accept unsigned payloads.
+const UnsignedPayload = "UNSIGNED-PAYLOAD"
+
+// StreamBody is a request body sent without being buffered in memory.
+type StreamBody struct {
+	// Reader is read from its current offset.
+	Reader io.Reader
+	// ContentLength is the body size, 0 when unknown. It is found by seeking
+	// when Reader is an io.Seeker.
+	ContentLength int64
+	// PayloadHash is the hex encoded SHA256 of the body. When empty it is
+	// computed by reading a seekable Reader once, otherwise UnsignedPayload is
+	// signed. Only a seekable Reader can be retried.
+	PayloadHash string
+}
+
+// NewStreamBody wraps r, computing nothing up front.
+func NewStreamBody(r io.Reader) *StreamBody {
+	return &StreamBody{Reader: r}
+}
+
+// CtxStream sends body as it is read and returns the response body unread.
+// Until the response headers arrive the client timeout is an idle timeout: it
+// fires when the body was not read for that long, so a slow upload is not cut
+// off while it makes progress. Reading the response body is bounded by ctx
+// only. The caller must close the returned body.
+func (client *Client) CtxStream(ctx context.Context, api string, query url.Values, body *StreamBody, ct string) (io.ReadCloser, int, error) {
+	apiInfo := client.ApiInfoList[api]
+	if apiInfo == nil {
+		return nil, 500, errors.New("The related api does not exist")
+	}
+	return client.streamThumb(ctx, api, apiInfo, query, body, ct)
+}
+
+// CtxMultiPartStream is CtxMultiPart without buffering the form: parts are
+// encoded while the request is sent. The body is signed as UnsignedPayload and
+// never retried.
+func (client *Client) CtxMultiPartStream(ctx context.Context, api string, query url.Values, form []*MultiPartItem) (io.ReadCloser, int, error) {
+	reader, writer := io.Pipe()
+	mw := multipart.NewWriter(writer)
+	go func() {
+		for _, item := range form {
+			part, err := mw.CreatePart(item.header)
+			if err != nil {
+				writer.CloseWithError(err)
+				return
+			}
+			if _, err = io.Copy(part, item.data); err != nil {
+				writer.CloseWithError(err)
+				return
+			}
+		}
+		writer.CloseWithError(mw.Close())
+	}()
+
+	rc, code, err := client.CtxStream(ctx, api, query, &StreamBody{Reader: reader, PayloadHash: UnsignedPayload}, mw.FormDataContentType())
+	// Unblock the encoder if the request ended before the form was consumed.
+	reader.Close()
+	return rc, code, err
+}
+
+func (client *Client) streamThumb(ctx context.Context, api string, apiInfo *ApiInfo, query url.Values, body *StreamBody, ct string) (io.ReadCloser, int, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if body == nil {
+		body = &StreamBody{Reader: strings.NewReader("")}
+	}
+
+	info, opts := client.GetServiceInfo(), client.getOptions()
+	timeout := getTimeout(info.Timeout, apiInfo.Timeout, client.getCustomTimeout())
+	header := mergeHeader(info.Header, apiInfo.Header)
+	query = mergeQuery(query, apiInfo.Query)
+	retrySettings := getRetrySetting(&info.Retry, &apiInfo.Retry)
+
+	u := url.URL{
+		Scheme:   info.Scheme,
+		Host:     info.Host,
+		Path:     apiInfo.Path,
+		RawQuery: query.Encode(),
+	}
+	req, err := http.NewRequest(strings.ToUpper(apiInfo.Method), u.String(), nil)
+	if err != nil {
+		return nil, 500, errors.New("Failed to build request")
+	}
+	req.Header = header
+	if ct != "" {
+		req.Header.Set("Content-Type", ct)
+	}
+	req.Header.Set("User-Agent", strings.Join([]string{SDKName, SDKVersion}, "/"))
+
+	payloadHash, contentLength := body.PayloadHash, body.ContentLength
+	seeker, seekable := body.Reader.(io.ReadSeeker)
+	var start int64
+	if seekable {
+		// The body is sent from the current offset, not from the start.
+		if start, err = seeker.Seek(0, io.SeekCurrent); err != nil {
+			return nil, 500, err
+		}
+		if payloadHash == "" {
+			h := sha256.New()
+			if _, err = io.Copy(h, seeker); err != nil {
+				return nil, 500, err
+			}
+			payloadHash = hex.EncodeToString(h.Sum(nil))
+		}
+		if contentLength <= 0 {
+			end, err := seeker.Seek(0, io.SeekEnd)
+			if err != nil {
+				return nil, 500, err
+			}
+			contentLength = end - start
+		}
+	} else if payloadHash == "" {
+		payloadHash = UnsignedPayload
+	}
+
+	policy := opts.retryPolicy
+	if policy == nil {
+		policy = &constantRetryPolicy{times: *retrySettings.RetryTimes, interval: *retrySettings.RetryInterval}
+	}
+	if !seekable {
+		policy = RetryPolicyFunc(func(*RetryAttempt) (time.Duration, bool) { return 0, false })
+	}
+
+	var result io.ReadCloser
+	var code int
+	err = DoWithRetry(ctx, policy, opts.retryBudget, func() *RetryAttempt {
+		if seekable {
+			if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+				code = 500
+				return &RetryAttempt{Api: api, Err: err, Permanent: true}
+			}
+		}
+		attemptCtx, cancel := context.WithCancel(ctx)
+		timer := newIdleTimer(timeout, cancel)
+		req.Body = ioutil.NopCloser(&idleTimeoutReader{Reader: body.Reader, timer: timer})
+		req.ContentLength = contentLength
+
+		resp, attempt := client.roundTrip(attemptCtx, api, req, payloadHash, info, opts)
+		timer.stop()
+		code = legacyStatusCode(attempt)
+		if attempt.Err != nil {
+			cancel()
+			return attempt
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			respBody, err := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			cancel()
+			if err != nil {
+				attempt.Err = err
+			} else {
+				attempt.Err = newAPIError(api, resp.StatusCode, respBody)
+			}
+			return attempt
+		}
+
+		result = &cancelReadCloser{ReadCloser: resp.Body, cancel: cancel}
+		return attempt
+	})
+	if err != nil {
+		return nil, code, err
+	}
+	return result, code, nil
+}
+
+// idleTimer calls its function once it was not reset for the timeout.
+type idleTimer struct {
+	lock    sync.Mutex
+	timer   *time.Timer
+	timeout time.Duration
+	stopped bool
+}
+
+func newIdleTimer(timeout time.Duration, f func()) *idleTimer {
+	return &idleTimer{timer: time.AfterFunc(timeout, f), timeout: timeout}
+}
+
+func (t *idleTimer) reset() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	// The transport may still read the body after the response arrived.
+	if !t.stopped {
+		t.timer.Reset(t.timeout)
+	}
+}
+
+func (t *idleTimer) stop() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.stopped = true
+	t.timer.Stop()
+}
+
+// idleTimeoutReader resets timer whenever the request body makes progress.
+type idleTimeoutReader struct {
+	io.Reader
+	timer *idleTimer
+}
+
+func (r *idleTimeoutReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if n > 0 {
+		r.timer.reset()
+	}
+	return n, err
+}
+
+// cancelReadCloser releases the request context once the body is closed.
+type cancelReadCloser struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelReadCloser) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
@@ -0,0 +1,149 @@
+package base
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_CtxStream(t *testing.T) {
+	payload := strings.Repeat("volc", 1<<16)
+	sum := sha256.Sum256([]byte(payload))
+
+	var served int
+	var gotHash, gotBody string
+	_, client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		served++
+		b, _ := ioutil.ReadAll(r.Body)
+		gotHash, gotBody = r.Header.Get("X-Content-Sha256"), string(b)
+		if served == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(payload))
+	})
+	retryTimes := uint64(1)
+	interval := time.Millisecond
+	client.SetRetrySettings(&RetrySettings{AutoRetry: true, RetryTimes: &retryTimes, RetryInterval: &interval})
+	client.ApiInfoList = map[string]*ApiInfo{"Upload": {Method: http.MethodPost, Path: "/", Retry: RetrySettings{AutoRetry: true}}}
+
+	// A seekable body is hashed up front and can be retried.
+	rc, code, err := client.CtxStream(context.Background(), "Upload", nil, NewStreamBody(strings.NewReader(payload)), "application/octet-stream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	resp, _ := ioutil.ReadAll(rc)
+	if code != http.StatusOK || served != 2 || string(resp) != payload {
+		t.Fatalf("unexpected response code %d after %d attempts, %d bytes", code, served, len(resp))
+	}
+	if gotHash != hex.EncodeToString(sum[:]) || gotBody != payload {
+		t.Fatalf("unexpected upload hash %s, %d bytes", gotHash, len(gotBody))
+	}
+
+	// A reader past its start is hashed and sent from its offset, retries
+	// included.
+	served = 0
+	reader := strings.NewReader(payload)
+	reader.Seek(4, io.SeekStart)
+	rc, code, err = client.CtxStream(context.Background(), "Upload", nil, NewStreamBody(reader), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc.Close()
+	tailSum := sha256.Sum256([]byte(payload[4:]))
+	if code != http.StatusOK || served != 2 || gotHash != hex.EncodeToString(tailSum[:]) || gotBody != payload[4:] {
+		t.Fatalf("unexpected upload hash %s, %d bytes after %d attempts", gotHash, len(gotBody), served)
+	}
+
+	// A plain reader is signed as unsigned payload and not retried.
+	served = 0
+	_, code, err = client.CtxStream(context.Background(), "Upload", nil, NewStreamBody(io.MultiReader(strings.NewReader(payload))), "")
+	if _, ok := AsAPIError(err); !ok || code != http.StatusServiceUnavailable || served != 1 {
+		t.Fatalf("expect APIError without retry, got %v code %d after %d attempts", err, code, served)
+	}
+	if gotHash != UnsignedPayload || gotBody != payload {
+		t.Fatalf("unexpected upload hash %s, %d bytes", gotHash, len(gotBody))
+	}
+}
+
+func TestClient_CtxMultiPartStream(t *testing.T) {
+	var fields map[string]string
+	var file string
+	_, client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fields = map[string]string{"name": r.FormValue("name")}
+		f, _, err := r.FormFile("file")
+		if err == nil {
+			b, _ := ioutil.ReadAll(f)
+			file = string(b)
+		}
+		w.Write([]byte(`{}`))
+	})
+	client.ApiInfoList = map[string]*ApiInfo{"Upload": {Method: http.MethodPost, Path: "/"}}
+
+	content := strings.Repeat("x", 1<<20)
+	rc, code, err := client.CtxMultiPartStream(context.Background(), "Upload", nil, []*MultiPartItem{
+		CreateMultiPartItemFormField("name", "image"),
+		CreateMultiPartItemFormFile("file", "a.bin", strings.NewReader(content)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc.Close()
+	if code != http.StatusOK || fields["name"] != "image" || file != content {
+		t.Fatalf("unexpected form %v, file %d bytes", fields, len(file))
+	}
+}
+
+// slowReader returns chunks of data, sleeping delay before each.
+type slowReader struct {
+	chunks []string
+	delay  time.Duration
+}
+
+func (r *slowReader) Read(p []byte) (int, error) {
+	if len(r.chunks) == 0 {
+		return 0, io.EOF
+	}
+	time.Sleep(r.delay)
+	n := copy(p, r.chunks[0])
+	r.chunks = r.chunks[1:]
+	return n, nil
+}
+
+func TestClient_CtxStreamIdleTimeout(t *testing.T) {
+	_, client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		w.Write(b)
+	})
+	client.SetTimeout(200 * time.Millisecond)
+	client.ApiInfoList = map[string]*ApiInfo{"Upload": {Method: http.MethodPost, Path: "/"}}
+
+	// An upload taking longer than the timeout succeeds while it makes progress.
+	chunks := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	rc, code, err := client.CtxStream(context.Background(), "Upload", nil, NewStreamBody(&slowReader{chunks: chunks, delay: 50 * time.Millisecond}), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, _ := ioutil.ReadAll(rc)
+	rc.Close()
+	if code != http.StatusOK || string(resp) != strings.Join(chunks, "") {
+		t.Fatalf("unexpected response code %d, body %q", code, resp)
+	}
+
+	// A stalled upload times out.
+	_, _, err = client.CtxStream(context.Background(), "Upload", nil, NewStreamBody(&slowReader{chunks: []string{"a", "b"}, delay: time.Second}), "")
+	if err == nil {
+		t.Fatal("expect the stalled upload to time out")
+	}
+}
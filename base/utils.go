@@ -225,7 +225,12 @@ func UnmarshalResultInto(data []byte, result interface{}) error {
 	}
 	errObj := resp.ResponseMetadata.Error
 	if errObj != nil && errObj.CodeN != 0 {
-		return fmt.Errorf("request %s error %s", resp.ResponseMetadata.RequestId, errObj.Message)
+		apiErr := &APIError{
+			Body: data,
+			msg:  fmt.Sprintf("request %s error %s", resp.ResponseMetadata.RequestId, errObj.Message),
+		}
+		apiErr.fill(&resp.ResponseMetadata)
+		return apiErr
 	}
 
 	data, err := json.Marshal(resp.Result)
@@ -237,3 +242,8 @@ func UnmarshalResultInto(data []byte, result interface{}) error {
 	}
 	return nil
 }
+
+// CopyQuery returns a deep copy of query, never nil.
+func CopyQuery(query url.Values) url.Values {
+	return mergeQuery(query, nil)
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/volcengine/volc-sdk-golang/service/tls"
+	"github.com/volcengine/volc-sdk-golang/service/tls/declarative"
+)
+
+func main() {
+	specFile := flag.String("spec", "", "资源配置文件，YAML或JSON格式")
+	prune := flag.Bool("prune", false, "删除配置文件中日志项目下未列出的日志主题、索引、采集配置和告警策略")
+	apply := flag.Bool("apply", false, "执行变更计划，默认只输出计划")
+	flag.Parse()
+	if *specFile == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	spec, err := declarative.LoadFile(*specFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	// 初始化客户端，推荐通过环境变量动态获取火山引擎密钥等身份认证信息，以免AccessKey硬编码引发数据安全风险。
+	client := tls.NewClient(os.Getenv("VOLCENGINE_ENDPOINT"), os.Getenv("VOLCENGINE_ACCESS_KEY_ID"),
+		os.Getenv("VOLCENGINE_ACCESS_KEY_SECRET"), os.Getenv("VOLCENGINE_TOKEN"), os.Getenv("VOLCENGINE_REGION"))
+
+	opts := &declarative.Options{Prune: *prune, Output: os.Stdout}
+	plan, err := declarative.NewPlan(context.Background(), client, spec, opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Print(plan)
+	if plan.Empty() || !*apply {
+		return
+	}
+
+	fmt.Println()
+	if err := declarative.Apply(context.Background(), client, plan, opts); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
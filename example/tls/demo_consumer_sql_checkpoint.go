@@ -0,0 +1,128 @@
+package tls
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/volcengine/volc-sdk-golang/service/tls"
+	log_consumer "github.com/volcengine/volc-sdk-golang/service/tls/consumer"
+)
+
+// 本示例展示如何将消费位点与处理结果在同一个数据库事务中提交，实现精确一次（exactly-once）消费。
+// 示例使用MySQL语法，需要的表结构如下：
+//
+//	CREATE TABLE tls_checkpoint (
+//		topic_id   VARCHAR(64) NOT NULL,
+//		shard_id   INT         NOT NULL,
+//		checkpoint VARCHAR(256) NOT NULL,
+//		PRIMARY KEY (topic_id, shard_id)
+//	);
+//	CREATE TABLE tls_log (
+//		topic_id VARCHAR(64) NOT NULL,
+//		shard_id INT         NOT NULL,
+//		log_time BIGINT      NOT NULL,
+//		content  TEXT        NOT NULL
+//	);
+
+// sqlCheckpointStore 从数据库读取消费位点。消费位点由sqlProcessor在写入日志的事务中提交，因此Save无需再次写入。
+type sqlCheckpointStore struct {
+	db *sql.DB
+}
+
+func (s *sqlCheckpointStore) Load(ctx context.Context, shard *tls.ConsumeShard) (string, error) {
+	var checkpoint string
+	err := s.db.QueryRowContext(ctx, "SELECT checkpoint FROM tls_checkpoint WHERE topic_id = ? AND shard_id = ?",
+		shard.TopicID, shard.ShardID).Scan(&checkpoint)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+
+	return checkpoint, err
+}
+
+func (s *sqlCheckpointStore) Save(ctx context.Context, shard *tls.ConsumeShard, checkpoint string) error {
+	return nil
+}
+
+// sqlProcessor 在同一个事务中写入日志和该批日志的消费位点。
+type sqlProcessor struct {
+	db *sql.DB
+}
+
+func (p *sqlProcessor) Initialize(shard *tls.ConsumeShard) error {
+	return nil
+}
+
+func (p *sqlProcessor) Process(ctx context.Context, batch *log_consumer.Batch) error {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, logGroup := range batch.Logs.LogGroups {
+		for _, log := range logGroup.Logs {
+			_, err := tx.ExecContext(ctx, "INSERT INTO tls_log (topic_id, shard_id, log_time, content) VALUES (?, ?, ?, ?)",
+				batch.Shard.TopicID, batch.Shard.ShardID, log.Time, log.String())
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO tls_checkpoint (topic_id, shard_id, checkpoint) VALUES (?, ?, ?) "+
+		"ON DUPLICATE KEY UPDATE checkpoint = VALUES(checkpoint)", batch.Shard.TopicID, batch.Shard.ShardID, batch.Cursor)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (p *sqlProcessor) Shutdown(shard *tls.ConsumeShard, reason log_consumer.ShutdownReason) {}
+
+func launchSQLConsumer() error {
+	// 请导入您使用的数据库驱动，例如 _ "github.com/go-sql-driver/mysql"
+	db, err := sql.Open("mysql", os.Getenv("MYSQL_DSN"))
+	if err != nil {
+		return errors.Wrap(err, "open database failed.")
+	}
+	defer db.Close()
+
+	consumerCfg := log_consumer.GetDefaultConsumerConfig()
+	consumerCfg.Endpoint = os.Getenv("VOLCENGINE_ENDPOINT")
+	consumerCfg.Region = os.Getenv("VOLCENGINE_REGION")
+	consumerCfg.AccessKeyID = os.Getenv("VOLCENGINE_ACCESS_KEY_ID")
+	consumerCfg.AccessKeySecret = os.Getenv("VOLCENGINE_ACCESS_KEY_SECRET")
+	consumerCfg.ProjectID = "<YOUR-PROJECT-ID>"
+	consumerCfg.TopicIDList = []string{"<YOUR-TOPIC-ID>"}
+	consumerCfg.ConsumerGroupName = "<CONSUMER-GROUP-NAME>"
+	consumerCfg.ConsumerName = "<CONSUMER_NAME>"
+	// 消费位点从数据库读取
+	consumerCfg.CheckpointStore = &sqlCheckpointStore{db: db}
+
+	consumer, err := log_consumer.NewConsumerWithProcessor(context.TODO(), consumerCfg, &sqlProcessor{db: db})
+	if err != nil {
+		return errors.Wrap(err, "get new consumer failed.")
+	}
+
+	if err := consumer.Start(); err != nil {
+		return errors.Wrap(err, "start consumer failed.")
+	}
+
+	<-time.After(time.Second * 60)
+
+	consumer.Stop()
+
+	return nil
+}
+
+func main() {
+	if err := launchSQLConsumer(); err != nil {
+		fmt.Println(err.Error())
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+
+	"github.com/volcengine/volc-sdk-golang/service/tls"
+)
+
+// filterFlags collects -filter key=value, key~value (contains) or ~value
+// (any key contains) options.
+type filterFlags []tls.TailFilter
+
+func (f *filterFlags) String() string {
+	return fmt.Sprint(*f)
+}
+
+func (f *filterFlags) Set(value string) error {
+	if i := strings.IndexAny(value, "=~"); i >= 0 {
+		*f = append(*f, tls.TailFilter{Key: value[:i], Value: value[i+1:], Contains: value[i] == '~'})
+		return nil
+	}
+
+	return fmt.Errorf("invalid filter %q, use key=value, key~value or ~value", value)
+}
+
+func main() {
+	var filters filterFlags
+	topicID := flag.String("topic", "", "日志主题ID")
+	from := flag.String("from", tls.TailFromEnd, "起始位置：begin、end或Unix时间戳（秒）")
+	rate := flag.Int("rate", 0, "每秒最多输出的日志条数，超出的日志将被丢弃，0表示不限制")
+	flag.Var(&filters, "filter", "过滤条件，可重复指定：key=value（等于）、key~value（包含）或~value（任意字段包含）")
+	flag.Parse()
+	if *topicID == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// 初始化客户端，推荐通过环境变量动态获取火山引擎密钥等身份认证信息，以免AccessKey硬编码引发数据安全风险。
+	client := tls.NewClient(os.Getenv("VOLCENGINE_ENDPOINT"), os.Getenv("VOLCENGINE_ACCESS_KEY_ID"),
+		os.Getenv("VOLCENGINE_ACCESS_KEY_SECRET"), os.Getenv("VOLCENGINE_TOKEN"), os.Getenv("VOLCENGINE_REGION"))
+
+	// 按Ctrl+C停止
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	go func() {
+		<-signals
+		cancel()
+	}()
+
+	stream, err := tls.Tail(ctx, client, *topicID, &tls.TailOptions{
+		From:             *from,
+		Filters:          filters,
+		MaxLogsPerSecond: *rate,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	for log := range stream.Logs() {
+		contents := make([]string, 0, len(log.Log.Contents))
+		for _, content := range log.Log.Contents {
+			contents = append(contents, content.Key+"="+content.Value)
+		}
+		sort.Strings(contents)
+		fmt.Printf("%s [shard %d] %s %s\n", log.Time.Format("2006-01-02 15:04:05.000"), log.ShardID, log.Source, strings.Join(contents, " "))
+	}
+	if dropped := stream.Dropped(); dropped > 0 {
+		fmt.Fprintf(os.Stderr, "%d logs dropped by -rate\n", dropped)
+	}
+	if err := stream.Err(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
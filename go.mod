@@ -12,7 +12,7 @@ require (
 	github.com/google/go-querystring v1.1.0
 	github.com/google/martian v2.1.0+incompatible
 	github.com/google/uuid v1.3.0
-	github.com/klauspost/compress v1.15.9 // indirect
+	github.com/klauspost/compress v1.15.9
 	github.com/pierrec/lz4 v2.6.1+incompatible
 	github.com/pkg/errors v0.9.1
 	github.com/stretchr/testify v1.7.0
@@ -21,7 +21,7 @@ require (
 	golang.org/x/net v0.12.0
 	google.golang.org/protobuf v1.28.0
 	gopkg.in/natefinch/lumberjack.v2 v2.0.0
-	gopkg.in/yaml.v3 v3.0.1 // indirect
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 replace github.com/volcengine/volc-sdk-golang => ../volc-sdk-golang
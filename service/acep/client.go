@@ -34,7 +34,7 @@ func (acep *ACEP) SetProxyHost(host, proxyUser, proxyPassword string) {
 	acep.Client.Client.Transport = &http.Transport{
 		Proxy: http.ProxyURL(&url.URL{
 			Host:   host,
-			Scheme: acep.ServiceInfo.Scheme,
+			Scheme: acep.GetServiceInfo().Scheme,
 			User:   url.UserPassword(proxyUser, proxyPassword),
 		}),
 	}
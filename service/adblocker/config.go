@@ -26,7 +26,9 @@ func (p *AdBlocker) SetRegion(region string) error {
 	if !ok {
 		return fmt.Errorf("region does not spport or unknown region")
 	}
-	p.ServiceInfo = serviceInfo
+	p.UpdateServiceInfo(func(info *base.ServiceInfo) {
+		*info = *serviceInfo
+	})
 	return nil
 }
 
@@ -1365,8 +1365,10 @@ func NewInstance() *AIoT {
 	instance := &AIoT{}
 	mergeApiInfo, _ := MergeApiConfig(ApiInfoList, ApiInfoListV3)
 	instance.Client = base.NewClient(ServiceInfo, mergeApiInfo)
-	instance.Client.ServiceInfo.Credentials.Service = ServiceName
-	instance.Client.ServiceInfo.Credentials.Region = DefaultRegion
+	instance.Client.UpdateServiceInfo(func(info *base.ServiceInfo) {
+		info.Credentials.Service = ServiceName
+		info.Credentials.Region = DefaultRegion
+	})
 	return instance
 }
 
@@ -1390,12 +1392,12 @@ func (p *AIoT) SetRegion(region string) {
 
 // SetHost .
 func (p *AIoT) SetHost(host string) {
-	p.Client.ServiceInfo.Host = host
+	p.Client.SetHost(host)
 }
 
 // SetSchema .
 func (p *AIoT) SetSchema(schema string) {
-	p.Client.ServiceInfo.Scheme = schema
+	p.Client.SetScheme(schema)
 }
 
 func (p *AIoT) AddRequestInterceptor(interceptor QueryInterceptorFunc) {
@@ -105,14 +105,16 @@ type Billing struct {
 func NewInstance() *Billing {
 	instance := &Billing{}
 	instance.Client = base.NewClient(ServiceInfo, ApiInfoList)
-	instance.Client.ServiceInfo.Credentials.Service = ServiceName
-	instance.Client.ServiceInfo.Credentials.Region = DefaultRegion
+	instance.Client.UpdateServiceInfo(func(info *base.ServiceInfo) {
+		info.Credentials.Service = ServiceName
+		info.Credentials.Region = DefaultRegion
+	})
 	return instance
 }
 
 // GetServiceInfo interface
 func (p *Billing) GetServiceInfo() *base.ServiceInfo {
-	return p.Client.ServiceInfo
+	return p.Client.GetServiceInfo()
 }
 
 // GetAPIInfo interface
@@ -125,15 +127,15 @@ func (p *Billing) GetAPIInfo(api string) *base.ApiInfo {
 
 // SetHost .
 func (p *Billing) SetRegion(region string) {
-	p.Client.ServiceInfo.Credentials.Region = region
+	p.Client.SetRegion(region)
 }
 
 // SetHost .
 func (p *Billing) SetHost(host string) {
-	p.Client.ServiceInfo.Host = host
+	p.Client.SetHost(host)
 }
 
 // SetSchema .
 func (p *Billing) SetSchema(schema string) {
-	p.Client.ServiceInfo.Scheme = schema
+	p.Client.SetScheme(schema)
 }
@@ -0,0 +1,61 @@
+package billing
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/volcengine/volc-sdk-golang/base"
+)
+
+// ListBillDetailPaginator pages through ListBillDetail by Offset/Limit lazily.
+type ListBillDetailPaginator struct {
+	paginator *base.Paginator
+	page      []*BillDetail
+}
+
+// NewListBillDetailPaginator pages with the Limit in query, 100 when absent.
+func (p *Billing) NewListBillDetailPaginator(query url.Values) *ListBillDetailPaginator {
+	limit, _ := strconv.Atoi(query.Get("Limit"))
+	if limit <= 0 {
+		limit = 100
+	}
+	lp := &ListBillDetailPaginator{}
+	lp.paginator = base.NewOffsetPaginator(limit, func(ctx context.Context, offset, limit int) (int, int, error) {
+		q := base.CopyQuery(query)
+		q.Set("Offset", strconv.Itoa(offset))
+		q.Set("Limit", strconv.Itoa(limit))
+		resp, _, err := p.ListBillDetailCtx(ctx, q)
+		if err != nil {
+			return 0, 0, err
+		}
+		if resp.Result == nil {
+			lp.page = nil
+			return 0, 0, nil
+		}
+		lp.page = resp.Result.List
+		return len(lp.page), resp.Result.Total, nil
+	})
+	return lp
+}
+
+func (p *ListBillDetailPaginator) HasMorePages() bool {
+	return p.paginator.HasMorePages()
+}
+
+func (p *ListBillDetailPaginator) NextPage(ctx context.Context) ([]*BillDetail, error) {
+	if err := p.paginator.NextPage(ctx); err != nil {
+		return nil, err
+	}
+	return p.page, nil
+}
+
+// ListAllBillDetail collects up to max bill details, all of them when max <= 0.
+func (p *Billing) ListAllBillDetail(ctx context.Context, query url.Values, max int) ([]*BillDetail, error) {
+	var all []*BillDetail
+	lp := p.NewListBillDetailPaginator(query)
+	err := lp.paginator.Collect(ctx, max, func(keep int) {
+		all = append(all, lp.page[:keep]...)
+	})
+	return all, err
+}
@@ -1,6 +1,7 @@
 package billing
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -10,7 +11,11 @@ import (
 
 // ListBillDetail 分页查询账单明细
 func (p *Billing) ListBillDetail(query url.Values) (*BillDetailListResp, int, error) {
-	respBody, status, err := p.Client.Query("ListBillDetail", query)
+	return p.ListBillDetailCtx(context.Background(), query)
+}
+
+func (p *Billing) ListBillDetailCtx(ctx context.Context, query url.Values) (*BillDetailListResp, int, error) {
+	respBody, status, err := p.Client.CtxQuery(ctx, "ListBillDetail", query)
 	if err != nil {
 		return nil, status, err
 	}
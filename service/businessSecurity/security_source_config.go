@@ -42,7 +42,9 @@ func (p *SecuritySecurityClient) SetRegion(region string) error {
 	if !ok {
 		return fmt.Errorf("region does not spport or unknown region")
 	}
-	p.ServiceInfo = serviceInfo
+	p.UpdateServiceInfo(func(info *base.ServiceInfo) {
+		*info = *serviceInfo
+	})
 	p.SetScheme("http")
 	return nil
 }
@@ -6,7 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	hi_sse "github.com/volcengine/volc-sdk-golang/service/businessSecurity/sse"
+	"github.com/volcengine/volc-sdk-golang/base/sse"
 	"github.com/volcengine/volc-sdk-golang/service/maas/models/api"
 	"io"
 	"io/ioutil"
@@ -1250,6 +1250,7 @@ func (p *SecuritySecurityClient) SecuritySource(req *RiskDetectionRequest) (*Sec
 }
 
 func (p *SecuritySecurityClient) SecuritySourceStream(req *RiskDetectionRequest) (<-chan *SecuritySourceResponse, error) {
+	info := p.GetServiceInfo()
 	reqData, err := json.Marshal(req)
 	if err != nil {
 		return nil, fmt.Errorf("SecuritySource: fail to marshal request, %v", err)
@@ -1260,15 +1261,19 @@ func (p *SecuritySecurityClient) SecuritySourceStream(req *RiskDetectionRequest)
 		return nil, api.NewClientSDKRequestError("the related api does not exist")
 	}
 
-	r, err := makeRequest(apiInfo, p.ServiceInfo, nil, "application/json")
+	r, err := makeRequest(apiInfo, info, nil, "application/json")
 	if err != nil {
 		return nil, api.NewClientSDKRequestError(fmt.Sprintf("failed to make request: %v", err))
 	}
 
 	r.Body = ioutil.NopCloser(bytes.NewReader(reqData))
-	timeout := getTimeout(p.ServiceInfo.Timeout, apiInfo.Timeout)
+	timeout := getTimeout(info.Timeout, apiInfo.Timeout)
 
-	r = p.ServiceInfo.Credentials.Sign(r)
+	cred, err := p.Credentials()
+	if err != nil {
+		return nil, api.NewClientSDKRequestError(fmt.Sprintf("failed to get credentials: %v", err))
+	}
+	r = cred.Sign(r)
 
 	ctx, cancel := context.WithTimeout(context.TODO(), timeout)
 	r = r.WithContext(ctx)
@@ -1297,12 +1302,12 @@ func (p *SecuritySecurityClient) SecuritySourceStream(req *RiskDetectionRequest)
 			cancel()
 			close(ch)
 		}()
-		stream := hi_sse.NewEventStreamFromReader(resp.Body, maxBufferSize)
+		stream := sse.NewDecoder(resp.Body, maxBufferSize)
 
 		for {
 			event, err := stream.Next()
 			if err != nil {
-				if errors.Is(err, io.EOF) {
+				if errors.Is(err, io.EOF) || errors.Is(err, context.Canceled) {
 					return
 				}
 				//if errors.Is(err, context.DeadlineExceeded) {
@@ -1314,10 +1319,8 @@ func (p *SecuritySecurityClient) SecuritySourceStream(req *RiskDetectionRequest)
 				}
 				return
 			}
-			if event != nil {
-				if bytes.Equal(event.Data, []byte(terminator)) {
-					return
-				}
+			if bytes.Equal(event.Data, []byte(terminator)) {
+				return
 			}
 
 			item := &SecuritySourceResponse{}
@@ -36,8 +36,10 @@ type CDN struct {
 func NewInstance() *CDN {
 	instance := new(CDN)
 	instance.Client = base.NewClient(ServiceInfo[DefaultRegion], ApiInfoList)
-	instance.Client.ServiceInfo.Credentials.Service = ServiceName
-	instance.Client.ServiceInfo.Credentials.Region = DefaultRegion
+	instance.Client.UpdateServiceInfo(func(info *base.ServiceInfo) {
+		info.Credentials.Service = ServiceName
+		info.Credentials.Region = DefaultRegion
+	})
 	return instance
 }
 
@@ -63,16 +65,16 @@ func (s *CDN) GetMethod(api string) string {
 }
 
 func (s *CDN) SetRegion(region string) {
-	s.Client.ServiceInfo.Credentials.Region = region
+	s.Client.SetRegion(region)
 }
 
 func (s *CDN) SetHost(host string) {
-	s.Client.ServiceInfo.Host = host
+	s.Client.SetHost(host)
 }
 
 // SetSchema .
 func (s *CDN) SetSchema(schema string) {
-	s.Client.ServiceInfo.Scheme = schema
+	s.Client.SetScheme(schema)
 }
 
 func (s *CDN) SetMethod(api, method string) bool {
@@ -48,14 +48,16 @@ type CloudTrail struct {
 func NewInstance() *CloudTrail {
 	instance := &CloudTrail{}
 	instance.Client = base.NewClient(ServiceInfo, ApiInfoList)
-	instance.Client.ServiceInfo.Credentials.Service = ServiceName
-	instance.Client.ServiceInfo.Credentials.Region = DefaultRegion
+	instance.Client.UpdateServiceInfo(func(info *base.ServiceInfo) {
+		info.Credentials.Service = ServiceName
+		info.Credentials.Region = DefaultRegion
+	})
 	return instance
 }
 
 // GetServiceInfo interface
 func (p *CloudTrail) GetServiceInfo() *base.ServiceInfo {
-	return p.Client.ServiceInfo
+	return p.Client.GetServiceInfo()
 }
 
 // GetAPIInfo interface
@@ -68,15 +70,15 @@ func (p *CloudTrail) GetAPIInfo(api string) *base.ApiInfo {
 
 // SetRegion .
 func (p *CloudTrail) SetRegion(region string) {
-	p.Client.ServiceInfo.Credentials.Region = region
+	p.Client.SetRegion(region)
 }
 
 // SetHost .
 func (p *CloudTrail) SetHost(host string) {
-	p.Client.ServiceInfo.Host = host
+	p.Client.SetHost(host)
 }
 
 // SetSchema .
 func (p *CloudTrail) SetSchema(schema string) {
-	p.Client.ServiceInfo.Scheme = schema
+	p.Client.SetScheme(schema)
 }
@@ -26,17 +26,19 @@ func NewVolcCaller() *VolcCaller {
 	instance := &VolcCaller{Volc: NewDefaultServiceInfo()}
 	instance.Volc.SetAccessKey(os.Getenv("VOLC_ACCESSKEY"))
 	instance.Volc.SetSecretKey(os.Getenv("VOLC_SECRETKEY"))
-	instance.Volc.SetHost(instance.Volc.ServiceInfo.Host)
-	instance.Volc.SetScheme(instance.Volc.ServiceInfo.Scheme)
-	instance.Volc.SetTimeout(instance.Volc.ServiceInfo.Timeout)
+	info := instance.Volc.GetServiceInfo()
+	instance.Volc.SetHost(info.Host)
+	instance.Volc.SetScheme(info.Scheme)
+	instance.Volc.SetTimeout(info.Timeout)
 
 	return instance
 }
 
 func (c *VolcCaller) Do(r *http.Request) (*http.Response, error) {
-	r.URL.Host = c.Volc.ServiceInfo.Host
-	r.URL.Scheme = c.Volc.ServiceInfo.Scheme
-	r.Host = c.Volc.ServiceInfo.Host
+	info := c.Volc.GetServiceInfo()
+	r.URL.Host = info.Host
+	r.URL.Scheme = info.Scheme
+	r.Host = info.Host
 
 	r.Header.Add("Content-Type", "application/json")
 	q := r.URL.Query()
@@ -44,9 +46,13 @@ func (c *VolcCaller) Do(r *http.Request) (*http.Response, error) {
 	q.Add("X-Account-Id", xTopAccountID)
 	r.URL.RawQuery = q.Encode()
 
-	r = c.Volc.ServiceInfo.Credentials.Sign(r)
+	cred, err := c.Volc.Credentials()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	r = cred.Sign(r)
 
-	ctx, cancel := context.WithTimeout(r.Context(), c.Volc.ServiceInfo.Timeout)
+	ctx, cancel := context.WithTimeout(r.Context(), info.Timeout)
 	defer cancel()
 	r = r.WithContext(ctx)
 
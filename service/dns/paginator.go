@@ -0,0 +1,62 @@
+package dns
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/volcengine/volc-sdk-golang/base"
+)
+
+// ListRecordsPaginator pages through ListRecords lazily.
+type ListRecordsPaginator struct {
+	paginator *base.Paginator
+	page      []TopRecordResponse
+}
+
+// NewListRecordsPaginator pages with the PageSize in data, 100 when absent.
+func (c *Client) NewListRecordsPaginator(data *ListRecordsRequest) *ListRecordsPaginator {
+	req := *data
+	pageSize := 100
+	if req.PageSize != nil {
+		if size, err := strconv.Atoi(*req.PageSize); err == nil && size > 0 {
+			pageSize = size
+		}
+	}
+	p := &ListRecordsPaginator{}
+	p.paginator = base.NewPageNumberPaginator(pageSize, func(ctx context.Context, page, size int) (int, int, error) {
+		pageNumber, pageSize := strconv.Itoa(page), strconv.Itoa(size)
+		req.PageNumber, req.PageSize = &pageNumber, &pageSize
+		resp, err := c.ListRecords(ctx, &req)
+		if err != nil {
+			return 0, 0, err
+		}
+		p.page = resp.Records
+		total := -1
+		if resp.TotalCount != nil {
+			total = int(*resp.TotalCount)
+		}
+		return len(resp.Records), total, nil
+	})
+	return p
+}
+
+func (p *ListRecordsPaginator) HasMorePages() bool {
+	return p.paginator.HasMorePages()
+}
+
+func (p *ListRecordsPaginator) NextPage(ctx context.Context) ([]TopRecordResponse, error) {
+	if err := p.paginator.NextPage(ctx); err != nil {
+		return nil, err
+	}
+	return p.page, nil
+}
+
+// ListAllRecords collects up to max records, all of them when max <= 0.
+func (c *Client) ListAllRecords(ctx context.Context, data *ListRecordsRequest, max int) ([]TopRecordResponse, error) {
+	var all []TopRecordResponse
+	p := c.NewListRecordsPaginator(data)
+	err := p.paginator.Collect(ctx, max, func(keep int) {
+		all = append(all, p.page[:keep]...)
+	})
+	return all, err
+}
@@ -29,7 +29,7 @@ func NewInstanceWithRegion(region string) *Dts {
 }
 
 func (client *Dts) SetRegionAndHost(region, host string) *Dts {
-	client.ServiceInfo.Host = host
-	client.ServiceInfo.Credentials.Region = region
+	client.SetHost(host)
+	client.SetRegion(region)
 	return client
 }
@@ -36,7 +36,9 @@ func (p *GameProtector) SetRegion(region string) error {
 	if !ok {
 		return fmt.Errorf("region does not spport or unknown region")
 	}
-	p.ServiceInfo = serviceInfo
+	p.UpdateServiceInfo(func(info *base.ServiceInfo) {
+		*info = *serviceInfo
+	})
 	return nil
 }
 
@@ -39,17 +39,17 @@ type SDKClient struct {
 
 // GetServiceInfo interface
 func (p *SDKClient) GetServiceInfo() *base.ServiceInfo {
-	return p.Client.ServiceInfo
+	return p.Client.GetServiceInfo()
 }
 
 func (p *SDKClient) SetRegion(region string) {
-	p.Client.ServiceInfo.Credentials.Region = region
+	p.Client.SetRegion(region)
 }
 
 func (p *SDKClient) SetHost(host string) {
-	p.Client.ServiceInfo.Host = host
+	p.Client.SetHost(host)
 }
 
 func (p *SDKClient) SetSchema(schema string) {
-	p.Client.ServiceInfo.Scheme = schema
+	p.Client.SetScheme(schema)
 }
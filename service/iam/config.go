@@ -364,14 +364,16 @@ type IAM struct {
 func NewInstance() *IAM {
 	instance := &IAM{}
 	instance.Client = base.NewClient(ServiceInfo, ApiInfoList)
-	instance.Client.ServiceInfo.Credentials.Service = ServiceName
-	instance.Client.ServiceInfo.Credentials.Region = DefaultRegion
+	instance.Client.UpdateServiceInfo(func(info *base.ServiceInfo) {
+		info.Credentials.Service = ServiceName
+		info.Credentials.Region = DefaultRegion
+	})
 	return instance
 }
 
 // GetServiceInfo interface
 func (p *IAM) GetServiceInfo() *base.ServiceInfo {
-	return p.Client.ServiceInfo
+	return p.Client.GetServiceInfo()
 }
 
 // GetAPIInfo interface
@@ -384,15 +386,15 @@ func (p *IAM) GetAPIInfo(api string) *base.ApiInfo {
 
 // SetHost .
 func (p *IAM) SetRegion(region string) {
-	p.Client.ServiceInfo.Credentials.Region = region
+	p.Client.SetRegion(region)
 }
 
 // SetHost .
 func (p *IAM) SetHost(host string) {
-	p.Client.ServiceInfo.Host = host
+	p.Client.SetHost(host)
 }
 
 // SetSchema .
 func (p *IAM) SetSchema(schema string) {
-	p.Client.ServiceInfo.Scheme = schema
+	p.Client.SetScheme(schema)
 }
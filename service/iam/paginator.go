@@ -0,0 +1,61 @@
+package iam
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/volcengine/volc-sdk-golang/base"
+)
+
+// ListRolesPaginator pages through ListRoles by Offset/Limit lazily.
+type ListRolesPaginator struct {
+	paginator *base.Paginator
+	page      []*RoleStructure
+}
+
+// NewListRolesPaginator pages with the Limit in query, 100 when absent.
+func (p *IAM) NewListRolesPaginator(query url.Values) *ListRolesPaginator {
+	limit, _ := strconv.Atoi(query.Get("Limit"))
+	if limit <= 0 {
+		limit = 100
+	}
+	lp := &ListRolesPaginator{}
+	lp.paginator = base.NewOffsetPaginator(limit, func(ctx context.Context, offset, limit int) (int, int, error) {
+		q := base.CopyQuery(query)
+		q.Set("Offset", strconv.Itoa(offset))
+		q.Set("Limit", strconv.Itoa(limit))
+		resp, _, err := p.ListRolesCtx(ctx, q)
+		if err != nil {
+			return 0, 0, err
+		}
+		if resp.Result == nil {
+			lp.page = nil
+			return 0, 0, nil
+		}
+		lp.page = resp.Result.RoleMetadata
+		return len(lp.page), resp.Result.Total, nil
+	})
+	return lp
+}
+
+func (p *ListRolesPaginator) HasMorePages() bool {
+	return p.paginator.HasMorePages()
+}
+
+func (p *ListRolesPaginator) NextPage(ctx context.Context) ([]*RoleStructure, error) {
+	if err := p.paginator.NextPage(ctx); err != nil {
+		return nil, err
+	}
+	return p.page, nil
+}
+
+// ListAllRoles collects up to max roles, all of them when max <= 0.
+func (p *IAM) ListAllRoles(ctx context.Context, query url.Values, max int) ([]*RoleStructure, error) {
+	var all []*RoleStructure
+	lp := p.NewListRolesPaginator(query)
+	err := lp.paginator.Collect(ctx, max, func(keep int) {
+		all = append(all, lp.page[:keep]...)
+	})
+	return all, err
+}
@@ -1,13 +1,18 @@
 package iam
 
 import (
+	"context"
 	"encoding/json"
 	"net/url"
 )
 
 // helper func
 func (p *IAM) commonHandler(api string, query url.Values, resp interface{}) (int, error) {
-	respBody, statusCode, err := p.Client.Query(api, query)
+	return p.commonHandlerCtx(context.Background(), api, query, resp)
+}
+
+func (p *IAM) commonHandlerCtx(ctx context.Context, api string, query url.Values, resp interface{}) (int, error) {
+	respBody, statusCode, err := p.Client.CtxQuery(ctx, api, query)
 	if err != nil {
 		return statusCode, err
 	}
@@ -107,8 +112,12 @@ func (p *IAM) DeleteRole(query url.Values) (*NullResultResp, int, error) {
 }
 
 func (p *IAM) ListRoles(query url.Values) (*RoleListResp, int, error) {
+	return p.ListRolesCtx(context.Background(), query)
+}
+
+func (p *IAM) ListRolesCtx(ctx context.Context, query url.Values) (*RoleListResp, int, error) {
 	resp := new(RoleListResp)
-	statusCode, err := p.commonHandler("ListRoles", query, resp)
+	statusCode, err := p.commonHandlerCtx(ctx, "ListRoles", query, resp)
 	if err != nil {
 		return nil, statusCode, err
 	}
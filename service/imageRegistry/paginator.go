@@ -0,0 +1,55 @@
+package imageRegistry
+
+import (
+	"context"
+
+	"github.com/volcengine/volc-sdk-golang/base"
+	. "github.com/volcengine/volc-sdk-golang/service/imageRegistry/models"
+)
+
+// ListNamespacesBasicPaginator pages through ListNamespacesBasic lazily.
+type ListNamespacesBasicPaginator struct {
+	paginator *base.Paginator
+	page      []Namespace
+}
+
+// NewListNamespacesBasicPaginator pages with the PageSize in req, 100 when absent.
+func (p *ImageRegistry) NewListNamespacesBasicPaginator(req *ListNamespacesBasicRequest) *ListNamespacesBasicPaginator {
+	request := *req
+	pageSize := int(request.PageSize)
+	if pageSize <= 0 {
+		pageSize = 100
+	}
+	lp := &ListNamespacesBasicPaginator{}
+	lp.paginator = base.NewPageNumberPaginator(pageSize, func(ctx context.Context, page, size int) (int, int, error) {
+		request.PageNumber, request.PageSize = int64(page), int64(size)
+		resp, err := p.ListNamespacesBasicCtx(ctx, &request)
+		if err != nil {
+			return 0, 0, err
+		}
+		lp.page = resp.Items
+		return len(resp.Items), int(resp.Total), nil
+	})
+	return lp
+}
+
+func (p *ListNamespacesBasicPaginator) HasMorePages() bool {
+	return p.paginator.HasMorePages()
+}
+
+func (p *ListNamespacesBasicPaginator) NextPage(ctx context.Context) ([]Namespace, error) {
+	if err := p.paginator.NextPage(ctx); err != nil {
+		return nil, err
+	}
+	return p.page, nil
+}
+
+// ListAllNamespacesBasic collects up to max namespaces, all of them when max <= 0.
+func (p *ImageRegistry) ListAllNamespacesBasic(ctx context.Context, req *ListNamespacesBasicRequest, max int) ([]Namespace, error) {
+	var all []Namespace
+	lp := p.NewListNamespacesBasicPaginator(req)
+	err := lp.paginator.Collect(ctx, max, func(keep int) {
+		all = append(all, lp.page[:keep]...)
+	})
+	return all, err
+}
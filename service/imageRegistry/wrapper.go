@@ -1,6 +1,7 @@
 package imageRegistry
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -121,15 +122,19 @@ func (p *ImageRegistry) GetNamespaceBasic(req *GetNamespaceBasicRequest) (*GetNa
 }
 
 func (p *ImageRegistry) ListNamespacesBasic(req *ListNamespacesBasicRequest) (*ListNamespacesBasicResponse, error) {
+	return p.ListNamespacesBasicCtx(context.Background(), req)
+}
+
+func (p *ImageRegistry) ListNamespacesBasicCtx(ctx context.Context, req *ListNamespacesBasicRequest) (*ListNamespacesBasicResponse, error) {
 	reqData, err := json.Marshal(req)
 	if err != nil {
 		return nil, fmt.Errorf("ListNamespacesBasicRequest: fail to marshal request, %v", err)
 	}
 
-	respBody, _, err := p.Client.Json("ListNamespacesBasic", nil, string(reqData))
+	respBody, _, err := p.Client.CtxJson(ctx, "ListNamespacesBasic", nil, string(reqData))
 	if err != nil {
 		if p.Retry() {
-			respBody, _, err = p.Client.Json("ListNamespacesBasic", nil, string(reqData))
+			respBody, _, err = p.Client.CtxJson(ctx, "ListNamespacesBasic", nil, string(reqData))
 			if err != nil {
 				return nil, fmt.Errorf("ListNamespacesBasic: fail to do request, %v", err)
 			}
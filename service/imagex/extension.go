@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/url"
 
 	"github.com/volcengine/volc-sdk-golang/base"
@@ -31,7 +32,13 @@ func (c *ImageX) GetImageEnhanceWithData(param *GetImageEnhanceParam, data io.Re
 	}
 	fieldItem := base.CreateMultiPartItemFormField("Input", string(paramStr))
 	fileItem := base.CreateMultiPartItemFormFile("Data", "img", data)
-	resp, _, err := c.CtxMultiPart(context.Background(), "GetImageEnhanceResultWithData", query, []*base.MultiPartItem{fieldItem, fileItem})
+	// The image is streamed rather than buffered into the form.
+	rc, _, err := c.CtxMultiPartStream(context.Background(), "GetImageEnhanceResultWithData", query, []*base.MultiPartItem{fieldItem, fileItem})
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	resp, err := ioutil.ReadAll(rc)
 	if err != nil {
 		return nil, err
 	}
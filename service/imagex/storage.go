@@ -316,7 +316,7 @@ func (c *ImageX) UploadImages(params *ApplyUploadImageParam, images [][]byte) (*
 			ct = params.ContentTypes[idx]
 		}
 		err = retry.Do(func() error {
-			ctx, cancel := context.WithTimeout(context.Background(), c.ServiceInfo.Timeout)
+			ctx, cancel := context.WithTimeout(context.Background(), c.GetServiceInfo().Timeout)
 			defer cancel()
 			return c.directUpload(ctx, host, idx, uploadTaskSet, info, imageCopy, ct)
 		}, retry.Attempts(2))
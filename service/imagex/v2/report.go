@@ -273,8 +273,8 @@ func (p *Imagex) buildDefaultUploadReport(serviceId string, cost int64, statusCo
 		r.Tags = append(r.Tags, &tag{true, tagErrorMsg, errMsg})
 	}
 	if p != nil {
-		if p.ServiceInfo.Host != "" {
-			r.Tags = append(r.Tags, &tag{true, tagFromHost, p.ServiceInfo.Host})
+		if host := p.GetServiceInfo().Host; host != "" {
+			r.Tags = append(r.Tags, &tag{true, tagFromHost, host})
 		}
 	}
 	return r
@@ -325,7 +325,7 @@ func (c *Imagex) UploadImages(params *ApplyUploadImageParam, images [][]byte) (r
 			ct = params.ContentTypes[idx]
 		}
 		err = retry.Do(func() error {
-			ctx, cancel := context.WithTimeout(context.Background(), c.ServiceInfo.Timeout)
+			ctx, cancel := context.WithTimeout(context.Background(), c.GetServiceInfo().Timeout)
 			defer cancel()
 			return c.directUpload(ctx, host, idx, uploadTaskSet, info, imageCopy, ct)
 		}, retry.Attempts(2))
@@ -225,14 +225,16 @@ type KMS struct {
 func NewInstance() *KMS {
 	instance := &KMS{}
 	instance.Client = base.NewClient(ServiceInfo, ApiInfoList)
-	instance.Client.ServiceInfo.Credentials.Service = ServiceName
-	instance.Client.ServiceInfo.Credentials.Region = DefaultRegion
+	instance.Client.UpdateServiceInfo(func(info *base.ServiceInfo) {
+		info.Credentials.Service = ServiceName
+		info.Credentials.Region = DefaultRegion
+	})
 	return instance
 }
 
 // GetServiceInfo interface
 func (p *KMS) GetServiceInfo() *base.ServiceInfo {
-	return p.Client.ServiceInfo
+	return p.Client.GetServiceInfo()
 }
 
 // GetAPIInfo interface
@@ -245,15 +247,15 @@ func (p *KMS) GetAPIInfo(api string) *base.ApiInfo {
 
 // SetRegion .
 func (p *KMS) SetRegion(region string) {
-	p.Client.ServiceInfo.Credentials.Region = region
+	p.Client.SetRegion(region)
 }
 
 // SetHost .
 func (p *KMS) SetHost(host string) {
-	p.Client.ServiceInfo.Host = host
+	p.Client.SetHost(host)
 }
 
 // SetSchema .
 func (p *KMS) SetSchema(schema string) {
-	p.Client.ServiceInfo.Scheme = schema
+	p.Client.SetScheme(schema)
 }
@@ -7,8 +7,8 @@ import (
 	"errors"
 	"fmt"
 	"github.com/volcengine/volc-sdk-golang/base"
+	"github.com/volcengine/volc-sdk-golang/base/sse"
 	"github.com/volcengine/volc-sdk-golang/service/maas/models/api"
-	"github.com/volcengine/volc-sdk-golang/service/maas/sse"
 	"io"
 	"net/http"
 )
@@ -195,20 +195,25 @@ func (cli *MaaS) ChatImpl(ctx context.Context, body []byte) (*api.ChatResp, int,
 }
 
 func (cli *MaaS) StreamChatImpl(ctx context.Context, body []byte) (<-chan *api.ChatResp, error) {
+	info := cli.GetServiceInfo()
 	apiInfo := cli.ApiInfoList[APIStreamChat]
 	if apiInfo == nil {
 		return nil, api.NewClientSDKRequestError("the related api does not exist")
 	}
 
 	// build request
-	req, err := MakeRequest(apiInfo, "", cli.ServiceInfo, nil, "application/json")
+	req, err := MakeRequest(apiInfo, "", info, nil, "application/json")
 	if err != nil {
 		return nil, api.NewClientSDKRequestError(fmt.Sprintf("failed to make request: %v", err))
 	}
 	req.Body = io.NopCloser(bytes.NewReader(body))
-	timeout := GetTimeout(cli.ServiceInfo.Timeout, apiInfo.Timeout)
+	timeout := GetTimeout(info.Timeout, apiInfo.Timeout)
 
-	req = cli.ServiceInfo.Credentials.Sign(req)
+	cred, err := cli.Credentials()
+	if err != nil {
+		return nil, api.NewClientSDKRequestError(fmt.Sprintf("failed to get credentials: %v", err))
+	}
+	req = cred.Sign(req)
 
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	req = req.WithContext(ctx)
@@ -240,11 +245,11 @@ func (cli *MaaS) StreamChatImpl(ctx context.Context, body []byte) (<-chan *api.C
 			close(ch)
 		}()
 
-		stream := sse.NewEventStreamFromReader(resp.Body, MaxBufferSize)
+		stream := sse.NewDecoder(resp.Body, MaxBufferSize)
 		for {
 			event, err := stream.Next()
 			if err != nil {
-				if errors.Is(err, io.EOF) {
+				if errors.Is(err, io.EOF) || errors.Is(err, context.Canceled) {
 					return
 				}
 				if errors.Is(err, context.DeadlineExceeded) {
@@ -258,20 +263,18 @@ func (cli *MaaS) StreamChatImpl(ctx context.Context, body []byte) (<-chan *api.C
 				}
 				return
 			}
-			if event != nil {
-				if bytes.Equal(event.Data, []byte(Terminator)) {
-					return
-				}
+			if bytes.Equal(event.Data, []byte(Terminator)) {
+				return
+			}
 
-				item := &api.ChatResp{}
-				if err = json.Unmarshal(event.Data, item); err != nil {
-					ch <- &api.ChatResp{
-						Error: api.NewClientSDKRequestError(fmt.Sprintf("failed to unmarshal response(data=%s): %v", string(event.Data), err)),
-					}
-					return
+			item := &api.ChatResp{}
+			if err = json.Unmarshal(event.Data, item); err != nil {
+				ch <- &api.ChatResp{
+					Error: api.NewClientSDKRequestError(fmt.Sprintf("failed to unmarshal response(data=%s): %v", string(event.Data), err)),
 				}
-				ch <- item
+				return
 			}
+			ch <- item
 		}
 	}()
 
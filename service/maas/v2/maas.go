@@ -17,9 +17,9 @@ import (
 	"github.com/cenkalti/backoff/v4"
 
 	"github.com/volcengine/volc-sdk-golang/base"
+	"github.com/volcengine/volc-sdk-golang/base/sse"
 	"github.com/volcengine/volc-sdk-golang/service/maas"
 	"github.com/volcengine/volc-sdk-golang/service/maas/models/api/v2"
-	"github.com/volcengine/volc-sdk-golang/service/maas/sse"
 )
 
 // MaaS ... use base client
@@ -228,6 +228,7 @@ func (cli *MaaS) ChatImpl(ctx context.Context, endpointId string, body []byte) (
 }
 
 func (cli *MaaS) StreamChatImpl(ctx context.Context, endpointId string, body []byte) (<-chan *api.ChatResp, error) {
+	info := cli.GetServiceInfo()
 	ctx = getContext(ctx)
 
 	apiInfo := cli.ApiInfoList[maas.APIStreamChat]
@@ -236,17 +237,21 @@ func (cli *MaaS) StreamChatImpl(ctx context.Context, endpointId string, body []b
 	}
 
 	// build request
-	req, err := maas.MakeRequest(apiInfo, endpointId, cli.ServiceInfo, nil, "application/json")
+	req, err := maas.MakeRequest(apiInfo, endpointId, info, nil, "application/json")
 	if err != nil {
 		return nil, api.NewClientSDKRequestError(fmt.Sprintf("failed to make request: %v", err), reqIdFromCtx(ctx))
 	}
 	req.Header.Add(reqIdHeaderKey, reqIdFromCtx(ctx))
 	req.Body = ioutil.NopCloser(bytes.NewReader(body))
-	timeout := maas.GetTimeout(cli.ServiceInfo.Timeout, apiInfo.Timeout)
+	timeout := maas.GetTimeout(info.Timeout, apiInfo.Timeout)
 
 	apikey := cli.settedApikey
 	if apikey == "" {
-		req = cli.ServiceInfo.Credentials.Sign(req)
+		cred, err := cli.Credentials()
+		if err != nil {
+			return nil, api.NewClientSDKRequestError(fmt.Sprintf("failed to get credentials: %v", err), reqIdFromCtx(ctx))
+		}
+		req = cred.Sign(req)
 	} else if apikey != "" {
 		req.Header.Set(reqAuthorizationHeaderKey, "Bearer "+apikey)
 	}
@@ -281,11 +286,11 @@ func (cli *MaaS) StreamChatImpl(ctx context.Context, endpointId string, body []b
 			close(ch)
 		}()
 
-		stream := sse.NewEventStreamFromReader(resp.Body, maas.MaxBufferSize)
+		stream := sse.NewDecoder(resp.Body, maas.MaxBufferSize)
 		for {
 			event, err := stream.Next()
 			if err != nil {
-				if errors.Is(err, io.EOF) {
+				if errors.Is(err, io.EOF) || errors.Is(err, context.Canceled) {
 					return
 				}
 				if errors.Is(err, context.DeadlineExceeded) {
@@ -299,24 +304,22 @@ func (cli *MaaS) StreamChatImpl(ctx context.Context, endpointId string, body []b
 				}
 				return
 			}
-			if event != nil {
-				if bytes.Equal(event.Data, []byte(maas.Terminator)) {
-					return
-				}
+			if bytes.Equal(event.Data, []byte(maas.Terminator)) {
+				return
+			}
 
-				item := &api.ChatResp{}
-				if err = json.Unmarshal(event.Data, item); err != nil {
-					ch <- &api.ChatResp{
-						Error: api.NewClientSDKRequestError(fmt.Sprintf("failed to unmarshal response(data=%s): %v", string(event.Data), err), reqIdFromCtx(ctx)),
-					}
-					return
+			item := &api.ChatResp{}
+			if err = json.Unmarshal(event.Data, item); err != nil {
+				ch <- &api.ChatResp{
+					Error: api.NewClientSDKRequestError(fmt.Sprintf("failed to unmarshal response(data=%s): %v", string(event.Data), err), reqIdFromCtx(ctx)),
 				}
-				item.ReqId = reqIdFromCtx(ctx)
-				if item.Error != nil {
-					item.Error.ReqId = reqIdFromCtx(ctx)
-				}
-				ch <- item
+				return
 			}
+			item.ReqId = reqIdFromCtx(ctx)
+			if item.Error != nil {
+				item.Error.ReqId = reqIdFromCtx(ctx)
+			}
+			ch <- item
 		}
 	}()
 
@@ -480,7 +483,12 @@ func (cli *MaaS) embeddingsImpl(ctx context.Context, endpointId string, body []b
 func (cli *MaaS) doRequest(inputContext context.Context, api string, req *http.Request, timeout time.Duration, authApikey string) (*http.Response, int, bool, error, context.CancelFunc) {
 
 	if authApikey == "" {
-		req = cli.ServiceInfo.Credentials.Sign(req)
+		cred, err := cli.Credentials()
+		if err != nil {
+			// credentials which cannot be retrieved are not retried.
+			return nil, 500, false, err, func() {}
+		}
+		req = cred.Sign(req)
 	} else if authApikey != "" {
 		req.Header.Set(reqAuthorizationHeaderKey, "Bearer "+authApikey)
 	}
@@ -515,20 +523,21 @@ func (cli *MaaS) doRequest(inputContext context.Context, api string, req *http.R
 }
 
 func (cli *MaaS) request(ctx context.Context, apiKey string, query url.Values, endpointId string, requestBodyBytes []byte, authApikey string) ([]byte, int, error) {
+	info := cli.GetServiceInfo()
 	apiInfo := cli.ApiInfoList[apiKey]
 	if apiInfo == nil {
 		return nil, 500, api.NewClientSDKRequestError("the related api does not exist", reqIdFromCtx(ctx))
 	}
 
 	// build request
-	req, err := maas.MakeRequest(apiInfo, endpointId, cli.ServiceInfo, query, "application/json")
+	req, err := maas.MakeRequest(apiInfo, endpointId, info, query, "application/json")
 	if err != nil {
 		return nil, 500, api.NewClientSDKRequestError(fmt.Sprintf("failed to make request: %v", err), reqIdFromCtx(ctx))
 	}
 	req.Header.Add(reqIdHeaderKey, reqIdFromCtx(ctx))
 	requestBody := bytes.NewReader(requestBodyBytes)
-	timeout := maas.GetTimeout(cli.ServiceInfo.Timeout, apiInfo.Timeout)
-	retrySettings := maas.GetRetrySetting(&cli.ServiceInfo.Retry, &apiInfo.Retry)
+	timeout := maas.GetTimeout(info.Timeout, apiInfo.Timeout)
+	retrySettings := maas.GetRetrySetting(&info.Retry, &apiInfo.Retry)
 
 	var body []byte
 	var resp *http.Response
@@ -579,6 +588,7 @@ func (cli *MaaS) request(ctx context.Context, apiKey string, query url.Values, e
 }
 
 func (cli *MaaS) streamRequest(ctx context.Context, apiKey string, query url.Values, endpointId string, requestBodyBytes []byte, authApikey string) (io.ReadCloser, int, error, context.CancelFunc) {
+	info := cli.GetServiceInfo()
 	cancel := func() {}
 	apiInfo := cli.ApiInfoList[apiKey]
 	if apiInfo == nil {
@@ -586,14 +596,14 @@ func (cli *MaaS) streamRequest(ctx context.Context, apiKey string, query url.Val
 	}
 
 	// build request
-	req, err := maas.MakeRequest(apiInfo, endpointId, cli.ServiceInfo, query, "application/json")
+	req, err := maas.MakeRequest(apiInfo, endpointId, info, query, "application/json")
 	if err != nil {
 		return nil, 500, api.NewClientSDKRequestError(fmt.Sprintf("failed to make request: %v", err), reqIdFromCtx(ctx)), nil
 	}
 	req.Header.Add(reqIdHeaderKey, reqIdFromCtx(ctx))
 	requestBody := bytes.NewReader(requestBodyBytes)
-	timeout := maas.GetTimeout(cli.ServiceInfo.Timeout, apiInfo.Timeout)
-	retrySettings := maas.GetRetrySetting(&cli.ServiceInfo.Retry, &apiInfo.Retry)
+	timeout := maas.GetTimeout(info.Timeout, apiInfo.Timeout)
+	retrySettings := maas.GetRetrySetting(&info.Retry, &apiInfo.Retry)
 
 	var body io.ReadCloser
 	var resp *http.Response
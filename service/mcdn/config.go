@@ -154,8 +154,10 @@ func NewInstance() *MCDN {
 	instance := &MCDN{
 		Client: base.NewClient(serviceInfo[DefaultRegion], apiInfo),
 	}
-	instance.Client.ServiceInfo.Credentials.Service = ServiceName
-	instance.Client.ServiceInfo.Credentials.Region = DefaultRegion
+	instance.Client.UpdateServiceInfo(func(info *base.ServiceInfo) {
+		info.Credentials.Service = ServiceName
+		info.Credentials.Region = DefaultRegion
+	})
 	return instance
 }
 
@@ -175,15 +177,15 @@ func (s *MCDN) GetAPIInfo(api string) *base.ApiInfo {
 
 // SetHost .
 func (s *MCDN) SetRegion(region string) {
-	s.Client.ServiceInfo.Credentials.Region = region
+	s.Client.SetRegion(region)
 }
 
 // SetHost .
 func (s *MCDN) SetHost(host string) {
-	s.Client.ServiceInfo.Host = host
+	s.Client.SetHost(host)
 }
 
 // SetSchema .
 func (s *MCDN) SetSchema(schema string) {
-	s.Client.ServiceInfo.Scheme = schema
+	s.Client.SetScheme(schema)
 }
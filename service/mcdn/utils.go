@@ -91,7 +91,11 @@ func mergeHeaders(headers ...http.Header) (res http.Header) {
 }
 
 func (s *MCDN) makeRequest(api string, req *http.Request, timeout time.Duration) ([]byte, int, error) {
-	req = s.Client.ServiceInfo.Credentials.Sign(req)
+	cred, err := s.Client.Credentials()
+	if err != nil {
+		return []byte(""), 500, err
+	}
+	req = cred.Sign(req)
 
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
@@ -122,6 +126,7 @@ func (s *MCDN) doRequest(apiName string, requestBody interface{}, responseBody i
 		requestBody = struct{}{}
 	}
 	client := s.Client
+	info := client.GetServiceInfo()
 	apiInfo := client.ApiInfoList[apiName]
 	body, err = json.Marshal(requestBody)
 	if err != nil {
@@ -129,13 +134,13 @@ func (s *MCDN) doRequest(apiName string, requestBody interface{}, responseBody i
 		return
 	}
 	query := url.Values{}
-	timeout := getTimeout(client.ServiceInfo.Timeout, apiInfo.Timeout)
-	header := mergeHeaders(client.ServiceInfo.Header, apiInfo.Header)
+	timeout := getTimeout(info.Timeout, apiInfo.Timeout)
+	header := mergeHeaders(info.Header, apiInfo.Header)
 	query = mergeQuery(query, apiInfo.Query)
 
 	u := url.URL{
-		Scheme:   client.ServiceInfo.Scheme,
-		Host:     client.ServiceInfo.Host,
+		Scheme:   info.Scheme,
+		Host:     info.Host,
 		Path:     apiInfo.Path,
 		RawQuery: query.Encode(),
 	}
@@ -90,6 +90,8 @@ func NewInstance() *Nlp {
 	instance := &Nlp{
 		Client: base.NewClient(ServiceInfoMap[base.RegionCnNorth1], ApiInfoList),
 	}
-	instance.Client.ServiceInfo.Credentials.Service = ServiceName
+	instance.Client.UpdateServiceInfo(func(info *base.ServiceInfo) {
+		info.Credentials.Service = ServiceName
+	})
 	return instance
 }
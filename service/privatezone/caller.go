@@ -24,23 +24,23 @@ type VolcCaller struct {
 
 // GetServiceInfo interface
 func (p *VolcCaller) GetServiceInfo() *base.ServiceInfo {
-	return p.Volc.ServiceInfo
+	return p.Volc.GetServiceInfo()
 }
 
 func (p *VolcCaller) SetService(service string) {
-	p.Volc.ServiceInfo.Credentials.Service = service
+	p.Volc.SetCredential(base.Credentials{Service: service})
 }
 
 func (p *VolcCaller) SetRegion(region string) {
-	p.Volc.ServiceInfo.Credentials.Region = region
+	p.Volc.SetRegion(region)
 }
 
 func (p *VolcCaller) SetHost(host string) {
-	p.Volc.ServiceInfo.Host = host
+	p.Volc.SetHost(host)
 }
 
 func (p *VolcCaller) SetSchema(schema string) {
-	p.Volc.ServiceInfo.Scheme = schema
+	p.Volc.SetScheme(schema)
 }
 
 func NewVolcCaller() *VolcCaller {
@@ -56,18 +56,23 @@ func InitDNSVolcClient() *Client {
 }
 
 func (c *VolcCaller) Do(r *http.Request) (*http.Response, error) {
-	r.URL.Host = c.Volc.ServiceInfo.Host
-	r.URL.Scheme = c.Volc.ServiceInfo.Scheme
-	r.Host = c.Volc.ServiceInfo.Host
+	info := c.Volc.GetServiceInfo()
+	r.URL.Host = info.Host
+	r.URL.Scheme = info.Scheme
+	r.Host = info.Host
 
 	r.Header.Add("Content-Type", "application/json")
 	q := r.URL.Query()
 	q.Add("Version", ServiceVersion)
 	r.URL.RawQuery = q.Encode()
 
-	r = c.Volc.ServiceInfo.Credentials.Sign(r)
+	cred, err := c.Volc.Credentials()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	r = cred.Sign(r)
 
-	ctx, cancel := context.WithTimeout(r.Context(), c.Volc.ServiceInfo.Timeout)
+	ctx, cancel := context.WithTimeout(r.Context(), info.Timeout)
 	defer cancel()
 	r = r.WithContext(ctx)
 
@@ -204,8 +204,10 @@ type SMS struct {
 func NewInstance() *SMS {
 	instance := &SMS{}
 	instance.Client = base.NewClient(ServiceInfo[DefaultRegion], ApiInfoList)
-	instance.Client.ServiceInfo.Credentials.Service = ServiceName
-	instance.Client.ServiceInfo.Credentials.Region = DefaultRegion
+	instance.Client.UpdateServiceInfo(func(info *base.ServiceInfo) {
+		info.Credentials.Service = ServiceName
+		info.Credentials.Region = DefaultRegion
+	})
 	return instance
 }
 
@@ -228,19 +230,21 @@ func (p *SMS) GetAPIInfo(api string) *base.ApiInfo {
 // SetHost .
 func (s *SMS) SetRegion(region string) {
 	if serviceInfo := s.GetServiceInfo(region); serviceInfo != nil {
-		si := serviceInfo.Clone()
-		si.Credentials = s.Client.ServiceInfo.Credentials.Clone()
-		si.Credentials.Region = region
-		s.Client.ServiceInfo = si
+		s.Client.UpdateServiceInfo(func(info *base.ServiceInfo) {
+			credentials := info.Credentials
+			*info = *serviceInfo.Clone()
+			info.Credentials = credentials
+			info.Credentials.Region = region
+		})
 	}
 }
 
 // SetHost .
 func (s *SMS) SetHost(host string) {
-	s.Client.ServiceInfo.Host = host
+	s.Client.SetHost(host)
 }
 
 // SetSchema .
 func (s *SMS) SetSchema(schema string) {
-	s.Client.ServiceInfo.Scheme = schema
+	s.Client.SetScheme(schema)
 }
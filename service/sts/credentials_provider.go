@@ -0,0 +1,171 @@
+package sts
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/volcengine/volc-sdk-golang/base"
+)
+
+const (
+	// DefaultExpiryWindow is how long before ExpiredTime the provider refreshes.
+	DefaultExpiryWindow = 5 * time.Minute
+	// DefaultExpiryJitter spreads refreshes of many processes sharing one role.
+	DefaultExpiryJitter = 1 * time.Minute
+	// DefaultRefreshBackoff is how long the provider waits after a failed
+	// AssumeRole before calling it again.
+	DefaultRefreshBackoff = 10 * time.Second
+)
+
+// AssumeRoleProvider is a base.CredentialsProvider backed by AssumeRole. The
+// temporary credentials are cached and renewed ahead of their ExpiredTime, so
+// one provider can be shared by any number of clients:
+//
+//	provider := sts.NewAssumeRoleProvider(sts.DefaultInstance, &sts.AssumeRoleRequest{...})
+//	iam.DefaultInstance.Client.SetCredentialsProvider(provider)
+//	tlsClient.SetCredentialsProvider(provider)
+type AssumeRoleProvider struct {
+	STS     *STS
+	Request AssumeRoleRequest
+
+	// ExpiryWindow and ExpiryJitter decide when cached credentials are renewed:
+	// between ExpiryWindow and ExpiryWindow+ExpiryJitter before they expire.
+	ExpiryWindow time.Duration
+	ExpiryJitter time.Duration
+	// RefreshBackoff is how long a failed AssumeRole is not retried, capped
+	// at the expiry of the cached credentials.
+	RefreshBackoff time.Duration
+
+	lock      sync.Mutex
+	cached    base.Credentials
+	expiredAt time.Time
+	refreshAt time.Time
+	// err is the error of the last refresh, nil after a successful one.
+	err error
+	// refreshing is closed when the AssumeRole call in flight returns, nil
+	// when there is none.
+	refreshing chan struct{}
+
+	now func() time.Time
+}
+
+func NewAssumeRoleProvider(instance *STS, req *AssumeRoleRequest) *AssumeRoleProvider {
+	if instance == nil {
+		instance = DefaultInstance
+	}
+	return &AssumeRoleProvider{
+		STS:            instance,
+		Request:        *req,
+		ExpiryWindow:   DefaultExpiryWindow,
+		ExpiryJitter:   DefaultExpiryJitter,
+		RefreshBackoff: DefaultRefreshBackoff,
+		now:            time.Now,
+	}
+}
+
+// Retrieve returns the cached credentials. When they are due for refresh
+// AssumeRole is called in the background and the cached credentials are
+// returned meanwhile; callers only wait for it when there are no valid
+// credentials. A single call is in flight at a time, and after a failure it
+// is not retried for RefreshBackoff.
+func (p *AssumeRoleProvider) Retrieve() (base.Credentials, error) {
+	p.lock.Lock()
+	now := p.now()
+	valid := now.Before(p.expiredAt)
+	if now.Before(p.refreshAt) && (valid || p.err != nil) {
+		defer p.lock.Unlock()
+		if valid {
+			return p.cached, nil
+		}
+		return base.Credentials{}, p.err
+	}
+
+	if p.refreshing == nil {
+		p.refreshing = make(chan struct{})
+		go p.refresh(p.refreshing)
+	}
+	if valid {
+		defer p.lock.Unlock()
+		return p.cached, nil
+	}
+	refreshing := p.refreshing
+	p.lock.Unlock()
+
+	<-refreshing
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.err != nil {
+		return base.Credentials{}, p.err
+	}
+	return p.cached, nil
+}
+
+// Expiration reports when the cached credentials expire, zero before the
+// first successful Retrieve.
+func (p *AssumeRoleProvider) Expiration() time.Time {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.expiredAt
+}
+
+// refresh calls AssumeRole and stores its credentials, or the error and
+// when to retry, then closes done.
+func (p *AssumeRoleProvider) refresh(done chan struct{}) {
+	cred, expiredAt, err := p.assumeRole()
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	defer close(done)
+	p.refreshing = nil
+
+	now := p.now()
+	p.err = err
+	if err != nil {
+		p.refreshAt = now.Add(p.RefreshBackoff)
+		if now.Before(p.expiredAt) && p.expiredAt.Before(p.refreshAt) {
+			p.refreshAt = p.expiredAt
+		}
+		return
+	}
+
+	refreshAt := expiredAt.Add(-p.ExpiryWindow)
+	if p.ExpiryJitter > 0 {
+		refreshAt = refreshAt.Add(-time.Duration(rand.Int63n(int64(p.ExpiryJitter))))
+	}
+	// Very short sessions would otherwise be refreshed on every call.
+	if half := now.Add(expiredAt.Sub(now) / 2); refreshAt.Before(half) {
+		refreshAt = half
+	}
+
+	p.cached = cred
+	p.expiredAt = expiredAt
+	p.refreshAt = refreshAt
+}
+
+func (p *AssumeRoleProvider) assumeRole() (base.Credentials, time.Time, error) {
+	resp, _, err := p.STS.AssumeRole(&p.Request)
+	if err != nil {
+		return base.Credentials{}, time.Time{}, err
+	}
+	if resp.ResponseMetadata.Error != nil && resp.ResponseMetadata.Error.Code != "" {
+		return base.Credentials{}, time.Time{}, fmt.Errorf("request %s error %s", resp.ResponseMetadata.RequestId, resp.ResponseMetadata.Error.Message)
+	}
+	if resp.Result == nil || resp.Result.Credentials == nil {
+		return base.Credentials{}, time.Time{}, errors.New("empty credentials in AssumeRole response")
+	}
+
+	cred := resp.Result.Credentials
+	expiredAt, err := time.Parse(time.RFC3339, cred.ExpiredTime)
+	if err != nil {
+		return base.Credentials{}, time.Time{}, fmt.Errorf("invalid ExpiredTime %q, %v", cred.ExpiredTime, err)
+	}
+
+	return base.Credentials{
+		AccessKeyID:     cred.AccessKeyId,
+		SecretAccessKey: cred.SecretAccessKey,
+		SessionToken:    cred.SessionToken,
+	}, expiredAt, nil
+}
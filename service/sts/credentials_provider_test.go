@@ -0,0 +1,243 @@
+package sts
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/volcengine/volc-sdk-golang/base"
+	"github.com/volcengine/volc-sdk-golang/service/tls"
+)
+
+// newFakeSTS serves AssumeRole with credentials numbered by call order.
+func newFakeSTS(t *testing.T, ttl time.Duration, calls *int32) *STS {
+	return newFlakySTS(t, ttl, calls, new(int32))
+}
+
+// newFlakySTS is newFakeSTS failing AssumeRole while *failing is not 0.
+func newFlakySTS(t *testing.T, ttl time.Duration, calls *int32, failing *int32) *STS {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("Action") != "AssumeRole" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if atomic.LoadInt32(failing) != 0 {
+			atomic.AddInt32(calls, 1)
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"ResponseMetadata":{"Error":{"Code":"AccessDenied","Message":"outage"}}}`))
+			return
+		}
+		n := atomic.AddInt32(calls, 1)
+		now := time.Now()
+		json.NewEncoder(w).Encode(&AssumeRoleResp{
+			ResponseMetadata: base.ResponseMetadata{RequestId: fmt.Sprint(n)},
+			Result: &AssumeRoleResult{Credentials: &Credentials{
+				CurrentTime:     now.Format(time.RFC3339),
+				ExpiredTime:     now.Add(ttl).Format(time.RFC3339),
+				AccessKeyId:     fmt.Sprintf("AKTP-%d", n),
+				SecretAccessKey: "secret",
+				SessionToken:    fmt.Sprintf("token-%d", n),
+			}},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	u, _ := url.Parse(server.URL)
+	instance := NewInstance()
+	instance.SetHost(u.Host)
+	instance.SetSchema(u.Scheme)
+	instance.Client.SetAccessKey(testAk)
+	instance.Client.SetSecretKey(testSk)
+	return instance
+}
+
+// fakeClock is the clock of a provider, which reads it from its refreshes.
+type fakeClock struct {
+	lock sync.Mutex
+	now  time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Add(d time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// waitRefreshed waits for the AssumeRole call in flight to return.
+func waitRefreshed(t *testing.T, provider *AssumeRoleProvider) {
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		provider.lock.Lock()
+		refreshing := provider.refreshing
+		provider.lock.Unlock()
+		if refreshing == nil {
+			return
+		}
+	}
+	t.Fatal("AssumeRole still in flight")
+}
+
+func TestAssumeRoleProvider_Cache(t *testing.T) {
+	var calls int32
+	provider := NewAssumeRoleProvider(newFakeSTS(t, time.Hour, &calls), &AssumeRoleRequest{
+		RoleTrn:         "trn:iam::2100000000:role/test",
+		RoleSessionName: "test",
+	})
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cred, err := provider.Retrieve()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if cred.AccessKeyID != "AKTP-1" || cred.SessionToken != "token-1" {
+				t.Errorf("unexpected credentials %+v", cred)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls != 1 {
+		t.Fatalf("expect a single AssumeRole call, got %d", calls)
+	}
+	if provider.Expiration().IsZero() {
+		t.Fatal("expect expiration to be set")
+	}
+}
+
+func TestAssumeRoleProvider_Refresh(t *testing.T) {
+	var calls int32
+	provider := NewAssumeRoleProvider(newFakeSTS(t, time.Hour, &calls), &AssumeRoleRequest{RoleTrn: "trn", RoleSessionName: "test"})
+	clock := &fakeClock{now: time.Now()}
+	provider.now = clock.Now
+
+	if cred, _ := provider.Retrieve(); cred.AccessKeyID != "AKTP-1" {
+		t.Fatalf("unexpected credentials %+v", cred)
+	}
+
+	clock.Add(time.Hour - DefaultExpiryWindow - DefaultExpiryJitter - time.Second)
+	if cred, _ := provider.Retrieve(); cred.AccessKeyID != "AKTP-1" {
+		t.Fatalf("expect cached credentials, got %+v", cred)
+	}
+
+	// Due credentials are still served while they are refreshed.
+	clock.Add(DefaultExpiryJitter + 2*time.Second)
+	if cred, _ := provider.Retrieve(); cred.AccessKeyID != "AKTP-1" {
+		t.Fatalf("expect cached credentials during the refresh, got %+v", cred)
+	}
+	waitRefreshed(t, provider)
+	if cred, _ := provider.Retrieve(); cred.AccessKeyID != "AKTP-2" {
+		t.Fatalf("expect refreshed credentials, got %+v", cred)
+	}
+}
+
+func TestAssumeRoleProvider_RefreshFailure(t *testing.T) {
+	var calls, failing int32
+	provider := NewAssumeRoleProvider(newFlakySTS(t, time.Hour, &calls, &failing), &AssumeRoleRequest{RoleTrn: "trn", RoleSessionName: "test"})
+	clock := &fakeClock{now: time.Now()}
+	provider.now = clock.Now
+
+	// Without credentials the callers get the error, AssumeRole is not
+	// called again before the backoff.
+	atomic.StoreInt32(&failing, 1)
+	for i := 0; i < 10; i++ {
+		if _, err := provider.Retrieve(); err == nil {
+			t.Fatal("expect an error without credentials")
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expect 1 AssumeRole call during the backoff, got %d", calls)
+	}
+
+	atomic.StoreInt32(&failing, 0)
+	clock.Add(DefaultRefreshBackoff)
+	if cred, err := provider.Retrieve(); err != nil || cred.AccessKeyID != "AKTP-2" {
+		t.Fatalf("unexpected credentials %+v, error %v", cred, err)
+	}
+
+	// Due credentials keep being served while AssumeRole fails, which is
+	// called once per backoff.
+	atomic.StoreInt32(&failing, 1)
+	clock.Add(time.Hour - DefaultExpiryWindow)
+	for backoff := 0; backoff < 3; backoff++ {
+		for i := 0; i < 10; i++ {
+			if cred, err := provider.Retrieve(); err != nil || cred.AccessKeyID != "AKTP-2" {
+				t.Fatalf("expect cached credentials, got %+v, error %v", cred, err)
+			}
+			waitRefreshed(t, provider)
+		}
+		clock.Add(DefaultRefreshBackoff)
+	}
+	if calls != 5 {
+		t.Fatalf("expect 1 AssumeRole call per backoff, got %d calls in total", calls)
+	}
+
+	// Past their expiry the error is returned.
+	clock.Add(DefaultExpiryWindow)
+	if _, err := provider.Retrieve(); err == nil {
+		t.Fatal("expect an error once the credentials expired")
+	}
+}
+
+func TestAssumeRoleProvider_Clients(t *testing.T) {
+	var calls int32
+	provider := NewAssumeRoleProvider(newFakeSTS(t, time.Hour, &calls), &AssumeRoleRequest{RoleTrn: "trn", RoleSessionName: "test"})
+
+	var lock sync.Mutex
+	var auths, tokens []string
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		auths = append(auths, r.Header.Get("Authorization"))
+		tokens = append(tokens, r.Header.Get("X-Security-Token"))
+		lock.Unlock()
+		w.Header().Set("X-Tls-Requestid", "test")
+		w.Write([]byte(`{}`))
+	}))
+	defer target.Close()
+	u, _ := url.Parse(target.URL)
+
+	client := base.NewClient(&base.ServiceInfo{
+		Timeout:     5 * time.Second,
+		Scheme:      u.Scheme,
+		Host:        u.Host,
+		Credentials: base.Credentials{Region: DefaultRegion, Service: "iam"},
+	}, map[string]*base.ApiInfo{"ListUsers": {Method: http.MethodGet, Path: "/"}})
+	client.SetCredentialsProvider(provider)
+	if _, _, err := client.Query("ListUsers", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tlsClient := tls.NewClient(target.URL, "", "", "", DefaultRegion)
+	tlsClient.SetCredentialsProvider(provider)
+	if _, err := tlsClient.DescribeProjects(&tls.DescribeProjectsRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(auths) != 2 {
+		t.Fatalf("expect 2 requests, got %d", len(auths))
+	}
+	for i := range auths {
+		if !strings.Contains(auths[i], "Credential=AKTP-1/") || tokens[i] != "token-1" {
+			t.Fatalf("request %d not signed with assumed role: %s %s", i, auths[i], tokens[i])
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expect credentials shared between clients, got %d AssumeRole calls", calls)
+	}
+}
@@ -1,6 +1,7 @@
 package tls
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
@@ -8,6 +9,10 @@ import (
 )
 
 func (c *LsClient) CreateAlarm(request *CreateAlarmRequest) (r *CreateAlarmResponse, e error) {
+	return c.CreateAlarmCtx(context.Background(), request)
+}
+
+func (c *LsClient) CreateAlarmCtx(ctx context.Context, request *CreateAlarmRequest) (r *CreateAlarmResponse, e error) {
 	reqHeaders := map[string]string{
 		"Content-Type": "application/json",
 	}
@@ -17,7 +22,7 @@ func (c *LsClient) CreateAlarm(request *CreateAlarmRequest) (r *CreateAlarmRespo
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPost, PathCreateAlarm, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPost, PathCreateAlarm, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -39,6 +44,10 @@ func (c *LsClient) CreateAlarm(request *CreateAlarmRequest) (r *CreateAlarmRespo
 }
 
 func (c *LsClient) DeleteAlarm(request *DeleteAlarmRequest) (r *CommonResponse, e error) {
+	return c.DeleteAlarmCtx(context.Background(), request)
+}
+
+func (c *LsClient) DeleteAlarmCtx(ctx context.Context, request *DeleteAlarmRequest) (r *CommonResponse, e error) {
 	reqHeaders := map[string]string{
 		"Content-Type": "application/json",
 	}
@@ -48,7 +57,7 @@ func (c *LsClient) DeleteAlarm(request *DeleteAlarmRequest) (r *CommonResponse,
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodDelete, PathDeleteAlarm, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodDelete, PathDeleteAlarm, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -70,6 +79,10 @@ func (c *LsClient) DeleteAlarm(request *DeleteAlarmRequest) (r *CommonResponse,
 }
 
 func (c *LsClient) ModifyAlarm(request *ModifyAlarmRequest) (r *CommonResponse, e error) {
+	return c.ModifyAlarmCtx(context.Background(), request)
+}
+
+func (c *LsClient) ModifyAlarmCtx(ctx context.Context, request *ModifyAlarmRequest) (r *CommonResponse, e error) {
 	reqHeaders := map[string]string{
 		"Content-Type": "application/json",
 	}
@@ -79,7 +92,7 @@ func (c *LsClient) ModifyAlarm(request *ModifyAlarmRequest) (r *CommonResponse,
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPut, PathModifyAlarm, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPut, PathModifyAlarm, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -101,6 +114,10 @@ func (c *LsClient) ModifyAlarm(request *ModifyAlarmRequest) (r *CommonResponse,
 }
 
 func (c *LsClient) DescribeAlarms(request *DescribeAlarmsRequest) (r *DescribeAlarmsResponse, e error) {
+	return c.DescribeAlarmsCtx(context.Background(), request)
+}
+
+func (c *LsClient) DescribeAlarmsCtx(ctx context.Context, request *DescribeAlarmsRequest) (r *DescribeAlarmsResponse, e error) {
 	reqHeaders := map[string]string{
 		"Content-Type": "application/json",
 	}
@@ -140,7 +157,7 @@ func (c *LsClient) DescribeAlarms(request *DescribeAlarmsRequest) (r *DescribeAl
 	body := map[string]string{}
 	bytesBody, err := json.Marshal(body)
 
-	rawResponse, err := c.Request(http.MethodGet, PathDescribeAlarms, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodGet, PathDescribeAlarms, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -162,6 +179,10 @@ func (c *LsClient) DescribeAlarms(request *DescribeAlarmsRequest) (r *DescribeAl
 }
 
 func (c *LsClient) CreateAlarmNotifyGroup(request *CreateAlarmNotifyGroupRequest) (r *CreateAlarmNotifyGroupResponse, e error) {
+	return c.CreateAlarmNotifyGroupCtx(context.Background(), request)
+}
+
+func (c *LsClient) CreateAlarmNotifyGroupCtx(ctx context.Context, request *CreateAlarmNotifyGroupRequest) (r *CreateAlarmNotifyGroupResponse, e error) {
 	reqHeaders := map[string]string{
 		"Content-Type": "application/json",
 	}
@@ -171,7 +192,7 @@ func (c *LsClient) CreateAlarmNotifyGroup(request *CreateAlarmNotifyGroupRequest
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPost, PathCreateAlarmNotifyGroup, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPost, PathCreateAlarmNotifyGroup, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -193,6 +214,10 @@ func (c *LsClient) CreateAlarmNotifyGroup(request *CreateAlarmNotifyGroupRequest
 }
 
 func (c *LsClient) DeleteAlarmNotifyGroup(request *DeleteAlarmNotifyGroupRequest) (r *CommonResponse, e error) {
+	return c.DeleteAlarmNotifyGroupCtx(context.Background(), request)
+}
+
+func (c *LsClient) DeleteAlarmNotifyGroupCtx(ctx context.Context, request *DeleteAlarmNotifyGroupRequest) (r *CommonResponse, e error) {
 	reqHeaders := map[string]string{
 		"Content-Type": "application/json",
 	}
@@ -202,7 +227,7 @@ func (c *LsClient) DeleteAlarmNotifyGroup(request *DeleteAlarmNotifyGroupRequest
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodDelete, PathDeleteAlarmNotifyGroup, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodDelete, PathDeleteAlarmNotifyGroup, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -224,6 +249,10 @@ func (c *LsClient) DeleteAlarmNotifyGroup(request *DeleteAlarmNotifyGroupRequest
 }
 
 func (c *LsClient) ModifyAlarmNotifyGroup(request *ModifyAlarmNotifyGroupRequest) (r *CommonResponse, e error) {
+	return c.ModifyAlarmNotifyGroupCtx(context.Background(), request)
+}
+
+func (c *LsClient) ModifyAlarmNotifyGroupCtx(ctx context.Context, request *ModifyAlarmNotifyGroupRequest) (r *CommonResponse, e error) {
 	reqHeaders := map[string]string{
 		"Content-Type": "application/json",
 	}
@@ -233,7 +262,7 @@ func (c *LsClient) ModifyAlarmNotifyGroup(request *ModifyAlarmNotifyGroupRequest
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPut, PathModifyAlarmNotifyGroup, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPut, PathModifyAlarmNotifyGroup, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -255,6 +284,10 @@ func (c *LsClient) ModifyAlarmNotifyGroup(request *ModifyAlarmNotifyGroupRequest
 }
 
 func (c *LsClient) DescribeAlarmNotifyGroups(request *DescribeAlarmNotifyGroupsRequest) (r *DescribeAlarmNotifyGroupsResponse, e error) {
+	return c.DescribeAlarmNotifyGroupsCtx(context.Background(), request)
+}
+
+func (c *LsClient) DescribeAlarmNotifyGroupsCtx(ctx context.Context, request *DescribeAlarmNotifyGroupsRequest) (r *DescribeAlarmNotifyGroupsResponse, e error) {
 	reqHeaders := map[string]string{
 		"Content-Type": "application/json",
 	}
@@ -288,7 +321,7 @@ func (c *LsClient) DescribeAlarmNotifyGroups(request *DescribeAlarmNotifyGroupsR
 	body := map[string]string{}
 	bytesBody, err := json.Marshal(body)
 
-	rawResponse, err := c.Request(http.MethodGet, PathDescribeAlarmNotifyGroups, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodGet, PathDescribeAlarmNotifyGroups, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 
 	if err != nil {
 		return nil, err
@@ -1,6 +1,7 @@
 package tls
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"io/ioutil"
@@ -9,6 +10,10 @@ import (
 )
 
 func (c *LsClient) DescribeCheckPoint(request *DescribeCheckPointRequest) (*DescribeCheckPointResponse, error) {
+	return c.DescribeCheckPointCtx(context.Background(), request)
+}
+
+func (c *LsClient) DescribeCheckPointCtx(ctx context.Context, request *DescribeCheckPointRequest) (*DescribeCheckPointResponse, error) {
 	reqHeaders := map[string]string{
 		"Content-Type": "application/json",
 	}
@@ -27,7 +32,7 @@ func (c *LsClient) DescribeCheckPoint(request *DescribeCheckPointRequest) (*Desc
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodGet, PathDescribeCheckPoint, params, reqHeaders, bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodGet, PathDescribeCheckPoint, params, reqHeaders, bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -49,6 +54,10 @@ func (c *LsClient) DescribeCheckPoint(request *DescribeCheckPointRequest) (*Desc
 }
 
 func (c *LsClient) ModifyCheckPoint(request *ModifyCheckPointRequest) (*CommonResponse, error) {
+	return c.ModifyCheckPointCtx(context.Background(), request)
+}
+
+func (c *LsClient) ModifyCheckPointCtx(ctx context.Context, request *ModifyCheckPointRequest) (*CommonResponse, error) {
 	reqHeaders := map[string]string{
 		"Content-Type": "application/json",
 	}
@@ -66,7 +75,7 @@ func (c *LsClient) ModifyCheckPoint(request *ModifyCheckPointRequest) (*CommonRe
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPut, PathModifyCheckPoint, nil, reqHeaders, jsonBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPut, PathModifyCheckPoint, nil, reqHeaders, jsonBody)
 	if err != nil {
 		return nil, err
 	}
@@ -86,6 +95,10 @@ func (c *LsClient) ModifyCheckPoint(request *ModifyCheckPointRequest) (*CommonRe
 }
 
 func (c *LsClient) ResetCheckPoint(request *ResetCheckPointRequest) (*CommonResponse, error) {
+	return c.ResetCheckPointCtx(context.Background(), request)
+}
+
+func (c *LsClient) ResetCheckPointCtx(ctx context.Context, request *ResetCheckPointRequest) (*CommonResponse, error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -105,7 +118,7 @@ func (c *LsClient) ResetCheckPoint(request *ResetCheckPointRequest) (*CommonResp
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPut, PathResetCheckPoint, nil, reqHeaders, jsonBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPut, PathResetCheckPoint, nil, reqHeaders, jsonBody)
 	if err != nil {
 		return nil, err
 	}
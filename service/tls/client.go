@@ -62,6 +62,9 @@ type LsClient struct {
 	Region          string
 	APIVersion      string
 	CustomUserAgent string
+
+	credentialsProvider base.CredentialsProvider
+	retryPolicy         base.RetryPolicy
 }
 
 func (c *LsClient) SetAPIVersion(version string) {
@@ -81,6 +84,14 @@ func (c *LsClient) ResetAccessKeyToken(accessKeyID, accessKeySecret, securityTok
 	c.accessLock.Unlock()
 }
 
+// SetCredentialsProvider makes the client fetch credentials from provider before
+// each request instead of using the keys set by ResetAccessKeyToken.
+func (c *LsClient) SetCredentialsProvider(provider base.CredentialsProvider) {
+	c.accessLock.Lock()
+	c.credentialsProvider = provider
+	c.accessLock.Unlock()
+}
+
 func (c *LsClient) SetTimeout(timeout time.Duration) {
 	c.Client.Timeout = timeout
 	defaultRequestTimeout = timeout
@@ -106,7 +117,21 @@ func (c *LsClient) SetRetryCounterMaximum(maximum int32) {
 	defaultRetryCounterMaximum = maximum
 }
 
+// SetRetryPolicy replaces RetryWithCondition for this client, e.g. with a
+// base.ExponentialRetryPolicy. Passing nil restores the default behaviour.
+func (c *LsClient) SetRetryPolicy(policy base.RetryPolicy) {
+	c.accessLock.Lock()
+	c.retryPolicy = policy
+	c.accessLock.Unlock()
+}
+
 func (c *LsClient) Request(method, uri string, params map[string]string, headers map[string]string, body []byte) (rsp *http.Response, e error) {
+	return c.RequestCtx(context.Background(), method, uri, params, headers, body)
+}
+
+// RequestCtx is Request bound to ctx: cancelling ctx aborts the in-flight
+// request and any retry wait.
+func (c *LsClient) RequestCtx(ctx context.Context, method, uri string, params map[string]string, headers map[string]string, body []byte) (rsp *http.Response, e error) {
 	defer func() {
 		if e != nil {
 			level.Error(innerlogger.DefaultLogger).Log(
@@ -150,15 +175,28 @@ func (c *LsClient) Request(method, uri string, params map[string]string, headers
 		realUri = uri
 	}
 
-	ctx := context.Background()
-	err = RetryWithCondition(ctx, func() error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	do := func() error {
 		r, iErr = c.realRequest(ctx, method, realUri, headers, body)
 		if iErr != nil {
 			level.Error(innerlogger.DefaultLogger).Log("msg", "Request failed", "reason", iErr)
 			level.Debug(innerlogger.DefaultLogger).Log("method", method, "uri", realUri, "headers", headers, "body", string(body))
 		}
 		return iErr
-	})
+	}
+
+	c.accessLock.RLock()
+	policy := c.retryPolicy
+	c.accessLock.RUnlock()
+	if policy != nil {
+		err = base.DoWithRetry(ctx, policy, nil, func() *base.RetryAttempt {
+			return newRetryAttempt(uri, do())
+		})
+	} else {
+		err = RetryWithCondition(ctx, do)
+	}
 
 	if err != nil {
 		return r, err
@@ -221,7 +259,7 @@ func (c *LsClient) realRequest(ctx context.Context, method, uri string, headers
 
 	reader := bytes.NewReader(body)
 	urlStr := fmt.Sprintf("%s%s", c.Endpoint, uri)
-	req, err := http.NewRequest(method, urlStr, reader)
+	req, err := http.NewRequestWithContext(ctx, method, urlStr, reader)
 	if err != nil {
 		return nil, NewClientError(err)
 	}
@@ -239,9 +277,20 @@ func (c *LsClient) realRequest(ctx context.Context, method, uri string, headers
 		Service:         ServiceName,
 		SessionToken:    c.SecurityToken,
 	}
+	provider := c.credentialsProvider
 
 	c.accessLock.RUnlock()
 
+	if provider != nil {
+		provided, err := provider.Retrieve()
+		if err != nil {
+			return nil, NewClientError(err)
+		}
+		credential.AccessKeyID = provided.AccessKeyID
+		credential.SecretAccessKey = provided.SecretAccessKey
+		credential.SessionToken = provided.SessionToken
+	}
+
 	req = credential.Sign(req)
 
 	// Get ready to do request
@@ -267,6 +316,7 @@ func (c *LsClient) realRequest(ctx context.Context, method, uri string, headers
 			return nil, NewBadResponseError(string(buf), resp.Header, resp.StatusCode)
 		}
 		err.RequestID = resp.Header.Get(RequestIDHeader)
+		err.RespHeader = resp.Header
 		return nil, err
 	}
 
@@ -1,9 +1,12 @@
 package tls
 
 import (
+	"context"
 	"net/http"
 	"sync"
 	"time"
+
+	"github.com/volcengine/volc-sdk-golang/base"
 )
 
 func NewClient(endpoint, accessKeyID, accessKeySecret, securityToken, region string) Client {
@@ -20,126 +23,222 @@ func NewClient(endpoint, accessKeyID, accessKeySecret, securityToken, region str
 	}
 }
 
+// Client is the TLS API. Every call has a Ctx variant taking a context that
+// cancels the in-flight request and its retries; the plain methods use
+// context.Background().
 type Client interface {
 	GetHttpClient() *http.Client
 	SetHttpClient(client *http.Client) error
 	ResetAccessKeyToken(accessKeyID, accessKeySecret, securityToken string)
+	SetCredentialsProvider(provider base.CredentialsProvider)
+	SetRetryPolicy(policy base.RetryPolicy)
 	SetTimeout(timeout time.Duration)
 	SetAPIVersion(version string)
 	SetCustomUserAgent(customUserAgent string)
 
 	PutLogs(request *PutLogsRequest) (response *CommonResponse, err error)
+	PutLogsCtx(ctx context.Context, request *PutLogsRequest) (response *CommonResponse, err error)
 	PutLogsV2(request *PutLogsV2Request) (response *CommonResponse, err error)
+	PutLogsV2Ctx(ctx context.Context, request *PutLogsV2Request) (response *CommonResponse, err error)
 	DescribeCursor(request *DescribeCursorRequest) (*DescribeCursorResponse, error)
+	DescribeCursorCtx(ctx context.Context, request *DescribeCursorRequest) (*DescribeCursorResponse, error)
 	ConsumeLogs(request *ConsumeLogsRequest) (*ConsumeLogsResponse, error)
+	ConsumeLogsCtx(ctx context.Context, request *ConsumeLogsRequest) (*ConsumeLogsResponse, error)
 	DescribeLogContext(request *DescribeLogContextRequest) (*DescribeLogContextResponse, error)
+	DescribeLogContextCtx(ctx context.Context, request *DescribeLogContextRequest) (*DescribeLogContextResponse, error)
 
 	CreateProject(request *CreateProjectRequest) (*CreateProjectResponse, error)
+	CreateProjectCtx(ctx context.Context, request *CreateProjectRequest) (*CreateProjectResponse, error)
 	DeleteProject(request *DeleteProjectRequest) (*CommonResponse, error)
+	DeleteProjectCtx(ctx context.Context, request *DeleteProjectRequest) (*CommonResponse, error)
 	DescribeProject(request *DescribeProjectRequest) (*DescribeProjectResponse, error)
+	DescribeProjectCtx(ctx context.Context, request *DescribeProjectRequest) (*DescribeProjectResponse, error)
 	DescribeProjects(request *DescribeProjectsRequest) (*DescribeProjectsResponse, error)
+	DescribeProjectsCtx(ctx context.Context, request *DescribeProjectsRequest) (*DescribeProjectsResponse, error)
 	ModifyProject(request *ModifyProjectRequest) (*CommonResponse, error)
+	ModifyProjectCtx(ctx context.Context, request *ModifyProjectRequest) (*CommonResponse, error)
 
 	CreateTopic(request *CreateTopicRequest) (*CreateTopicResponse, error)
+	CreateTopicCtx(ctx context.Context, request *CreateTopicRequest) (*CreateTopicResponse, error)
 	DeleteTopic(request *DeleteTopicRequest) (*CommonResponse, error)
+	DeleteTopicCtx(ctx context.Context, request *DeleteTopicRequest) (*CommonResponse, error)
 	DescribeTopic(request *DescribeTopicRequest) (*DescribeTopicResponse, error)
+	DescribeTopicCtx(ctx context.Context, request *DescribeTopicRequest) (*DescribeTopicResponse, error)
 	DescribeTopics(request *DescribeTopicsRequest) (*DescribeTopicsResponse, error)
+	DescribeTopicsCtx(ctx context.Context, request *DescribeTopicsRequest) (*DescribeTopicsResponse, error)
 	ModifyTopic(request *ModifyTopicRequest) (*CommonResponse, error)
+	ModifyTopicCtx(ctx context.Context, request *ModifyTopicRequest) (*CommonResponse, error)
 
 	CreateIndex(request *CreateIndexRequest) (*CreateIndexResponse, error)
+	CreateIndexCtx(ctx context.Context, request *CreateIndexRequest) (*CreateIndexResponse, error)
 	DeleteIndex(request *DeleteIndexRequest) (*CommonResponse, error)
+	DeleteIndexCtx(ctx context.Context, request *DeleteIndexRequest) (*CommonResponse, error)
 	DescribeIndex(request *DescribeIndexRequest) (*DescribeIndexResponse, error)
+	DescribeIndexCtx(ctx context.Context, request *DescribeIndexRequest) (*DescribeIndexResponse, error)
 	ModifyIndex(request *ModifyIndexRequest) (*CommonResponse, error)
+	ModifyIndexCtx(ctx context.Context, request *ModifyIndexRequest) (*CommonResponse, error)
 	SearchLogs(request *SearchLogsRequest) (*SearchLogsResponse, error)
+	SearchLogsCtx(ctx context.Context, request *SearchLogsRequest) (*SearchLogsResponse, error)
 	SearchLogsV2(request *SearchLogsRequest) (*SearchLogsResponse, error)
+	SearchLogsV2Ctx(ctx context.Context, request *SearchLogsRequest) (*SearchLogsResponse, error)
 
 	DescribeShards(request *DescribeShardsRequest) (*DescribeShardsResponse, error)
+	DescribeShardsCtx(ctx context.Context, request *DescribeShardsRequest) (*DescribeShardsResponse, error)
 
 	CreateRule(request *CreateRuleRequest) (*CreateRuleResponse, error)
+	CreateRuleCtx(ctx context.Context, request *CreateRuleRequest) (*CreateRuleResponse, error)
 	DeleteRule(request *DeleteRuleRequest) (*CommonResponse, error)
+	DeleteRuleCtx(ctx context.Context, request *DeleteRuleRequest) (*CommonResponse, error)
 	ModifyRule(request *ModifyRuleRequest) (*CommonResponse, error)
+	ModifyRuleCtx(ctx context.Context, request *ModifyRuleRequest) (*CommonResponse, error)
 	DescribeRule(request *DescribeRuleRequest) (*DescribeRuleResponse, error)
+	DescribeRuleCtx(ctx context.Context, request *DescribeRuleRequest) (*DescribeRuleResponse, error)
 	DescribeRules(request *DescribeRulesRequest) (*DescribeRulesResponse, error)
+	DescribeRulesCtx(ctx context.Context, request *DescribeRulesRequest) (*DescribeRulesResponse, error)
 	ApplyRuleToHostGroups(request *ApplyRuleToHostGroupsRequest) (*CommonResponse, error)
+	ApplyRuleToHostGroupsCtx(ctx context.Context, request *ApplyRuleToHostGroupsRequest) (*CommonResponse, error)
 	DeleteRuleFromHostGroups(request *DeleteRuleFromHostGroupsRequest) (*CommonResponse, error)
+	DeleteRuleFromHostGroupsCtx(ctx context.Context, request *DeleteRuleFromHostGroupsRequest) (*CommonResponse, error)
 
 	CreateHostGroup(request *CreateHostGroupRequest) (*CreateHostGroupResponse, error)
+	CreateHostGroupCtx(ctx context.Context, request *CreateHostGroupRequest) (*CreateHostGroupResponse, error)
 	DeleteHostGroup(request *DeleteHostGroupRequest) (*CommonResponse, error)
+	DeleteHostGroupCtx(ctx context.Context, request *DeleteHostGroupRequest) (*CommonResponse, error)
 	ModifyHostGroup(request *ModifyHostGroupRequest) (*CommonResponse, error)
+	ModifyHostGroupCtx(ctx context.Context, request *ModifyHostGroupRequest) (*CommonResponse, error)
 	DescribeHostGroup(request *DescribeHostGroupRequest) (*DescribeHostGroupResponse, error)
+	DescribeHostGroupCtx(ctx context.Context, request *DescribeHostGroupRequest) (*DescribeHostGroupResponse, error)
 	DescribeHostGroups(request *DescribeHostGroupsRequest) (*DescribeHostGroupsResponse, error)
+	DescribeHostGroupsCtx(ctx context.Context, request *DescribeHostGroupsRequest) (*DescribeHostGroupsResponse, error)
 	DescribeHosts(request *DescribeHostsRequest) (*DescribeHostsResponse, error)
+	DescribeHostsCtx(ctx context.Context, request *DescribeHostsRequest) (*DescribeHostsResponse, error)
 	DeleteHost(request *DeleteHostRequest) (*CommonResponse, error)
+	DeleteHostCtx(ctx context.Context, request *DeleteHostRequest) (*CommonResponse, error)
 	DescribeHostGroupRules(request *DescribeHostGroupRulesRequest) (*DescribeHostGroupRulesResponse, error)
+	DescribeHostGroupRulesCtx(ctx context.Context, request *DescribeHostGroupRulesRequest) (*DescribeHostGroupRulesResponse, error)
 	ModifyHostGroupsAutoUpdate(request *ModifyHostGroupsAutoUpdateRequest) (*ModifyHostGroupsAutoUpdateResponse, error)
+	ModifyHostGroupsAutoUpdateCtx(ctx context.Context, request *ModifyHostGroupsAutoUpdateRequest) (*ModifyHostGroupsAutoUpdateResponse, error)
 	DeleteAbnormalHosts(request *DeleteAbnormalHostsRequest) (*CommonResponse, error)
+	DeleteAbnormalHostsCtx(ctx context.Context, request *DeleteAbnormalHostsRequest) (*CommonResponse, error)
 
 	CreateAlarm(request *CreateAlarmRequest) (*CreateAlarmResponse, error)
+	CreateAlarmCtx(ctx context.Context, request *CreateAlarmRequest) (*CreateAlarmResponse, error)
 	DeleteAlarm(request *DeleteAlarmRequest) (*CommonResponse, error)
+	DeleteAlarmCtx(ctx context.Context, request *DeleteAlarmRequest) (*CommonResponse, error)
 	ModifyAlarm(request *ModifyAlarmRequest) (*CommonResponse, error)
+	ModifyAlarmCtx(ctx context.Context, request *ModifyAlarmRequest) (*CommonResponse, error)
 	DescribeAlarms(request *DescribeAlarmsRequest) (*DescribeAlarmsResponse, error)
+	DescribeAlarmsCtx(ctx context.Context, request *DescribeAlarmsRequest) (*DescribeAlarmsResponse, error)
 	CreateAlarmNotifyGroup(request *CreateAlarmNotifyGroupRequest) (*CreateAlarmNotifyGroupResponse, error)
+	CreateAlarmNotifyGroupCtx(ctx context.Context, request *CreateAlarmNotifyGroupRequest) (*CreateAlarmNotifyGroupResponse, error)
 	DeleteAlarmNotifyGroup(request *DeleteAlarmNotifyGroupRequest) (*CommonResponse, error)
+	DeleteAlarmNotifyGroupCtx(ctx context.Context, request *DeleteAlarmNotifyGroupRequest) (*CommonResponse, error)
 	ModifyAlarmNotifyGroup(request *ModifyAlarmNotifyGroupRequest) (*CommonResponse, error)
+	ModifyAlarmNotifyGroupCtx(ctx context.Context, request *ModifyAlarmNotifyGroupRequest) (*CommonResponse, error)
 	DescribeAlarmNotifyGroups(request *DescribeAlarmNotifyGroupsRequest) (*DescribeAlarmNotifyGroupsResponse, error)
+	DescribeAlarmNotifyGroupsCtx(ctx context.Context, request *DescribeAlarmNotifyGroupsRequest) (*DescribeAlarmNotifyGroupsResponse, error)
 
 	CreateDownloadTask(request *CreateDownloadTaskRequest) (*CreateDownloadTaskResponse, error)
+	CreateDownloadTaskCtx(ctx context.Context, request *CreateDownloadTaskRequest) (*CreateDownloadTaskResponse, error)
 	DescribeDownloadTasks(request *DescribeDownloadTasksRequest) (*DescribeDownloadTasksResponse, error)
+	DescribeDownloadTasksCtx(ctx context.Context, request *DescribeDownloadTasksRequest) (*DescribeDownloadTasksResponse, error)
 	DescribeDownloadUrl(request *DescribeDownloadUrlRequest) (*DescribeDownloadUrlResponse, error)
+	DescribeDownloadUrlCtx(ctx context.Context, request *DescribeDownloadUrlRequest) (*DescribeDownloadUrlResponse, error)
 
 	WebTracks(request *WebTracksRequest) (*WebTracksResponse, error)
+	WebTracksCtx(ctx context.Context, request *WebTracksRequest) (*WebTracksResponse, error)
 
 	OpenKafkaConsumer(request *OpenKafkaConsumerRequest) (*OpenKafkaConsumerResponse, error)
+	OpenKafkaConsumerCtx(ctx context.Context, request *OpenKafkaConsumerRequest) (*OpenKafkaConsumerResponse, error)
 	CloseKafkaConsumer(request *CloseKafkaConsumerRequest) (*CloseKafkaConsumerResponse, error)
+	CloseKafkaConsumerCtx(ctx context.Context, request *CloseKafkaConsumerRequest) (*CloseKafkaConsumerResponse, error)
 	DescribeKafkaConsumer(request *DescribeKafkaConsumerRequest) (*DescribeKafkaConsumerResponse, error)
+	DescribeKafkaConsumerCtx(ctx context.Context, request *DescribeKafkaConsumerRequest) (*DescribeKafkaConsumerResponse, error)
 
 	// Deprecated: use DescribeHistogramV1 instead
 	DescribeHistogram(request *DescribeHistogramRequest) (*DescribeHistogramResponse, error)
+	// Deprecated: use DescribeHistogramV1Ctx instead
+	DescribeHistogramCtx(ctx context.Context, request *DescribeHistogramRequest) (*DescribeHistogramResponse, error)
 	DescribeHistogramV1(request *DescribeHistogramV1Request) (*DescribeHistogramV1Response, error)
+	DescribeHistogramV1Ctx(ctx context.Context, request *DescribeHistogramV1Request) (*DescribeHistogramV1Response, error)
 
 	CreateConsumerGroup(request *CreateConsumerGroupRequest) (*CreateConsumerGroupResponse, error)
+	CreateConsumerGroupCtx(ctx context.Context, request *CreateConsumerGroupRequest) (*CreateConsumerGroupResponse, error)
 	DeleteConsumerGroup(request *DeleteConsumerGroupRequest) (*CommonResponse, error)
+	DeleteConsumerGroupCtx(ctx context.Context, request *DeleteConsumerGroupRequest) (*CommonResponse, error)
 	DescribeConsumerGroups(request *DescribeConsumerGroupsRequest) (*DescribeConsumerGroupsResponse, error)
+	DescribeConsumerGroupsCtx(ctx context.Context, request *DescribeConsumerGroupsRequest) (*DescribeConsumerGroupsResponse, error)
 	ModifyConsumerGroup(request *ModifyConsumerGroupRequest) (*CommonResponse, error)
+	ModifyConsumerGroupCtx(ctx context.Context, request *ModifyConsumerGroupRequest) (*CommonResponse, error)
 
 	ConsumerHeartbeat(request *ConsumerHeartbeatRequest) (*ConsumerHeartbeatResponse, error)
+	ConsumerHeartbeatCtx(ctx context.Context, request *ConsumerHeartbeatRequest) (*ConsumerHeartbeatResponse, error)
 	DescribeCheckPoint(request *DescribeCheckPointRequest) (*DescribeCheckPointResponse, error)
+	DescribeCheckPointCtx(ctx context.Context, request *DescribeCheckPointRequest) (*DescribeCheckPointResponse, error)
 	ModifyCheckPoint(request *ModifyCheckPointRequest) (*CommonResponse, error)
+	ModifyCheckPointCtx(ctx context.Context, request *ModifyCheckPointRequest) (*CommonResponse, error)
 	ResetCheckPoint(request *ResetCheckPointRequest) (*CommonResponse, error)
+	ResetCheckPointCtx(ctx context.Context, request *ResetCheckPointRequest) (*CommonResponse, error)
 
 	AddTagsToResource(request *AddTagsToResourceRequest) (*CommonResponse, error)
+	AddTagsToResourceCtx(ctx context.Context, request *AddTagsToResourceRequest) (*CommonResponse, error)
 	RemoveTagsFromResource(request *RemoveTagsFromResourceRequest) (*CommonResponse, error)
+	RemoveTagsFromResourceCtx(ctx context.Context, request *RemoveTagsFromResourceRequest) (*CommonResponse, error)
 
 	CreateETLTask(request *CreateETLTaskRequest) (*CreateETLTaskResponse, error)
+	CreateETLTaskCtx(ctx context.Context, request *CreateETLTaskRequest) (*CreateETLTaskResponse, error)
 	DeleteETLTask(request *DeleteETLTaskRequest) (*CommonResponse, error)
+	DeleteETLTaskCtx(ctx context.Context, request *DeleteETLTaskRequest) (*CommonResponse, error)
 	ModifyETLTask(request *ModifyETLTaskRequest) (*CommonResponse, error)
+	ModifyETLTaskCtx(ctx context.Context, request *ModifyETLTaskRequest) (*CommonResponse, error)
 	DescribeETLTask(request *DescribeETLTaskRequest) (*DescribeETLTaskResponse, error)
+	DescribeETLTaskCtx(ctx context.Context, request *DescribeETLTaskRequest) (*DescribeETLTaskResponse, error)
 	DescribeETLTasks(request *DescribeETLTasksRequest) (*DescribeETLTasksResponse, error)
+	DescribeETLTasksCtx(ctx context.Context, request *DescribeETLTasksRequest) (*DescribeETLTasksResponse, error)
 	ModifyETLTaskStatus(request *ModifyETLTaskStatusRequest) (*CommonResponse, error)
+	ModifyETLTaskStatusCtx(ctx context.Context, request *ModifyETLTaskStatusRequest) (*CommonResponse, error)
 
 	CreateImportTask(request *CreateImportTaskRequest) (*CreateImportTaskResponse, error)
+	CreateImportTaskCtx(ctx context.Context, request *CreateImportTaskRequest) (*CreateImportTaskResponse, error)
 	DeleteImportTask(request *DeleteImportTaskRequest) (*DeleteImportTaskResponse, error)
+	DeleteImportTaskCtx(ctx context.Context, request *DeleteImportTaskRequest) (*DeleteImportTaskResponse, error)
 	ModifyImportTask(request *ModifyImportTaskRequest) (*ModifyImportTaskResponse, error)
+	ModifyImportTaskCtx(ctx context.Context, request *ModifyImportTaskRequest) (*ModifyImportTaskResponse, error)
 	DescribeImportTask(request *DescribeImportTaskRequest) (*DescribeImportTaskResponse, error)
+	DescribeImportTaskCtx(ctx context.Context, request *DescribeImportTaskRequest) (*DescribeImportTaskResponse, error)
 	DescribeImportTasks(request *DescribeImportTasksRequest) (*DescribeImportTasksResponse, error)
+	DescribeImportTasksCtx(ctx context.Context, request *DescribeImportTasksRequest) (*DescribeImportTasksResponse, error)
 
 	CreateShipper(request *CreateShipperRequest) (*CreateShipperResponse, error)
+	CreateShipperCtx(ctx context.Context, request *CreateShipperRequest) (*CreateShipperResponse, error)
 	DeleteShipper(request *DeleteShipperRequest) (*DeleteShipperResponse, error)
+	DeleteShipperCtx(ctx context.Context, request *DeleteShipperRequest) (*DeleteShipperResponse, error)
 	ModifyShipper(request *ModifyShipperRequest) (*ModifyShipperResponse, error)
+	ModifyShipperCtx(ctx context.Context, request *ModifyShipperRequest) (*ModifyShipperResponse, error)
 	DescribeShipper(request *DescribeShipperRequest) (*DescribeShipperResponse, error)
+	DescribeShipperCtx(ctx context.Context, request *DescribeShipperRequest) (*DescribeShipperResponse, error)
 	DescribeShippers(request *DescribeShippersRequest) (*DescribeShippersResponse, error)
+	DescribeShippersCtx(ctx context.Context, request *DescribeShippersRequest) (*DescribeShippersResponse, error)
 
 	// AI应用实例管理
 	CreateAppInstance(request *CreateAppInstanceReq) (*CreateAppInstanceResp, error)
+	CreateAppInstanceCtx(ctx context.Context, request *CreateAppInstanceReq) (*CreateAppInstanceResp, error)
 	DescribeAppInstances(request *DescribeAppInstancesReq) (*DescribeAppInstancesResp, error)
+	DescribeAppInstancesCtx(ctx context.Context, request *DescribeAppInstancesReq) (*DescribeAppInstancesResp, error)
 	DeleteAppInstance(request *DeleteAppInstanceReq) (*DeleteAppInstanceResp, error)
+	DeleteAppInstanceCtx(ctx context.Context, request *DeleteAppInstanceReq) (*DeleteAppInstanceResp, error)
 
 	// AI场景元数据管理
 	CreateAppSceneMeta(request *CreateAppSceneMetaReq) (*CreateAppSceneMetaResp, error)
+	CreateAppSceneMetaCtx(ctx context.Context, request *CreateAppSceneMetaReq) (*CreateAppSceneMetaResp, error)
 	DescribeAppSceneMetas(request *DescribeAppSceneMetasReq) (*DescribeAppSceneMetasResp, error)
+	DescribeAppSceneMetasCtx(ctx context.Context, request *DescribeAppSceneMetasReq) (*DescribeAppSceneMetasResp, error)
 	DeleteAppSceneMeta(request *DeleteAppSceneMetaReq) (*DeleteAppSceneMetaResp, error)
+	DeleteAppSceneMetaCtx(ctx context.Context, request *DeleteAppSceneMetaReq) (*DeleteAppSceneMetaResp, error)
 	ModifyAppSceneMeta(request *ModifyAppSceneMetaReq) (*ModifyAppSceneMetaResp, error)
+	ModifyAppSceneMetaCtx(ctx context.Context, request *ModifyAppSceneMetaReq) (*ModifyAppSceneMetaResp, error)
 
 	// AI对话接口
 	DescribeSessionAnswer(request *DescribeSessionAnswerReq) (reader *CopilotSSEReader, err error)
+	DescribeSessionAnswerCtx(ctx context.Context, request *DescribeSessionAnswerReq) (reader *CopilotSSEReader, err error)
 }
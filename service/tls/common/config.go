@@ -1,5 +1,7 @@
 package common
 
+import "github.com/volcengine/volc-sdk-golang/base"
+
 type LoggerConfig struct {
 	LogLevel      string
 	LogFileName   string
@@ -15,4 +17,8 @@ type ClientConfig struct {
 	AccessKeySecret string
 	SecurityToken   string
 	Region          string
+
+	// CredentialsProvider, when set, takes precedence over the static keys above,
+	// e.g. an sts.AssumeRoleProvider that renews temporary credentials.
+	CredentialsProvider base.CredentialsProvider
 }
@@ -0,0 +1,75 @@
+package tls
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/volcengine/volc-sdk-golang/service/tls/pb"
+)
+
+// newFakeLogServer stores the log group list of the last PutLogs and serves it
+// back from ConsumeLogs, both in the compression the client asked for.
+func newFakeLogServer(t *testing.T) *httptest.Server {
+	var stored *pb.LogGroupList
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		switch r.URL.Path {
+		case PathPutLogs:
+			rawSize, _ := strconv.ParseInt(r.Header.Get(rawBodySizeHeader), 10, 64)
+			list, err := parseLogList(body, r.Header.Get("x-tls-compresstype"), rawSize)
+			if err != nil {
+				t.Errorf("decode PutLogs body: %v", err)
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			stored = list
+		case PathConsumeLogs:
+			var req struct{ Compression string }
+			json.Unmarshal(body, &req)
+			out, rawSize, err := GetPutLogsBody(req.Compression, stored)
+			if err != nil {
+				t.Errorf("encode ConsumeLogs body: %v", err)
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set(rawBodySizeHeader, strconv.Itoa(rawSize))
+			w.Header().Set("x-tls-count", strconv.Itoa(len(stored.LogGroups)))
+			w.Header().Set("x-tls-cursor", "next")
+			w.Write(out)
+		}
+	}))
+}
+
+func TestCompressRoundTrip(t *testing.T) {
+	server := newFakeLogServer(t)
+	defer server.Close()
+	client := NewClient(server.URL, "ak", "sk", "", "cn-beijing")
+
+	for _, compression := range []string{CompressLz4, CompressZstd, CompressSnappy, CompressNone} {
+		var logs []*pb.Log
+		for i := 0; i < 100; i++ {
+			logs = append(logs, &pb.Log{Time: 1, Contents: []*pb.LogContent{{Key: "message", Value: compression + " " + strconv.Itoa(i)}}})
+		}
+		_, err := client.PutLogs(&PutLogsRequest{
+			TopicID:      "topic",
+			CompressType: compression,
+			LogBody:      &pb.LogGroupList{LogGroups: []*pb.LogGroup{{Source: "127.0.0.1", Logs: logs}}},
+		})
+		if err != nil {
+			t.Fatalf("%s: PutLogs: %v", compression, err)
+		}
+
+		resp, err := client.ConsumeLogs(&ConsumeLogsRequest{TopicID: "topic", Cursor: "begin", Compression: StrPtr(compression)})
+		if err != nil {
+			t.Fatalf("%s: ConsumeLogs: %v", compression, err)
+		}
+		got := resp.Logs.LogGroups[0].Logs
+		if resp.Count != 1 || len(got) != len(logs) || got[99].Contents[0].Value != compression+" 99" {
+			t.Fatalf("%s: unexpected logs %v", compression, resp.Logs)
+		}
+	}
+}
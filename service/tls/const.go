@@ -6,9 +6,11 @@ const (
 	ContentMd5Header = "Content-MD5"
 	ServiceName      = "TLS"
 
-	CompressLz4  = "lz4"
-	CompressGz   = "gzip"
-	CompressNone = "none"
+	CompressLz4    = "lz4"
+	CompressGz     = "gzip"
+	CompressNone   = "none"
+	CompressZstd   = "zstd"
+	CompressSnappy = "snappy"
 
 	FullTextIndexKey = "__content__"
 
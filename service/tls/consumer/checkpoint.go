@@ -9,66 +9,102 @@ import (
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	"github.com/volcengine/volc-sdk-golang/service/tls"
+	"github.com/volcengine/volc-sdk-golang/service/tls/metrics"
 )
 
+// retryCheckpointInterval is how often checkpoints that failed to commit
+// synchronously are retried.
+const retryCheckpointInterval = time.Second
+
 type checkpointManager struct {
-	logger log.Logger
-	conf   *Config
-	client tls.Client
+	logger  log.Logger
+	conf    *Config
+	store   CheckpointStore
+	metrics metrics.Metrics
 
 	mapLock       *sync.RWMutex
 	checkpointMap map[string]*checkpointInfo
 	commitCh      <-chan struct{}
+	// uploadLock keeps concurrent uploads from committing an older checkpoint
+	// of a shard after a newer one. It also guards committed.
+	uploadLock sync.Mutex
+	committed  map[string]*checkpointInfo
 }
 
 func (c *checkpointManager) run(ctx context.Context, wg *sync.WaitGroup) {
 	level.Info(c.logger).Log("msg", "checkpoint manager start")
 	defer wg.Done()
 
-	uploadCheckpointTicker := time.NewTicker(time.Duration(c.conf.FlushCheckpointIntervalSecond) * time.Second)
+	interval := time.Duration(c.conf.FlushCheckpointIntervalSecond) * time.Second
+	if c.synchronous() {
+		interval = retryCheckpointInterval
+	}
+	uploadCheckpointTicker := time.NewTicker(interval)
 	defer uploadCheckpointTicker.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
-			c.uploadCheckpoint()
+			// ctx is already cancelled, commit the last checkpoints without it.
+			c.uploadCheckpoint(context.Background())
 
 			return
 		case <-c.commitCh:
-			c.uploadCheckpoint()
+			c.uploadCheckpoint(ctx)
 		case <-uploadCheckpointTicker.C:
-			c.uploadCheckpoint()
+			c.uploadCheckpoint(ctx)
 		}
 	}
 }
 
+// synchronous reports whether checkpoints are committed right after each
+// batch instead of periodically.
+func (c *checkpointManager) synchronous() bool {
+	return c.conf.FlushCheckpointIntervalSecond == 0
+}
+
 func (c *checkpointManager) addCheckpoint(checkpoint *checkpointInfo) {
 	c.mapLock.Lock()
 	defer c.mapLock.Unlock()
 
-	c.checkpointMap[checkpoint.shardInfo.TopicID+strconv.Itoa(checkpoint.shardInfo.ShardID)] = checkpoint
+	c.checkpointMap[checkpointKey(checkpoint.shardInfo)] = checkpoint
+}
+
+func (c *checkpointManager) uploadCheckpoint(ctx context.Context) {
+	c.upload(ctx, func(string) bool { return true })
 }
 
-func (c *checkpointManager) uploadCheckpoint() {
+// uploadShardCheckpoint commits the pending checkpoint of one shard.
+func (c *checkpointManager) uploadShardCheckpoint(ctx context.Context, shard *tls.ConsumeShard) {
+	key := checkpointKey(shard)
+	c.upload(ctx, func(k string) bool { return k == key })
+}
+
+func (c *checkpointManager) upload(ctx context.Context, match func(key string) bool) {
+	c.uploadLock.Lock()
+	defer c.uploadLock.Unlock()
+
 	checkpointSnapshot := make(map[string]checkpointInfo)
 	c.mapLock.Lock()
 	for k, checkpoint := range c.checkpointMap {
-		checkpointSnapshot[k] = *checkpoint
+		if match(k) {
+			checkpointSnapshot[k] = *checkpoint
+		}
 	}
 	c.mapLock.Unlock()
 
 	for k, checkpoint := range checkpointSnapshot {
-		if _, err := c.client.ModifyCheckPoint(&tls.ModifyCheckPointRequest{
-			ProjectID:         c.conf.ProjectID,
-			TopicID:           checkpoint.shardInfo.TopicID,
-			ConsumerGroupName: c.conf.ConsumerGroupName,
-			ShardID:           checkpoint.shardInfo.ShardID,
-			Checkpoint:        checkpoint.checkpoint,
-		}); err != nil {
+		if err := c.store.Save(ctx, checkpoint.shardInfo, checkpoint.checkpoint); err != nil {
 			level.Error(c.logger).Log("error", "upload checkpoint failed, err: "+err.Error())
 			delete(checkpointSnapshot, k)
+
+			continue
 		}
+		committed := checkpoint
+		committed.updateTime = time.Now()
+		c.committed[k] = &committed
 	}
+	c.reportCheckpointAge()
 
 	c.mapLock.Lock()
 	for k, checkpoint := range checkpointSnapshot {
@@ -79,16 +115,53 @@ func (c *checkpointManager) uploadCheckpoint() {
 	c.mapLock.Unlock()
 }
 
+// reportCheckpointAge sets the checkpoint age of every shard committed so far.
+func (c *checkpointManager) reportCheckpointAge() {
+	for _, checkpoint := range c.committed {
+		c.metrics.Set(metrics.ConsumerCheckpointAge, time.Since(checkpoint.updateTime).Seconds(),
+			metrics.LabelTopic, checkpoint.shardInfo.TopicID, metrics.LabelShard, strconv.Itoa(checkpoint.shardInfo.ShardID))
+	}
+}
+
+// forgetShard commits the pending checkpoint of a shard no longer consumed by
+// this consumer and stops reporting its checkpoint age. A checkpoint failing
+// to commit is dropped, the next owner of the shard resumes from the last
+// committed one.
+func (c *checkpointManager) forgetShard(shard *tls.ConsumeShard) {
+	key := checkpointKey(shard)
+	c.upload(context.Background(), func(k string) bool { return k == key })
+
+	// The entry is deleted under uploadLock, an upload in progress may hold a
+	// snapshot of it and look it up again once saved.
+	c.uploadLock.Lock()
+	defer c.uploadLock.Unlock()
+	c.mapLock.Lock()
+	delete(c.checkpointMap, key)
+	c.mapLock.Unlock()
+	delete(c.committed, key)
+	metrics.Delete(c.metrics, metrics.ConsumerCheckpointAge,
+		metrics.LabelTopic, shard.TopicID, metrics.LabelShard, strconv.Itoa(shard.ShardID))
+}
+
 type checkpointInfo struct {
 	shardInfo  *tls.ConsumeShard
 	checkpoint string
+	// updateTime is when the checkpoint was committed, only set for the
+	// entries of checkpointManager.committed.
+	updateTime time.Time
+}
+
+func checkpointKey(shard *tls.ConsumeShard) string {
+	return shard.TopicID + strconv.Itoa(shard.ShardID)
 }
 
-func newCheckpointManager(logger log.Logger, conf *Config, client tls.Client, commitCh chan struct{}) *checkpointManager {
+func newCheckpointManager(logger log.Logger, conf *Config, store CheckpointStore, commitCh chan struct{}) *checkpointManager {
 	return &checkpointManager{
 		logger:        logger,
 		conf:          conf,
-		client:        client,
+		store:         store,
+		metrics:       metrics.OrNop(conf.Metrics),
+		committed:     make(map[string]*checkpointInfo),
 		mapLock:       &sync.RWMutex{},
 		checkpointMap: make(map[string]*checkpointInfo),
 		commitCh:      commitCh,
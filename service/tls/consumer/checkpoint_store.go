@@ -0,0 +1,152 @@
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/volcengine/volc-sdk-golang/service/tls"
+)
+
+// CheckpointStore persists the checkpoints of a consumer group. Load returns
+// an empty checkpoint for a shard that never committed one, the shard is then
+// consumed from Config.ConsumeFrom.
+//
+// To commit a checkpoint in the same transaction as the processed data, have
+// the Processor write Batch.Cursor together with the data and let Load read
+// it back; Save may then be a no-op.
+type CheckpointStore interface {
+	Load(ctx context.Context, shard *tls.ConsumeShard) (string, error)
+	Save(ctx context.Context, shard *tls.ConsumeShard, checkpoint string) error
+}
+
+// ServerCheckpointStore keeps the checkpoints on the TLS server, which is
+// where the consumer group console shows the consumption progress. It is
+// the default store.
+type ServerCheckpointStore struct {
+	client            tls.Client
+	projectID         string
+	consumerGroupName string
+}
+
+func NewServerCheckpointStore(client tls.Client, projectID, consumerGroupName string) *ServerCheckpointStore {
+	return &ServerCheckpointStore{
+		client:            client,
+		projectID:         projectID,
+		consumerGroupName: consumerGroupName,
+	}
+}
+
+func (s *ServerCheckpointStore) Load(ctx context.Context, shard *tls.ConsumeShard) (string, error) {
+	resp, err := s.client.DescribeCheckPointCtx(ctx, &tls.DescribeCheckPointRequest{
+		ProjectID:         s.projectID,
+		TopicID:           shard.TopicID,
+		ConsumerGroupName: s.consumerGroupName,
+		ShardID:           shard.ShardID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return resp.Checkpoint, nil
+}
+
+func (s *ServerCheckpointStore) Save(ctx context.Context, shard *tls.ConsumeShard, checkpoint string) error {
+	_, err := s.client.ModifyCheckPointCtx(ctx, &tls.ModifyCheckPointRequest{
+		ProjectID:         s.projectID,
+		TopicID:           shard.TopicID,
+		ConsumerGroupName: s.consumerGroupName,
+		ShardID:           shard.ShardID,
+		Checkpoint:        checkpoint,
+	})
+
+	return err
+}
+
+// FileCheckpointStore keeps the checkpoints in a local JSON file, which is
+// rewritten atomically on every Save. Use one file per consumer group.
+type FileCheckpointStore struct {
+	path string
+
+	lock        sync.Mutex
+	checkpoints map[string]string
+}
+
+func NewFileCheckpointStore(path string) (*FileCheckpointStore, error) {
+	s := &FileCheckpointStore{path: path, checkpoints: make(map[string]string)}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &s.checkpoints); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *FileCheckpointStore) Load(ctx context.Context, shard *tls.ConsumeShard) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.checkpoints[fileCheckpointKey(shard)], nil
+}
+
+func (s *FileCheckpointStore) Save(ctx context.Context, shard *tls.ConsumeShard, checkpoint string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	key := fileCheckpointKey(shard)
+	prev, ok := s.checkpoints[key]
+	s.checkpoints[key] = checkpoint
+	if err := s.write(); err != nil {
+		if ok {
+			s.checkpoints[key] = prev
+		} else {
+			delete(s.checkpoints, key)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (s *FileCheckpointStore) write() error {
+	data, err := json.Marshal(s.checkpoints)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path)
+}
+
+func fileCheckpointKey(shard *tls.ConsumeShard) string {
+	return shard.TopicID + "/" + strconv.Itoa(shard.ShardID)
+}
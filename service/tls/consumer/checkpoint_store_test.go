@@ -0,0 +1,104 @@
+package consumer
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/volcengine/volc-sdk-golang/service/tls"
+	"github.com/volcengine/volc-sdk-golang/service/tls/pb"
+)
+
+// syncCheckingStore records the checkpoints saved to the file store.
+type syncCheckingStore struct {
+	*FileCheckpointStore
+
+	lock  sync.Mutex
+	saved []string
+}
+
+func (s *syncCheckingStore) Save(ctx context.Context, shard *tls.ConsumeShard, checkpoint string) error {
+	s.lock.Lock()
+	s.saved = append(s.saved, checkpoint)
+	s.lock.Unlock()
+	return s.FileCheckpointStore.Save(ctx, shard, checkpoint)
+}
+
+func (s *syncCheckingStore) snapshot() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]string(nil), s.saved...)
+}
+
+func TestFileCheckpointStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tls-checkpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "checkpoints.json")
+
+	server := newFakeServer(map[int][]*pb.LogGroupList{0: {newTestLogGroupList("a"), newTestLogGroupList("b"), newTestLogGroupList("c")}})
+	defer server.Close()
+
+	fileStore, err := NewFileCheckpointStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Resume after the first batch.
+	if err := fileStore.Save(context.Background(), &tls.ConsumeShard{TopicID: "topic", ShardID: 0}, "1"); err != nil {
+		t.Fatal(err)
+	}
+	store := &syncCheckingStore{FileCheckpointStore: fileStore}
+
+	var lock sync.Mutex
+	var processed, savedBefore []string
+	conf := newTestConfig(server.URL)
+	conf.FlushCheckpointIntervalSecond = 0
+	conf.CheckpointStore = store
+	c, err := NewConsumerWithProcessor(context.Background(), conf, ProcessFunc(func(ctx context.Context, batch *Batch) error {
+		lock.Lock()
+		defer lock.Unlock()
+		processed = append(processed, batch.Logs.LogGroups[0].Logs[0].Contents[0].Value)
+		savedBefore = append(savedBefore, store.snapshot()...)
+		savedBefore = append(savedBefore, "|")
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if !waitFor(func() bool { return len(store.snapshot()) == 2 }) {
+		t.Fatalf("checkpoints not saved, saved %v", store.snapshot())
+	}
+	c.Stop()
+
+	lock.Lock()
+	defer lock.Unlock()
+	if len(processed) != 2 || processed[0] != "b" || processed[1] != "c" {
+		t.Fatalf("unexpected processed batches %v", processed)
+	}
+	// Each batch sees the checkpoint of the batch before it already saved.
+	expect := []string{"|", "2", "|"}
+	for i, v := range expect {
+		if len(savedBefore) != len(expect) || savedBefore[i] != v {
+			t.Fatalf("checkpoints not saved synchronously, got %v", savedBefore)
+		}
+	}
+	if server.checkpoint(0) != "" {
+		t.Fatalf("checkpoint committed to the server")
+	}
+
+	reopened, err := NewFileCheckpointStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checkpoint, _ := reopened.Load(context.Background(), &tls.ConsumeShard{TopicID: "topic", ShardID: 0}); checkpoint != "3" {
+		t.Fatalf("expect checkpoint 3, got %q", checkpoint)
+	}
+}
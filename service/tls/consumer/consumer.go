@@ -11,7 +11,7 @@ import (
 	"github.com/go-kit/kit/log/level"
 	"github.com/volcengine/volc-sdk-golang/service/tls"
 	"github.com/volcengine/volc-sdk-golang/service/tls/common"
-	"github.com/volcengine/volc-sdk-golang/service/tls/pb"
+	"github.com/volcengine/volc-sdk-golang/service/tls/metrics"
 )
 
 type consumer struct {
@@ -19,30 +19,40 @@ type consumer struct {
 	cancel             context.CancelFunc
 	logger             log.Logger
 	conf               *Config
-	consumeFunc        func(topicID string, shardID int, l *pb.LogGroupList)
+	processor          Processor
 	heartbeatExpiredCh chan struct{}
 	commitCh           chan struct{}
 	wg                 *sync.WaitGroup
 
 	heartbeat  *heartbeatRunner
 	checkpoint *checkpointManager
+	pool       *workerPool
 	workerMap  map[string]*logConsumer
+	// retiring holds removed workers until their Processor is shut down.
+	retiring map[string]*logConsumer
 
 	client tls.Client
 }
 
-func newConsumer(ctx context.Context, conf *Config, consumeFunc func(topicID string, shardID int, l *pb.LogGroupList)) (*consumer, error) {
+func newConsumer(ctx context.Context, conf *Config, processor Processor) (*consumer, error) {
 	if err := validateConsumerConfig(conf); err != nil {
 		return nil, err
 	}
 
 	client := tls.NewClient(conf.Endpoint, conf.AccessKeyID, conf.AccessKeySecret, conf.SecurityToken, conf.Region)
+	if conf.CredentialsProvider != nil {
+		client.SetCredentialsProvider(conf.CredentialsProvider)
+	}
 	var logger log.Logger
 	if conf.Logger != nil {
 		logger = *conf.Logger
 	} else {
 		logger = common.LogConfig(conf.LoggerConfig)
 	}
+	store := conf.CheckpointStore
+	if store == nil {
+		store = NewServerCheckpointStore(client, conf.ProjectID, conf.ConsumerGroupName)
+	}
 	heartbeatExpiredCh := make(chan struct{})
 	commitCh := make(chan struct{})
 
@@ -50,12 +60,13 @@ func newConsumer(ctx context.Context, conf *Config, consumeFunc func(topicID str
 		ctx:                ctx,
 		logger:             logger,
 		conf:               conf,
-		consumeFunc:        consumeFunc,
+		processor:          processor,
 		heartbeatExpiredCh: heartbeatExpiredCh,
 		commitCh:           commitCh,
 		heartbeat:          newHeartbeatRunner(logger, client, conf, heartbeatExpiredCh),
-		checkpoint:         newCheckpointManager(logger, conf, client, commitCh),
+		checkpoint:         newCheckpointManager(logger, conf, store, commitCh),
 		workerMap:          make(map[string]*logConsumer),
+		retiring:           make(map[string]*logConsumer),
 		client:             client,
 		wg:                 &sync.WaitGroup{},
 	}, nil
@@ -69,10 +80,15 @@ func (c *consumer) Start() error {
 	ctx, cancel := context.WithCancel(c.ctx)
 	c.cancel = cancel
 
-	if err := c.init(); err != nil {
+	if err := c.init(ctx); err != nil {
+		cancel()
 		return err
 	}
 
+	if c.conf.ProcessWorkers > 0 {
+		c.pool = newWorkerPool(c.conf.ProcessWorkers)
+	}
+
 	c.wg.Add(3)
 	go c.checkpoint.run(ctx, c.wg)
 	go c.heartbeat.run(ctx, c.wg)
@@ -95,29 +111,33 @@ func (c *consumer) run(ctx context.Context, wg *sync.WaitGroup) {
 	for {
 		select {
 		case <-fetchDataTicker.C:
-			c.handleShards(c.heartbeat.getShards())
+			c.handleShards(ctx, c.heartbeat.getShards())
 		case <-ctx.Done():
 			return
 		}
 	}
 }
 
-func (c *consumer) handleShards(shards []*tls.ConsumeShard) {
+func (c *consumer) handleShards(ctx context.Context, shards []*tls.ConsumeShard) {
 	newShardsSet := make(map[string]*tls.ConsumeShard)
 	for _, shard := range shards {
 		newShardsSet[shard.TopicID+strconv.Itoa(shard.ShardID)] = shard
 	}
 
-	for shardName := range c.workerMap {
+	for shardName, worker := range c.workerMap {
 		if _, ok := newShardsSet[shardName]; !ok {
 			delete(c.workerMap, shardName)
+			c.retire(shardName, worker, ShutdownReasonShardReassigned)
 		}
 	}
 
 	for shardName, shardInfo := range newShardsSet {
 		lc, ok := c.workerMap[shardName]
+		if ok && lc.loadStatus() == waitForRestart {
+			c.retire(shardName, lc, ShutdownReasonHeartbeatExpired)
+		}
 		if !ok || lc.loadStatus() == waitForRestart {
-			c.workerMap[shardName] = c.newLogConsumer(shardInfo)
+			c.workerMap[shardName] = c.newLogConsumer(ctx, shardInfo)
 		}
 	}
 
@@ -126,27 +146,55 @@ func (c *consumer) handleShards(shards []*tls.ConsumeShard) {
 	}
 }
 
-func (c *consumer) newLogConsumer(consumeShard *tls.ConsumeShard) *logConsumer {
-	return &logConsumer{
-		ctx:                c.ctx,
+// retire shuts the worker down in the background, the next worker of the shard
+// waits for it before initializing.
+func (c *consumer) retire(shardName string, worker *logConsumer, reason ShutdownReason) {
+	c.retiring[shardName] = worker
+	go worker.shutdown(reason)
+}
+
+func (c *consumer) newLogConsumer(ctx context.Context, consumeShard *tls.ConsumeShard) *logConsumer {
+	shardName := consumeShard.TopicID + strconv.Itoa(consumeShard.ShardID)
+	var prevDone <-chan struct{}
+	if prev, ok := c.retiring[shardName]; ok {
+		prevDone = prev.done
+		delete(c.retiring, shardName)
+	}
+
+	lc := &logConsumer{
+		ctx:                ctx,
 		client:             c.client,
 		logger:             c.logger,
+		metrics:            metrics.OrNop(c.conf.Metrics),
 		conf:               c.conf,
 		statusLock:         &sync.RWMutex{},
 		status:             pending,
 		shard:              consumeShard,
-		consumeFunc:        c.consumeFunc,
+		processor:          c.processor,
 		checkpoint:         c.checkpoint,
 		heartbeatRestartCh: c.heartbeatExpiredCh,
 		commitCh:           c.commitCh,
 		nextCheckpoint:     "",
 		currLogGroupList:   nil,
+		prevDone:           prevDone,
+		done:               make(chan struct{}),
+		pool:               c.pool,
+	}
+	lc.retrier = &BatchRetrier{
+		Processor:          c.processor,
+		MaxProcessAttempts: c.conf.MaxProcessAttempts,
+		DeadLetterSink:     c.conf.DeadLetterSink,
+		Logger:             c.logger,
+	}
+	if c.pool != nil {
+		lc.taskCtx, lc.cancelTasks = context.WithCancel(ctx)
 	}
-}
 
-func (c *consumer) init() error {
+	return lc
+}
 
-	describeConsumerGroupsRes, err := c.client.DescribeConsumerGroups(&tls.DescribeConsumerGroupsRequest{
+func (c *consumer) init(ctx context.Context) error {
+	describeConsumerGroupsRes, err := c.client.DescribeConsumerGroupsCtx(ctx, &tls.DescribeConsumerGroupsRequest{
 		ProjectID:         c.conf.ProjectID,
 		ConsumerGroupName: c.conf.ConsumerGroupName,
 	})
@@ -161,7 +209,7 @@ func (c *consumer) init() error {
 		}
 	}
 
-	_, err = c.client.CreateConsumerGroup(&tls.CreateConsumerGroupRequest{
+	_, err = c.client.CreateConsumerGroupCtx(ctx, &tls.CreateConsumerGroupRequest{
 		ProjectID:         c.conf.ProjectID,
 		TopicIDList:       c.conf.TopicIDList,
 		ConsumerGroupName: c.conf.ConsumerGroupName,
@@ -181,8 +229,22 @@ func (c *consumer) init() error {
 }
 
 func (c *consumer) Stop() {
-	c.workerMap = make(map[string]*logConsumer)
-
 	c.cancel()
 	c.wg.Wait()
+
+	for _, worker := range c.workerMap {
+		worker.shutdown(ShutdownReasonConsumerStopped)
+	}
+	for _, worker := range c.retiring {
+		<-worker.done
+	}
+	c.workerMap = make(map[string]*logConsumer)
+	c.retiring = make(map[string]*logConsumer)
+	if c.pool != nil {
+		c.pool.stop()
+		c.pool = nil
+	}
+
+	// Commit what the workers processed while shutting down.
+	c.checkpoint.uploadCheckpoint(context.Background())
 }
@@ -5,7 +5,9 @@ import (
 	"github.com/go-kit/kit/log"
 	"regexp"
 
+	"github.com/volcengine/volc-sdk-golang/service/tls"
 	"github.com/volcengine/volc-sdk-golang/service/tls/common"
+	"github.com/volcengine/volc-sdk-golang/service/tls/metrics"
 )
 
 type Config struct {
@@ -18,10 +20,29 @@ type Config struct {
 	ConsumeFrom                    string
 	HeartbeatIntervalInSecond      int
 	DataFetchIntervalInMillisecond int64
-	FlushCheckpointIntervalSecond  int
-	MaxFetchLogGroupCount          int
-	OrderedConsume                 bool
-	Logger                         *log.Logger
+	// FlushCheckpointIntervalSecond is how often checkpoints are committed,
+	// 0 commits synchronously after each batch.
+	FlushCheckpointIntervalSecond int
+	MaxFetchLogGroupCount         int
+	OrderedConsume                bool
+	// CompressType is the ConsumeLogs compression: tls.CompressLz4 (default),
+	// tls.CompressZstd or tls.CompressSnappy.
+	CompressType string
+	// MaxProcessAttempts is how often a batch is processed before it is given
+	// to DeadLetterSink and skipped. 0 retries the batch forever.
+	MaxProcessAttempts int
+	DeadLetterSink     DeadLetterSink
+	// CheckpointStore persists the checkpoints, the TLS server by default.
+	CheckpointStore CheckpointStore
+	// ProcessWorkers is the number of goroutines processing the logs of all
+	// shards by ProcessKey, 0 processes each shard one batch at a time.
+	ProcessWorkers int
+	// ProcessKey splits the batches when ProcessWorkers is set, logs of one key
+	// are processed in order.
+	ProcessKey KeyFunc
+	// Metrics receives the consumer metrics, see package metrics.
+	Metrics metrics.Metrics
+	Logger  *log.Logger
 }
 
 func GetDefaultConsumerConfig() *Config {
@@ -40,6 +61,7 @@ func GetDefaultConsumerConfig() *Config {
 		MaxFetchLogGroupCount:          100,
 		FlushCheckpointIntervalSecond:  5,
 		OrderedConsume:                 false,
+		CompressType:                   tls.CompressLz4,
 	}
 }
 
@@ -72,13 +94,35 @@ func validateConsumerConfig(c *Config) error {
 		return errors.New("invalid DataFetchIntervalInMillisecond. acceptable range: (1, 300000]")
 	}
 
-	if c.FlushCheckpointIntervalSecond <= 0 || c.FlushCheckpointIntervalSecond > 300 {
-		return errors.New("invalid FlushCheckpointIntervalSecond. acceptable range: (1, 300]")
+	if c.FlushCheckpointIntervalSecond < 0 || c.FlushCheckpointIntervalSecond > 300 {
+		return errors.New("invalid FlushCheckpointIntervalSecond. acceptable range: [0, 300]")
 	}
 
 	if c.MaxFetchLogGroupCount <= 0 || c.MaxFetchLogGroupCount > 1000 {
 		return errors.New("invalid MaxFetchLogGroupCount. acceptable range: (1, 1000]")
 	}
 
+	switch c.CompressType {
+	case "", tls.CompressLz4, tls.CompressZstd, tls.CompressSnappy:
+	default:
+		return errors.New("invalid CompressType. valid options: \"lz4\", \"zstd\", \"snappy\"")
+	}
+
+	if c.MaxProcessAttempts < 0 {
+		return errors.New("invalid MaxProcessAttempts. acceptable range: [0, +inf)")
+	}
+
+	if c.MaxProcessAttempts > 0 && c.DeadLetterSink == nil {
+		return errors.New("empty DeadLetterSink. required when MaxProcessAttempts is set")
+	}
+
+	if c.ProcessWorkers < 0 {
+		return errors.New("invalid ProcessWorkers. acceptable range: [0, +inf)")
+	}
+
+	if c.ProcessWorkers > 0 && c.ProcessKey == nil {
+		return errors.New("empty ProcessKey. required when ProcessWorkers is set")
+	}
+
 	return nil
 }
@@ -13,5 +13,11 @@ type Consumer interface {
 }
 
 func NewConsumer(ctx context.Context, conf *Config, f func(topicID string, shardID int, l *pb.LogGroupList)) (Consumer, error) {
-	return newConsumer(ctx, conf, f)
+	return newConsumer(ctx, conf, consumeFuncProcessor(f))
+}
+
+// NewConsumerWithProcessor is NewConsumer with a Processor, whose errors keep
+// the checkpoint of the shard until the batch is processed.
+func NewConsumerWithProcessor(ctx context.Context, conf *Config, processor Processor) (Consumer, error) {
+	return newConsumer(ctx, conf, processor)
 }
@@ -0,0 +1,208 @@
+package consumer
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/volcengine/volc-sdk-golang/service/tls/pb"
+	"github.com/volcengine/volc-sdk-golang/service/tls/producer"
+)
+
+// Dead letter metadata, added as LogTags by FileDeadLetterSink and as log
+// contents by ProducerDeadLetterSink.
+const (
+	DeadLetterKeyTopic    = "__dead_letter_topic__"
+	DeadLetterKeyShard    = "__dead_letter_shard__"
+	DeadLetterKeyCursor   = "__dead_letter_cursor__"
+	DeadLetterKeyAttempts = "__dead_letter_attempts__"
+	DeadLetterKeyError    = "__dead_letter_error__"
+)
+
+// DeadLetter is a batch the Processor failed MaxProcessAttempts times.
+type DeadLetter struct {
+	Batch    *Batch
+	Attempts int
+	// Err is the error of the last attempt.
+	Err error
+}
+
+func (l *DeadLetter) metadata() []*pb.LogTag {
+	return []*pb.LogTag{
+		{Key: DeadLetterKeyTopic, Value: l.Batch.Shard.TopicID},
+		{Key: DeadLetterKeyShard, Value: strconv.Itoa(l.Batch.Shard.ShardID)},
+		{Key: DeadLetterKeyCursor, Value: l.Batch.Cursor},
+		{Key: DeadLetterKeyAttempts, Value: strconv.Itoa(l.Attempts)},
+		{Key: DeadLetterKeyError, Value: l.Err.Error()},
+	}
+}
+
+// DeadLetterSink stores dead letters. The checkpoint of the shard advances
+// past the batch only once Send succeeded, a failed Send is retried.
+type DeadLetterSink interface {
+	Send(ctx context.Context, letter *DeadLetter) error
+}
+
+// DeadLetterFunc is a DeadLetterSink calling a user function.
+type DeadLetterFunc func(ctx context.Context, letter *DeadLetter) error
+
+func (f DeadLetterFunc) Send(ctx context.Context, letter *DeadLetter) error {
+	return f(ctx, letter)
+}
+
+// FileDeadLetterSink appends dead letters to a local file as length-delimited
+// pb.LogGroupList messages, each log group tagged with the dead letter
+// metadata. ReadDeadLetters reads them back.
+type FileDeadLetterSink struct {
+	lock sync.Mutex
+	file *os.File
+}
+
+func NewFileDeadLetterSink(path string) (*FileDeadLetterSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileDeadLetterSink{file: f}, nil
+}
+
+func (s *FileDeadLetterSink) Send(ctx context.Context, letter *DeadLetter) error {
+	tags := letter.metadata()
+	list := &pb.LogGroupList{}
+	for _, group := range letter.Batch.Logs.LogGroups {
+		tagged := *group
+		tagged.LogTags = append(append([]*pb.LogTag(nil), group.LogTags...), tags...)
+		list.LogGroups = append(list.LogGroups, &tagged)
+	}
+
+	data, err := list.Marshal()
+	if err != nil {
+		return err
+	}
+	record := make([]byte, binary.MaxVarintLen64, binary.MaxVarintLen64+len(data))
+	record = append(record[:binary.PutUvarint(record, uint64(len(data)))], data...)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, err := s.file.Write(record); err != nil {
+		return err
+	}
+
+	return s.file.Sync()
+}
+
+func (s *FileDeadLetterSink) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.file.Close()
+}
+
+// ReadDeadLetters calls f with every dead letter written by a
+// FileDeadLetterSink to r.
+func ReadDeadLetters(r io.Reader, f func(list *pb.LogGroupList) error) error {
+	br := bufio.NewReader(r)
+	for {
+		n, err := binary.ReadUvarint(br)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		data := make([]byte, n)
+		if _, err := io.ReadFull(br, data); err != nil {
+			return err
+		}
+
+		list := &pb.LogGroupList{}
+		if err := list.Unmarshal(data); err != nil {
+			return err
+		}
+		if err := f(list); err != nil {
+			return err
+		}
+	}
+}
+
+// ProducerDeadLetterSink writes dead letters to another TLS topic, adding the
+// dead letter metadata to the contents of every log. Send returns once the
+// producer delivered all log groups of the batch.
+type ProducerDeadLetterSink struct {
+	Producer producer.Producer
+	TopicID  string
+}
+
+func NewProducerDeadLetterSink(p producer.Producer, topicID string) *ProducerDeadLetterSink {
+	return &ProducerDeadLetterSink{Producer: p, TopicID: topicID}
+}
+
+func (s *ProducerDeadLetterSink) Send(ctx context.Context, letter *DeadLetter) error {
+	var metadata []*pb.LogContent
+	for _, tag := range letter.metadata() {
+		metadata = append(metadata, &pb.LogContent{Key: tag.Key, Value: tag.Value})
+	}
+
+	// The producer calls the callback once per log, the buffer holds all of
+	// them so late callbacks never block the producer.
+	logCount := 0
+	for _, group := range letter.Batch.Logs.LogGroups {
+		logCount += len(group.Logs)
+	}
+	callback := &deadLetterCallback{results: make(chan *producer.Result, logCount)}
+	for _, group := range letter.Batch.Logs.LogGroups {
+		if len(group.Logs) == 0 {
+			continue
+		}
+
+		tagged := &pb.LogGroup{Source: group.Source, FileName: group.FileName, ContextFlow: group.ContextFlow}
+		for _, log := range group.Logs {
+			contents := append(append([]*pb.LogContent(nil), log.Contents...), metadata...)
+			tagged.Logs = append(tagged.Logs, &pb.Log{Time: log.Time, Contents: contents, OptionalTimeNs: log.OptionalTimeNs})
+		}
+		if err := s.Producer.SendLogsCtx(ctx, "", s.TopicID, group.Source, group.FileName, tagged, callback); err != nil {
+			return err
+		}
+	}
+
+	for pending := logCount; pending > 0; pending-- {
+		select {
+		case result := <-callback.results:
+			if !result.SuccessFlag {
+				return errDeadLetterNotDelivered(result)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+type deadLetterCallback struct {
+	results chan *producer.Result
+}
+
+func (c *deadLetterCallback) Success(result *producer.Result) {
+	c.results <- result
+}
+
+func (c *deadLetterCallback) Fail(result *producer.Result) {
+	c.results <- result
+}
+
+func errDeadLetterNotDelivered(result *producer.Result) error {
+	if n := len(result.Attempts); n > 0 {
+		return errors.New("send dead letter failed: " + result.Attempts[n-1].ErrorMessage)
+	}
+
+	return errors.New("send dead letter failed")
+}
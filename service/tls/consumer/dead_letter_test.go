@@ -0,0 +1,217 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/pierrec/lz4"
+	"github.com/volcengine/volc-sdk-golang/service/tls"
+	"github.com/volcengine/volc-sdk-golang/service/tls/pb"
+	"github.com/volcengine/volc-sdk-golang/service/tls/producer"
+)
+
+func TestDeadLetter(t *testing.T) {
+	server := newFakeServer(map[int][]*pb.LogGroupList{0: {newTestLogGroupList("poison"), newTestLogGroupList("ok")}})
+	defer server.Close()
+
+	var lock sync.Mutex
+	var letters []*DeadLetter
+	var processed []string
+	sinkFailures := 1
+	conf := newTestConfig(server.URL)
+	conf.MaxProcessAttempts = 2
+	conf.DeadLetterSink = DeadLetterFunc(func(ctx context.Context, letter *DeadLetter) error {
+		lock.Lock()
+		defer lock.Unlock()
+		if sinkFailures > 0 {
+			sinkFailures--
+			return errors.New("sink unavailable")
+		}
+		letters = append(letters, letter)
+		return nil
+	})
+	processor := ProcessFunc(func(ctx context.Context, batch *Batch) error {
+		value := batch.Logs.LogGroups[0].Logs[0].Contents[0].Value
+		if value == "poison" {
+			return errors.New("can not parse " + value)
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		processed = append(processed, value)
+		return nil
+	})
+
+	c, err := NewConsumerWithProcessor(context.Background(), conf, processor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Stop()
+
+	// The poison batch is dead-lettered once the sink accepts it, and the
+	// batch after it is processed.
+	if !waitFor(func() bool { return server.checkpoint(0) == "2" }) {
+		t.Fatal("checkpoint not committed")
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	if len(letters) != 1 || letters[0].Attempts != 2 || letters[0].Err.Error() != "can not parse poison" || letters[0].Batch.Cursor != "1" {
+		t.Fatalf("unexpected dead letters %+v", letters)
+	}
+	if len(processed) != 1 || processed[0] != "ok" {
+		t.Fatalf("unexpected processed batches %v", processed)
+	}
+}
+
+func TestBatchRetrier(t *testing.T) {
+	var letters []*DeadLetter
+	r := &BatchRetrier{
+		Processor: ProcessFunc(func(ctx context.Context, batch *Batch) error {
+			panic("poison")
+		}),
+		MaxProcessAttempts: 2,
+		DeadLetterSink: DeadLetterFunc(func(ctx context.Context, letter *DeadLetter) error {
+			letters = append(letters, letter)
+			return nil
+		}),
+		Logger: log.NewNopLogger(),
+	}
+	batch := &Batch{Shard: &tls.ConsumeShard{TopicID: "topic"}, Cursor: "1"}
+
+	var attempts BatchAttempts
+	for i := 1; i <= 2; i++ {
+		if delay, err := r.Attempt(context.Background(), batch, &attempts); err == nil || delay <= 0 || attempts.Count != i {
+			t.Fatalf("attempt %d: got delay %v, error %v, %d attempts", i, delay, err, attempts.Count)
+		}
+	}
+	if _, err := r.Attempt(context.Background(), batch, &attempts); err != nil {
+		t.Fatalf("expect the batch to be sent to the dead letter sink, got %v", err)
+	}
+	if len(letters) != 1 || letters[0].Attempts != 2 || letters[0].Err.Error() != "process panicked: poison" {
+		t.Fatalf("unexpected dead letters %v", letters)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	r.MaxProcessAttempts = 0
+	if err := r.Run(ctx, batch); err != context.DeadlineExceeded {
+		t.Fatalf("expect Run to stop with ctx, got %v", err)
+	}
+}
+
+func TestFileDeadLetterSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tls-dead-letter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "dead_letters")
+	sink, err := NewFileDeadLetterSink(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shard := &tls.ConsumeShard{TopicID: "topic", ShardID: 3}
+	for _, v := range []string{"a", "b"} {
+		letter := &DeadLetter{Batch: &Batch{Shard: shard, Logs: newTestLogGroupList(v), Cursor: v}, Attempts: 5, Err: errors.New("failed " + v)}
+		if err := sink.Send(context.Background(), letter); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var read []map[string]string
+	err = ReadDeadLetters(f, func(list *pb.LogGroupList) error {
+		tags := make(map[string]string)
+		for _, tag := range list.LogGroups[0].LogTags {
+			tags[tag.Key] = tag.Value
+		}
+		tags["message"] = list.LogGroups[0].Logs[0].Contents[0].Value
+		read = append(read, tags)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read) != 2 {
+		t.Fatalf("expect 2 dead letters, got %v", read)
+	}
+	for i, v := range []string{"a", "b"} {
+		tags := read[i]
+		if tags["message"] != v || tags[DeadLetterKeyCursor] != v || tags[DeadLetterKeyError] != "failed "+v ||
+			tags[DeadLetterKeyTopic] != "topic" || tags[DeadLetterKeyShard] != "3" || tags[DeadLetterKeyAttempts] != "5" {
+			t.Fatalf("unexpected dead letter %v", tags)
+		}
+	}
+}
+
+func TestProducerDeadLetterSink(t *testing.T) {
+	var lock sync.Mutex
+	var received []*pb.Log
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		rawSize, _ := strconv.Atoi(r.Header.Get("x-tls-bodyrawsize"))
+		raw := make([]byte, rawSize)
+		lz4.UncompressBlock(body, raw)
+		list := &pb.LogGroupList{}
+		list.Unmarshal(raw)
+		lock.Lock()
+		defer lock.Unlock()
+		for _, group := range list.LogGroups {
+			received = append(received, group.Logs...)
+		}
+	}))
+	defer server.Close()
+
+	conf := producer.GetDefaultProducerConfig()
+	conf.Endpoint = server.URL
+	conf.Region = "cn-beijing"
+	conf.AccessKeyID, conf.AccessKeySecret = "ak", "sk"
+	conf.LingerTime = 100 * time.Millisecond
+	conf.LogLevel = "error"
+	p := producer.NewProducer(conf)
+	p.Start()
+	defer p.Close()
+
+	logs := &pb.LogGroupList{LogGroups: []*pb.LogGroup{newTestLogGroupList("a", "b").LogGroups[0], {}, newTestLogGroupList("c").LogGroups[0]}}
+	letter := &DeadLetter{Batch: &Batch{Shard: &tls.ConsumeShard{TopicID: "topic", ShardID: 1}, Logs: logs, Cursor: "7"}, Attempts: 3, Err: errors.New("failed")}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := NewProducerDeadLetterSink(p, "dead-letters").Send(ctx, letter); err != nil {
+		t.Fatal(err)
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	if len(received) != 3 {
+		t.Fatalf("expect 3 dead letter logs, got %d", len(received))
+	}
+	for _, log := range received {
+		contents := make(map[string]string)
+		for _, content := range log.Contents {
+			contents[content.Key] = content.Value
+		}
+		if contents[DeadLetterKeyCursor] != "7" || contents[DeadLetterKeyShard] != "1" || contents[DeadLetterKeyError] != "failed" {
+			t.Fatalf("unexpected dead letter log %v", contents)
+		}
+	}
+}
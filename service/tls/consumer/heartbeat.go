@@ -8,12 +8,14 @@ import (
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	"github.com/volcengine/volc-sdk-golang/service/tls"
+	"github.com/volcengine/volc-sdk-golang/service/tls/metrics"
 )
 
 type heartbeatRunner struct {
 	conf           *Config
 	client         tls.Client
 	logger         log.Logger
+	metrics        metrics.Metrics
 	lastUpdateTime time.Time
 
 	heartbeatExpiredCh <-chan struct{}
@@ -26,6 +28,7 @@ func newHeartbeatRunner(logger log.Logger, client tls.Client, conf *Config, hear
 		conf:               conf,
 		client:             client,
 		logger:             logger,
+		metrics:            metrics.OrNop(conf.Metrics),
 		lock:               &sync.RWMutex{},
 		lastUpdateTime:     time.Now(),
 		heartbeatExpiredCh: heartbeatExpiredChan,
@@ -37,15 +40,19 @@ func (h *heartbeatRunner) run(ctx context.Context, wg *sync.WaitGroup) {
 	defer wg.Done()
 
 	for i := 0; i < 5; i++ {
-		err := h.uploadHeartbeat()
+		err := h.uploadHeartbeat(ctx)
 		if err != nil {
 			level.Error(h.logger).Log("error", "heartbeat runner upload heartbeat failed, err: "+err.Error())
 		}
 
 		if len(h.getShards()) > 0 {
 			break
-		} else {
-			time.Sleep(time.Millisecond * 500)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Millisecond * 500):
 		}
 	}
 
@@ -60,14 +67,14 @@ func (h *heartbeatRunner) run(ctx context.Context, wg *sync.WaitGroup) {
 			return
 		case <-h.heartbeatExpiredCh:
 			level.Debug(h.logger).Log("msg", "heartbeat expired, heartbeatRunner sends heartbeat at "+time.Now().String())
-			if err := h.uploadHeartbeat(); err != nil {
+			if err := h.uploadHeartbeat(ctx); err != nil {
 				level.Error(h.logger).Log("error", "heartbeatRunner failed to upload heartbeat, err: "+err.Error())
 			}
 
 			level.Debug(h.logger).Log("msg", "heartbeat runner upload heartbeat done.")
 		case sendTime := <-heartbeatTicker.C:
 			level.Debug(h.logger).Log("msg", "heartbeatRunner sends heartbeat at "+sendTime.String())
-			if err := h.uploadHeartbeat(); err != nil {
+			if err := h.uploadHeartbeat(ctx); err != nil {
 				level.Error(h.logger).Log("error", "heartbeatRunner failed to upload heartbeat, err: "+err.Error())
 			}
 
@@ -97,13 +104,15 @@ func (h *heartbeatRunner) clearShards() {
 	h.shards = nil
 }
 
-func (h *heartbeatRunner) uploadHeartbeat() error {
-	heartbeatResp, err := h.client.ConsumerHeartbeat(&tls.ConsumerHeartbeatRequest{
+func (h *heartbeatRunner) uploadHeartbeat(ctx context.Context) error {
+	heartbeatResp, err := h.client.ConsumerHeartbeatCtx(ctx, &tls.ConsumerHeartbeatRequest{
 		ProjectID:         h.conf.ProjectID,
 		ConsumerGroupName: h.conf.ConsumerGroupName,
 		ConsumerName:      h.conf.ConsumerName,
 	})
 	if err != nil {
+		h.metrics.Add(metrics.ConsumerHeartbeatFailures, 1)
+
 		return err
 	}
 
@@ -3,32 +3,38 @@ package consumer
 import (
 	"context"
 	"errors"
+	"math"
 	"net/http"
 	"runtime/debug"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	"github.com/volcengine/volc-sdk-golang/service/tls"
+	"github.com/volcengine/volc-sdk-golang/service/tls/metrics"
 	"github.com/volcengine/volc-sdk-golang/service/tls/pb"
 )
 
 var (
 	errBusy             = errors.New("server is busy")
 	errHeartbeatExpired = errors.New("heartbeat expired")
+	errProcessBackoff   = errors.New("waiting to process the batch again")
+	errShutdown         = errors.New("log consumer is shut down")
 )
 
 type logConsumer struct {
-	ctx    context.Context
-	client tls.Client
-	logger log.Logger
+	ctx     context.Context
+	client  tls.Client
+	logger  log.Logger
+	metrics metrics.Metrics
 
 	conf               *Config
 	statusLock         *sync.RWMutex
 	status             int
 	shard              *tls.ConsumeShard
-	consumeFunc        func(topicID string, shardID int, l *pb.LogGroupList)
+	processor          Processor
 	checkpoint         *checkpointManager
 	heartbeatRestartCh chan<- struct{}
 	commitCh           chan<- struct{}
@@ -36,6 +42,31 @@ type logConsumer struct {
 	nextCheckpoint   string
 	currLogGroupList *pb.LogGroupList
 	lastBackoffTime  time.Time
+
+	// processLock serializes the Processor calls of the shard.
+	processLock     sync.Mutex
+	initialized     bool
+	closed          bool
+	retrier         *BatchRetrier
+	processAttempts BatchAttempts
+	processRetryAt  time.Time
+	// prevDone is closed once the previous log consumer of the shard is shut
+	// down, done once this one is.
+	prevDone <-chan struct{}
+	done     chan struct{}
+
+	// pool processes the batches by key when Config.ProcessWorkers is set.
+	// taskCtx is cancelled when the shard is shut down, tasks counts its
+	// queued sub-batches and pending the batches not processed completely.
+	// parked holds the sub-batches of the keys waiting to be retried.
+	pool        *workerPool
+	taskCtx     context.Context
+	cancelTasks context.CancelFunc
+	tasks       sync.WaitGroup
+	pendingLock sync.Mutex
+	pending     []*pendingBatch
+	parkedLock  sync.Mutex
+	parked      map[string][]*keyTask
 }
 
 func (lc *logConsumer) run() {
@@ -98,25 +129,44 @@ func (lc *logConsumer) loadStatus() int {
 }
 
 func (lc *logConsumer) init() error {
-	checkpointResp, err := lc.client.DescribeCheckPoint(&tls.DescribeCheckPointRequest{
-		ProjectID:         lc.conf.ProjectID,
-		TopicID:           lc.shard.TopicID,
-		ConsumerGroupName: lc.conf.ConsumerGroupName,
-		ShardID:           lc.shard.ShardID,
-	})
+	if lc.prevDone != nil {
+		select {
+		case <-lc.prevDone:
+		case <-lc.ctx.Done():
+			return lc.ctx.Err()
+		}
+	}
+
+	lc.processLock.Lock()
+	defer lc.processLock.Unlock()
+
+	if lc.closed {
+		return errShutdown
+	}
+
+	if !lc.initialized {
+		if err := lc.processor.Initialize(lc.shard); err != nil {
+			level.Error(lc.logger).Log("error", "init log consumer failed in initializing processor, err: "+err.Error())
+
+			return err
+		}
+		lc.initialized = true
+	}
+
+	checkpoint, err := lc.checkpoint.store.Load(lc.ctx, lc.shard)
 	if err != nil {
 		level.Error(lc.logger).Log("error", "init log consumer failed in getting checkpoint, err: "+err.Error())
 
 		return err
 	}
 
-	if len(checkpointResp.Checkpoint) != 0 {
-		lc.nextCheckpoint = checkpointResp.Checkpoint
+	if len(checkpoint) != 0 {
+		lc.nextCheckpoint = checkpoint
 
 		return nil
 	}
 
-	descCursorResp, err := lc.client.DescribeCursor(&tls.DescribeCursorRequest{
+	descCursorResp, err := lc.client.DescribeCursorCtx(lc.ctx, &tls.DescribeCursorRequest{
 		TopicID: lc.shard.TopicID,
 		ShardID: lc.shard.ShardID,
 		From:    lc.conf.ConsumeFrom,
@@ -134,9 +184,12 @@ func (lc *logConsumer) init() error {
 }
 
 func (lc *logConsumer) fetchData() error {
-	compressType := tls.CompressLz4
+	compressType := lc.conf.CompressType
+	if compressType == "" {
+		compressType = tls.CompressLz4
+	}
 
-	fetchResp, err := lc.client.ConsumeLogs(&tls.ConsumeLogsRequest{
+	fetchResp, err := lc.client.ConsumeLogsCtx(lc.ctx, &tls.ConsumeLogsRequest{
 		TopicID:           lc.shard.TopicID,
 		ShardID:           lc.shard.ShardID,
 		Cursor:            lc.nextCheckpoint,
@@ -152,8 +205,12 @@ func (lc *logConsumer) fetchData() error {
 		}
 
 		if clientErr.Code == tls.ErrConsumerHeartbeatExpired {
-			lc.heartbeatRestartCh <- struct{}{}
-			lc.commitCh <- struct{}{}
+			for _, ch := range []chan<- struct{}{lc.heartbeatRestartCh, lc.commitCh} {
+				select {
+				case ch <- struct{}{}:
+				case <-lc.ctx.Done():
+				}
+			}
 			return errHeartbeatExpired
 		}
 
@@ -164,10 +221,33 @@ func (lc *logConsumer) fetchData() error {
 
 	lc.currLogGroupList = fetchResp.Logs
 	lc.nextCheckpoint = fetchResp.Cursor
+	lc.reportFetchLag()
 
 	return nil
 }
 
+// reportFetchLag sets the fetch lag from the newest log of the fetched batch,
+// an empty batch means the shard is caught up.
+func (lc *logConsumer) reportFetchLag() {
+	var newest time.Time
+	if lc.currLogGroupList != nil {
+		for _, group := range lc.currLogGroupList.LogGroups {
+			for _, log := range group.Logs {
+				if t := tls.LogTime(log); log.Time > 0 && t.After(newest) {
+					newest = t
+				}
+			}
+		}
+	}
+
+	// Clock skew can put the newest log in the future.
+	lag := 0.0
+	if !newest.IsZero() {
+		lag = math.Max(time.Since(newest).Seconds(), 0)
+	}
+	lc.metrics.Set(metrics.ConsumerFetchLag, lag, metrics.LabelTopic, lc.shard.TopicID, metrics.LabelShard, strconv.Itoa(lc.shard.ShardID))
+}
+
 func (lc *logConsumer) consume() error {
 	if lc.currLogGroupList == nil {
 		return nil
@@ -177,13 +257,76 @@ func (lc *logConsumer) consume() error {
 		return nil
 	}
 
-	lc.consumeFunc(lc.shard.TopicID, lc.shard.ShardID, lc.currLogGroupList)
+	if time.Now().Before(lc.processRetryAt) {
+		return errProcessBackoff
+	}
+
+	lc.processLock.Lock()
+	defer lc.processLock.Unlock()
+
+	if lc.closed {
+		return errShutdown
+	}
+
+	batch := &Batch{
+		Shard:  lc.shard,
+		Logs:   lc.currLogGroupList,
+		Cursor: lc.nextCheckpoint,
+	}
+	if lc.pool != nil {
+		return lc.dispatch(batch)
+	}
+
+	if delay, err := lc.retrier.Attempt(lc.ctx, batch, &lc.processAttempts); err != nil {
+		lc.processRetryAt = time.Now().Add(delay)
+
+		return err
+	}
+
+	lc.processAttempts = BatchAttempts{}
+	lc.processRetryAt = time.Time{}
+	lc.commit(lc.ctx, batch.Cursor)
+
+	return nil
+}
+
+// commit adds the checkpoint of a processed batch.
+func (lc *logConsumer) commit(ctx context.Context, cursor string) {
 	lc.checkpoint.addCheckpoint(&checkpointInfo{
 		shardInfo:  lc.shard,
-		checkpoint: lc.nextCheckpoint,
+		checkpoint: cursor,
 	})
+	if lc.checkpoint.synchronous() {
+		// A failed commit is retried by the checkpoint manager, the batch is
+		// not processed again.
+		lc.checkpoint.uploadShardCheckpoint(ctx, lc.shard)
+	}
+}
 
-	return nil
+// shutdown calls Processor.Shutdown once the running Processor calls
+// returned, queued sub-batches are dropped. No Processor method of the shard
+// is called afterwards.
+func (lc *logConsumer) shutdown(reason ShutdownReason) {
+	if lc.cancelTasks != nil {
+		lc.cancelTasks()
+	}
+
+	lc.processLock.Lock()
+	defer lc.processLock.Unlock()
+
+	if lc.closed {
+		return
+	}
+	lc.closed = true
+	defer close(lc.done)
+
+	lc.tasks.Wait()
+
+	if lc.initialized {
+		lc.processor.Shutdown(lc.shard, reason)
+	}
+	lc.checkpoint.forgetShard(lc.shard)
+	metrics.Delete(lc.metrics, metrics.ConsumerFetchLag, metrics.LabelTopic, lc.shard.TopicID, metrics.LabelShard, strconv.Itoa(lc.shard.ShardID))
 }
 
 func (lc *logConsumer) backoff() error {
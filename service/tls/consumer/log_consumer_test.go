@@ -0,0 +1,103 @@
+package consumer
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+
+	"github.com/volcengine/volc-sdk-golang/service/tls"
+	"github.com/volcengine/volc-sdk-golang/service/tls/metrics"
+	"github.com/volcengine/volc-sdk-golang/service/tls/pb"
+)
+
+type gaugeRecorder struct {
+	metrics.Nop
+	values map[string]float64
+}
+
+func (r *gaugeRecorder) Set(name string, value float64, labels ...string) {
+	r.values[name] = value
+}
+
+func TestReportFetchLag(t *testing.T) {
+	recorder := &gaugeRecorder{values: map[string]float64{}}
+	lc := &logConsumer{metrics: recorder, shard: &tls.ConsumeShard{TopicID: "topic"}}
+	lag := func(times ...int64) float64 {
+		var logs []*pb.Log
+		for _, t := range times {
+			logs = append(logs, &pb.Log{Time: t})
+		}
+		lc.currLogGroupList = &pb.LogGroupList{LogGroups: []*pb.LogGroup{{Logs: logs}}}
+		lc.reportFetchLag()
+		return recorder.values[metrics.ConsumerFetchLag]
+	}
+
+	now := time.Now()
+	for name, times := range map[string][]int64{
+		"seconds":      {now.Add(-time.Hour).Unix(), now.Add(-time.Minute).Unix()},
+		"milliseconds": {now.Add(-time.Hour).UnixNano() / 1e6, now.Add(-time.Minute).UnixNano() / 1e6},
+	} {
+		if got := lag(times...); got < 59 || got > 70 {
+			t.Errorf("got a fetch lag of %vs for logs in %s, want about 60s", got, name)
+		}
+	}
+
+	if got := lag(now.Add(time.Hour).UnixNano() / 1e6); got != 0 {
+		t.Errorf("got a fetch lag of %vs for a log in the future, want 0", got)
+	}
+	if got := lag(); got != 0 {
+		t.Errorf("got a fetch lag of %vs for an empty batch, want 0", got)
+	}
+}
+
+func TestShutdownForgetsShard(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tls-checkpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	store, err := NewFileCheckpointStore(filepath.Join(dir, "checkpoints.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registry := metrics.NewRegistry()
+	conf := &Config{FlushCheckpointIntervalSecond: 5, Metrics: registry}
+	manager := newCheckpointManager(log.NewNopLogger(), conf, store, make(chan struct{}))
+	shards := []*tls.ConsumeShard{{TopicID: "topic", ShardID: 0}, {TopicID: "topic", ShardID: 1}}
+	var consumers []*logConsumer
+	for _, shard := range shards {
+		lc := &logConsumer{logger: log.NewNopLogger(), metrics: registry, conf: conf, shard: shard, checkpoint: manager, done: make(chan struct{})}
+		lc.currLogGroupList = &pb.LogGroupList{LogGroups: []*pb.LogGroup{{Logs: []*pb.Log{{Time: time.Now().Unix()}}}}}
+		lc.reportFetchLag()
+		lc.commit(context.Background(), "1")
+		consumers = append(consumers, lc)
+	}
+	manager.uploadCheckpoint(context.Background())
+
+	// The last checkpoint of shard 0 is committed before its series go away.
+	consumers[0].commit(context.Background(), "2")
+	consumers[0].shutdown(ShutdownReasonShardReassigned)
+	if checkpoint, _ := store.Load(context.Background(), shards[0]); checkpoint != "2" {
+		t.Fatalf("got checkpoint %q for the shut down shard, want 2", checkpoint)
+	}
+	manager.uploadCheckpoint(context.Background())
+
+	var buf bytes.Buffer
+	registry.WritePrometheus(&buf)
+	for _, name := range []string{metrics.ConsumerCheckpointAge, metrics.ConsumerFetchLag} {
+		if !strings.Contains(buf.String(), name+`{topic="topic",shard="1"}`) {
+			t.Fatalf("%s of shard 1 missing:\n%s", name, buf.String())
+		}
+		if strings.Contains(buf.String(), `shard="0"`) {
+			t.Fatalf("series of shard 0 still reported:\n%s", buf.String())
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package consumer
+
+import (
+	"context"
+
+	"github.com/volcengine/volc-sdk-golang/service/tls"
+	"github.com/volcengine/volc-sdk-golang/service/tls/pb"
+)
+
+// ShutdownReason tells Processor.Shutdown why a shard stopped being consumed.
+type ShutdownReason string
+
+const (
+	// ShutdownReasonShardReassigned means the heartbeat handed the shard to
+	// another consumer of the group.
+	ShutdownReasonShardReassigned ShutdownReason = "ShardReassigned"
+	// ShutdownReasonHeartbeatExpired means the shard lease expired, the shard
+	// is initialized again once the heartbeat assigns it back.
+	ShutdownReasonHeartbeatExpired ShutdownReason = "HeartbeatExpired"
+	// ShutdownReasonConsumerStopped means Consumer.Stop was called.
+	ShutdownReasonConsumerStopped ShutdownReason = "ConsumerStopped"
+)
+
+// Batch is one ConsumeLogs result of a shard.
+type Batch struct {
+	Shard *tls.ConsumeShard
+	Logs  *pb.LogGroupList
+	// Cursor is the checkpoint committed once the batch is processed. With
+	// Config.ProcessWorkers it is committed once the sub-batches of all keys
+	// of this and the earlier batches are processed.
+	Cursor string
+	// Key is the Config.ProcessKey shared by the logs of a sub-batch, empty
+	// without Config.ProcessWorkers.
+	Key string
+}
+
+// Processor handles the logs of the shards assigned to a consumer. The
+// methods of one shard are never called concurrently, different shards are
+// processed in parallel. With Config.ProcessWorkers, Process is called for a
+// sub-batch per key instead, concurrently for different keys and in order for
+// the same key; Initialize and Shutdown still run alone.
+type Processor interface {
+	// Initialize is called before the first batch of a shard. An error
+	// leaves the shard unconsumed and Initialize is called again later.
+	Initialize(shard *tls.ConsumeShard) error
+	// Process handles a batch. Returning an error keeps the checkpoint where
+	// it was and the same batch is processed again after a backoff, until
+	// Config.MaxProcessAttempts is reached. ctx is cancelled when the consumer
+	// stops, with Config.ProcessWorkers also when the shard is shut down.
+	Process(ctx context.Context, batch *Batch) error
+	// Shutdown is called once no more batches of the shard will be processed,
+	// after the last Process call returned.
+	Shutdown(shard *tls.ConsumeShard, reason ShutdownReason)
+}
+
+// ProcessFunc is a Processor without per-shard resources.
+type ProcessFunc func(ctx context.Context, batch *Batch) error
+
+func (f ProcessFunc) Initialize(shard *tls.ConsumeShard) error {
+	return nil
+}
+
+func (f ProcessFunc) Process(ctx context.Context, batch *Batch) error {
+	return f(ctx, batch)
+}
+
+func (f ProcessFunc) Shutdown(shard *tls.ConsumeShard, reason ShutdownReason) {}
+
+// consumeFuncProcessor adapts the callback taken by NewConsumer, which can not
+// fail.
+func consumeFuncProcessor(f func(topicID string, shardID int, l *pb.LogGroupList)) Processor {
+	return ProcessFunc(func(ctx context.Context, batch *Batch) error {
+		f(batch.Shard.TopicID, batch.Shard.ShardID, batch.Logs)
+		return nil
+	})
+}
@@ -0,0 +1,100 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/volcengine/volc-sdk-golang/service/tls"
+	"github.com/volcengine/volc-sdk-golang/service/tls/pb"
+)
+
+type recordingProcessor struct {
+	lock      sync.Mutex
+	failures  int
+	events    []string
+	processed []string
+}
+
+func (p *recordingProcessor) Initialize(shard *tls.ConsumeShard) error {
+	p.record("initialize")
+	return nil
+}
+
+func (p *recordingProcessor) Process(ctx context.Context, batch *Batch) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.failures > 0 {
+		p.failures--
+		p.events = append(p.events, "fail")
+		return errors.New("downstream unavailable")
+	}
+	p.events = append(p.events, "process "+batch.Cursor)
+	p.processed = append(p.processed, batch.Logs.LogGroups[0].Logs[0].Contents[0].Value)
+	return nil
+}
+
+func (p *recordingProcessor) Shutdown(shard *tls.ConsumeShard, reason ShutdownReason) {
+	p.record("shutdown " + string(reason))
+}
+
+func (p *recordingProcessor) record(event string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.events = append(p.events, event)
+}
+
+func (p *recordingProcessor) snapshot() []string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return append([]string(nil), p.events...)
+}
+
+func TestProcessor(t *testing.T) {
+	server := newFakeServer(map[int][]*pb.LogGroupList{0: {newTestLogGroupList("a"), newTestLogGroupList("b")}})
+	defer server.Close()
+
+	processor := &recordingProcessor{failures: 2}
+	c, err := NewConsumerWithProcessor(context.Background(), newTestConfig(server.URL), processor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Failed batches hold the checkpoint and are processed again.
+	if !waitFor(func() bool { return server.checkpoint(0) == "2" }) {
+		t.Fatalf("checkpoint not committed, events %v", processor.snapshot())
+	}
+	expect := []string{"initialize", "fail", "fail", "process 1", "process 2"}
+	if events := processor.snapshot(); len(events) != len(expect) {
+		t.Fatalf("unexpected events %v", events)
+	}
+
+	// The heartbeat taking the shard away shuts it down.
+	server.setShards(nil)
+	if !waitFor(func() bool {
+		events := processor.snapshot()
+		return events[len(events)-1] == "shutdown "+string(ShutdownReasonShardReassigned)
+	}) {
+		t.Fatalf("shard not shut down, events %v", processor.snapshot())
+	}
+
+	// Getting it back initializes it again, stopping shuts it down.
+	server.setShards([]*tls.ConsumeShard{{TopicID: "topic", ShardID: 0}})
+	if !waitFor(func() bool { return len(processor.snapshot()) == len(expect)+2 }) {
+		t.Fatalf("shard not initialized again, events %v", processor.snapshot())
+	}
+	c.Stop()
+	events := processor.snapshot()
+	if events[len(events)-2] != "initialize" || events[len(events)-1] != "shutdown "+string(ShutdownReasonConsumerStopped) {
+		t.Fatalf("unexpected events %v", events)
+	}
+	for i, v := range expect {
+		if events[i] != v {
+			t.Fatalf("unexpected events %v", events)
+		}
+	}
+}
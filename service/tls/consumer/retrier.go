@@ -0,0 +1,114 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/volcengine/volc-sdk-golang/base"
+)
+
+const (
+	processRetryBaseInterval = 500 * time.Millisecond
+	processRetryMaxInterval  = 30 * time.Second
+)
+
+// ProcessBackoff returns the delay before processing a batch again after it
+// failed attempts times.
+func ProcessBackoff(attempts int) time.Duration {
+	return base.ExponentialBackoff(attempts, processRetryBaseInterval, processRetryMaxInterval)
+}
+
+// BatchAttempts is the retry state of one batch.
+type BatchAttempts struct {
+	// Count is the number of failed process attempts, Err the last error.
+	Count int
+	Err   error
+}
+
+// BatchRetrier processes batches with the retries and dead letter handling of
+// the consumer, for other consumers of TLS logs such as the kafka package to
+// handle failures the same way. A batch which failed MaxProcessAttempts times
+// is sent to DeadLetterSink, 0 retries it until it is processed. Panics of the
+// Processor and the sink are turned into errors.
+type BatchRetrier struct {
+	Processor          Processor
+	MaxProcessAttempts int
+	DeadLetterSink     DeadLetterSink
+	Logger             log.Logger
+}
+
+// Attempt processes batch once, or sends it to the dead letter sink once it
+// used its attempts. It returns nil when the batch is done with, otherwise the
+// error and the delay before the next attempt.
+func (r *BatchRetrier) Attempt(ctx context.Context, batch *Batch, attempts *BatchAttempts) (time.Duration, error) {
+	if r.MaxProcessAttempts > 0 && attempts.Count >= r.MaxProcessAttempts {
+		err := r.deadLetter(ctx, &DeadLetter{Batch: batch, Attempts: attempts.Count, Err: attempts.Err})
+		if err != nil {
+			level.Error(r.Logger).Log("msg", "send dead letter failed, retry later", "topic", batch.Shard.TopicID, "shard", batch.Shard.ShardID, "key", batch.Key, "error", err)
+			return ProcessBackoff(attempts.Count), err
+		}
+		return 0, nil
+	}
+
+	if err := r.process(ctx, batch); err != nil {
+		attempts.Count++
+		attempts.Err = err
+		level.Warn(r.Logger).Log("msg", "process batch failed, retry later", "topic", batch.Shard.TopicID, "shard", batch.Shard.ShardID, "key", batch.Key, "attempts", attempts.Count, "error", err)
+		return ProcessBackoff(attempts.Count), err
+	}
+
+	return 0, nil
+}
+
+// Run attempts batch until it is done with, waiting between the attempts. It
+// returns the error of ctx if ctx is done first.
+func (r *BatchRetrier) Run(ctx context.Context, batch *Batch) error {
+	var attempts BatchAttempts
+	for ctx.Err() == nil {
+		delay, err := r.Attempt(ctx, batch, &attempts)
+		if err == nil {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}
+
+	return ctx.Err()
+}
+
+// process runs the processor on a batch, turning a panic into an error so the
+// batch is retried with backoff.
+func (r *BatchRetrier) process(ctx context.Context, batch *Batch) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("process panicked: %v", p)
+			level.Error(r.Logger).Log("panic", "panic happened during processing. info: "+string(debug.Stack()))
+		}
+	}()
+
+	return r.Processor.Process(ctx, batch)
+}
+
+// deadLetter hands a batch which exhausted MaxProcessAttempts to the
+// DeadLetterSink so the checkpoint can move past it.
+func (r *BatchRetrier) deadLetter(ctx context.Context, letter *DeadLetter) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("dead letter sink panicked: %v", p)
+			level.Error(r.Logger).Log("panic", "panic happened during sending dead letter. info: "+string(debug.Stack()))
+		}
+	}()
+
+	level.Warn(r.Logger).Log("msg", "batch exhausted its process attempts, sending it to the dead letter sink", "topic", letter.Batch.Shard.TopicID, "shard", letter.Batch.Shard.ShardID, "attempts", letter.Attempts)
+
+	return r.DeadLetterSink.Send(ctx, letter)
+}
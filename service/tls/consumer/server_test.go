@@ -0,0 +1,122 @@
+package consumer
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/volcengine/volc-sdk-golang/service/tls"
+	"github.com/volcengine/volc-sdk-golang/service/tls/pb"
+)
+
+// fakeServer serves the consumer group APIs for one consumer. Cursor "N" of a
+// shard points at its N-th log group list.
+type fakeServer struct {
+	*httptest.Server
+
+	lock        sync.Mutex
+	shards      []*tls.ConsumeShard
+	logs        map[int][]*pb.LogGroupList
+	checkpoints map[int]string
+}
+
+func newFakeServer(logs map[int][]*pb.LogGroupList) *fakeServer {
+	s := &fakeServer{logs: logs, checkpoints: make(map[int]string)}
+	for shardID := range logs {
+		s.shards = append(s.shards, &tls.ConsumeShard{TopicID: "topic", ShardID: shardID})
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+func (s *fakeServer) serve(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	body, _ := ioutil.ReadAll(r.Body)
+	shardID, _ := strconv.Atoi(r.URL.Query().Get("ShardId"))
+	switch r.URL.Path {
+	case tls.PathDescribeConsumerGroups:
+		json.NewEncoder(w).Encode(tls.DescribeConsumerGroupsResponse{ConsumerGroups: []*tls.ConsumerGroupResp{{ConsumerGroupName: "group"}}})
+	case tls.PathConsumerHeartbeat:
+		json.NewEncoder(w).Encode(tls.ConsumerHeartbeatResponse{Shards: s.shards})
+	case tls.PathDescribeCheckPoint:
+		json.NewEncoder(w).Encode(tls.DescribeCheckPointResponse{Checkpoint: s.checkpoints[shardID]})
+	case tls.PathModifyCheckPoint:
+		var req struct {
+			ShardID    int
+			Checkpoint string
+		}
+		json.Unmarshal(body, &req)
+		s.checkpoints[req.ShardID] = req.Checkpoint
+	case tls.PathDescribeCursor:
+		json.NewEncoder(w).Encode(tls.DescribeCursorResponse{Cursor: "0"})
+	case tls.PathConsumeLogs:
+		var req struct {
+			Cursor      string
+			Compression string
+		}
+		json.Unmarshal(body, &req)
+		next, _ := strconv.Atoi(req.Cursor)
+		list := &pb.LogGroupList{}
+		if next < len(s.logs[shardID]) {
+			list = s.logs[shardID][next]
+			next++
+		}
+		out, rawSize, _ := tls.GetPutLogsBody(req.Compression, list)
+		w.Header().Set("x-tls-bodyrawsize", strconv.Itoa(rawSize))
+		w.Header().Set("x-tls-count", strconv.Itoa(len(list.LogGroups)))
+		w.Header().Set("x-tls-cursor", strconv.Itoa(next))
+		w.Write(out)
+	}
+}
+
+func (s *fakeServer) setShards(shards []*tls.ConsumeShard) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.shards = shards
+}
+
+func (s *fakeServer) checkpoint(shardID int) string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.checkpoints[shardID]
+}
+
+func newTestConfig(endpoint string) *Config {
+	conf := GetDefaultConsumerConfig()
+	conf.Endpoint = endpoint
+	conf.Region = "cn-beijing"
+	conf.AccessKeyID, conf.AccessKeySecret = "ak", "sk"
+	conf.ProjectID = "project"
+	conf.TopicIDList = []string{"topic"}
+	conf.ConsumerGroupName = "group"
+	conf.ConsumerName = "consumer"
+	conf.HeartbeatIntervalInSecond = 1
+	conf.DataFetchIntervalInMillisecond = 10
+	conf.FlushCheckpointIntervalSecond = 1
+	conf.LogLevel = "error"
+	return conf
+}
+
+func newTestLogGroupList(values ...string) *pb.LogGroupList {
+	group := &pb.LogGroup{}
+	for _, v := range values {
+		group.Logs = append(group.Logs, &pb.Log{Time: 1, Contents: []*pb.LogContent{{Key: "message", Value: v}}})
+	}
+	return &pb.LogGroupList{LogGroups: []*pb.LogGroup{group}}
+}
+
+// waitFor polls cond for up to 5 seconds.
+func waitFor(cond func() bool) bool {
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if cond() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,260 @@
+package consumer
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/volcengine/volc-sdk-golang/service/tls/pb"
+)
+
+// processQueueSize is how many sub-batches wait for each worker, consuming a
+// shard blocks while the queue of one of its keys is full.
+const processQueueSize = 64
+
+// KeyFunc returns the key of a log when Config.ProcessWorkers is set. Logs of
+// the same key are processed in order, different keys in parallel.
+type KeyFunc func(group *pb.LogGroup, log *pb.Log) string
+
+// LogContentKey keys logs by the value of their content name, logs without it
+// share the empty key.
+func LogContentKey(name string) KeyFunc {
+	return func(group *pb.LogGroup, log *pb.Log) string {
+		for _, content := range log.Contents {
+			if content.Key == name {
+				return content.Value
+			}
+		}
+
+		return ""
+	}
+}
+
+// workerPool runs the sub-batches of all shards on a fixed number of
+// goroutines. A key always goes to the same worker, which runs its tasks one
+// by one.
+type workerPool struct {
+	queues []chan func()
+	wg     sync.WaitGroup
+}
+
+func newWorkerPool(workers int) *workerPool {
+	pool := &workerPool{queues: make([]chan func(), workers)}
+	pool.wg.Add(workers)
+	for i := range pool.queues {
+		queue := make(chan func(), processQueueSize)
+		pool.queues[i] = queue
+		go func() {
+			defer pool.wg.Done()
+			for task := range queue {
+				task()
+			}
+		}()
+	}
+
+	return pool
+}
+
+func (pool *workerPool) submit(ctx context.Context, key string, task func()) error {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+
+	select {
+	case pool.queues[h.Sum32()%uint32(len(pool.queues))] <- task:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// stop waits for the queued tasks, nothing may be submitted afterwards.
+func (pool *workerPool) stop() {
+	for _, queue := range pool.queues {
+		close(queue)
+	}
+	pool.wg.Wait()
+}
+
+// pendingBatch is a fetched batch whose sub-batches are not all processed yet.
+type pendingBatch struct {
+	cursor    string
+	remaining int
+}
+
+// keyTask is a sub-batch of pending.
+type keyTask struct {
+	batch    *Batch
+	pending  *pendingBatch
+	attempts BatchAttempts
+	// retryDelay is the backoff after the last failed attempt.
+	retryDelay time.Duration
+}
+
+// dispatch splits the batch by key and queues the sub-batches. The checkpoint
+// moves to the cursor of a batch once it and all batches before it are
+// processed.
+func (lc *logConsumer) dispatch(batch *Batch) error {
+	keys, subBatches := splitBatch(batch, lc.conf.ProcessKey)
+
+	// The extra count holds the batch until all sub-batches are queued.
+	pending := &pendingBatch{cursor: batch.Cursor, remaining: len(keys) + 1}
+	lc.pendingLock.Lock()
+	lc.pending = append(lc.pending, pending)
+	lc.pendingLock.Unlock()
+
+	for _, key := range keys {
+		task := &keyTask{batch: subBatches[key], pending: pending}
+		lc.tasks.Add(1)
+		err := lc.pool.submit(lc.taskCtx, key, func() { lc.runTask(task) })
+		if err != nil {
+			lc.tasks.Done()
+			return err
+		}
+	}
+	lc.completeTask(pending)
+
+	return nil
+}
+
+// splitBatch returns the keys of the batch in order of appearance and a
+// sub-batch per key, which holds a copy of each log group with the logs of
+// that key.
+func splitBatch(batch *Batch, keyFunc KeyFunc) ([]string, map[string]*Batch) {
+	var keys []string
+	subBatches := make(map[string]*Batch)
+	for _, group := range batch.Logs.LogGroups {
+		groups := make(map[string]*pb.LogGroup)
+		for _, log := range group.Logs {
+			key := keyFunc(group, log)
+			subGroup, ok := groups[key]
+			if !ok {
+				subGroup = &pb.LogGroup{
+					Source:      group.Source,
+					LogTags:     group.LogTags,
+					FileName:    group.FileName,
+					ContextFlow: group.ContextFlow,
+				}
+				groups[key] = subGroup
+
+				subBatch, ok := subBatches[key]
+				if !ok {
+					subBatch = &Batch{
+						Shard:  batch.Shard,
+						Logs:   &pb.LogGroupList{},
+						Cursor: batch.Cursor,
+						Key:    key,
+					}
+					subBatches[key] = subBatch
+					keys = append(keys, key)
+				}
+				subBatch.Logs.LogGroups = append(subBatch.Logs.LogGroups, subGroup)
+			}
+			subGroup.Logs = append(subGroup.Logs, log)
+		}
+	}
+
+	return keys, subBatches
+}
+
+// runTask processes a sub-batch, or queues it behind the sub-batch of its key
+// waiting to be retried.
+func (lc *logConsumer) runTask(task *keyTask) {
+	lc.parkedLock.Lock()
+	if parked, ok := lc.parked[task.batch.Key]; ok {
+		lc.parked[task.batch.Key] = append(parked, task)
+		lc.parkedLock.Unlock()
+		return
+	}
+	lc.parkedLock.Unlock()
+
+	lc.runTasks(task.batch.Key, []*keyTask{task})
+}
+
+// runTasks processes the sub-batches of key in order. When one fails, it and
+// the ones after it are parked and queued again after a backoff, so that the
+// worker goes on with the other keys meanwhile.
+func (lc *logConsumer) runTasks(key string, tasks []*keyTask) {
+	for i, task := range tasks {
+		if lc.taskCtx.Err() != nil {
+			lc.dropTasks(tasks[i:])
+			return
+		}
+		if !lc.attemptTask(task) {
+			lc.park(key, tasks[i:])
+			return
+		}
+		lc.completeTask(task.pending)
+		lc.tasks.Done()
+	}
+}
+
+// attemptTask processes a sub-batch once with the same dead letter handling as
+// a whole batch. It returns false if the sub-batch is to be retried.
+func (lc *logConsumer) attemptTask(task *keyTask) bool {
+	delay, err := lc.retrier.Attempt(lc.taskCtx, task.batch, &task.attempts)
+	task.retryDelay = delay
+
+	return err == nil
+}
+
+// park holds the sub-batches of key until the backoff of the first one is
+// over, then queues them again. They are dropped if the shard is shut down
+// meanwhile.
+func (lc *logConsumer) park(key string, tasks []*keyTask) {
+	lc.parkedLock.Lock()
+	if lc.parked == nil {
+		lc.parked = make(map[string][]*keyTask)
+	}
+	lc.parked[key] = tasks
+	lc.parkedLock.Unlock()
+
+	timer := time.NewTimer(tasks[0].retryDelay)
+	go func() {
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			if lc.pool.submit(lc.taskCtx, key, func() { lc.runTasks(key, lc.unpark(key)) }) == nil {
+				return
+			}
+		case <-lc.taskCtx.Done():
+		}
+		lc.dropTasks(lc.unpark(key))
+	}()
+}
+
+// unpark returns the parked sub-batches of key, including the ones queued
+// behind the first one meanwhile.
+func (lc *logConsumer) unpark(key string) []*keyTask {
+	lc.parkedLock.Lock()
+	defer lc.parkedLock.Unlock()
+
+	tasks := lc.parked[key]
+	delete(lc.parked, key)
+
+	return tasks
+}
+
+// dropTasks gives up sub-batches of a shard being shut down.
+func (lc *logConsumer) dropTasks(tasks []*keyTask) {
+	for range tasks {
+		lc.tasks.Done()
+	}
+}
+
+// completeTask counts a processed sub-batch and commits the cursor of the
+// last batch which is processed completely together with all before it.
+func (lc *logConsumer) completeTask(pending *pendingBatch) {
+	lc.pendingLock.Lock()
+	defer lc.pendingLock.Unlock()
+
+	pending.remaining--
+	var cursor string
+	for len(lc.pending) > 0 && lc.pending[0].remaining == 0 {
+		cursor = lc.pending[0].cursor
+		lc.pending = lc.pending[1:]
+	}
+	if cursor != "" {
+		lc.commit(lc.ctx, cursor)
+	}
+}
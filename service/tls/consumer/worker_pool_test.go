@@ -0,0 +1,152 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/volcengine/volc-sdk-golang/service/tls/pb"
+)
+
+type keyedProcessor struct {
+	ProcessFunc
+
+	lock      sync.Mutex
+	processed map[string][]string
+}
+
+func (p *keyedProcessor) values(key string) []string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return append([]string(nil), p.processed[key]...)
+}
+
+func TestProcessWorkers(t *testing.T) {
+	server := newFakeServer(map[int][]*pb.LogGroupList{0: {
+		newTestLogGroupList("a1", "b1"),
+		newTestLogGroupList("a2", "b2"),
+		newTestLogGroupList("a3", "b3"),
+	}})
+	defer server.Close()
+
+	release := make(chan struct{})
+	processor := &keyedProcessor{processed: make(map[string][]string)}
+	processor.ProcessFunc = func(ctx context.Context, batch *Batch) error {
+		if batch.Key == "b" {
+			select {
+			case <-release:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		processor.lock.Lock()
+		defer processor.lock.Unlock()
+		for _, group := range batch.Logs.LogGroups {
+			for _, log := range group.Logs {
+				processor.processed[batch.Key] = append(processor.processed[batch.Key], log.Contents[0].Value)
+			}
+		}
+		return nil
+	}
+
+	conf := newTestConfig(server.URL)
+	conf.FlushCheckpointIntervalSecond = 0
+	conf.ProcessWorkers = 2
+	conf.ProcessKey = func(group *pb.LogGroup, log *pb.Log) string {
+		return log.Contents[0].Value[:1]
+	}
+	c, err := NewConsumerWithProcessor(context.Background(), conf, processor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Stop()
+
+	// Key a is processed while key b blocks, the checkpoint waits for b.
+	if !waitFor(func() bool { return len(processor.values("a")) == 3 }) {
+		t.Fatalf("key a not processed, got %v", processor.values("a"))
+	}
+	if got := processor.values("a"); !reflect.DeepEqual(got, []string{"a1", "a2", "a3"}) {
+		t.Fatalf("key a out of order %v", got)
+	}
+	if checkpoint := server.checkpoint(0); checkpoint != "" {
+		t.Fatalf("checkpoint %q committed before key b was processed", checkpoint)
+	}
+
+	close(release)
+	if !waitFor(func() bool { return server.checkpoint(0) == "3" }) {
+		t.Fatalf("checkpoint not committed, got %q", server.checkpoint(0))
+	}
+	if got := processor.values("b"); !reflect.DeepEqual(got, []string{"b1", "b2", "b3"}) {
+		t.Fatalf("key b out of order %v", got)
+	}
+}
+
+func TestProcessWorkersRetry(t *testing.T) {
+	server := newFakeServer(map[int][]*pb.LogGroupList{0: {
+		newTestLogGroupList("b1", "a1"),
+		newTestLogGroupList("a2", "b2"),
+		newTestLogGroupList("b3", "a3"),
+	}})
+	defer server.Close()
+
+	var failing int32 = 1
+	processor := &keyedProcessor{processed: make(map[string][]string)}
+	processor.ProcessFunc = func(ctx context.Context, batch *Batch) error {
+		if batch.Key == "b" && atomic.LoadInt32(&failing) == 1 {
+			return errors.New("poison batch")
+		}
+		processor.lock.Lock()
+		defer processor.lock.Unlock()
+		for _, group := range batch.Logs.LogGroups {
+			for _, log := range group.Logs {
+				processor.processed[batch.Key] = append(processor.processed[batch.Key], log.Contents[0].Value)
+			}
+		}
+		return nil
+	}
+
+	conf := newTestConfig(server.URL)
+	conf.FlushCheckpointIntervalSecond = 0
+	conf.ProcessWorkers = 1
+	conf.ProcessKey = func(group *pb.LogGroup, log *pb.Log) string {
+		return log.Contents[0].Value[:1]
+	}
+	c, err := NewConsumerWithProcessor(context.Background(), conf, processor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Stop()
+
+	// Key b retrying does not hold the only worker, key a goes on.
+	if !waitFor(func() bool { return len(processor.values("a")) == 3 }) {
+		t.Fatalf("key a blocked by the retries of key b, got %v", processor.values("a"))
+	}
+
+	atomic.StoreInt32(&failing, 0)
+	if !waitFor(func() bool { return server.checkpoint(0) == "3" }) {
+		t.Fatalf("checkpoint not committed, got %q", server.checkpoint(0))
+	}
+	if got := processor.values("b"); !reflect.DeepEqual(got, []string{"b1", "b2", "b3"}) {
+		t.Fatalf("key b out of order %v", got)
+	}
+}
+
+func TestLogContentKey(t *testing.T) {
+	key := LogContentKey("user")
+	log := &pb.Log{Contents: []*pb.LogContent{{Key: "message", Value: "hello"}, {Key: "user", Value: "alice"}}}
+	if got := key(&pb.LogGroup{}, log); got != "alice" {
+		t.Fatalf("unexpected key %q", got)
+	}
+	if got := key(&pb.LogGroup{}, &pb.Log{}); got != "" {
+		t.Fatalf("unexpected key %q for a log without the content", got)
+	}
+}
@@ -1,6 +1,7 @@
 package tls
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"io/ioutil"
@@ -9,6 +10,10 @@ import (
 )
 
 func (c *LsClient) CreateConsumerGroup(request *CreateConsumerGroupRequest) (*CreateConsumerGroupResponse, error) {
+	return c.CreateConsumerGroupCtx(context.Background(), request)
+}
+
+func (c *LsClient) CreateConsumerGroupCtx(ctx context.Context, request *CreateConsumerGroupRequest) (*CreateConsumerGroupResponse, error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -30,7 +35,7 @@ func (c *LsClient) CreateConsumerGroup(request *CreateConsumerGroupRequest) (*Cr
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPost, PathCreateConsumerGroup, nil, reqHeaders, bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPost, PathCreateConsumerGroup, nil, reqHeaders, bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -51,6 +56,10 @@ func (c *LsClient) CreateConsumerGroup(request *CreateConsumerGroupRequest) (*Cr
 }
 
 func (c *LsClient) DeleteConsumerGroup(request *DeleteConsumerGroupRequest) (*CommonResponse, error) {
+	return c.DeleteConsumerGroupCtx(context.Background(), request)
+}
+
+func (c *LsClient) DeleteConsumerGroupCtx(ctx context.Context, request *DeleteConsumerGroupRequest) (*CommonResponse, error) {
 	reqHeaders := map[string]string{
 		"Content-Type": "application/json",
 	}
@@ -65,7 +74,7 @@ func (c *LsClient) DeleteConsumerGroup(request *DeleteConsumerGroupRequest) (*Co
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodDelete, PathDeleteConsumerGroup, nil, reqHeaders, jsonBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodDelete, PathDeleteConsumerGroup, nil, reqHeaders, jsonBody)
 	if err != nil {
 		return nil, err
 	}
@@ -86,6 +95,10 @@ func (c *LsClient) DeleteConsumerGroup(request *DeleteConsumerGroupRequest) (*Co
 }
 
 func (c *LsClient) DescribeConsumerGroups(request *DescribeConsumerGroupsRequest) (*DescribeConsumerGroupsResponse, error) {
+	return c.DescribeConsumerGroupsCtx(context.Background(), request)
+}
+
+func (c *LsClient) DescribeConsumerGroupsCtx(ctx context.Context, request *DescribeConsumerGroupsRequest) (*DescribeConsumerGroupsResponse, error) {
 	reqHeaders := map[string]string{
 		"Content-Type": "application/json",
 	}
@@ -121,7 +134,7 @@ func (c *LsClient) DescribeConsumerGroups(request *DescribeConsumerGroupsRequest
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodGet, PathDescribeConsumerGroups, params, reqHeaders, bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodGet, PathDescribeConsumerGroups, params, reqHeaders, bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -143,6 +156,10 @@ func (c *LsClient) DescribeConsumerGroups(request *DescribeConsumerGroupsRequest
 }
 
 func (c *LsClient) ModifyConsumerGroup(request *ModifyConsumerGroupRequest) (*CommonResponse, error) {
+	return c.ModifyConsumerGroupCtx(context.Background(), request)
+}
+
+func (c *LsClient) ModifyConsumerGroupCtx(ctx context.Context, request *ModifyConsumerGroupRequest) (*CommonResponse, error) {
 	reqHeaders := map[string]string{
 		"Content-Type": "application/json",
 	}
@@ -169,7 +186,7 @@ func (c *LsClient) ModifyConsumerGroup(request *ModifyConsumerGroupRequest) (*Co
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPut, PathModifyConsumerGroup, nil, reqHeaders, jsonBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPut, PathModifyConsumerGroup, nil, reqHeaders, jsonBody)
 	if err != nil {
 		return nil, err
 	}
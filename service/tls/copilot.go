@@ -1,21 +1,24 @@
 package tls
 
 import (
-	"bufio"
-	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"io/ioutil"
 	"net/http"
 	"strconv"
-	"strings"
 	"sync"
+
+	"github.com/volcengine/volc-sdk-golang/base/sse"
 )
 
 // CreateAppInstance 创建应用实例，返回应用实例ID
 func (c *LsClient) CreateAppInstance(request *CreateAppInstanceReq) (*CreateAppInstanceResp, error) {
+	return c.CreateAppInstanceCtx(context.Background(), request)
+}
+
+func (c *LsClient) CreateAppInstanceCtx(ctx context.Context, request *CreateAppInstanceReq) (*CreateAppInstanceResp, error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -31,7 +34,7 @@ func (c *LsClient) CreateAppInstance(request *CreateAppInstanceReq) (*CreateAppI
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPost, PathCreateAppInstance, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPost, PathCreateAppInstance, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -52,6 +55,10 @@ func (c *LsClient) CreateAppInstance(request *CreateAppInstanceReq) (*CreateAppI
 }
 
 func (c *LsClient) DescribeAppInstances(request *DescribeAppInstancesReq) (*DescribeAppInstancesResp, error) {
+	return c.DescribeAppInstancesCtx(context.Background(), request)
+}
+
+func (c *LsClient) DescribeAppInstancesCtx(ctx context.Context, request *DescribeAppInstancesReq) (*DescribeAppInstancesResp, error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -74,7 +81,7 @@ func (c *LsClient) DescribeAppInstances(request *DescribeAppInstancesReq) (*Desc
 		params["InstanceType"] = string(*request.InstanceType)
 	}
 
-	rawResponse, err := c.Request(http.MethodGet, PathDescribeAppInstances, params, c.assembleHeader(request.CommonRequest, reqHeaders), nil)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodGet, PathDescribeAppInstances, params, c.assembleHeader(request.CommonRequest, reqHeaders), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -95,6 +102,10 @@ func (c *LsClient) DescribeAppInstances(request *DescribeAppInstancesReq) (*Desc
 }
 
 func (c *LsClient) DeleteAppInstance(request *DeleteAppInstanceReq) (*DeleteAppInstanceResp, error) {
+	return c.DeleteAppInstanceCtx(context.Background(), request)
+}
+
+func (c *LsClient) DeleteAppInstanceCtx(ctx context.Context, request *DeleteAppInstanceReq) (*DeleteAppInstanceResp, error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -110,7 +121,7 @@ func (c *LsClient) DeleteAppInstance(request *DeleteAppInstanceReq) (*DeleteAppI
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodDelete, PathDeleteAppInstance, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodDelete, PathDeleteAppInstance, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -131,6 +142,10 @@ func (c *LsClient) DeleteAppInstance(request *DeleteAppInstanceReq) (*DeleteAppI
 }
 
 func (c *LsClient) CreateAppSceneMeta(request *CreateAppSceneMetaReq) (*CreateAppSceneMetaResp, error) {
+	return c.CreateAppSceneMetaCtx(context.Background(), request)
+}
+
+func (c *LsClient) CreateAppSceneMetaCtx(ctx context.Context, request *CreateAppSceneMetaReq) (*CreateAppSceneMetaResp, error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -146,7 +161,7 @@ func (c *LsClient) CreateAppSceneMeta(request *CreateAppSceneMetaReq) (*CreateAp
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPost, PathCreateAppSceneMeta, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPost, PathCreateAppSceneMeta, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -167,6 +182,10 @@ func (c *LsClient) CreateAppSceneMeta(request *CreateAppSceneMetaReq) (*CreateAp
 }
 
 func (c *LsClient) DescribeAppSceneMetas(request *DescribeAppSceneMetasReq) (*DescribeAppSceneMetasResp, error) {
+	return c.DescribeAppSceneMetasCtx(context.Background(), request)
+}
+
+func (c *LsClient) DescribeAppSceneMetasCtx(ctx context.Context, request *DescribeAppSceneMetasReq) (*DescribeAppSceneMetasResp, error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -196,7 +215,7 @@ func (c *LsClient) DescribeAppSceneMetas(request *DescribeAppSceneMetasReq) (*De
 		params["PageContext"] = *request.PageContext
 	}
 
-	rawResponse, err := c.Request(http.MethodGet, PathDescribeAppSceneMetas, params, c.assembleHeader(request.CommonRequest, reqHeaders), nil)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodGet, PathDescribeAppSceneMetas, params, c.assembleHeader(request.CommonRequest, reqHeaders), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -217,6 +236,10 @@ func (c *LsClient) DescribeAppSceneMetas(request *DescribeAppSceneMetasReq) (*De
 }
 
 func (c *LsClient) ModifyAppSceneMeta(request *ModifyAppSceneMetaReq) (*ModifyAppSceneMetaResp, error) {
+	return c.ModifyAppSceneMetaCtx(context.Background(), request)
+}
+
+func (c *LsClient) ModifyAppSceneMetaCtx(ctx context.Context, request *ModifyAppSceneMetaReq) (*ModifyAppSceneMetaResp, error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -232,7 +255,7 @@ func (c *LsClient) ModifyAppSceneMeta(request *ModifyAppSceneMetaReq) (*ModifyAp
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPut, PathModifyAppSceneMeta, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPut, PathModifyAppSceneMeta, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -253,6 +276,10 @@ func (c *LsClient) ModifyAppSceneMeta(request *ModifyAppSceneMetaReq) (*ModifyAp
 }
 
 func (c *LsClient) DeleteAppSceneMeta(request *DeleteAppSceneMetaReq) (*DeleteAppSceneMetaResp, error) {
+	return c.DeleteAppSceneMetaCtx(context.Background(), request)
+}
+
+func (c *LsClient) DeleteAppSceneMetaCtx(ctx context.Context, request *DeleteAppSceneMetaReq) (*DeleteAppSceneMetaResp, error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -268,7 +295,7 @@ func (c *LsClient) DeleteAppSceneMeta(request *DeleteAppSceneMetaReq) (*DeleteAp
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodDelete, PathDeleteAppSceneMeta, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodDelete, PathDeleteAppSceneMeta, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -289,6 +316,10 @@ func (c *LsClient) DeleteAppSceneMeta(request *DeleteAppSceneMetaReq) (*DeleteAp
 }
 
 func (c *LsClient) DescribeSessionAnswer(request *DescribeSessionAnswerReq) (reader *CopilotSSEReader, err error) {
+	return c.DescribeSessionAnswerCtx(context.Background(), request)
+}
+
+func (c *LsClient) DescribeSessionAnswerCtx(ctx context.Context, request *DescribeSessionAnswerReq) (reader *CopilotSSEReader, err error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -303,7 +334,7 @@ func (c *LsClient) DescribeSessionAnswer(request *DescribeSessionAnswerReq) (rea
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPost, PathDescribeSessionAnswer, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPost, PathDescribeSessionAnswer, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -316,16 +347,14 @@ func (c *LsClient) DescribeSessionAnswer(request *DescribeSessionAnswerReq) (rea
 	}
 
 	return &CopilotSSEReader{
-		reader:   bufio.NewReader(rawResponse.Body),
+		decoder:  sse.NewDecoder(rawResponse.Body, 0),
 		response: rawResponse,
-		finished: false,
 	}, nil
 }
 
 type CopilotSSEReader struct {
-	reader   *bufio.Reader
+	decoder  *sse.Decoder
 	response *http.Response
-	finished bool
 
 	once sync.Once
 }
@@ -340,53 +369,24 @@ func (r *CopilotSSEReader) ReadEvent() (_ *CopilotAnswer, err error) {
 		}
 	}()
 
-	if r.finished {
-		return nil, io.EOF
-	}
-
-	var event string
-	var dataBuffer bytes.Buffer
-
 	for {
-		line, err1 := r.reader.ReadBytes('\n')
-		if err1 != nil {
-			if errors.Is(err1, io.EOF) {
-				// 处理流结束时的逻辑
-				r.finished = true
-				return nil, err1
-			}
-			return nil, err1
-		}
-		line = bytes.TrimSpace(line)
-
-		if len(line) == 0 {
-			continue
-		}
-
-		if bytes.HasPrefix(line, []byte("event:")) {
-			event = strings.TrimSpace(string(line[6:]))
-		} else if bytes.HasPrefix(line, []byte("data:")) {
-			if dataBuffer.Len() > 0 {
-				dataBuffer.WriteByte('\n')
-			}
-			dataBuffer.Write(bytes.TrimSpace(line[5:]))
+		event, err := r.decoder.Next()
+		if err != nil {
+			return nil, err
 		}
-
-		if event == "" || dataBuffer.Len() == 0 {
+		if event.Type == "" || len(event.Data) == 0 {
 			continue
 		}
 
 		// 解析 JSON
 		var describeRsp DescribeSessionAnswerResp
-		if err := json.Unmarshal(dataBuffer.Bytes(), &describeRsp); err != nil {
+		if err := json.Unmarshal(event.Data, &describeRsp); err != nil {
 			return nil, err
 		}
 
 		switch describeRsp.ConversationMessageType {
 		case CopilotProgress:
 			// 忽略进度消息
-			dataBuffer.Reset()
-			event = ""
 			continue
 		case CopilotMessage:
 			copilotAnswer := &CopilotAnswer{
@@ -412,7 +412,7 @@ func (r *CopilotSSEReader) ReadEvent() (_ *CopilotAnswer, err error) {
 			if describeRsp.Message != nil {
 				return nil, fmt.Errorf("error occured, errorCode: %s, errorDetail: %s", describeRsp.Message.MessageId, describeRsp.Message.Answer)
 			} else {
-				return nil, fmt.Errorf("error occured, originRsp: %s", dataBuffer.Bytes())
+				return nil, fmt.Errorf("error occured, originRsp: %s", event.Data)
 			}
 		}
 	}
@@ -6,6 +6,7 @@ import (
 	"github.com/google/uuid"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
@@ -634,3 +635,26 @@ func (suite *SessionAnswerTestSuite) TestDescribeSessionAnswerAbnormally() {
 		}
 	}
 }
+
+func TestCopilotSSEReader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, ": keep-alive\n\n"+
+			"event: message\ndata: {\"ConversationMessageType\": \"progress\"}\n\n"+
+			"event: message\r\ndata: {\"ConversationMessageType\": \"message\",\r\ndata: \"Message\": {\"Answer\": \"hello\"}}\r\n\r\n"+
+			"event: message\ndata: {\"ConversationMessageType\": \"error\", \"Message\": {\"MessageId\": \"InternalError\"}}\n\n")
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "ak", "sk", "", "cn-beijing")
+	reader, err := client.DescribeSessionAnswer(&DescribeSessionAnswerReq{InstanceId: "instance", TopicId: "topic", SessionId: "session", Question: "hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	answer, err := reader.ReadEvent()
+	if err != nil || answer.ModelAnswer == nil || answer.ModelAnswer.Answer != "hello" {
+		t.Fatalf("got answer %+v, error %v", answer, err)
+	}
+	if _, err := reader.ReadEvent(); err == nil || !strings.Contains(err.Error(), "InternalError") {
+		t.Fatalf("got error %v, want the error message", err)
+	}
+}
@@ -0,0 +1,385 @@
+package declarative
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/volcengine/volc-sdk-golang/service/tls"
+)
+
+var appliedActions = map[Action]string{ActionCreate: "created", ActionModify: "modified", ActionDelete: "deleted"}
+
+// Apply makes the changes of plan in order and stops at the first one which
+// fails. With Options.DryRun it only reports them.
+func Apply(ctx context.Context, client tls.Client, plan *Plan, opts *Options) error {
+	if opts == nil {
+		opts = &Options{}
+	}
+	a := &applier{
+		ctx:        ctx,
+		client:     client,
+		region:     plan.region,
+		projectIDs: make(map[string]string, len(plan.projectIDs)),
+		topicIDs:   make(map[string]string, len(plan.topicIDs)),
+	}
+	for name, id := range plan.projectIDs {
+		a.projectIDs[name] = id
+	}
+	for address, id := range plan.topicIDs {
+		a.topicIDs[address] = id
+	}
+
+	for _, change := range plan.Changes {
+		if opts.DryRun {
+			a.report(opts, "would %s %s %s", change.Action, change.Kind, change.Address)
+			continue
+		}
+		id, err := a.apply(change)
+		if err != nil {
+			return fmt.Errorf("%s %s %s: %w", change.Action, change.Kind, change.Address, err)
+		}
+		a.report(opts, "%s %s %s %s", appliedActions[change.Action], change.Kind, change.Address, id)
+	}
+
+	return nil
+}
+
+type applier struct {
+	ctx        context.Context
+	client     tls.Client
+	region     string
+	projectIDs map[string]string
+	topicIDs   map[string]string
+}
+
+func (a *applier) report(opts *Options, format string, args ...interface{}) {
+	if opts.Output != nil {
+		fmt.Fprintf(opts.Output, format+"\n", args...)
+	}
+}
+
+// apply makes a change and returns the id of the resource.
+func (a *applier) apply(change *Change) (string, error) {
+	switch change.Kind {
+	case KindProject:
+		return a.applyProject(change)
+	case KindTopic:
+		return a.applyTopic(change)
+	case KindIndex:
+		return a.applyIndex(change)
+	case KindRule:
+		return a.applyRule(change)
+	case KindAlarm:
+		return a.applyAlarm(change)
+	}
+
+	return "", errors.New("unknown kind " + string(change.Kind))
+}
+
+// projectID returns the id of the project of a change, which was created
+// before it if the plan creates it.
+func (a *applier) projectID(change *Change) (string, error) {
+	id, ok := a.projectIDs[change.project.ProjectName]
+	if !ok {
+		return "", errors.New("project " + change.project.ProjectName + " does not exist")
+	}
+
+	return id, nil
+}
+
+func (a *applier) topicID(project *Project, topicName string) (string, error) {
+	address := project.ProjectName + "/" + topicName
+	id, ok := a.topicIDs[address]
+	if !ok {
+		return "", errors.New("topic " + address + " does not exist")
+	}
+
+	return id, nil
+}
+
+func (a *applier) applyProject(change *Change) (string, error) {
+	project := change.project
+	switch change.Action {
+	case ActionCreate:
+		resp, err := a.client.CreateProjectCtx(a.ctx, &tls.CreateProjectRequest{
+			ProjectName:    project.ProjectName,
+			Description:    project.Description,
+			Region:         a.region,
+			IamProjectName: project.IamProjectName,
+			Tags:           project.Tags,
+		})
+		if err != nil {
+			return "", err
+		}
+		a.projectIDs[project.ProjectName] = resp.ProjectID
+		return resp.ProjectID, nil
+	case ActionModify:
+		_, err := a.client.ModifyProjectCtx(a.ctx, &tls.ModifyProjectRequest{ProjectID: change.ID, Description: &project.Description})
+		return change.ID, err
+	}
+
+	return "", errors.New("projects are never deleted")
+}
+
+func (a *applier) applyTopic(change *Change) (string, error) {
+	if change.Action == ActionDelete {
+		_, err := a.client.DeleteTopicCtx(a.ctx, &tls.DeleteTopicRequest{TopicID: change.ID})
+		return change.ID, err
+	}
+
+	topic := change.topic
+	if change.Action == ActionModify {
+		req := &tls.ModifyTopicRequest{
+			TopicID:        change.ID,
+			Ttl:            &topic.Ttl,
+			AutoSplit:      topic.AutoSplit,
+			MaxSplitShard:  topic.MaxSplitShard,
+			EnableTracking: topic.EnableTracking,
+			TimeKey:        topic.TimeKey,
+			TimeFormat:     topic.TimeFormat,
+			LogPublicIP:    topic.LogPublicIP,
+		}
+		if topic.Description != "" {
+			req.Description = &topic.Description
+		}
+		_, err := a.client.ModifyTopicCtx(a.ctx, req)
+		return change.ID, err
+	}
+
+	projectID, err := a.projectID(change)
+	if err != nil {
+		return "", err
+	}
+	req := &tls.CreateTopicRequest{
+		ProjectID:      projectID,
+		TopicName:      topic.TopicName,
+		Ttl:            topic.Ttl,
+		Description:    topic.Description,
+		ShardCount:     topic.ShardCount,
+		MaxSplitShard:  topic.MaxSplitShard,
+		EnableTracking: topic.EnableTracking,
+		TimeKey:        topic.TimeKey,
+		TimeFormat:     topic.TimeFormat,
+		Tags:           topic.Tags,
+		LogPublicIP:    topic.LogPublicIP,
+	}
+	if topic.AutoSplit != nil {
+		req.AutoSplit = *topic.AutoSplit
+	}
+	resp, err := a.client.CreateTopicCtx(a.ctx, req)
+	if err != nil {
+		return "", err
+	}
+	a.topicIDs[change.Address] = resp.TopicID
+
+	return resp.TopicID, nil
+}
+
+func (a *applier) applyIndex(change *Change) (string, error) {
+	topicID, err := a.topicID(change.project, change.topic.TopicName)
+	if err != nil {
+		return "", err
+	}
+	index := change.topic.Index
+	var keyValue, userInnerKeyValue *[]tls.KeyValueInfo
+	if len(index.KeyValue) > 0 {
+		keyValue = &index.KeyValue
+	}
+	if len(index.UserInnerKeyValue) > 0 {
+		userInnerKeyValue = &index.UserInnerKeyValue
+	}
+
+	if change.Action == ActionModify {
+		_, err = a.client.ModifyIndexCtx(a.ctx, &tls.ModifyIndexRequest{TopicID: topicID, FullText: index.FullText, KeyValue: keyValue, UserInnerKeyValue: userInnerKeyValue})
+	} else {
+		_, err = a.client.CreateIndexCtx(a.ctx, &tls.CreateIndexRequest{TopicID: topicID, FullText: index.FullText, KeyValue: keyValue, UserInnerKeyValue: userInnerKeyValue})
+	}
+
+	return topicID, err
+}
+
+func (a *applier) applyRule(change *Change) (string, error) {
+	if change.Action == ActionDelete {
+		_, err := a.client.DeleteRuleCtx(a.ctx, &tls.DeleteRuleRequest{RuleID: change.ID})
+		return change.ID, err
+	}
+
+	rule := change.rule
+	var paths *[]string
+	var excludePaths *[]tls.ExcludePath
+	var logType, logSample *string
+	if rule.Paths != nil {
+		paths = &rule.Paths
+	}
+	if rule.ExcludePaths != nil {
+		excludePaths = &rule.ExcludePaths
+	}
+	if rule.LogType != "" {
+		logType = &rule.LogType
+	}
+	if rule.LogSample != "" {
+		logSample = &rule.LogSample
+	}
+
+	if change.Action == ActionModify {
+		_, err := a.client.ModifyRuleCtx(a.ctx, &tls.ModifyRuleRequest{
+			RuleID:         change.ID,
+			Paths:          paths,
+			LogType:        logType,
+			ExtractRule:    rule.ExtractRule,
+			ExcludePaths:   excludePaths,
+			UserDefineRule: rule.UserDefineRule,
+			LogSample:      logSample,
+			InputType:      rule.InputType,
+			ContainerRule:  rule.ContainerRule,
+		})
+		if err != nil {
+			return "", err
+		}
+		return change.ID, a.bindHostGroups(change.ID, change.hostGroupIDs, rule.HostGroupIDs)
+	}
+
+	topicID, err := a.topicID(change.project, change.topic.TopicName)
+	if err != nil {
+		return "", err
+	}
+	resp, err := a.client.CreateRuleCtx(a.ctx, &tls.CreateRuleRequest{
+		TopicID:        topicID,
+		RuleName:       rule.RuleName,
+		Paths:          paths,
+		LogType:        logType,
+		ExtractRule:    rule.ExtractRule,
+		ExcludePaths:   excludePaths,
+		UserDefineRule: rule.UserDefineRule,
+		LogSample:      logSample,
+		InputType:      rule.InputType,
+		ContainerRule:  rule.ContainerRule,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return resp.RuleID, a.bindHostGroups(resp.RuleID, nil, rule.HostGroupIDs)
+}
+
+// bindHostGroups binds a rule to the host groups in want and unbinds it from
+// the others in have. A nil want leaves the bindings as they are.
+func (a *applier) bindHostGroups(ruleID string, have, want []string) error {
+	if want == nil {
+		return nil
+	}
+	wanted := make(map[string]bool, len(want))
+	for _, id := range want {
+		wanted[id] = true
+	}
+	var unbind []string
+	for _, id := range have {
+		if !wanted[id] {
+			unbind = append(unbind, id)
+		}
+		delete(wanted, id)
+	}
+	var bind []string
+	for _, id := range want {
+		if wanted[id] {
+			bind = append(bind, id)
+			delete(wanted, id)
+		}
+	}
+
+	if len(bind) > 0 {
+		if _, err := a.client.ApplyRuleToHostGroupsCtx(a.ctx, &tls.ApplyRuleToHostGroupsRequest{RuleID: ruleID, HostGroupIDs: bind}); err != nil {
+			return err
+		}
+	}
+	if len(unbind) > 0 {
+		if _, err := a.client.DeleteRuleFromHostGroupsCtx(a.ctx, &tls.DeleteRuleFromHostGroupsRequest{RuleID: ruleID, HostGroupIDs: unbind}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *applier) applyAlarm(change *Change) (string, error) {
+	if change.Action == ActionDelete {
+		_, err := a.client.DeleteAlarmCtx(a.ctx, &tls.DeleteAlarmRequest{AlarmID: change.ID})
+		return change.ID, err
+	}
+
+	alarm := change.alarm
+	queries := make(tls.QueryRequests, 0, len(alarm.QueryRequest))
+	for _, query := range alarm.QueryRequest {
+		topicID, err := a.topicID(change.project, query.TopicName)
+		if err != nil {
+			return "", err
+		}
+		queries = append(queries, tls.QueryRequest{
+			Query:           query.Query,
+			Number:          query.Number,
+			TopicID:         topicID,
+			TopicName:       query.TopicName,
+			StartTimeOffset: query.StartTimeOffset,
+			EndTimeOffset:   query.EndTimeOffset,
+			TimeSpanType:    query.TimeSpanType,
+			TruncatedTime:   query.TruncatedTime,
+		})
+	}
+
+	if change.Action == ActionModify {
+		req := &tls.ModifyAlarmRequest{
+			AlarmID:            change.ID,
+			Status:             alarm.Status,
+			QueryRequest:       &queries,
+			UserDefineMsg:      alarm.UserDefineMsg,
+			Severity:           alarm.Severity,
+			AlarmPeriodDetail:  alarm.AlarmPeriodDetail,
+			JoinConfigurations: alarm.JoinConfigurations,
+			TriggerConditions:  alarm.TriggerConditions,
+		}
+		if alarm.RequestCycle != (tls.RequestCycle{}) {
+			req.RequestCycle = &alarm.RequestCycle
+		}
+		if alarm.Condition != "" {
+			req.Condition = &alarm.Condition
+		}
+		if alarm.TriggerPeriod != 0 {
+			req.TriggerPeriod = &alarm.TriggerPeriod
+		}
+		if alarm.AlarmPeriod != 0 {
+			req.AlarmPeriod = &alarm.AlarmPeriod
+		}
+		if alarm.AlarmNotifyGroup != nil {
+			req.AlarmNotifyGroup = &alarm.AlarmNotifyGroup
+		}
+		_, err := a.client.ModifyAlarmCtx(a.ctx, req)
+		return change.ID, err
+	}
+
+	projectID, err := a.projectID(change)
+	if err != nil {
+		return "", err
+	}
+	resp, err := a.client.CreateAlarmCtx(a.ctx, &tls.CreateAlarmRequest{
+		AlarmName:          alarm.AlarmName,
+		ProjectID:          projectID,
+		Status:             alarm.Status,
+		QueryRequest:       queries,
+		RequestCycle:       alarm.RequestCycle,
+		Condition:          alarm.Condition,
+		TriggerPeriod:      alarm.TriggerPeriod,
+		AlarmPeriod:        alarm.AlarmPeriod,
+		AlarmNotifyGroup:   alarm.AlarmNotifyGroup,
+		UserDefineMsg:      alarm.UserDefineMsg,
+		Severity:           alarm.Severity,
+		AlarmPeriodDetail:  alarm.AlarmPeriodDetail,
+		JoinConfigurations: alarm.JoinConfigurations,
+		TriggerConditions:  alarm.TriggerConditions,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return resp.AlarmID, nil
+}
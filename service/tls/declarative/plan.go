@@ -0,0 +1,550 @@
+package declarative
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/volcengine/volc-sdk-golang/service/tls"
+)
+
+// Action is what a change does to a resource.
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionModify Action = "modify"
+	ActionDelete Action = "delete"
+)
+
+// Kind is the type of a resource.
+type Kind string
+
+const (
+	KindProject Kind = "project"
+	KindTopic   Kind = "topic"
+	KindIndex   Kind = "index"
+	KindRule    Kind = "rule"
+	KindAlarm   Kind = "alarm"
+)
+
+var actionSymbols = map[Action]string{ActionCreate: "+", ActionModify: "~", ActionDelete: "-"}
+
+// Options configures NewPlan and Apply.
+type Options struct {
+	// Prune deletes the topics, rules and alarms of the projects of the spec
+	// which the spec does not list. Projects are never deleted, and the index
+	// of a listed topic without Index is left as is.
+	Prune bool
+	// DryRun makes Apply report the changes of the plan without making them.
+	DryRun bool
+	// Output receives a line per change made by Apply, nothing if nil.
+	Output io.Writer
+}
+
+// Change is a create, modify or delete of a resource.
+type Change struct {
+	Action Action
+	Kind   Kind
+	// Address names the resource after its parents, such as app/nginx for a
+	// topic and app/nginx/access for one of its rules. An index has the
+	// address of its topic.
+	Address string
+	// ID is the id of the resource, empty if it is created. It is the topic
+	// id for an index.
+	ID string
+	// Diffs lists the fields a modify changes as "Field: old => new", values
+	// in JSON.
+	Diffs []string
+
+	project *Project
+	topic   *Topic
+	rule    *Rule
+	alarm   *Alarm
+	// hostGroupIDs are the ids of the host groups a modified rule is bound
+	// to.
+	hostGroupIDs []string
+}
+
+func (c *Change) String() string {
+	line := actionSymbols[c.Action] + " " + string(c.Kind) + " " + c.Address
+	if c.ID != "" && c.Kind != KindIndex {
+		line += " (" + c.ID + ")"
+	}
+
+	return line
+}
+
+// Plan is the ordered changes reconciling the service with a spec. Creates
+// and modifies come first, parents before children, then deletes, children
+// before parents.
+type Plan struct {
+	Changes []*Change
+
+	region string
+	// projectIDs and topicIDs are the ids of the existing projects by name
+	// and topics by address.
+	projectIDs map[string]string
+	topicIDs   map[string]string
+}
+
+// Empty tells whether the service already matches the spec.
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// String formats the plan for review, a line per change followed by the
+// fields it modifies.
+func (p *Plan) String() string {
+	if p.Empty() {
+		return "No changes.\n"
+	}
+
+	var b strings.Builder
+	counts := make(map[Action]int)
+	for _, change := range p.Changes {
+		counts[change.Action]++
+		b.WriteString(change.String())
+		b.WriteString("\n")
+		for _, diff := range change.Diffs {
+			b.WriteString("      ")
+			b.WriteString(diff)
+			b.WriteString("\n")
+		}
+	}
+	fmt.Fprintf(&b, "\nPlan: %d to create, %d to modify, %d to delete.\n", counts[ActionCreate], counts[ActionModify], counts[ActionDelete])
+
+	return b.String()
+}
+
+// NewPlan compares spec with the resources of client and returns the changes
+// needed to make them match.
+func NewPlan(ctx context.Context, client tls.Client, spec *Spec, opts *Options) (*Plan, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	p := &planner{
+		ctx:     ctx,
+		client:  client,
+		opts:    opts,
+		deletes: make(map[Kind][]*Change),
+		plan: &Plan{
+			region:     spec.Region,
+			projectIDs: make(map[string]string),
+			topicIDs:   make(map[string]string),
+		},
+	}
+	for _, project := range spec.Projects {
+		if err := p.project(project); err != nil {
+			return nil, fmt.Errorf("project %s: %w", project.ProjectName, err)
+		}
+	}
+	for _, kind := range []Kind{KindAlarm, KindRule, KindTopic} {
+		p.plan.Changes = append(p.plan.Changes, p.deletes[kind]...)
+	}
+
+	return p.plan, nil
+}
+
+type planner struct {
+	ctx     context.Context
+	client  tls.Client
+	opts    *Options
+	plan    *Plan
+	deletes map[Kind][]*Change
+}
+
+func (p *planner) add(change *Change) {
+	if change.Action == ActionDelete {
+		p.deletes[change.Kind] = append(p.deletes[change.Kind], change)
+		return
+	}
+	p.plan.Changes = append(p.plan.Changes, change)
+}
+
+// existingProject returns the project named name, nil if there is none.
+func (p *planner) existingProject(name string) (*tls.ProjectInfo, error) {
+	projects, err := tls.DescribeAllProjects(p.ctx, p.client, &tls.DescribeProjectsRequest{ProjectName: name, IsFullName: true}, 0)
+	if err != nil {
+		return nil, err
+	}
+	var found *tls.ProjectInfo
+	for i := range projects {
+		if projects[i].ProjectName != name {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("projects %s and %s have the same name", found.ProjectID, projects[i].ProjectID)
+		}
+		found = &projects[i]
+	}
+
+	return found, nil
+}
+
+func (p *planner) project(project *Project) error {
+	have, err := p.existingProject(project.ProjectName)
+	if err != nil {
+		return err
+	}
+	if have == nil {
+		if p.plan.region == "" {
+			return errors.New("creating a project needs the Region of the spec")
+		}
+		p.add(&Change{Action: ActionCreate, Kind: KindProject, Address: project.ProjectName, project: project})
+		for _, topic := range project.Topics {
+			p.createTopic(project, topic)
+		}
+		for _, alarm := range project.Alarms {
+			if err := checkAlarmTopics(alarm, project, nil); err != nil {
+				return err
+			}
+			p.add(&Change{Action: ActionCreate, Kind: KindAlarm, Address: project.ProjectName + "/" + alarm.AlarmName, project: project, alarm: alarm})
+		}
+		return nil
+	}
+
+	p.plan.projectIDs[project.ProjectName] = have.ProjectID
+	d := &differ{}
+	d.field("Description", have.Description, project.Description)
+	p.modify(&Change{Kind: KindProject, Address: project.ProjectName, ID: have.ProjectID, project: project}, d)
+
+	topics, err := tls.DescribeAllTopics(p.ctx, p.client, &tls.DescribeTopicsRequest{ProjectID: have.ProjectID}, 0)
+	if err != nil {
+		return err
+	}
+	topicNames := make(map[string]string, len(topics))
+	for _, topic := range topics {
+		topicNames[topic.TopicID] = topic.TopicName
+		p.plan.topicIDs[project.ProjectName+"/"+topic.TopicName] = topic.TopicID
+	}
+	rules, err := tls.DescribeAllRules(p.ctx, p.client, &tls.DescribeRulesRequest{ProjectID: have.ProjectID}, 0)
+	if err != nil {
+		return err
+	}
+	alarms, err := tls.DescribeAllAlarms(p.ctx, p.client, &tls.DescribeAlarmsRequest{ProjectID: have.ProjectID}, 0)
+	if err != nil {
+		return err
+	}
+
+	wanted := make(map[string]bool)
+	for _, topic := range project.Topics {
+		wanted[topic.TopicName] = true
+		if err := p.topic(project, topic, topics, rules); err != nil {
+			return err
+		}
+	}
+	if p.opts.Prune {
+		for _, topic := range topics {
+			if !wanted[topic.TopicName] {
+				p.add(&Change{Action: ActionDelete, Kind: KindTopic, Address: project.ProjectName + "/" + topic.TopicName, ID: topic.TopicID})
+			}
+		}
+		for _, rule := range rules {
+			if !wanted[topicNames[rule.TopicID]] {
+				p.add(&Change{Action: ActionDelete, Kind: KindRule, Address: project.ProjectName + "/" + topicNames[rule.TopicID] + "/" + rule.RuleName, ID: rule.RuleID})
+			}
+		}
+	}
+
+	// Without Prune, alarms may query the topics the spec does not list.
+	existing := topicNames
+	if p.opts.Prune {
+		existing = nil
+	}
+	for _, alarm := range project.Alarms {
+		if err := checkAlarmTopics(alarm, project, existing); err != nil {
+			return err
+		}
+	}
+	p.alarms(project, alarms, topicNames)
+
+	return nil
+}
+
+func (p *planner) createTopic(project *Project, topic *Topic) {
+	address := project.ProjectName + "/" + topic.TopicName
+	p.add(&Change{Action: ActionCreate, Kind: KindTopic, Address: address, project: project, topic: topic})
+	if topic.Index != nil {
+		p.add(&Change{Action: ActionCreate, Kind: KindIndex, Address: address, project: project, topic: topic})
+	}
+	for _, rule := range topic.Rules {
+		p.add(&Change{Action: ActionCreate, Kind: KindRule, Address: address + "/" + rule.RuleName, project: project, topic: topic, rule: rule})
+	}
+}
+
+func (p *planner) topic(project *Project, topic *Topic, topics []*tls.Topic, rules []*tls.RuleInfo) error {
+	var have *tls.Topic
+	for _, t := range topics {
+		if t.TopicName == topic.TopicName {
+			have = t
+			break
+		}
+	}
+	if have == nil {
+		p.createTopic(project, topic)
+		return nil
+	}
+
+	address := project.ProjectName + "/" + topic.TopicName
+	d := &differ{}
+	d.field("Description", have.Description, topic.Description)
+	d.field("Ttl", have.Ttl, topic.Ttl)
+	d.field("AutoSplit", have.AutoSplit, topic.AutoSplit)
+	d.field("MaxSplitShard", have.MaxSplitShard, topic.MaxSplitShard)
+	d.field("EnableTracking", have.EnableTracking, topic.EnableTracking)
+	d.field("TimeKey", have.TimeKey, topic.TimeKey)
+	d.field("TimeFormat", have.TimeFormat, topic.TimeFormat)
+	d.field("LogPublicIP", have.LogPublicIP, topic.LogPublicIP)
+	p.modify(&Change{Kind: KindTopic, Address: address, ID: have.TopicID, project: project, topic: topic}, d)
+
+	if err := p.index(project, topic, have.TopicID); err != nil {
+		return err
+	}
+
+	wanted := make(map[string]bool, len(topic.Rules))
+	for _, rule := range topic.Rules {
+		wanted[rule.RuleName] = true
+		var haveRule *tls.RuleInfo
+		for _, r := range rules {
+			if r.TopicID == have.TopicID && r.RuleName == rule.RuleName {
+				haveRule = r
+				break
+			}
+		}
+		change := &Change{Kind: KindRule, Address: address + "/" + rule.RuleName, project: project, topic: topic, rule: rule}
+		if haveRule == nil {
+			change.Action = ActionCreate
+			p.add(change)
+			continue
+		}
+
+		d := &differ{}
+		d.field("Paths", haveRule.Paths, rule.Paths)
+		d.field("LogType", haveRule.LogType, rule.LogType)
+		d.field("ExtractRule", haveRule.ExtractRule, rule.ExtractRule)
+		d.field("ExcludePaths", haveRule.ExcludePaths, rule.ExcludePaths)
+		d.field("UserDefineRule", haveRule.UserDefineRule, rule.UserDefineRule)
+		d.field("LogSample", haveRule.LogSample, rule.LogSample)
+		d.field("InputType", haveRule.InputType, rule.InputType)
+		d.field("ContainerRule", haveRule.ContainerRule, rule.ContainerRule)
+		if rule.HostGroupIDs != nil {
+			groups, err := p.ruleHostGroups(haveRule.RuleID)
+			if err != nil {
+				return err
+			}
+			d.exact("HostGroupIDs", groups, sortedStrings(rule.HostGroupIDs))
+			change.hostGroupIDs = groups
+		}
+		change.ID = haveRule.RuleID
+		p.modify(change, d)
+	}
+	if p.opts.Prune {
+		for _, r := range rules {
+			if r.TopicID == have.TopicID && !wanted[r.RuleName] {
+				p.add(&Change{Action: ActionDelete, Kind: KindRule, Address: address + "/" + r.RuleName, ID: r.RuleID})
+			}
+		}
+	}
+
+	return nil
+}
+
+// ruleHostGroups returns the sorted ids of the host groups of a rule.
+func (p *planner) ruleHostGroups(ruleID string) ([]string, error) {
+	resp, err := p.client.DescribeRuleCtx(p.ctx, &tls.DescribeRuleRequest{RuleID: ruleID})
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]string, 0, len(resp.HostGroupInfos))
+	for _, group := range resp.HostGroupInfos {
+		groups = append(groups, group.HostGroupID)
+	}
+
+	return sortedStrings(groups), nil
+}
+
+func (p *planner) index(project *Project, topic *Topic, topicID string) error {
+	if topic.Index == nil {
+		return nil
+	}
+
+	have, err := p.client.DescribeIndexCtx(p.ctx, &tls.DescribeIndexRequest{TopicID: topicID})
+	if err != nil {
+		if tls.NewClientError(err).Code != tls.ErrIndexNotExists {
+			return err
+		}
+		have = nil
+	}
+
+	change := &Change{Kind: KindIndex, Address: project.ProjectName + "/" + topic.TopicName, ID: topicID, project: project, topic: topic}
+	if have == nil {
+		change.Action = ActionCreate
+		p.add(change)
+		return nil
+	}
+	d := &differ{}
+	d.exact("FullText", have.FullText, topic.Index.FullText)
+	d.exact("KeyValue", have.KeyValue, topic.Index.KeyValue)
+	d.exact("UserInnerKeyValue", have.UserInnerKeyValue, topic.Index.UserInnerKeyValue)
+	p.modify(change, d)
+
+	return nil
+}
+
+func (p *planner) alarms(project *Project, alarms []tls.QueryResp, topicNames map[string]string) {
+	wanted := make(map[string]bool, len(project.Alarms))
+	for _, alarm := range project.Alarms {
+		wanted[alarm.AlarmName] = true
+		change := &Change{Kind: KindAlarm, Address: project.ProjectName + "/" + alarm.AlarmName, project: project, alarm: alarm}
+		var have *tls.QueryResp
+		for i := range alarms {
+			if alarms[i].AlarmName == alarm.AlarmName {
+				have = &alarms[i]
+				break
+			}
+		}
+		if have == nil {
+			change.Action = ActionCreate
+			p.add(change)
+			continue
+		}
+
+		queries := make([]AlarmQuery, 0, len(have.QueryRequest))
+		for _, query := range have.QueryRequest {
+			name, ok := topicNames[query.TopicID]
+			if !ok {
+				name = query.TopicName
+			}
+			queries = append(queries, AlarmQuery{
+				TopicName:       name,
+				Query:           query.Query,
+				Number:          query.Number,
+				StartTimeOffset: query.StartTimeOffset,
+				EndTimeOffset:   query.EndTimeOffset,
+				TimeSpanType:    query.TimeSpanType,
+				TruncatedTime:   query.TruncatedTime,
+			})
+		}
+		groups := make([]string, 0, len(have.AlarmNotifyGroup))
+		for _, group := range have.AlarmNotifyGroup {
+			groups = append(groups, group.NotifyGroupID)
+		}
+
+		d := &differ{}
+		d.field("Status", have.Status, alarm.Status)
+		d.field("QueryRequest", queries, alarm.QueryRequest)
+		d.field("RequestCycle", have.RequestCycle, alarm.RequestCycle)
+		d.field("Condition", have.Condition, alarm.Condition)
+		d.field("TriggerPeriod", have.TriggerPeriod, alarm.TriggerPeriod)
+		d.field("AlarmPeriod", have.AlarmPeriod, alarm.AlarmPeriod)
+		d.field("AlarmNotifyGroup", groups, alarm.AlarmNotifyGroup)
+		d.field("UserDefineMsg", have.UserDefineMsg, alarm.UserDefineMsg)
+		d.field("Severity", have.Severity, alarm.Severity)
+		d.field("AlarmPeriodDetail", have.AlarmPeriodDetail, alarm.AlarmPeriodDetail)
+		d.field("JoinConfigurations", have.JoinConfigurations, alarm.JoinConfigurations)
+		d.field("TriggerConditions", have.TriggerConditions, alarm.TriggerConditions)
+		change.ID = have.AlarmID
+		p.modify(change, d)
+	}
+	if p.opts.Prune {
+		for _, alarm := range alarms {
+			if !wanted[alarm.AlarmName] {
+				p.add(&Change{Action: ActionDelete, Kind: KindAlarm, Address: project.ProjectName + "/" + alarm.AlarmName, ID: alarm.AlarmID})
+			}
+		}
+	}
+}
+
+// modify adds change as a modify if d found differences.
+func (p *planner) modify(change *Change, d *differ) {
+	if len(d.diffs) == 0 {
+		return
+	}
+	change.Action = ActionModify
+	change.Diffs = d.diffs
+	p.add(change)
+}
+
+// checkAlarmTopics checks that the topics queried by an alarm are in the spec
+// of its project or among the existing topics, keyed by id.
+func checkAlarmTopics(alarm *Alarm, project *Project, existing map[string]string) error {
+	for _, query := range alarm.QueryRequest {
+		if projectTopic(project, query.TopicName) != nil {
+			continue
+		}
+		found := false
+		for _, name := range existing {
+			if name == query.TopicName {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("alarm %s queries unknown topic %s", alarm.AlarmName, query.TopicName)
+		}
+	}
+
+	return nil
+}
+
+func projectTopic(project *Project, name string) *Topic {
+	for _, topic := range project.Topics {
+		if topic.TopicName == name {
+			return topic
+		}
+	}
+
+	return nil
+}
+
+// differ collects the differences between the fields of a resource and its
+// spec.
+type differ struct {
+	diffs []string
+}
+
+// field records a difference unless want is the zero value, a field left out
+// of the spec. Pointers are compared by the values they point to.
+func (d *differ) field(name string, have, want interface{}) {
+	if v := reflect.ValueOf(want); !v.IsValid() || v.IsZero() {
+		return
+	}
+	d.exact(name, have, want)
+}
+
+// exact records a difference between have and want, JSON null, empty arrays
+// and empty objects being the same.
+func (d *differ) exact(name string, have, want interface{}) {
+	haveText, wantText := jsonText(have), jsonText(want)
+	if haveText == wantText || isEmptyJSON(haveText) && isEmptyJSON(wantText) {
+		return
+	}
+	d.diffs = append(d.diffs, name+": "+haveText+" => "+wantText)
+}
+
+// sortedStrings returns a sorted copy of values.
+func sortedStrings(values []string) []string {
+	sorted := append([]string{}, values...)
+	sort.Strings(sorted)
+
+	return sorted
+}
+
+func jsonText(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(data)
+}
+
+func isEmptyJSON(text string) bool {
+	return text == "null" || text == "[]" || text == "{}"
+}
@@ -0,0 +1,238 @@
+package declarative
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/volcengine/volc-sdk-golang/service/tls"
+	"github.com/volcengine/volc-sdk-golang/service/tls/tlstest"
+)
+
+// hostGroups returns the ids of the host groups of a rule.
+func hostGroups(t *testing.T, client tls.Client, ruleID string) []string {
+	resp, err := client.DescribeRule(&tls.DescribeRuleRequest{RuleID: ruleID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, group := range resp.HostGroupInfos {
+		ids = append(ids, group.HostGroupID)
+	}
+	return ids
+}
+
+// summary lists the changes of a plan as "action kind address".
+func summary(plan *Plan) []string {
+	var lines []string
+	for _, change := range plan.Changes {
+		lines = append(lines, string(change.Action)+" "+string(change.Kind)+" "+change.Address)
+	}
+	return lines
+}
+
+func TestPlanApply(t *testing.T) {
+	server := tlstest.NewServer()
+	defer server.Close()
+	client := server.NewClient()
+	ctx := context.Background()
+
+	spec, err := Load([]byte(testSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, err := NewPlan(ctx, client, spec, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"create project app",
+		"create topic app/nginx",
+		"create index app/nginx",
+		"create rule app/nginx/access",
+		"create alarm app/errors",
+	}
+	if got := summary(plan); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got plan %v, want %v", got, want)
+	}
+	if !strings.HasSuffix(plan.String(), "Plan: 5 to create, 0 to modify, 0 to delete.\n") {
+		t.Fatalf("got plan\n%s", plan)
+	}
+
+	// A dry run only reports the changes.
+	var out bytes.Buffer
+	if err := Apply(ctx, client, plan, &Options{DryRun: true, Output: &out}); err != nil {
+		t.Fatal(err)
+	}
+	if server.Requests(tls.PathCreateProject) != 0 || !strings.Contains(out.String(), "would create topic app/nginx\n") {
+		t.Fatalf("dry run made changes or reported\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := Apply(ctx, client, plan, &Options{Output: &out}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "created rule app/nginx/access rule-") {
+		t.Fatalf("got output\n%s", out.String())
+	}
+	plan, err = NewPlan(ctx, client, spec, &Options{Prune: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.Empty() {
+		t.Fatalf("applied spec still has changes\n%s", plan)
+	}
+
+	// Resources changed or added outside of the spec.
+	projects, err := tls.DescribeAllProjects(ctx, client, &tls.DescribeProjectsRequest{ProjectName: "app", IsFullName: true}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CreateTopic(&tls.CreateTopicRequest{ProjectID: projects[0].ProjectID, TopicName: "legacy", Ttl: 1, ShardCount: 1}); err != nil {
+		t.Fatal(err)
+	}
+	nginx := spec.Projects[0].Topics[0]
+	nginx.Ttl = 7
+	nginx.Index.FullText.CaseSensitive = true
+	nginx.Rules = nil
+	spec.Projects[0].Topics = append(spec.Projects[0].Topics, &Topic{TopicName: "api", Ttl: 3, ShardCount: 1})
+
+	plan, err = NewPlan(ctx, client, spec, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []string{
+		"modify topic app/nginx",
+		"modify index app/nginx",
+		"create topic app/api",
+	}
+	if got := summary(plan); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got plan %v, want %v", got, want)
+	}
+	if diffs := plan.Changes[0].Diffs; !reflect.DeepEqual(diffs, []string{"Ttl: 30 => 7"}) {
+		t.Fatalf("got diffs %v", diffs)
+	}
+
+	plan, err = NewPlan(ctx, client, spec, &Options{Prune: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = append(want, "delete rule app/nginx/access", "delete topic app/legacy")
+	if got := summary(plan); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got pruning plan %v, want %v", got, want)
+	}
+	if err := Apply(ctx, client, plan, nil); err != nil {
+		t.Fatal(err)
+	}
+	plan, err = NewPlan(ctx, client, spec, &Options{Prune: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.Empty() {
+		t.Fatalf("applied spec still has changes\n%s", plan)
+	}
+
+	// A listed topic without Index keeps its index, even with Prune.
+	nginx.Index = nil
+	plan, err = NewPlan(ctx, client, spec, &Options{Prune: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.Empty() {
+		t.Fatalf("got plan for a topic without Index\n%s", plan)
+	}
+	if _, err := client.DescribeIndex(&tls.DescribeIndexRequest{TopicID: plan.topicIDs["app/nginx"]}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPlanRuleHostGroups(t *testing.T) {
+	server := tlstest.NewServer()
+	defer server.Close()
+	client := server.NewClient()
+	ctx := context.Background()
+
+	spec, err := Load([]byte(testSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, err := NewPlan(ctx, client, spec, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Apply(ctx, client, plan, nil); err != nil {
+		t.Fatal(err)
+	}
+	plan, err = NewPlan(ctx, client, spec, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.Empty() {
+		t.Fatalf("applied spec still has changes\n%s", plan)
+	}
+	rules, err := tls.DescribeAllRules(ctx, client, &tls.DescribeRulesRequest{ProjectID: plan.projectIDs["app"]}, 0)
+	if err != nil || len(rules) != 1 {
+		t.Fatalf("got rules %v, error %v", rules, err)
+	}
+	ruleID := rules[0].RuleID
+	if got := hostGroups(t, client, ruleID); !reflect.DeepEqual(got, []string{"hostgroup-1"}) {
+		t.Fatalf("created rule has host groups %v", got)
+	}
+
+	rule := spec.Projects[0].Topics[0].Rules[0]
+	rule.HostGroupIDs = []string{"hostgroup-3", "hostgroup-2"}
+	plan, err = NewPlan(ctx, client, spec, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := summary(plan); !reflect.DeepEqual(got, []string{"modify rule app/nginx/access"}) {
+		t.Fatalf("got plan %v", got)
+	}
+	if diffs := plan.Changes[0].Diffs; !reflect.DeepEqual(diffs, []string{`HostGroupIDs: ["hostgroup-1"] => ["hostgroup-2","hostgroup-3"]`}) {
+		t.Fatalf("got diffs %v", diffs)
+	}
+	if err := Apply(ctx, client, plan, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := hostGroups(t, client, ruleID); !reflect.DeepEqual(got, []string{"hostgroup-2", "hostgroup-3"}) {
+		t.Fatalf("modified rule has host groups %v", got)
+	}
+
+	// Without HostGroupIDs the bindings are left as they are.
+	rule.HostGroupIDs = nil
+	plan, err = NewPlan(ctx, client, spec, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.Empty() {
+		t.Fatalf("got plan for a rule without HostGroupIDs\n%s", plan)
+	}
+}
+
+func TestPlanErrors(t *testing.T) {
+	server := tlstest.NewServer()
+	defer server.Close()
+	client := server.NewClient()
+
+	spec, err := Load([]byte(testSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec.Region = ""
+	if _, err := NewPlan(context.Background(), client, spec, nil); err == nil || !strings.Contains(err.Error(), "Region") {
+		t.Fatalf("got error %v for a project without region", err)
+	}
+
+	spec.Region = tlstest.Region
+	spec.Projects[0].Alarms[0].QueryRequest[0].TopicName = "missing"
+	if _, err := NewPlan(context.Background(), client, spec, nil); err == nil || !strings.Contains(err.Error(), "unknown topic missing") {
+		t.Fatalf("got error %v for an alarm of a missing topic", err)
+	}
+
+	server.InjectFault(tlstest.Fault{Path: tls.PathDescribeProjects, HTTPCode: 400, ErrorCode: tls.ErrInvalidParam})
+	if _, err := NewPlan(context.Background(), client, spec, nil); err == nil {
+		t.Fatal("planned without describing the projects")
+	}
+}
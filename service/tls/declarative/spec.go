@@ -0,0 +1,254 @@
+// Package declarative reconciles TLS resources with a spec: the projects,
+// topics, indexes, rules and alarms the spec lists are compared with the ones
+// of the service, and the differences are turned into a plan of creates,
+// modifies and deletes which can be reviewed, then applied.
+//
+//	spec, err := declarative.LoadFile("tls.yaml")
+//	plan, err := declarative.NewPlan(ctx, client, spec, opts)
+//	fmt.Print(plan)
+//	err = declarative.Apply(ctx, client, plan, opts)
+//
+// Resources are matched by name. Fields left out of the spec are not managed,
+// except for indexes which are managed as a whole.
+package declarative
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/volcengine/volc-sdk-golang/service/tls"
+)
+
+// Spec is the desired state of some projects. Its fields are named as in the
+// TLS API, in YAML or JSON:
+//
+//	Region: cn-beijing
+//	Projects:
+//	  - ProjectName: app
+//	    Topics:
+//	      - TopicName: nginx
+//	        Ttl: 30
+//	        ShardCount: 2
+//	        Index:
+//	          FullText: {Delimiter: ", ", CaseSensitive: false}
+type Spec struct {
+	// Region is the region of the projects to create.
+	Region   string
+	Projects []*Project
+}
+
+// Project is a project and the topics and alarms it holds.
+type Project struct {
+	ProjectName string
+	Description string
+	// IamProjectName and Tags are set when the project is created only.
+	IamProjectName *string
+	Tags           []tls.TagInfo
+	Topics         []*Topic
+	Alarms         []*Alarm
+}
+
+// Topic is a topic, its index and its rules.
+type Topic struct {
+	TopicName   string
+	Description string
+	Ttl         uint16
+	// ShardCount and Tags are set when the topic is created only.
+	ShardCount     int
+	Tags           []tls.TagInfo
+	AutoSplit      *bool
+	MaxSplitShard  *int32
+	EnableTracking *bool
+	TimeKey        *string
+	TimeFormat     *string
+	LogPublicIP    *bool
+	// Index is the index of the topic, the index is left as is if nil.
+	Index *Index
+	Rules []*Rule
+}
+
+// Index is the index of a topic. Unlike other resources, fields left out are
+// removed from the index.
+type Index struct {
+	FullText          *tls.FullTextInfo
+	KeyValue          []tls.KeyValueInfo
+	UserInnerKeyValue []tls.KeyValueInfo
+}
+
+// Rule is a collection rule of a topic.
+type Rule struct {
+	RuleName       string
+	Paths          []string
+	LogType        string
+	ExtractRule    *tls.ExtractRule
+	ExcludePaths   []tls.ExcludePath
+	UserDefineRule *tls.UserDefineRule
+	LogSample      string
+	InputType      *int
+	ContainerRule  *tls.ContainerRule
+	// HostGroupIDs are the ids of the host groups the rule collects logs
+	// from, the bindings are left as is if nil.
+	HostGroupIDs []string
+}
+
+// Alarm is an alarm of a project.
+type Alarm struct {
+	AlarmName          string
+	Status             *bool
+	QueryRequest       []AlarmQuery
+	RequestCycle       tls.RequestCycle
+	Condition          string
+	TriggerPeriod      int
+	AlarmPeriod        int
+	AlarmNotifyGroup   []string
+	UserDefineMsg      *string
+	Severity           *string
+	AlarmPeriodDetail  *tls.AlarmPeriodSetting
+	JoinConfigurations []tls.JoinConfig
+	TriggerConditions  []tls.TriggerCondition
+}
+
+// AlarmQuery is a query of an alarm, run on a topic of the project of the
+// alarm named TopicName.
+type AlarmQuery struct {
+	TopicName       string
+	Query           string
+	Number          uint8
+	StartTimeOffset int
+	EndTimeOffset   int
+	TimeSpanType    string `json:",omitempty"`
+	TruncatedTime   string `json:",omitempty"`
+}
+
+// Load parses a spec in YAML or JSON. Unknown fields are errors.
+func Load(data []byte) (*Spec, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) == 0 || trimmed[0] != '{' {
+		var doc interface{}
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+		var err error
+		if data, err = json.Marshal(jsonValue(doc)); err != nil {
+			return nil, err
+		}
+	}
+
+	spec := &Spec{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(spec); err != nil {
+		return nil, err
+	}
+	if err := spec.validate(); err != nil {
+		return nil, err
+	}
+
+	return spec, nil
+}
+
+// LoadFile parses the spec in a YAML or JSON file.
+func LoadFile(path string) (*Spec, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := Load(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return spec, nil
+}
+
+// jsonValue converts a YAML document to values encoding/json can marshal.
+func jsonValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			v[key] = jsonValue(value)
+		}
+		return v
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			m[fmt.Sprint(key)] = jsonValue(value)
+		}
+		return m
+	case []interface{}:
+		for i, value := range v {
+			v[i] = jsonValue(value)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func (s *Spec) validate() error {
+	projects := make(map[string]bool)
+	for _, p := range s.Projects {
+		if p == nil || p.ProjectName == "" {
+			return errors.New("project without ProjectName")
+		}
+		if projects[p.ProjectName] {
+			return fmt.Errorf("project %s is listed twice", p.ProjectName)
+		}
+		projects[p.ProjectName] = true
+
+		topics := make(map[string]bool)
+		for _, t := range p.Topics {
+			if t == nil || t.TopicName == "" {
+				return fmt.Errorf("topic without TopicName in project %s", p.ProjectName)
+			}
+			address := p.ProjectName + "/" + t.TopicName
+			if topics[t.TopicName] {
+				return fmt.Errorf("topic %s is listed twice", address)
+			}
+			topics[t.TopicName] = true
+			if t.Ttl <= 0 {
+				return fmt.Errorf("topic %s: Ttl must be bigger than 0", address)
+			}
+			if t.ShardCount <= 0 {
+				return fmt.Errorf("topic %s: ShardCount must be bigger than 0", address)
+			}
+
+			rules := make(map[string]bool)
+			for _, r := range t.Rules {
+				if r == nil || r.RuleName == "" {
+					return fmt.Errorf("rule without RuleName in topic %s", address)
+				}
+				if rules[r.RuleName] {
+					return fmt.Errorf("rule %s/%s is listed twice", address, r.RuleName)
+				}
+				rules[r.RuleName] = true
+			}
+		}
+
+		alarms := make(map[string]bool)
+		for _, a := range p.Alarms {
+			if a == nil || a.AlarmName == "" {
+				return fmt.Errorf("alarm without AlarmName in project %s", p.ProjectName)
+			}
+			address := p.ProjectName + "/" + a.AlarmName
+			if alarms[a.AlarmName] {
+				return fmt.Errorf("alarm %s is listed twice", address)
+			}
+			alarms[a.AlarmName] = true
+			if len(a.QueryRequest) == 0 {
+				return fmt.Errorf("alarm %s: empty QueryRequest", address)
+			}
+			for _, query := range a.QueryRequest {
+				if query.TopicName == "" {
+					return fmt.Errorf("alarm %s: query without TopicName", address)
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package declarative
+
+import (
+	"strings"
+	"testing"
+)
+
+const testSpec = `
+Region: cn-beijing
+Projects:
+  - ProjectName: app
+    Description: application logs
+    Topics:
+      - TopicName: nginx
+        Ttl: 30
+        ShardCount: 2
+        AutoSplit: true
+        Index:
+          FullText: {Delimiter: ", ", CaseSensitive: false}
+          KeyValue:
+            - Key: status
+              Value: {ValueType: long}
+        Rules:
+          - RuleName: access
+            Paths: [/var/log/nginx/access.log]
+            LogType: minimalist_log
+            HostGroupIds: [hostgroup-1]
+    Alarms:
+      - AlarmName: errors
+        QueryRequest:
+          - TopicName: nginx
+            Query: "status:500 | select count(*) as errors"
+            Number: 1
+            StartTimeOffset: -15
+        RequestCycle: {Type: Period, Time: 10}
+        Condition: "$1.errors > 10"
+        AlarmPeriod: 60
+        AlarmNotifyGroup: [group-1]
+`
+
+func TestLoad(t *testing.T) {
+	spec, err := Load([]byte(testSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spec.Region != "cn-beijing" || len(spec.Projects) != 1 {
+		t.Fatalf("got spec %+v", spec)
+	}
+	topic := spec.Projects[0].Topics[0]
+	if topic.Ttl != 30 || topic.ShardCount != 2 || topic.AutoSplit == nil || !*topic.AutoSplit {
+		t.Fatalf("got topic %+v", topic)
+	}
+	if topic.Index.FullText.Delimiter != ", " || topic.Index.KeyValue[0].Value.ValueType != "long" {
+		t.Fatalf("got index %+v", topic.Index)
+	}
+	if alarm := spec.Projects[0].Alarms[0]; alarm.QueryRequest[0].StartTimeOffset != -15 || alarm.RequestCycle.Time != 10 {
+		t.Fatalf("got alarm %+v", alarm)
+	}
+
+	json, err := Load([]byte(`{"Projects": [{"ProjectName": "app", "Topics": [{"TopicName": "nginx", "Ttl": 30, "ShardCount": 2}]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if json.Projects[0].Topics[0].TopicName != "nginx" {
+		t.Fatalf("got spec %+v", json)
+	}
+
+	for spec, want := range map[string]string{
+		"Projects: [{ProjectName: app, Topic: []}]":                                    "unknown field",
+		"Projects: [{ProjectName: app}, {ProjectName: app}]":                           "listed twice",
+		"Projects: [{ProjectName: app, Topics: [{TopicName: t, ShardCount: 1}]}]":      "Ttl",
+		"Projects: [{ProjectName: app, Alarms: [{AlarmName: a, QueryRequest: [{}]}]}]": "without TopicName",
+		"Projects: [{Description: nameless}]":                                          "without ProjectName",
+	} {
+		if _, err := Load([]byte(spec)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("loading %s: got error %v, want %q", spec, err, want)
+		}
+	}
+}
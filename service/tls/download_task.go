@@ -1,6 +1,7 @@
 package tls
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
@@ -8,6 +9,10 @@ import (
 )
 
 func (c *LsClient) CreateDownloadTask(request *CreateDownloadTaskRequest) (r *CreateDownloadTaskResponse, e error) {
+	return c.CreateDownloadTaskCtx(context.Background(), request)
+}
+
+func (c *LsClient) CreateDownloadTaskCtx(ctx context.Context, request *CreateDownloadTaskRequest) (r *CreateDownloadTaskResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -23,7 +28,7 @@ func (c *LsClient) CreateDownloadTask(request *CreateDownloadTaskRequest) (r *Cr
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPost, PathCreateDownloadTask, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPost, PathCreateDownloadTask, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -44,6 +49,10 @@ func (c *LsClient) CreateDownloadTask(request *CreateDownloadTaskRequest) (r *Cr
 }
 
 func (c *LsClient) DescribeDownloadTasks(request *DescribeDownloadTasksRequest) (r *DescribeDownloadTasksResponse, e error) {
+	return c.DescribeDownloadTasksCtx(context.Background(), request)
+}
+
+func (c *LsClient) DescribeDownloadTasksCtx(ctx context.Context, request *DescribeDownloadTasksRequest) (r *DescribeDownloadTasksResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -70,7 +79,7 @@ func (c *LsClient) DescribeDownloadTasks(request *DescribeDownloadTasksRequest)
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodGet, PathDescribeDownloadTasks, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodGet, PathDescribeDownloadTasks, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -91,6 +100,10 @@ func (c *LsClient) DescribeDownloadTasks(request *DescribeDownloadTasksRequest)
 }
 
 func (c *LsClient) DescribeDownloadUrl(request *DescribeDownloadUrlRequest) (r *DescribeDownloadUrlResponse, e error) {
+	return c.DescribeDownloadUrlCtx(context.Background(), request)
+}
+
+func (c *LsClient) DescribeDownloadUrlCtx(ctx context.Context, request *DescribeDownloadUrlRequest) (r *DescribeDownloadUrlResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -108,7 +121,7 @@ func (c *LsClient) DescribeDownloadUrl(request *DescribeDownloadUrlRequest) (r *
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodGet, PathDescribeDownloadUrl, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodGet, PathDescribeDownloadUrl, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
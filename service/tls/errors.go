@@ -9,6 +9,8 @@ type Error struct {
 	Code      string `json:"errorCode"`
 	Message   string `json:"errorMessage"`
 	RequestID string `json:"requestID"`
+	// RespHeader is the header of the response, kept to read Retry-After.
+	RespHeader map[string][]string `json:"-"`
 }
 
 func NewClientError(err error) *Error {
@@ -1,6 +1,7 @@
 package tls
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"io/ioutil"
@@ -9,6 +10,10 @@ import (
 )
 
 func (c *LsClient) CreateETLTask(request *CreateETLTaskRequest) (*CreateETLTaskResponse, error) {
+	return c.CreateETLTaskCtx(context.Background(), request)
+}
+
+func (c *LsClient) CreateETLTaskCtx(ctx context.Context, request *CreateETLTaskRequest) (*CreateETLTaskResponse, error) {
 	reqHeaders := map[string]string{
 		"Content-Type": "application/json",
 	}
@@ -18,7 +23,7 @@ func (c *LsClient) CreateETLTask(request *CreateETLTaskRequest) (*CreateETLTaskR
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPost, PathCreateETLTask, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPost, PathCreateETLTask, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -40,6 +45,10 @@ func (c *LsClient) CreateETLTask(request *CreateETLTaskRequest) (*CreateETLTaskR
 }
 
 func (c *LsClient) DeleteETLTask(request *DeleteETLTaskRequest) (*CommonResponse, error) {
+	return c.DeleteETLTaskCtx(context.Background(), request)
+}
+
+func (c *LsClient) DeleteETLTaskCtx(ctx context.Context, request *DeleteETLTaskRequest) (*CommonResponse, error) {
 
 	reqHeaders := map[string]string{
 		"Content-Type": "application/json",
@@ -54,7 +63,7 @@ func (c *LsClient) DeleteETLTask(request *DeleteETLTaskRequest) (*CommonResponse
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodDelete, PathDeleteETLTask, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodDelete, PathDeleteETLTask, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -75,6 +84,10 @@ func (c *LsClient) DeleteETLTask(request *DeleteETLTaskRequest) (*CommonResponse
 }
 
 func (c *LsClient) ModifyETLTask(request *ModifyETLTaskRequest) (*CommonResponse, error) {
+	return c.ModifyETLTaskCtx(context.Background(), request)
+}
+
+func (c *LsClient) ModifyETLTaskCtx(ctx context.Context, request *ModifyETLTaskRequest) (*CommonResponse, error) {
 
 	reqHeaders := map[string]string{
 		"Content-Type": "application/json",
@@ -85,7 +98,7 @@ func (c *LsClient) ModifyETLTask(request *ModifyETLTaskRequest) (*CommonResponse
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPut, PathModifyETLTask, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPut, PathModifyETLTask, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -105,6 +118,10 @@ func (c *LsClient) ModifyETLTask(request *ModifyETLTaskRequest) (*CommonResponse
 }
 
 func (c *LsClient) DescribeETLTask(request *DescribeETLTaskRequest) (*DescribeETLTaskResponse, error) {
+	return c.DescribeETLTaskCtx(context.Background(), request)
+}
+
+func (c *LsClient) DescribeETLTaskCtx(ctx context.Context, request *DescribeETLTaskRequest) (*DescribeETLTaskResponse, error) {
 
 	reqHeaders := map[string]string{
 		"Content-Type": "application/json",
@@ -120,7 +137,7 @@ func (c *LsClient) DescribeETLTask(request *DescribeETLTaskRequest) (*DescribeET
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodGet, PathDescribeETLTask, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodGet, PathDescribeETLTask, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -142,6 +159,10 @@ func (c *LsClient) DescribeETLTask(request *DescribeETLTaskRequest) (*DescribeET
 }
 
 func (c *LsClient) DescribeETLTasks(request *DescribeETLTasksRequest) (*DescribeETLTasksResponse, error) {
+	return c.DescribeETLTasksCtx(context.Background(), request)
+}
+
+func (c *LsClient) DescribeETLTasksCtx(ctx context.Context, request *DescribeETLTasksRequest) (*DescribeETLTasksResponse, error) {
 	reqHeaders := map[string]string{
 		"Content-Type": "application/json",
 	}
@@ -194,7 +215,7 @@ func (c *LsClient) DescribeETLTasks(request *DescribeETLTasksRequest) (*Describe
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodGet, PathDescribeETLTasks, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodGet, PathDescribeETLTasks, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -216,6 +237,10 @@ func (c *LsClient) DescribeETLTasks(request *DescribeETLTasksRequest) (*Describe
 }
 
 func (c *LsClient) ModifyETLTaskStatus(request *ModifyETLTaskStatusRequest) (*CommonResponse, error) {
+	return c.ModifyETLTaskStatusCtx(context.Background(), request)
+}
+
+func (c *LsClient) ModifyETLTaskStatusCtx(ctx context.Context, request *ModifyETLTaskStatusRequest) (*CommonResponse, error) {
 
 	reqHeaders := map[string]string{
 		"Content-Type": "application/json",
@@ -226,7 +251,7 @@ func (c *LsClient) ModifyETLTaskStatus(request *ModifyETLTaskStatusRequest) (*Co
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPut, PathModifyETLTaskStatus, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPut, PathModifyETLTaskStatus, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
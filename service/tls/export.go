@@ -0,0 +1,502 @@
+package tls
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ExportFormatJSON = "json"
+	ExportFormatCSV  = "csv"
+
+	ExportCompressionGzip = "gzip"
+	ExportCompressionNone = "none"
+
+	defaultExportLimit        = 1000000
+	defaultExportPollInterval = 2 * time.Second
+	exportProgressBytes       = 1 << 20
+	// exportMissingPolls is how many polls in a row may not find the task
+	// before Export gives up on it.
+	exportMissingPolls = 5
+)
+
+// ErrDownloadTaskFailed is wrapped by the error of Export when the download
+// task ends in a failed state.
+var ErrDownloadTaskFailed = errors.New("download task failed")
+
+// ExportStage is the step of Export reported to ExportRequest.Progress.
+type ExportStage string
+
+const (
+	ExportStageCreated     ExportStage = "Created"
+	ExportStageGenerating  ExportStage = "Generating"
+	ExportStageDownloading ExportStage = "Downloading"
+	ExportStageDone        ExportStage = "Done"
+)
+
+// ExportRequest selects the logs exported by Export.
+type ExportRequest struct {
+	TopicID string
+	Query   string
+	// StartTime and EndTime are unix times in milliseconds.
+	StartTime int64
+	EndTime   int64
+	// DataFormat is ExportFormatJSON, by default, or ExportFormatCSV.
+	DataFormat string
+	// Compression of the result to download, ExportCompressionGzip by
+	// default. Export decompresses it either way.
+	Compression string
+	// Limit is the most logs exported, 1000000 by default.
+	Limit int
+	// Sort is "asc", by default, or "desc".
+	Sort string
+	// TaskName names the download task, a random one by default.
+	TaskName string
+	// PollInterval is how often the task status is described while it is
+	// generated, 2s by default.
+	PollInterval time.Duration
+	// DownloadTimeout limits the download of the result, which is not bound
+	// by the timeout of the client. 0 leaves it to ctx.
+	DownloadTimeout time.Duration
+	// Progress, when set, is called whenever the export advances: after each
+	// task status check and every MiB downloaded.
+	Progress func(progress ExportProgress)
+}
+
+// ExportProgress reports how far Export got.
+type ExportProgress struct {
+	Stage      ExportStage
+	TaskID     string
+	TaskStatus string
+	// LogCount and LogSize describe the generated result, once known.
+	LogCount int64
+	LogSize  int64
+	// BytesDownloaded counts the compressed bytes downloaded so far.
+	BytesDownloaded int64
+}
+
+// ExportResult describes a finished Export.
+type ExportResult struct {
+	TaskID          string
+	LogCount        int64
+	BytesDownloaded int64
+	// Records is the number of records decoded, 0 when writing to an
+	// io.Writer.
+	Records int
+}
+
+// Export creates a download task for the logs of req, waits for it to be
+// generated, then downloads and decompresses the result into dest, which is
+// one of:
+//
+//   - an io.Writer, receiving the result in its data format
+//   - a func(record map[string]string) error, called for every log; an error
+//     stops the export
+//   - a pointer to a slice of structs, filled as DecodeRows does
+//
+// The export stops when ctx is done. A task ending in a failed state returns
+// an error wrapping ErrDownloadTaskFailed.
+func Export(ctx context.Context, client Client, req *ExportRequest, dest interface{}) (*ExportResult, error) {
+	sink, err := newExportSink(dest)
+	if err != nil {
+		return nil, err
+	}
+	e := newExporter(client, req)
+
+	created, err := client.CreateDownloadTaskCtx(ctx, &CreateDownloadTaskRequest{
+		TopicID:     e.req.TopicID,
+		TaskName:    e.req.TaskName,
+		Query:       e.req.Query,
+		StartTime:   e.req.StartTime,
+		EndTime:     e.req.EndTime,
+		Compression: e.req.Compression,
+		DataFormat:  e.req.DataFormat,
+		Limit:       e.req.Limit,
+		Sort:        e.req.Sort,
+	})
+	if err != nil {
+		return nil, err
+	}
+	e.progress.TaskID = created.TaskId
+	e.report(ExportStageCreated)
+
+	if err := e.wait(ctx); err != nil {
+		return nil, err
+	}
+	url, err := client.DescribeDownloadUrlCtx(ctx, &DescribeDownloadUrlRequest{TaskId: created.TaskId})
+	if err != nil {
+		return nil, err
+	}
+	e.report(ExportStageDownloading)
+	records, err := e.download(ctx, url.DownloadUrl, sink)
+	if err != nil {
+		return nil, err
+	}
+	e.report(ExportStageDone)
+
+	return &ExportResult{
+		TaskID:          created.TaskId,
+		LogCount:        e.progress.LogCount,
+		BytesDownloaded: e.progress.BytesDownloaded,
+		Records:         records,
+	}, nil
+}
+
+type exporter struct {
+	client   Client
+	req      ExportRequest
+	progress ExportProgress
+}
+
+func newExporter(client Client, req *ExportRequest) *exporter {
+	e := &exporter{client: client, req: *req}
+	if e.req.Query == "" {
+		e.req.Query = "*"
+	}
+	if e.req.DataFormat == "" {
+		e.req.DataFormat = ExportFormatJSON
+	}
+	if e.req.Compression == "" {
+		e.req.Compression = ExportCompressionGzip
+	}
+	if e.req.Limit <= 0 {
+		e.req.Limit = defaultExportLimit
+	}
+	if e.req.Sort == "" {
+		e.req.Sort = "asc"
+	}
+	if e.req.TaskName == "" {
+		e.req.TaskName = "export-" + uuid.NewString()
+	}
+	if e.req.PollInterval <= 0 {
+		e.req.PollInterval = defaultExportPollInterval
+	}
+
+	return e
+}
+
+func (e *exporter) report(stage ExportStage) {
+	e.progress.Stage = stage
+	if e.req.Progress != nil {
+		e.req.Progress(e.progress)
+	}
+}
+
+// wait describes the task until it succeeded or failed. It fails if the task
+// is missing from exportMissingPolls polls in a row.
+func (e *exporter) wait(ctx context.Context) error {
+	for missing := 0; ; {
+		task, err := e.describeTask(ctx)
+		if err != nil {
+			return err
+		}
+		if task == nil {
+			missing++
+			if missing >= exportMissingPolls {
+				return fmt.Errorf("task %s is missing from DescribeDownloadTasks", e.progress.TaskID)
+			}
+		} else {
+			missing = 0
+			e.progress.TaskStatus = task.TaskStatus
+			e.progress.LogCount = task.LogCount
+			e.progress.LogSize = task.LogSize
+			switch downloadTaskState(task.TaskStatus) {
+			case downloadTaskSucceeded:
+				return nil
+			case downloadTaskFailed:
+				return fmt.Errorf("%w: task %s is %s", ErrDownloadTaskFailed, task.TaskId, task.TaskStatus)
+			}
+		}
+		e.report(ExportStageGenerating)
+
+		timer := time.NewTimer(e.req.PollInterval)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("wait for task %s: %w", e.progress.TaskID, ctx.Err())
+		}
+	}
+}
+
+// describeTask pages through the tasks named TaskName until it finds the
+// exported one, nil if it is not listed.
+func (e *exporter) describeTask(ctx context.Context) (*DownloadTaskResp, error) {
+	taskName := e.req.TaskName
+	p := NewDescribeDownloadTasksPaginator(e.client, &DescribeDownloadTasksRequest{TopicID: e.req.TopicID, TaskName: &taskName})
+	for p.HasMorePages() {
+		tasks, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, task := range tasks {
+			if task.TaskId == e.progress.TaskID {
+				return task, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+const (
+	downloadTaskRunning = iota
+	downloadTaskSucceeded
+	downloadTaskFailed
+)
+
+// downloadTaskState classifies the TaskStatus of a download task, such as
+// generating, success or generate_fail.
+func downloadTaskState(status string) int {
+	status = strings.ToLower(status)
+	switch {
+	case strings.Contains(status, "fail"):
+		return downloadTaskFailed
+	case status == "success" || strings.HasSuffix(status, "_success"):
+		return downloadTaskSucceeded
+	default:
+		return downloadTaskRunning
+	}
+}
+
+// download streams the result at url into sink, it returns the number of
+// records decoded.
+func (e *exporter) download(ctx context.Context, url string, sink exportSink) (int, error) {
+	if url == "" {
+		return 0, errors.New("empty download url of task " + e.progress.TaskID)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	client := *e.client.GetHttpClient()
+	client.Timeout = e.req.DownloadTimeout
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("download result of task %s: %s", e.progress.TaskID, response.Status)
+	}
+
+	body := bufio.NewReader(&progressReader{reader: response.Body, exporter: e})
+	// The result is decompressed by its content rather than by Compression, a
+	// gzip file may be served with Content-Encoding and decoded already.
+	var reader io.Reader = body
+	if magic, err := body.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			return 0, err
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	return sink(reader, e.req.DataFormat)
+}
+
+// progressReader counts the bytes downloaded, reporting every
+// exportProgressBytes of them.
+type progressReader struct {
+	reader   io.Reader
+	exporter *exporter
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	progress := &r.exporter.progress
+	before := progress.BytesDownloaded
+	progress.BytesDownloaded += int64(n)
+	if before/exportProgressBytes != progress.BytesDownloaded/exportProgressBytes {
+		r.exporter.report(ExportStageDownloading)
+	}
+
+	return n, err
+}
+
+// exportSink consumes the decompressed result of a data format.
+type exportSink func(reader io.Reader, format string) (int, error)
+
+func newExportSink(dest interface{}) (exportSink, error) {
+	switch dest := dest.(type) {
+	case io.Writer:
+		return func(reader io.Reader, format string) (int, error) {
+			_, err := io.Copy(dest, reader)
+			return 0, err
+		}, nil
+	case func(record map[string]string) error:
+		return func(reader io.Reader, format string) (int, error) {
+			return readExportRecords(reader, format, dest)
+		}, nil
+	}
+
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		return nil, fmt.Errorf("export: unsupported destination %T", dest)
+	}
+	slice := v.Elem()
+	elemType := slice.Type().Elem()
+	structType := elemType
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("export: %s is not a struct", elemType)
+	}
+
+	// Every record is decoded as it is read, the result is never held twice.
+	return func(reader io.Reader, format string) (int, error) {
+		result := reflect.MakeSlice(slice.Type(), 0, 0)
+		n, err := readExportRecords(reader, format, func(record map[string]string) error {
+			row := make(map[string]interface{}, len(record))
+			for key, value := range record {
+				row[key] = value
+			}
+			elem := reflect.New(structType)
+			if err := decodeRow(row, elem.Elem()); err != nil {
+				return fmt.Errorf("export: record %d: %w", result.Len(), err)
+			}
+			if elemType.Kind() != reflect.Ptr {
+				elem = elem.Elem()
+			}
+			result = reflect.Append(result, elem)
+			return nil
+		})
+		if err != nil {
+			return n, err
+		}
+		slice.Set(result)
+		return n, nil
+	}, nil
+}
+
+// readExportRecords calls f with every record of a JSON result, made of JSON
+// objects or an array of them, or of a CSV result with a header row.
+func readExportRecords(reader io.Reader, format string, f func(record map[string]string) error) (int, error) {
+	if strings.EqualFold(format, ExportFormatCSV) {
+		return readCSVRecords(reader, f)
+	}
+
+	return readJSONRecords(reader, f)
+}
+
+func readJSONRecords(reader io.Reader, f func(record map[string]string) error) (int, error) {
+	buffered := bufio.NewReader(reader)
+	first, err := peekNonSpace(buffered)
+	if err == io.EOF {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	decoder := json.NewDecoder(buffered)
+	decoder.UseNumber()
+	array := first == '['
+	if array {
+		// Skip the opening bracket.
+		if _, err := decoder.Token(); err != nil {
+			return 0, err
+		}
+	}
+
+	count := 0
+	for !array || decoder.More() {
+		var fields map[string]interface{}
+		err := decoder.Decode(&fields)
+		if err == io.EOF && !array {
+			break
+		}
+		if err != nil {
+			return count, fmt.Errorf("export: record %d: %w", count, err)
+		}
+		record := make(map[string]string, len(fields))
+		for key, value := range fields {
+			record[key] = exportFieldText(value)
+		}
+		if err := f(record); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// exportFieldText returns a JSON value as text, nested values as JSON.
+func exportFieldText(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return ""
+	case string, json.Number:
+		return columnText(value)
+	}
+	data, _ := json.Marshal(value)
+
+	return string(data)
+}
+
+func peekNonSpace(reader *bufio.Reader) (byte, error) {
+	for {
+		c, err := reader.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch c {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+
+		return c, reader.UnreadByte()
+	}
+}
+
+func readCSVRecords(reader io.Reader, f func(record map[string]string) error) (int, error) {
+	r := csv.NewReader(reader)
+	r.FieldsPerRecord = -1
+	header, err := r.Read()
+	if err == io.EOF {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if len(header) > 0 {
+		// Drop the byte order mark of the file.
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	count := 0
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			return count, nil
+		}
+		if err != nil {
+			return count, fmt.Errorf("export: record %d: %w", count, err)
+		}
+		record := make(map[string]string, len(header))
+		for i, value := range row {
+			if i < len(header) {
+				record[header[i]] = value
+			}
+		}
+		if err := f(record); err != nil {
+			return count, err
+		}
+		count++
+	}
+}
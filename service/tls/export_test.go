@@ -0,0 +1,67 @@
+package tls
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadExportRecords(t *testing.T) {
+	want := []map[string]string{
+		{"message": "a", "code": "200", "extra": `{"k":"v"}`},
+		{"message": "b", "code": "", "extra": "[1,2]"},
+	}
+	for name, input := range map[string]struct {
+		format, data string
+	}{
+		"json lines": {ExportFormatJSON, `{"message":"a","code":200,"extra":{"k":"v"}}` + "\n" + `{"message":"b","code":null,"extra":[1,2]}` + "\n"},
+		"json array": {ExportFormatJSON, ` [{"message":"a","code":200,"extra":{"k":"v"}}, {"message":"b","code":null,"extra":[1,2]}]`},
+		"csv":        {ExportFormatCSV, "\ufeffmessage,code,extra\na,200,\"{\"\"k\"\":\"\"v\"\"}\"\nb,,\"[1,2]\"\n"},
+	} {
+		var got []map[string]string
+		n, err := readExportRecords(strings.NewReader(input.data), input.format, func(record map[string]string) error {
+			got = append(got, record)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if n != 2 || !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s: got %d records %v", name, n, got)
+		}
+	}
+
+	if n, err := readExportRecords(strings.NewReader(""), ExportFormatJSON, nil); n != 0 || err != nil {
+		t.Fatalf("empty result: got %d records, error %v", n, err)
+	}
+	if _, err := readExportRecords(strings.NewReader(`{"message":`), ExportFormatJSON, func(map[string]string) error { return nil }); err == nil {
+		t.Fatal("read a truncated record")
+	}
+	if _, err := newExportSink(42); err == nil {
+		t.Fatal("accepted an unsupported destination")
+	}
+	if _, err := newExportSink(&[]int{}); err == nil {
+		t.Fatal("accepted a slice of non-structs")
+	}
+
+	var rows []*struct {
+		Message string `tls:"message"`
+		Code    *int   `tls:"code"`
+	}
+	sink, err := newExportSink(&rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := sink(strings.NewReader("message,code\na,200\nb,404\n"), ExportFormatCSV); n != 2 || err != nil {
+		t.Fatalf("slice sink: got %d records, error %v", n, err)
+	}
+	if len(rows) != 2 || rows[0].Message != "a" || *rows[0].Code != 200 || rows[1].Message != "b" || *rows[1].Code != 404 {
+		t.Fatalf("slice sink: unexpected rows %v", rows)
+	}
+	if _, err := sink(strings.NewReader("message,code\na,x\n"), ExportFormatCSV); err == nil {
+		t.Fatal("slice sink: decoded a malformed code")
+	}
+	if state := downloadTaskState("generate_fail"); state != downloadTaskFailed {
+		t.Fatalf("generate_fail is state %d", state)
+	}
+}
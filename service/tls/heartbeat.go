@@ -1,12 +1,17 @@
 package tls
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
 )
 
 func (c *LsClient) ConsumerHeartbeat(request *ConsumerHeartbeatRequest) (*ConsumerHeartbeatResponse, error) {
+	return c.ConsumerHeartbeatCtx(context.Background(), request)
+}
+
+func (c *LsClient) ConsumerHeartbeatCtx(ctx context.Context, request *ConsumerHeartbeatRequest) (*ConsumerHeartbeatResponse, error) {
 	reqHeaders := map[string]string{
 		"Content-Type": "application/json",
 	}
@@ -22,7 +27,7 @@ func (c *LsClient) ConsumerHeartbeat(request *ConsumerHeartbeatRequest) (*Consum
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPost, PathConsumerHeartbeat, nil, reqHeaders, bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPost, PathConsumerHeartbeat, nil, reqHeaders, bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,7 @@
 package tls
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
@@ -8,6 +9,11 @@ import (
 
 // Deprecated: use DescribeHistogramV1 instead
 func (c *LsClient) DescribeHistogram(request *DescribeHistogramRequest) (r *DescribeHistogramResponse, e error) {
+	return c.DescribeHistogramCtx(context.Background(), request)
+}
+
+// Deprecated: use DescribeHistogramV1Ctx instead
+func (c *LsClient) DescribeHistogramCtx(ctx context.Context, request *DescribeHistogramRequest) (r *DescribeHistogramResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -23,7 +29,7 @@ func (c *LsClient) DescribeHistogram(request *DescribeHistogramRequest) (r *Desc
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPost, PathDescribeHistogram, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPost, PathDescribeHistogram, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -44,6 +50,10 @@ func (c *LsClient) DescribeHistogram(request *DescribeHistogramRequest) (r *Desc
 }
 
 func (c *LsClient) DescribeHistogramV1(request *DescribeHistogramV1Request) (r *DescribeHistogramV1Response, e error) {
+	return c.DescribeHistogramV1Ctx(context.Background(), request)
+}
+
+func (c *LsClient) DescribeHistogramV1Ctx(ctx context.Context, request *DescribeHistogramV1Request) (r *DescribeHistogramV1Response, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -57,7 +67,7 @@ func (c *LsClient) DescribeHistogramV1(request *DescribeHistogramV1Request) (r *
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPost, PathDescribeHistogramV1, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPost, PathDescribeHistogramV1, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
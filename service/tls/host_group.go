@@ -1,6 +1,7 @@
 package tls
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
@@ -8,6 +9,10 @@ import (
 )
 
 func (c *LsClient) CreateHostGroup(request *CreateHostGroupRequest) (r *CreateHostGroupResponse, e error) {
+	return c.CreateHostGroupCtx(context.Background(), request)
+}
+
+func (c *LsClient) CreateHostGroupCtx(ctx context.Context, request *CreateHostGroupRequest) (r *CreateHostGroupResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -21,7 +26,7 @@ func (c *LsClient) CreateHostGroup(request *CreateHostGroupRequest) (r *CreateHo
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPost, PathCreateHostGroup, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPost, PathCreateHostGroup, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -43,6 +48,10 @@ func (c *LsClient) CreateHostGroup(request *CreateHostGroupRequest) (r *CreateHo
 }
 
 func (c *LsClient) DeleteHostGroup(request *DeleteHostGroupRequest) (r *CommonResponse, e error) {
+	return c.DeleteHostGroupCtx(context.Background(), request)
+}
+
+func (c *LsClient) DeleteHostGroupCtx(ctx context.Context, request *DeleteHostGroupRequest) (r *CommonResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -56,7 +65,7 @@ func (c *LsClient) DeleteHostGroup(request *DeleteHostGroupRequest) (r *CommonRe
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodDelete, PathDeleteHostGroup, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodDelete, PathDeleteHostGroup, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -78,6 +87,10 @@ func (c *LsClient) DeleteHostGroup(request *DeleteHostGroupRequest) (r *CommonRe
 }
 
 func (c *LsClient) ModifyHostGroup(request *ModifyHostGroupRequest) (r *CommonResponse, e error) {
+	return c.ModifyHostGroupCtx(context.Background(), request)
+}
+
+func (c *LsClient) ModifyHostGroupCtx(ctx context.Context, request *ModifyHostGroupRequest) (r *CommonResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -91,7 +104,7 @@ func (c *LsClient) ModifyHostGroup(request *ModifyHostGroupRequest) (r *CommonRe
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPut, PathModifyHostGroup, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPut, PathModifyHostGroup, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -113,6 +126,10 @@ func (c *LsClient) ModifyHostGroup(request *ModifyHostGroupRequest) (r *CommonRe
 }
 
 func (c *LsClient) DescribeHostGroup(request *DescribeHostGroupRequest) (r *DescribeHostGroupResponse, e error) {
+	return c.DescribeHostGroupCtx(context.Background(), request)
+}
+
+func (c *LsClient) DescribeHostGroupCtx(ctx context.Context, request *DescribeHostGroupRequest) (r *DescribeHostGroupResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -128,7 +145,7 @@ func (c *LsClient) DescribeHostGroup(request *DescribeHostGroupRequest) (r *Desc
 	body := map[string]string{}
 	bytesBody, err := json.Marshal(body)
 
-	rawResponse, err := c.Request(http.MethodGet, PathDescribeHostGroup, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodGet, PathDescribeHostGroup, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -154,6 +171,10 @@ func (c *LsClient) DescribeHostGroup(request *DescribeHostGroupRequest) (r *Desc
 }
 
 func (c *LsClient) DescribeHostGroups(request *DescribeHostGroupsRequest) (r *DescribeHostGroupsResponse, e error) {
+	return c.DescribeHostGroupsCtx(context.Background(), request)
+}
+
+func (c *LsClient) DescribeHostGroupsCtx(ctx context.Context, request *DescribeHostGroupsRequest) (r *DescribeHostGroupsResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -199,7 +220,7 @@ func (c *LsClient) DescribeHostGroups(request *DescribeHostGroupsRequest) (r *De
 	body := map[string]string{}
 	bytesBody, err := json.Marshal(body)
 
-	rawResponse, err := c.Request(http.MethodGet, PathDescribeHostGroups, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodGet, PathDescribeHostGroups, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -221,6 +242,10 @@ func (c *LsClient) DescribeHostGroups(request *DescribeHostGroupsRequest) (r *De
 }
 
 func (c *LsClient) DescribeHosts(request *DescribeHostsRequest) (r *DescribeHostsResponse, e error) {
+	return c.DescribeHostsCtx(context.Background(), request)
+}
+
+func (c *LsClient) DescribeHostsCtx(ctx context.Context, request *DescribeHostsRequest) (r *DescribeHostsResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -252,7 +277,7 @@ func (c *LsClient) DescribeHosts(request *DescribeHostsRequest) (r *DescribeHost
 	body := map[string]string{}
 	bytesBody, err := json.Marshal(body)
 
-	rawResponse, err := c.Request(http.MethodGet, PathDescribeHosts, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodGet, PathDescribeHosts, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 
 	if err != nil {
 		return nil, err
@@ -275,6 +300,10 @@ func (c *LsClient) DescribeHosts(request *DescribeHostsRequest) (r *DescribeHost
 }
 
 func (c *LsClient) DeleteHost(request *DeleteHostRequest) (r *CommonResponse, e error) {
+	return c.DeleteHostCtx(context.Background(), request)
+}
+
+func (c *LsClient) DeleteHostCtx(ctx context.Context, request *DeleteHostRequest) (r *CommonResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -288,7 +317,7 @@ func (c *LsClient) DeleteHost(request *DeleteHostRequest) (r *CommonResponse, e
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodDelete, PathDeleteHost, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodDelete, PathDeleteHost, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -310,6 +339,10 @@ func (c *LsClient) DeleteHost(request *DeleteHostRequest) (r *CommonResponse, e
 }
 
 func (c *LsClient) DescribeHostGroupRules(request *DescribeHostGroupRulesRequest) (r *DescribeHostGroupRulesResponse, e error) {
+	return c.DescribeHostGroupRulesCtx(context.Background(), request)
+}
+
+func (c *LsClient) DescribeHostGroupRulesCtx(ctx context.Context, request *DescribeHostGroupRulesRequest) (r *DescribeHostGroupRulesResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -335,7 +368,7 @@ func (c *LsClient) DescribeHostGroupRules(request *DescribeHostGroupRulesRequest
 	body := map[string]string{}
 	bytesBody, err := json.Marshal(body)
 
-	rawResponse, err := c.Request(http.MethodGet, PathDescribeHostGroupRules, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodGet, PathDescribeHostGroupRules, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 
 	if err != nil {
 		return nil, err
@@ -358,6 +391,10 @@ func (c *LsClient) DescribeHostGroupRules(request *DescribeHostGroupRulesRequest
 }
 
 func (c *LsClient) ModifyHostGroupsAutoUpdate(request *ModifyHostGroupsAutoUpdateRequest) (r *ModifyHostGroupsAutoUpdateResponse, e error) {
+	return c.ModifyHostGroupsAutoUpdateCtx(context.Background(), request)
+}
+
+func (c *LsClient) ModifyHostGroupsAutoUpdateCtx(ctx context.Context, request *ModifyHostGroupsAutoUpdateRequest) (r *ModifyHostGroupsAutoUpdateResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -373,7 +410,7 @@ func (c *LsClient) ModifyHostGroupsAutoUpdate(request *ModifyHostGroupsAutoUpdat
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPut, PathModifyHostGroupsAutoUpdate, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPut, PathModifyHostGroupsAutoUpdate, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 
 	if err != nil {
 		return nil, err
@@ -396,6 +433,10 @@ func (c *LsClient) ModifyHostGroupsAutoUpdate(request *ModifyHostGroupsAutoUpdat
 }
 
 func (c *LsClient) DeleteAbnormalHosts(request *DeleteAbnormalHostsRequest) (*CommonResponse, error) {
+	return c.DeleteAbnormalHostsCtx(context.Background(), request)
+}
+
+func (c *LsClient) DeleteAbnormalHostsCtx(ctx context.Context, request *DeleteAbnormalHostsRequest) (*CommonResponse, error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -409,7 +450,7 @@ func (c *LsClient) DeleteAbnormalHosts(request *DeleteAbnormalHostsRequest) (*Co
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodDelete, PathDeleteAbnormalHosts, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodDelete, PathDeleteAbnormalHosts, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,7 @@
 package tls
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
@@ -8,6 +9,10 @@ import (
 )
 
 func (c *LsClient) CreateImportTask(request *CreateImportTaskRequest) (r *CreateImportTaskResponse, err error) {
+	return c.CreateImportTaskCtx(context.Background(), request)
+}
+
+func (c *LsClient) CreateImportTaskCtx(ctx context.Context, request *CreateImportTaskRequest) (r *CreateImportTaskResponse, err error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -21,7 +26,7 @@ func (c *LsClient) CreateImportTask(request *CreateImportTaskRequest) (r *Create
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPost, PathCreateImportTask, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPost, PathCreateImportTask, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -42,6 +47,10 @@ func (c *LsClient) CreateImportTask(request *CreateImportTaskRequest) (r *Create
 }
 
 func (c *LsClient) DeleteImportTask(request *DeleteImportTaskRequest) (r *DeleteImportTaskResponse, err error) {
+	return c.DeleteImportTaskCtx(context.Background(), request)
+}
+
+func (c *LsClient) DeleteImportTaskCtx(ctx context.Context, request *DeleteImportTaskRequest) (r *DeleteImportTaskResponse, err error) {
 	reqHeaders := map[string]string{
 		"Content-Type": "application/json",
 	}
@@ -51,7 +60,7 @@ func (c *LsClient) DeleteImportTask(request *DeleteImportTaskRequest) (r *Delete
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodDelete, PathDeleteImportTask, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodDelete, PathDeleteImportTask, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -72,6 +81,10 @@ func (c *LsClient) DeleteImportTask(request *DeleteImportTaskRequest) (r *Delete
 }
 
 func (c *LsClient) ModifyImportTask(request *ModifyImportTaskRequest) (r *ModifyImportTaskResponse, err error) {
+	return c.ModifyImportTaskCtx(context.Background(), request)
+}
+
+func (c *LsClient) ModifyImportTaskCtx(ctx context.Context, request *ModifyImportTaskRequest) (r *ModifyImportTaskResponse, err error) {
 	reqHeaders := map[string]string{
 		"Content-Type": "application/json",
 	}
@@ -81,7 +94,7 @@ func (c *LsClient) ModifyImportTask(request *ModifyImportTaskRequest) (r *Modify
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPut, PathModifyImportTask, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPut, PathModifyImportTask, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -102,6 +115,10 @@ func (c *LsClient) ModifyImportTask(request *ModifyImportTaskRequest) (r *Modify
 }
 
 func (c *LsClient) DescribeImportTask(request *DescribeImportTaskRequest) (r *DescribeImportTaskResponse, err error) {
+	return c.DescribeImportTaskCtx(context.Background(), request)
+}
+
+func (c *LsClient) DescribeImportTaskCtx(ctx context.Context, request *DescribeImportTaskRequest) (r *DescribeImportTaskResponse, err error) {
 	reqHeaders := map[string]string{
 		"Content-Type": "application/json",
 	}
@@ -110,7 +127,7 @@ func (c *LsClient) DescribeImportTask(request *DescribeImportTaskRequest) (r *De
 		"TaskId": request.TaskID,
 	}
 
-	rawResponse, err := c.Request(http.MethodGet, PathDescribeImportTask, params, c.assembleHeader(request.CommonRequest, reqHeaders), nil)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodGet, PathDescribeImportTask, params, c.assembleHeader(request.CommonRequest, reqHeaders), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -132,6 +149,10 @@ func (c *LsClient) DescribeImportTask(request *DescribeImportTaskRequest) (r *De
 }
 
 func (c *LsClient) DescribeImportTasks(request *DescribeImportTasksRequest) (r *DescribeImportTasksResponse, err error) {
+	return c.DescribeImportTasksCtx(context.Background(), request)
+}
+
+func (c *LsClient) DescribeImportTasksCtx(ctx context.Context, request *DescribeImportTasksRequest) (r *DescribeImportTasksResponse, err error) {
 	reqHeaders := map[string]string{
 		"Content-Type": "application/json",
 	}
@@ -171,7 +192,7 @@ func (c *LsClient) DescribeImportTasks(request *DescribeImportTasksRequest) (r *
 		params["Status"] = request.Status
 	}
 
-	rawResponse, err := c.Request(http.MethodGet, PathDescribeImportTasks, params, c.assembleHeader(request.CommonRequest, reqHeaders), nil)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodGet, PathDescribeImportTasks, params, c.assembleHeader(request.CommonRequest, reqHeaders), nil)
 	if err != nil {
 		return nil, err
 	}
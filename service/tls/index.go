@@ -1,6 +1,7 @@
 package tls
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"io/ioutil"
@@ -9,6 +10,10 @@ import (
 )
 
 func (c *LsClient) CreateIndex(request *CreateIndexRequest) (r *CreateIndexResponse, e error) {
+	return c.CreateIndexCtx(context.Background(), request)
+}
+
+func (c *LsClient) CreateIndexCtx(ctx context.Context, request *CreateIndexRequest) (r *CreateIndexResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -22,7 +27,7 @@ func (c *LsClient) CreateIndex(request *CreateIndexRequest) (r *CreateIndexRespo
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPost, PathCreateIndex, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPost, PathCreateIndex, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -44,6 +49,10 @@ func (c *LsClient) CreateIndex(request *CreateIndexRequest) (r *CreateIndexRespo
 }
 
 func (c *LsClient) DeleteIndex(request *DeleteIndexRequest) (r *CommonResponse, e error) {
+	return c.DeleteIndexCtx(context.Background(), request)
+}
+
+func (c *LsClient) DeleteIndexCtx(ctx context.Context, request *DeleteIndexRequest) (r *CommonResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -61,7 +70,7 @@ func (c *LsClient) DeleteIndex(request *DeleteIndexRequest) (r *CommonResponse,
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodDelete, PathDeleteIndex, nil, c.assembleHeader(request.CommonRequest, reqHeaders), jsonBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodDelete, PathDeleteIndex, nil, c.assembleHeader(request.CommonRequest, reqHeaders), jsonBody)
 	if err != nil {
 		return nil, err
 	}
@@ -82,6 +91,10 @@ func (c *LsClient) DeleteIndex(request *DeleteIndexRequest) (r *CommonResponse,
 }
 
 func (c *LsClient) DescribeIndex(request *DescribeIndexRequest) (r *DescribeIndexResponse, e error) {
+	return c.DescribeIndexCtx(context.Background(), request)
+}
+
+func (c *LsClient) DescribeIndexCtx(ctx context.Context, request *DescribeIndexRequest) (r *DescribeIndexResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -97,7 +110,7 @@ func (c *LsClient) DescribeIndex(request *DescribeIndexRequest) (r *DescribeInde
 	body := map[string]string{}
 	bytesBody, err := json.Marshal(body)
 
-	rawResponse, err := c.Request(http.MethodGet, PathDescribeIndex, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodGet, PathDescribeIndex, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -138,6 +151,10 @@ func (c *LsClient) DescribeIndex(request *DescribeIndexRequest) (r *DescribeInde
 // 由于该接口为全量更新接口，等同于重新创建一个新的索引，因此要注意不要漏填字段
 
 func (c *LsClient) ModifyIndex(request *ModifyIndexRequest) (r *CommonResponse, e error) {
+	return c.ModifyIndexCtx(context.Background(), request)
+}
+
+func (c *LsClient) ModifyIndexCtx(ctx context.Context, request *ModifyIndexRequest) (r *CommonResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -151,7 +168,7 @@ func (c *LsClient) ModifyIndex(request *ModifyIndexRequest) (r *CommonResponse,
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPut, PathModifyIndex, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPut, PathModifyIndex, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -172,16 +189,20 @@ func (c *LsClient) ModifyIndex(request *ModifyIndexRequest) (r *CommonResponse,
 }
 
 func (c *LsClient) SearchLogs(request *SearchLogsRequest) (r *SearchLogsResponse, e error) {
+	return c.SearchLogsCtx(context.Background(), request)
+}
+
+func (c *LsClient) SearchLogsCtx(ctx context.Context, request *SearchLogsRequest) (r *SearchLogsResponse, e error) {
 	reqHeaders := map[string]string{
 		"Content-Type":    "application/json",
 		"Accept-Encoding": CompressGz,
 		HeaderAPIVersion:  APIVersion2,
 	}
 
-	return c.search(request, reqHeaders)
+	return c.search(ctx, request, reqHeaders)
 }
 
-func (c *LsClient) search(request *SearchLogsRequest, reqHeaders map[string]string) (*SearchLogsResponse, error) {
+func (c *LsClient) search(ctx context.Context, request *SearchLogsRequest, reqHeaders map[string]string) (*SearchLogsResponse, error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -202,7 +223,7 @@ func (c *LsClient) search(request *SearchLogsRequest, reqHeaders map[string]stri
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPost, PathSearchLogs, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPost, PathSearchLogs, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -226,11 +247,15 @@ func (c *LsClient) search(request *SearchLogsRequest, reqHeaders map[string]stri
 
 // SearchLogsV2 搜索按照0.3.0api版本进行，和默认的0.2.0版本区别见文档https://www.volcengine.com/docs/6470/112170
 func (c *LsClient) SearchLogsV2(request *SearchLogsRequest) (*SearchLogsResponse, error) {
+	return c.SearchLogsV2Ctx(context.Background(), request)
+}
+
+func (c *LsClient) SearchLogsV2Ctx(ctx context.Context, request *SearchLogsRequest) (*SearchLogsResponse, error) {
 	reqHeaders := map[string]string{
 		"Content-Type":    "application/json",
 		"Accept-Encoding": CompressGz,
 		HeaderAPIVersion:  APIVersion3,
 	}
 
-	return c.search(request, reqHeaders)
+	return c.search(ctx, request, reqHeaders)
 }
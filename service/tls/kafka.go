@@ -1,12 +1,17 @@
 package tls
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
 )
 
 func (c *LsClient) OpenKafkaConsumer(request *OpenKafkaConsumerRequest) (r *OpenKafkaConsumerResponse, e error) {
+	return c.OpenKafkaConsumerCtx(context.Background(), request)
+}
+
+func (c *LsClient) OpenKafkaConsumerCtx(ctx context.Context, request *OpenKafkaConsumerRequest) (r *OpenKafkaConsumerResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -22,7 +27,7 @@ func (c *LsClient) OpenKafkaConsumer(request *OpenKafkaConsumerRequest) (r *Open
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPut, PathOpenKafkaConsumer, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPut, PathOpenKafkaConsumer, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -43,6 +48,10 @@ func (c *LsClient) OpenKafkaConsumer(request *OpenKafkaConsumerRequest) (r *Open
 }
 
 func (c *LsClient) CloseKafkaConsumer(request *CloseKafkaConsumerRequest) (r *CloseKafkaConsumerResponse, e error) {
+	return c.CloseKafkaConsumerCtx(context.Background(), request)
+}
+
+func (c *LsClient) CloseKafkaConsumerCtx(ctx context.Context, request *CloseKafkaConsumerRequest) (r *CloseKafkaConsumerResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -58,7 +67,7 @@ func (c *LsClient) CloseKafkaConsumer(request *CloseKafkaConsumerRequest) (r *Cl
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPut, PathCloseKafkaConsumer, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPut, PathCloseKafkaConsumer, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -79,6 +88,10 @@ func (c *LsClient) CloseKafkaConsumer(request *CloseKafkaConsumerRequest) (r *Cl
 }
 
 func (c *LsClient) DescribeKafkaConsumer(request *DescribeKafkaConsumerRequest) (r *DescribeKafkaConsumerResponse, e error) {
+	return c.DescribeKafkaConsumerCtx(context.Background(), request)
+}
+
+func (c *LsClient) DescribeKafkaConsumerCtx(ctx context.Context, request *DescribeKafkaConsumerRequest) (r *DescribeKafkaConsumerResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -96,7 +109,7 @@ func (c *LsClient) DescribeKafkaConsumer(request *DescribeKafkaConsumerRequest)
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodGet, PathDescribeKafkaConsumer, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodGet, PathDescribeKafkaConsumer, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,160 @@
+package kafka
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/go-kit/kit/log"
+
+	"github.com/volcengine/volc-sdk-golang/service/tls/common"
+	"github.com/volcengine/volc-sdk-golang/service/tls/consumer"
+)
+
+// kafkaPort is the SASL_SSL port of the Kafka endpoints of TLS.
+const kafkaPort = 9093
+
+type Config struct {
+	common.LoggerConfig
+	// ClientConfig is used for the OpenAPI calls and, as user ProjectID and
+	// password AccessKeyID#AccessKeySecret, for the SASL authentication.
+	common.ClientConfig
+	ProjectID         string
+	TopicID           string
+	ConsumerGroupName string
+	// Brokers overrides the Kafka endpoint of Region.
+	Brokers []string
+	// PrivateNetwork uses the endpoint of Region reachable from volcengine
+	// VPCs instead of the public one.
+	PrivateNetwork bool
+	// ConsumeFrom is where a consumer group without offsets starts:
+	// consumer.ConsumeFromBegin or consumer.ConsumeFromEnd.
+	ConsumeFrom string
+	// FlushCheckpointIntervalSecond is how often offsets are committed, 0
+	// commits synchronously after each batch.
+	FlushCheckpointIntervalSecond int
+	// MaxBatchLogCount is the most logs given to one Process call.
+	MaxBatchLogCount int
+	// MaxProcessAttempts is how often a batch is processed before it is given
+	// to DeadLetterSink and skipped. 0 retries the batch forever.
+	MaxProcessAttempts int
+	DeadLetterSink     consumer.DeadLetterSink
+	// Decode turns a Kafka message into a log group, DecodeJSON by default.
+	Decode DecodeFunc
+	// Sarama, when set, adjusts the sarama configuration built from the
+	// fields above.
+	Sarama func(config *sarama.Config)
+	Logger *log.Logger
+}
+
+func GetDefaultConfig() *Config {
+	return &Config{
+		LoggerConfig: common.LoggerConfig{
+			LogLevel:      "info",
+			LogFileName:   "",
+			IsJsonType:    false,
+			LogMaxSize:    10,
+			LogMaxBackups: 10,
+			LogCompress:   false,
+		},
+		ConsumeFrom:                   consumer.ConsumeFromBegin,
+		FlushCheckpointIntervalSecond: 5,
+		MaxBatchLogCount:              100,
+	}
+}
+
+func validateConfig(c *Config) error {
+	if len(c.ProjectID) == 0 {
+		return errors.New("empty ProjectID")
+	}
+
+	if len(c.TopicID) == 0 {
+		return errors.New("empty TopicID")
+	}
+
+	if len(c.ConsumerGroupName) == 0 {
+		return errors.New("empty ConsumerGroupName")
+	}
+
+	if len(c.Brokers) == 0 && len(c.Region) == 0 {
+		return errors.New("empty Region. required when Brokers is not set")
+	}
+
+	if c.ConsumeFrom != consumer.ConsumeFromBegin && c.ConsumeFrom != consumer.ConsumeFromEnd {
+		return errors.New("invalid ConsumeFrom. valid options: \"begin\", \"end\"")
+	}
+
+	if c.FlushCheckpointIntervalSecond < 0 || c.FlushCheckpointIntervalSecond > 300 {
+		return errors.New("invalid FlushCheckpointIntervalSecond. acceptable range: [0, 300]")
+	}
+
+	if c.MaxBatchLogCount <= 0 || c.MaxBatchLogCount > 10000 {
+		return errors.New("invalid MaxBatchLogCount. acceptable range: [1, 10000]")
+	}
+
+	if c.MaxProcessAttempts < 0 {
+		return errors.New("invalid MaxProcessAttempts. acceptable range: [0, +inf)")
+	}
+
+	if c.MaxProcessAttempts > 0 && c.DeadLetterSink == nil {
+		return errors.New("empty DeadLetterSink. required when MaxProcessAttempts is set")
+	}
+
+	return nil
+}
+
+// Brokers returns the Kafka endpoint of a region, such as
+// tls-cn-beijing.volces.com:9093, or tls-cn-beijing.ivolces.com:9093 for the
+// private network.
+func Brokers(region string, privateNetwork bool) []string {
+	domain := "volces.com"
+	if privateNetwork {
+		domain = "ivolces.com"
+	}
+
+	return []string{fmt.Sprintf("tls-%s.%s:%d", region, domain, kafkaPort)}
+}
+
+// NewSaramaConfig returns the sarama configuration of a consumer group of
+// conf: SASL/PLAIN over TLS with the credentials of conf, offsets committed
+// as conf asks. Credentials of a CredentialsProvider are retrieved once, a
+// consumer group has to be created again when they expire.
+func NewSaramaConfig(conf *Config) (*sarama.Config, error) {
+	accessKeyID, accessKeySecret := conf.AccessKeyID, conf.AccessKeySecret
+	if conf.CredentialsProvider != nil {
+		credentials, err := conf.CredentialsProvider.Retrieve()
+		if err != nil {
+			return nil, err
+		}
+		accessKeyID, accessKeySecret = credentials.AccessKeyID, credentials.SecretAccessKey
+	}
+	if len(accessKeyID) == 0 || len(accessKeySecret) == 0 {
+		return nil, errors.New("empty AccessKeyID or AccessKeySecret")
+	}
+
+	config := sarama.NewConfig()
+	config.Version = sarama.V2_0_0_0
+	config.Net.TLS.Enable = true
+	config.Net.TLS.Config = &tls.Config{MinVersion: tls.VersionTLS12}
+	config.Net.SASL.Enable = true
+	config.Net.SASL.Mechanism = sarama.SASLTypePlaintext
+	config.Net.SASL.User = conf.ProjectID
+	config.Net.SASL.Password = accessKeyID + "#" + accessKeySecret
+	config.Consumer.Return.Errors = true
+	config.Consumer.Offsets.Initial = sarama.OffsetOldest
+	if conf.ConsumeFrom == consumer.ConsumeFromEnd {
+		config.Consumer.Offsets.Initial = sarama.OffsetNewest
+	}
+	if conf.FlushCheckpointIntervalSecond > 0 {
+		config.Consumer.Offsets.AutoCommit.Interval = time.Duration(conf.FlushCheckpointIntervalSecond) * time.Second
+	} else {
+		config.Consumer.Offsets.AutoCommit.Enable = false
+	}
+	if conf.Sarama != nil {
+		conf.Sarama(config)
+	}
+
+	return config, config.Validate()
+}
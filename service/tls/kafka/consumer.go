@@ -0,0 +1,204 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+
+	"github.com/volcengine/volc-sdk-golang/base"
+	"github.com/volcengine/volc-sdk-golang/service/tls"
+	"github.com/volcengine/volc-sdk-golang/service/tls/common"
+	"github.com/volcengine/volc-sdk-golang/service/tls/consumer"
+)
+
+const (
+	consumeRetryBaseInterval = time.Second
+	consumeRetryMaxInterval  = 30 * time.Second
+)
+
+// Consumer consumes a topic through the Kafka protocol, delivering its logs
+// to a consumer.Processor like the native consumer does.
+type Consumer interface {
+	Start() error
+	Stop()
+}
+
+// EnableConsumer turns on Kafka consumption of a topic unless it is on
+// already, and returns the Kafka topic to consume.
+func EnableConsumer(ctx context.Context, client tls.Client, topicID string) (string, error) {
+	request := &tls.DescribeKafkaConsumerRequest{TopicID: topicID}
+	resp, err := client.DescribeKafkaConsumerCtx(ctx, request)
+	if err != nil {
+		return "", err
+	}
+	if resp.AllowConsume && resp.ConsumeTopic != "" {
+		return resp.ConsumeTopic, nil
+	}
+
+	if _, err := client.OpenKafkaConsumerCtx(ctx, &tls.OpenKafkaConsumerRequest{TopicID: topicID}); err != nil {
+		return "", err
+	}
+	if resp, err = client.DescribeKafkaConsumerCtx(ctx, request); err != nil {
+		return "", err
+	}
+	if !resp.AllowConsume || resp.ConsumeTopic == "" {
+		return "", errors.New("kafka consumption of topic " + topicID + " is not enabled")
+	}
+
+	return resp.ConsumeTopic, nil
+}
+
+// NewConsumerGroup enables Kafka consumption of conf.TopicID with client and
+// returns a sarama consumer group of conf.ConsumerGroupName along with the
+// Kafka topic it should consume.
+func NewConsumerGroup(ctx context.Context, client tls.Client, conf *Config) (sarama.ConsumerGroup, string, error) {
+	if err := validateConfig(conf); err != nil {
+		return nil, "", err
+	}
+
+	config, err := NewSaramaConfig(conf)
+	if err != nil {
+		return nil, "", err
+	}
+	topic, err := EnableConsumer(ctx, client, conf.TopicID)
+	if err != nil {
+		return nil, "", err
+	}
+	brokers := conf.Brokers
+	if len(brokers) == 0 {
+		brokers = Brokers(conf.Region, conf.PrivateNetwork)
+	}
+	group, err := sarama.NewConsumerGroup(brokers, conf.ConsumerGroupName, config)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return group, topic, nil
+}
+
+type kafkaConsumer struct {
+	ctx       context.Context
+	cancel    context.CancelFunc
+	logger    log.Logger
+	conf      *Config
+	processor consumer.Processor
+	client    tls.Client
+	group     sarama.ConsumerGroup
+	stopped   int32
+	wg        sync.WaitGroup
+	errorsWg  sync.WaitGroup
+}
+
+// NewConsumer returns a consumer of conf.TopicID calling processor with the
+// logs of each partition, which is the shard of Batch.Shard. Batches follow
+// the semantics of consumer.Processor: an error keeps the offset of the
+// partition where it was until the batch is processed, or given to
+// conf.DeadLetterSink after conf.MaxProcessAttempts. Messages Decode fails on
+// are logged and skipped.
+func NewConsumer(ctx context.Context, conf *Config, processor consumer.Processor) (Consumer, error) {
+	if err := validateConfig(conf); err != nil {
+		return nil, err
+	}
+
+	client := tls.NewClient(conf.Endpoint, conf.AccessKeyID, conf.AccessKeySecret, conf.SecurityToken, conf.Region)
+	if conf.CredentialsProvider != nil {
+		client.SetCredentialsProvider(conf.CredentialsProvider)
+	}
+	var logger log.Logger
+	if conf.Logger != nil {
+		logger = *conf.Logger
+	} else {
+		logger = common.LogConfig(conf.LoggerConfig)
+	}
+
+	return &kafkaConsumer{
+		ctx:       ctx,
+		logger:    logger,
+		conf:      conf,
+		processor: processor,
+		client:    client,
+	}, nil
+}
+
+func (c *kafkaConsumer) Start() error {
+	if c.group != nil {
+		return errors.New("consumer is started already")
+	}
+
+	ctx, cancel := context.WithCancel(c.ctx)
+	group, topic, err := NewConsumerGroup(ctx, c.client, c.conf)
+	if err != nil {
+		cancel()
+		return err
+	}
+	c.cancel = cancel
+	c.group = group
+
+	handler := newGroupHandler(c.logger, c.conf, c.processor, func() bool {
+		return atomic.LoadInt32(&c.stopped) == 1
+	})
+	c.wg.Add(1)
+	go c.run(ctx, topic, handler)
+	c.errorsWg.Add(1)
+	go c.logErrors()
+
+	return nil
+}
+
+// run consumes the topic until ctx is cancelled, Consume returns whenever
+// the partitions of the group are rebalanced.
+func (c *kafkaConsumer) run(ctx context.Context, topic string, handler sarama.ConsumerGroupHandler) {
+	level.Info(c.logger).Log("msg", "kafka consumer start", "topic", topic)
+	defer c.wg.Done()
+
+	attempts := 0
+	for ctx.Err() == nil {
+		err := c.group.Consume(ctx, []string{topic}, handler)
+		if err == nil || ctx.Err() != nil {
+			attempts = 0
+			continue
+		}
+
+		attempts++
+		level.Warn(c.logger).Log("msg", "consume failed, retry later", "topic", topic, "attempts", attempts, "error", err)
+		timer := time.NewTimer(base.ExponentialBackoff(attempts, consumeRetryBaseInterval, consumeRetryMaxInterval))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}
+}
+
+func (c *kafkaConsumer) logErrors() {
+	defer c.errorsWg.Done()
+
+	for err := range c.group.Errors() {
+		level.Warn(c.logger).Log("msg", "kafka consumer error", "error", err)
+	}
+}
+
+// Stop waits for the running batches and shuts the processors of the
+// partitions down before it returns.
+func (c *kafkaConsumer) Stop() {
+	if c.group == nil {
+		return
+	}
+
+	atomic.StoreInt32(&c.stopped, 1)
+	c.cancel()
+	// The session commits the marked offsets when Consume returns, before the
+	// group leaves.
+	c.wg.Wait()
+	if err := c.group.Close(); err != nil {
+		level.Warn(c.logger).Log("msg", "close kafka consumer group failed", "error", err)
+	}
+	c.errorsWg.Wait()
+	level.Info(c.logger).Log("msg", "kafka consumer stopped")
+}
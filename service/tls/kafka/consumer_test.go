@@ -0,0 +1,262 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/go-kit/kit/log"
+
+	"github.com/volcengine/volc-sdk-golang/service/tls"
+	"github.com/volcengine/volc-sdk-golang/service/tls/consumer"
+	"github.com/volcengine/volc-sdk-golang/service/tls/tlstest"
+)
+
+func newTestConfig() *Config {
+	conf := GetDefaultConfig()
+	conf.Region = tlstest.Region
+	conf.AccessKeyID = tlstest.AccessKeyID
+	conf.AccessKeySecret = tlstest.AccessKeySecret
+	conf.ProjectID = "project"
+	conf.TopicID = "topic"
+	conf.ConsumerGroupName = "group"
+	return conf
+}
+
+func TestEnableConsumer(t *testing.T) {
+	server := tlstest.NewServer()
+	defer server.Close()
+	server.CreateTopic("topic", 1)
+	client := server.NewClient()
+
+	for i := 0; i < 2; i++ {
+		topic, err := EnableConsumer(context.Background(), client, "topic")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if topic != tlstest.KafkaTopic("topic") {
+			t.Fatalf("got Kafka topic %q", topic)
+		}
+	}
+	if n := server.Requests(tls.PathOpenKafkaConsumer); n != 1 {
+		t.Fatalf("opened Kafka consumption %d times, want once", n)
+	}
+
+	if _, err := EnableConsumer(context.Background(), client, "missing"); err == nil {
+		t.Fatal("enabled a missing topic")
+	}
+}
+
+func TestNewSaramaConfig(t *testing.T) {
+	conf := newTestConfig()
+	conf.ConsumeFrom = consumer.ConsumeFromEnd
+	conf.FlushCheckpointIntervalSecond = 0
+	config, err := NewSaramaConfig(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Net.SASL.User != "project" || config.Net.SASL.Password != tlstest.AccessKeyID+"#"+tlstest.AccessKeySecret {
+		t.Fatalf("got SASL user %q password %q", config.Net.SASL.User, config.Net.SASL.Password)
+	}
+	if !config.Net.TLS.Enable || config.Net.SASL.Mechanism != sarama.SASLTypePlaintext {
+		t.Fatal("SASL_SSL is not configured")
+	}
+	if config.Consumer.Offsets.Initial != sarama.OffsetNewest || config.Consumer.Offsets.AutoCommit.Enable {
+		t.Fatal("offsets are not configured as asked")
+	}
+
+	if got := Brokers("cn-shanghai", true); !reflect.DeepEqual(got, []string{"tls-cn-shanghai.ivolces.com:9093"}) {
+		t.Fatalf("got brokers %v", got)
+	}
+
+	conf.AccessKeySecret = ""
+	if _, err := NewSaramaConfig(conf); err == nil {
+		t.Fatal("built a config without credentials")
+	}
+}
+
+type fakeSession struct {
+	ctx context.Context
+
+	lock    sync.Mutex
+	marked  []int64
+	commits int
+}
+
+func (s *fakeSession) Claims() map[string][]int32                                              { return nil }
+func (s *fakeSession) MemberID() string                                                        { return "member" }
+func (s *fakeSession) GenerationID() int32                                                     { return 1 }
+func (s *fakeSession) MarkOffset(topic string, partition int32, offset int64, metadata string) {}
+func (s *fakeSession) ResetOffset(topic string, partition int32, offset int64, metadata string) {
+}
+func (s *fakeSession) Context() context.Context { return s.ctx }
+
+func (s *fakeSession) MarkMessage(msg *sarama.ConsumerMessage, metadata string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.marked = append(s.marked, msg.Offset)
+}
+
+func (s *fakeSession) Commit() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.commits++
+}
+
+func (s *fakeSession) lastMarked() int64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if len(s.marked) == 0 {
+		return -1
+	}
+	return s.marked[len(s.marked)-1]
+}
+
+type fakeClaim struct {
+	messages chan *sarama.ConsumerMessage
+}
+
+func (c *fakeClaim) Topic() string                            { return "out-topic" }
+func (c *fakeClaim) Partition() int32                         { return 3 }
+func (c *fakeClaim) InitialOffset() int64                     { return 0 }
+func (c *fakeClaim) HighWaterMarkOffset() int64               { return 0 }
+func (c *fakeClaim) Messages() <-chan *sarama.ConsumerMessage { return c.messages }
+
+func newTestClaim(values ...string) *fakeClaim {
+	claim := &fakeClaim{messages: make(chan *sarama.ConsumerMessage, len(values))}
+	for i, value := range values {
+		claim.messages <- &sarama.ConsumerMessage{Topic: "out-topic", Partition: 3, Offset: int64(i), Value: []byte(value)}
+	}
+	return claim
+}
+
+type recordingProcessor struct {
+	consumer.ProcessFunc
+
+	lock      sync.Mutex
+	processed []string
+	shutdowns []consumer.ShutdownReason
+}
+
+func (p *recordingProcessor) Shutdown(shard *tls.ConsumeShard, reason consumer.ShutdownReason) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.shutdowns = append(p.shutdowns, reason)
+}
+
+func (p *recordingProcessor) record(batch *consumer.Batch) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for _, group := range batch.Logs.LogGroups {
+		for _, log := range group.Logs {
+			p.processed = append(p.processed, log.Contents[0].Value)
+		}
+	}
+}
+
+func (p *recordingProcessor) values() []string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return append([]string(nil), p.processed...)
+}
+
+func waitFor(f func() bool) bool {
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if f() {
+			return true
+		}
+	}
+	return false
+}
+
+func TestConsumeClaim(t *testing.T) {
+	processor := &recordingProcessor{}
+	failures := 1
+	processor.ProcessFunc = func(ctx context.Context, batch *consumer.Batch) error {
+		if batch.Shard.TopicID != "topic" || batch.Shard.ShardID != 3 {
+			t.Errorf("got shard %v", batch.Shard)
+		}
+		if failures > 0 {
+			failures--
+			return errors.New("not yet")
+		}
+		processor.record(batch)
+		return nil
+	}
+
+	conf := newTestConfig()
+	conf.FlushCheckpointIntervalSecond = 0
+	conf.MaxBatchLogCount = 2
+	handler := newGroupHandler(log.NewNopLogger(), conf, processor, func() bool { return true })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	session := &fakeSession{ctx: ctx}
+	claim := newTestClaim(`{"msg":"a"}`, `{"msg":"b"}`, `broken`, `{"msg":"c"}`)
+	done := make(chan error)
+	go func() { done <- handler.ConsumeClaim(session, claim) }()
+
+	// The broken message is skipped, the failed batch is processed again.
+	if !waitFor(func() bool { return session.lastMarked() == 3 }) {
+		t.Fatalf("offsets not marked, got %v", session.marked)
+	}
+	if got := processor.values(); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Fatalf("got processed %v", got)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if session.commits != len(session.marked) {
+		t.Fatalf("committed %d times for %d batches", session.commits, len(session.marked))
+	}
+	if !reflect.DeepEqual(processor.shutdowns, []consumer.ShutdownReason{consumer.ShutdownReasonConsumerStopped}) {
+		t.Fatalf("got shutdowns %v", processor.shutdowns)
+	}
+}
+
+func TestConsumeClaimDeadLetter(t *testing.T) {
+	processor := &recordingProcessor{}
+	processor.ProcessFunc = func(ctx context.Context, batch *consumer.Batch) error {
+		return errors.New("always")
+	}
+	letters := make(chan *consumer.DeadLetter, 1)
+
+	conf := newTestConfig()
+	conf.MaxProcessAttempts = 1
+	conf.DeadLetterSink = consumer.DeadLetterFunc(func(ctx context.Context, letter *consumer.DeadLetter) error {
+		letters <- letter
+		return nil
+	})
+	handler := newGroupHandler(log.NewNopLogger(), conf, processor, func() bool { return false })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	session := &fakeSession{ctx: ctx}
+	done := make(chan error)
+	go func() { done <- handler.ConsumeClaim(session, newTestClaim(`{"msg":"a"}`)) }()
+
+	select {
+	case letter := <-letters:
+		if letter.Attempts != 1 || letter.Batch.Cursor != "1" || !strings.Contains(letter.Err.Error(), "always") {
+			t.Fatalf("got dead letter %+v", letter)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no dead letter")
+	}
+	if !waitFor(func() bool { return session.lastMarked() == 0 }) {
+		t.Fatal("dead letter not marked")
+	}
+	cancel()
+	<-done
+	if session.commits != 0 {
+		t.Fatalf("committed %d times, want auto commit", session.commits)
+	}
+	if !reflect.DeepEqual(processor.shutdowns, []consumer.ShutdownReason{consumer.ShutdownReasonShardReassigned}) {
+		t.Fatalf("got shutdowns %v", processor.shutdowns)
+	}
+}
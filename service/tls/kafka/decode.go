@@ -0,0 +1,92 @@
+package kafka
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Shopify/sarama"
+
+	"github.com/volcengine/volc-sdk-golang/service/tls/pb"
+)
+
+// Fields of the JSON messages which hold the log group metadata.
+const (
+	FieldTime   = "__time__"
+	FieldSource = "__source__"
+	FieldPath   = "__path__"
+
+	tagPrefix = "__tag__"
+	tagSuffix = "__"
+)
+
+// DecodeFunc turns a Kafka message into a log group of one log.
+type DecodeFunc func(msg *sarama.ConsumerMessage) (*pb.LogGroup, error)
+
+// DecodeJSON decodes a log in the JSON format TLS writes to Kafka: a flat
+// object of its contents along with __time__, __source__, __path__ and a
+// __tag__<key>__ field per log tag. Contents are sorted by key, values which
+// are not strings are kept as JSON text. Logs without __time__ take the
+// timestamp of the message.
+func DecodeJSON(msg *sarama.ConsumerMessage) (*pb.LogGroup, error) {
+	decoder := json.NewDecoder(bytes.NewReader(msg.Value))
+	decoder.UseNumber()
+	var fields map[string]interface{}
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, err
+	}
+	if fields == nil {
+		return nil, errors.New("message is not a JSON object")
+	}
+
+	group := &pb.LogGroup{}
+	log := &pb.Log{}
+	if !msg.Timestamp.IsZero() {
+		log.Time = msg.Timestamp.UnixNano() / 1e6
+	}
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value, err := fieldText(fields[key])
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case key == FieldTime:
+			if log.Time, err = strconv.ParseInt(value, 10, 64); err != nil {
+				return nil, errors.New("invalid " + FieldTime + " " + value)
+			}
+		case key == FieldSource:
+			group.Source = value
+		case key == FieldPath:
+			group.FileName = value
+		case len(key) > len(tagPrefix)+len(tagSuffix) && strings.HasPrefix(key, tagPrefix) && strings.HasSuffix(key, tagSuffix):
+			group.LogTags = append(group.LogTags, &pb.LogTag{Key: key[len(tagPrefix) : len(key)-len(tagSuffix)], Value: value})
+		default:
+			log.Contents = append(log.Contents, &pb.LogContent{Key: key, Value: value})
+		}
+	}
+	group.Logs = []*pb.Log{log}
+
+	return group, nil
+}
+
+func fieldText(value interface{}) (string, error) {
+	switch value := value.(type) {
+	case string:
+		return value, nil
+	case json.Number:
+		return value.String(), nil
+	case nil:
+		return "", nil
+	default:
+		data, err := json.Marshal(value)
+		return string(data), err
+	}
+}
@@ -0,0 +1,55 @@
+package kafka
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+
+	"github.com/volcengine/volc-sdk-golang/service/tls/pb"
+)
+
+func TestDecodeJSON(t *testing.T) {
+	msg := &sarama.ConsumerMessage{
+		Value:     []byte(`{"__time__":1700000000123,"__source__":"10.0.0.1","__path__":"/var/log/app.log","__tag__env__":"prod","msg":"hello","code":200,"extra":{"a":1},"empty":null}`),
+		Timestamp: time.Unix(1, 0),
+	}
+	group, err := DecodeJSON(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &pb.LogGroup{
+		Source:   "10.0.0.1",
+		FileName: "/var/log/app.log",
+		LogTags:  []*pb.LogTag{{Key: "env", Value: "prod"}},
+		Logs: []*pb.Log{{
+			Time: 1700000000123,
+			Contents: []*pb.LogContent{
+				{Key: "code", Value: "200"},
+				{Key: "empty", Value: ""},
+				{Key: "extra", Value: `{"a":1}`},
+				{Key: "msg", Value: "hello"},
+			},
+		}},
+	}
+	if !reflect.DeepEqual(group, want) {
+		t.Fatalf("got %v, want %v", group, want)
+	}
+
+	// Without __time__ the message timestamp is used.
+	group, err = DecodeJSON(&sarama.ConsumerMessage{Value: []byte(`{"msg":"hello"}`), Timestamp: time.Unix(2, 0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if group.Logs[0].Time != 2000 {
+		t.Fatalf("got time %d, want 2000", group.Logs[0].Time)
+	}
+
+	for _, value := range []string{`not json`, `null`, `{"__time__":"soon"}`} {
+		if _, err := DecodeJSON(&sarama.ConsumerMessage{Value: []byte(value)}); err == nil {
+			t.Fatalf("decoded %s", value)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package kafka
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+
+	"github.com/volcengine/volc-sdk-golang/service/tls"
+	"github.com/volcengine/volc-sdk-golang/service/tls/consumer"
+	"github.com/volcengine/volc-sdk-golang/service/tls/pb"
+)
+
+// groupHandler runs a consumer.Processor on the partitions claimed by a
+// sarama consumer group session, one goroutine per partition.
+type groupHandler struct {
+	logger    log.Logger
+	conf      *Config
+	processor consumer.Processor
+	retrier   *consumer.BatchRetrier
+	decode    DecodeFunc
+	// stopped tells whether the session ends because the consumer stops.
+	stopped func() bool
+}
+
+func newGroupHandler(logger log.Logger, conf *Config, processor consumer.Processor, stopped func() bool) *groupHandler {
+	decode := conf.Decode
+	if decode == nil {
+		decode = DecodeJSON
+	}
+
+	return &groupHandler{
+		logger:    logger,
+		conf:      conf,
+		processor: processor,
+		retrier: &consumer.BatchRetrier{
+			Processor:          processor,
+			MaxProcessAttempts: conf.MaxProcessAttempts,
+			DeadLetterSink:     conf.DeadLetterSink,
+			Logger:             logger,
+		},
+		decode:  decode,
+		stopped: stopped,
+	}
+}
+
+func (h *groupHandler) Setup(session sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (h *groupHandler) Cleanup(session sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+// ConsumeClaim processes the messages of a partition batch by batch, marking
+// the offset past a batch once it was processed or sent to the dead letter
+// sink. It returns when the session ends, leaving a running batch unmarked.
+func (h *groupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	ctx := session.Context()
+	shard := &tls.ConsumeShard{TopicID: h.conf.TopicID, ShardID: int(claim.Partition())}
+	if !h.initialize(ctx, shard) {
+		return nil
+	}
+	defer func() {
+		reason := consumer.ShutdownReasonShardReassigned
+		if h.stopped() {
+			reason = consumer.ShutdownReasonConsumerStopped
+		}
+		h.processor.Shutdown(shard, reason)
+	}()
+
+	for {
+		batch, last := h.nextBatch(ctx, shard, claim.Messages())
+		if last == nil {
+			return nil
+		}
+		if len(batch.Logs.LogGroups) > 0 && h.retrier.Run(ctx, batch) != nil {
+			return nil
+		}
+		session.MarkMessage(last, "")
+		if h.conf.FlushCheckpointIntervalSecond == 0 {
+			session.Commit()
+		}
+	}
+}
+
+// initialize calls Processor.Initialize until it succeeds or ctx is done.
+func (h *groupHandler) initialize(ctx context.Context, shard *tls.ConsumeShard) bool {
+	for attempts := 1; ; attempts++ {
+		err := h.processor.Initialize(shard)
+		if err == nil {
+			return true
+		}
+		level.Error(h.logger).Log("error", "init partition failed in initializing processor, err: "+err.Error(), "topic", shard.TopicID, "shard", shard.ShardID)
+
+		timer := time.NewTimer(consumer.ProcessBackoff(attempts))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return false
+		}
+	}
+}
+
+// nextBatch waits for a message and adds those which arrived meanwhile, up
+// to MaxBatchLogCount. It returns the last message of the batch, nil when
+// the claim ends.
+func (h *groupHandler) nextBatch(ctx context.Context, shard *tls.ConsumeShard, messages <-chan *sarama.ConsumerMessage) (*consumer.Batch, *sarama.ConsumerMessage) {
+	var last *sarama.ConsumerMessage
+	select {
+	case msg, ok := <-messages:
+		if !ok {
+			return nil, nil
+		}
+		last = msg
+	case <-ctx.Done():
+		return nil, nil
+	}
+
+	batch := &consumer.Batch{Shard: shard, Logs: &pb.LogGroupList{}}
+	h.add(batch, last)
+fill:
+	for len(batch.Logs.LogGroups) < h.conf.MaxBatchLogCount {
+		select {
+		case msg, ok := <-messages:
+			if !ok {
+				break fill
+			}
+			last = msg
+			h.add(batch, last)
+		default:
+			break fill
+		}
+	}
+	batch.Cursor = strconv.FormatInt(last.Offset+1, 10)
+
+	return batch, last
+}
+
+func (h *groupHandler) add(batch *consumer.Batch, msg *sarama.ConsumerMessage) {
+	group, err := h.decode(msg)
+	if err != nil {
+		level.Warn(h.logger).Log("msg", "decode message failed, skip it", "topic", batch.Shard.TopicID, "shard", batch.Shard.ShardID, "offset", msg.Offset, "error", err)
+		return
+	}
+	batch.Logs.LogGroups = append(batch.Logs.LogGroups, group)
+}
@@ -1,6 +1,7 @@
 package tls
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"io/ioutil"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	"github.com/gogo/protobuf/proto"
+	"github.com/klauspost/compress/snappy"
 	"github.com/pierrec/lz4"
 	"github.com/volcengine/volc-sdk-golang/service/tls/pb"
 )
@@ -19,6 +21,10 @@ const (
 )
 
 func (c *LsClient) PutLogs(request *PutLogsRequest) (r *CommonResponse, e error) {
+	return c.PutLogsCtx(context.Background(), request)
+}
+
+func (c *LsClient) PutLogsCtx(ctx context.Context, request *PutLogsRequest) (r *CommonResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -59,7 +65,7 @@ func (c *LsClient) PutLogs(request *PutLogsRequest) (r *CommonResponse, e error)
 		"x-tls-compresstype": request.CompressType,
 	}
 
-	rawResponse, err := c.Request(http.MethodPost, PathPutLogs, params, c.assembleHeader(request.CommonRequest, headers), bodyBytes)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPost, PathPutLogs, params, c.assembleHeader(request.CommonRequest, headers), bodyBytes)
 	if err != nil {
 		return nil, err
 	}
@@ -81,6 +87,10 @@ func (c *LsClient) PutLogs(request *PutLogsRequest) (r *CommonResponse, e error)
 }
 
 func (c *LsClient) PutLogsV2(request *PutLogsV2Request) (r *CommonResponse, e error) {
+	return c.PutLogsV2Ctx(context.Background(), request)
+}
+
+func (c *LsClient) PutLogsV2Ctx(ctx context.Context, request *PutLogsV2Request) (r *CommonResponse, e error) {
 	if len(request.Logs) == 0 {
 		return nil, nil
 	}
@@ -117,7 +127,7 @@ func (c *LsClient) PutLogsV2(request *PutLogsV2Request) (r *CommonResponse, e er
 			},
 		},
 	}
-	return c.PutLogs(realRequest)
+	return c.PutLogsCtx(ctx, realRequest)
 }
 
 func lz4Decompress(input []byte, rawLength int64) ([]byte, error) {
@@ -129,6 +139,15 @@ func lz4Decompress(input []byte, rawLength int64) ([]byte, error) {
 	return decompressedBuffer, nil
 }
 
+func zstdDecompress(input []byte, rawLength int64) ([]byte, error) {
+	decoder, err := getZstdDecoder()
+	if err != nil {
+		return nil, err
+	}
+
+	return decoder.DecodeAll(input, make([]byte, 0, rawLength))
+}
+
 func parseLogList(input []byte, compression string, rawSize int64) (*pb.LogGroupList, error) {
 	var (
 		decompressed []byte
@@ -136,11 +155,21 @@ func parseLogList(input []byte, compression string, rawSize int64) (*pb.LogGroup
 	)
 
 	switch strings.ToLower(compression) {
-	case "lz4":
+	case CompressLz4:
 		decompressed, err = lz4Decompress(input, rawSize)
 		if err != nil {
 			return nil, err
 		}
+	case CompressZstd:
+		decompressed, err = zstdDecompress(input, rawSize)
+		if err != nil {
+			return nil, err
+		}
+	case CompressSnappy:
+		decompressed, err = snappy.Decode(nil, input)
+		if err != nil {
+			return nil, err
+		}
 	default:
 		decompressed = input
 	}
@@ -154,6 +183,10 @@ func parseLogList(input []byte, compression string, rawSize int64) (*pb.LogGroup
 }
 
 func (c *LsClient) ConsumeLogs(request *ConsumeLogsRequest) (r *ConsumeLogsResponse, e error) {
+	return c.ConsumeLogsCtx(context.Background(), request)
+}
+
+func (c *LsClient) ConsumeLogsCtx(ctx context.Context, request *ConsumeLogsRequest) (r *ConsumeLogsResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -196,7 +229,7 @@ func (c *LsClient) ConsumeLogs(request *ConsumeLogsRequest) (r *ConsumeLogsRespo
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodGet, PathConsumeLogs, params, c.assembleHeader(request.CommonRequest, headers), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodGet, PathConsumeLogs, params, c.assembleHeader(request.CommonRequest, headers), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -250,6 +283,10 @@ func (c *LsClient) ConsumeLogs(request *ConsumeLogsRequest) (r *ConsumeLogsRespo
 }
 
 func (c *LsClient) DescribeCursor(request *DescribeCursorRequest) (r *DescribeCursorResponse, e error) {
+	return c.DescribeCursorCtx(context.Background(), request)
+}
+
+func (c *LsClient) DescribeCursorCtx(ctx context.Context, request *DescribeCursorRequest) (r *DescribeCursorResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -272,7 +309,7 @@ func (c *LsClient) DescribeCursor(request *DescribeCursorRequest) (r *DescribeCu
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodGet, PathDescribeCursor, params, c.assembleHeader(request.CommonRequest, headers), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodGet, PathDescribeCursor, params, c.assembleHeader(request.CommonRequest, headers), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -294,6 +331,10 @@ func (c *LsClient) DescribeCursor(request *DescribeCursorRequest) (r *DescribeCu
 }
 
 func (c *LsClient) DescribeLogContext(request *DescribeLogContextRequest) (r *DescribeLogContextResponse, e error) {
+	return c.DescribeLogContextCtx(context.Background(), request)
+}
+
+func (c *LsClient) DescribeLogContextCtx(ctx context.Context, request *DescribeLogContextRequest) (r *DescribeLogContextResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -324,7 +365,7 @@ func (c *LsClient) DescribeLogContext(request *DescribeLogContextRequest) (r *De
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPost, PathDescribeLogContext, params, c.assembleHeader(request.CommonRequest, headers), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPost, PathDescribeLogContext, params, c.assembleHeader(request.CommonRequest, headers), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -344,3 +385,13 @@ func (c *LsClient) DescribeLogContext(request *DescribeLogContextRequest) (r *De
 
 	return response, nil
 }
+
+// LogTime returns the time of a log, whose Time is in seconds or in
+// milliseconds as PutLogsV2 writes it.
+func LogTime(log *pb.Log) time.Time {
+	if log.Time > 1e11 || log.Time < -1e11 {
+		return time.Unix(0, log.Time*int64(time.Millisecond))
+	}
+
+	return time.Unix(log.Time, 0)
+}
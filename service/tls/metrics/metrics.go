@@ -0,0 +1,98 @@
+// Package metrics exposes the internal state of the TLS producer and consumer.
+// Set producer.Config.Metrics or consumer.Config.Metrics to a Metrics
+// implementation, e.g. a Registry, to collect them.
+package metrics
+
+import "time"
+
+// Labels are passed as key, value pairs.
+const (
+	LabelTopic     = "topic"
+	LabelShard     = "shard"
+	LabelErrorCode = "error_code"
+)
+
+// Producer metrics.
+const (
+	// ProducerPendingBytes is the gauge of log bytes accepted by SendLog(s)
+	// and not yet delivered or failed, the queue bounded by TotalSizeLnBytes.
+	ProducerPendingBytes = "tls_producer_pending_bytes"
+	// ProducerBatchesInFlight is the gauge of PutLogs requests being sent.
+	ProducerBatchesInFlight = "tls_producer_batches_in_flight"
+	// ProducerRetryQueueLength is the gauge of batches waiting to be retried.
+	ProducerRetryQueueLength = "tls_producer_retry_queue_length"
+	// ProducerSendLatency observes the PutLogs latency in seconds, by topic.
+	ProducerSendLatency = "tls_producer_send_latency_seconds"
+	// ProducerSentBytes and ProducerSentLogs count the delivered log bytes and
+	// logs, by topic.
+	ProducerSentBytes = "tls_producer_sent_bytes_total"
+	ProducerSentLogs  = "tls_producer_sent_logs_total"
+	// ProducerSendFailures counts failed PutLogs requests, including the ones
+	// retried, by topic and error code.
+	ProducerSendFailures = "tls_producer_send_failures_total"
+	// ProducerDroppedLogs counts the logs dropped by the overflow policy, by
+	// topic.
+	ProducerDroppedLogs = "tls_producer_dropped_logs_total"
+)
+
+// Consumer metrics.
+const (
+	// ConsumerFetchLag is the gauge of seconds between now and the newest log
+	// of the last batch fetched from a shard, 0 once the shard is caught up.
+	ConsumerFetchLag = "tls_consumer_fetch_lag_seconds"
+	// ConsumerCheckpointAge is the gauge of seconds since the checkpoint of a
+	// shard was last committed.
+	ConsumerCheckpointAge = "tls_consumer_checkpoint_age_seconds"
+	// ConsumerHeartbeatFailures counts failed consumer heartbeats.
+	ConsumerHeartbeatFailures = "tls_consumer_heartbeat_failures_total"
+)
+
+// Metrics receives the measurements of a producer or consumer. Its methods
+// are called concurrently and must not block.
+type Metrics interface {
+	// Add adds delta to a counter.
+	Add(name string, delta float64, labels ...string)
+	// Set sets a gauge.
+	Set(name string, value float64, labels ...string)
+	// Observe records a sample of a distribution, e.g. a latency.
+	Observe(name string, value float64, labels ...string)
+}
+
+// Deleter is implemented by the Metrics able to drop a series, e.g. the
+// gauges of a shard no longer consumed.
+type Deleter interface {
+	// Delete removes the series of name with labels.
+	Delete(name string, labels ...string)
+}
+
+// Delete removes a series from m, or sets it to 0 if m is not a Deleter.
+func Delete(m Metrics, name string, labels ...string) {
+	if d, ok := m.(Deleter); ok {
+		d.Delete(name, labels...)
+		return
+	}
+	m.Set(name, 0, labels...)
+}
+
+// Nop discards all measurements.
+type Nop struct{}
+
+func (Nop) Add(name string, delta float64, labels ...string) {}
+
+func (Nop) Set(name string, value float64, labels ...string) {}
+
+func (Nop) Observe(name string, value float64, labels ...string) {}
+
+// OrNop returns m, or Nop if m is nil.
+func OrNop(m Metrics) Metrics {
+	if m == nil {
+		return Nop{}
+	}
+
+	return m
+}
+
+// Since returns the seconds elapsed since t, the unit of the latency metrics.
+func Since(t time.Time) float64 {
+	return time.Since(t).Seconds()
+}
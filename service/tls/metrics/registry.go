@@ -0,0 +1,183 @@
+package metrics
+
+import (
+	"bytes"
+	"expvar"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type metricKind int
+
+const (
+	kindCounter metricKind = iota
+	kindGauge
+	kindSummary
+)
+
+func (k metricKind) String() string {
+	switch k {
+	case kindCounter:
+		return "counter"
+	case kindGauge:
+		return "gauge"
+	default:
+		return "summary"
+	}
+}
+
+type series struct {
+	name   string
+	labels string
+	kind   metricKind
+	value  float64
+	count  uint64
+}
+
+// Registry is an in-memory Metrics that serves its state in the Prometheus
+// text format and as an expvar variable. Observed samples are exported as a
+// summary with _sum and _count only.
+type Registry struct {
+	lock   sync.Mutex
+	series map[string]*series
+}
+
+func NewRegistry() *Registry {
+	return &Registry{series: make(map[string]*series)}
+}
+
+func (r *Registry) Add(name string, delta float64, labels ...string) {
+	r.update(name, kindCounter, labels, func(s *series) { s.value += delta })
+}
+
+func (r *Registry) Set(name string, value float64, labels ...string) {
+	r.update(name, kindGauge, labels, func(s *series) { s.value = value })
+}
+
+func (r *Registry) Observe(name string, value float64, labels ...string) {
+	r.update(name, kindSummary, labels, func(s *series) {
+		s.value += value
+		s.count++
+	})
+}
+
+func (r *Registry) Delete(name string, labels ...string) {
+	key := name + formatLabels(labels)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	delete(r.series, key)
+}
+
+func (r *Registry) update(name string, kind metricKind, labels []string, f func(s *series)) {
+	formatted := formatLabels(labels)
+	key := name + formatted
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	s, ok := r.series[key]
+	if !ok {
+		s = &series{name: name, labels: formatted, kind: kind}
+		r.series[key] = s
+	}
+	f(s)
+}
+
+func (r *Registry) snapshot() []series {
+	r.lock.Lock()
+	list := make([]series, 0, len(r.series))
+	for _, s := range r.series {
+		list = append(list, *s)
+	}
+	r.lock.Unlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].name != list[j].name {
+			return list[i].name < list[j].name
+		}
+		return list[i].labels < list[j].labels
+	})
+
+	return list
+}
+
+// WritePrometheus writes all series in the Prometheus text exposition format.
+func (r *Registry) WritePrometheus(w io.Writer) error {
+	var buf bytes.Buffer
+	lastName := ""
+	for _, s := range r.snapshot() {
+		if s.name != lastName {
+			fmt.Fprintf(&buf, "# TYPE %s %s\n", s.name, s.kind)
+			lastName = s.name
+		}
+		if s.kind == kindSummary {
+			fmt.Fprintf(&buf, "%s_sum%s %s\n", s.name, s.labels, formatValue(s.value))
+			fmt.Fprintf(&buf, "%s_count%s %d\n", s.name, s.labels, s.count)
+			continue
+		}
+		fmt.Fprintf(&buf, "%s%s %s\n", s.name, s.labels, formatValue(s.value))
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// ServeHTTP serves WritePrometheus, so the Registry can be mounted as the
+// /metrics endpoint scraped by Prometheus.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	r.WritePrometheus(w)
+}
+
+// Publish exports the registry as the expvar variable name, a map from the
+// series, e.g. `tls_producer_sent_logs_total{topic="x"}`, to its value.
+// Summaries are exported as their _sum and _count series. Like
+// expvar.Publish, it panics if name is already registered.
+func (r *Registry) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		values := make(map[string]float64)
+		for _, s := range r.snapshot() {
+			if s.kind == kindSummary {
+				values[s.name+"_sum"+s.labels] = s.value
+				values[s.name+"_count"+s.labels] = float64(s.count)
+				continue
+			}
+			values[s.name+s.labels] = s.value
+		}
+		return values
+	}))
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatLabels(labels []string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i := 0; i+1 < len(labels); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(labels[i])
+		b.WriteString(`="`)
+		b.WriteString(labelValueEscaper.Replace(labels[i+1]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+
+	return b.String()
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
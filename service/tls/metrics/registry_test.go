@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"expvar"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	r.Add(ProducerSentLogs, 2, LabelTopic, "a")
+	r.Add(ProducerSentLogs, 3, LabelTopic, "a")
+	r.Add(ProducerSentLogs, 1, LabelTopic, `b"\`)
+	r.Set(ProducerPendingBytes, 10)
+	r.Set(ProducerPendingBytes, 4)
+	r.Observe(ProducerSendLatency, 0.5, LabelTopic, "a")
+	r.Observe(ProducerSendLatency, 0.25, LabelTopic, "a")
+
+	var buf bytes.Buffer
+	if err := r.WritePrometheus(&buf); err != nil {
+		t.Fatal(err)
+	}
+	expect := `# TYPE tls_producer_pending_bytes gauge
+tls_producer_pending_bytes 4
+# TYPE tls_producer_send_latency_seconds summary
+tls_producer_send_latency_seconds_sum{topic="a"} 0.75
+tls_producer_send_latency_seconds_count{topic="a"} 2
+# TYPE tls_producer_sent_logs_total counter
+tls_producer_sent_logs_total{topic="a"} 5
+tls_producer_sent_logs_total{topic="b\"\\"} 1
+`
+	if buf.String() != expect {
+		t.Fatalf("unexpected output:\n%s", buf.String())
+	}
+
+	r.Set(ConsumerCheckpointAge, 1, LabelTopic, "a", LabelShard, "0")
+	Delete(r, ConsumerCheckpointAge, LabelTopic, "a", LabelShard, "0")
+	var deleted bytes.Buffer
+	r.WritePrometheus(&deleted)
+	if deleted.String() != expect {
+		t.Fatalf("unexpected output after Delete:\n%s", deleted.String())
+	}
+
+	r.Publish("tls_test_registry")
+	var values map[string]float64
+	if err := json.Unmarshal([]byte(expvar.Get("tls_test_registry").String()), &values); err != nil {
+		t.Fatal(err)
+	}
+	if values[`tls_producer_sent_logs_total{topic="a"}`] != 5 || values[`tls_producer_send_latency_seconds_count{topic="a"}`] != 2 {
+		t.Fatalf("unexpected expvar %v", values)
+	}
+}
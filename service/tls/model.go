@@ -438,17 +438,19 @@ func (v *DescribeShardsRequest) CheckValidation() error {
 	return nil
 }
 
+type ShardInfo struct {
+	TopicID           string `json:"TopicId"`
+	ShardID           int32  `json:"ShardId"`
+	InclusiveBeginKey string `json:"InclusiveBeginKey"`
+	ExclusiveEndKey   string `json:"ExclusiveEndKey"`
+	Status            string `json:"Status"`
+	ModifyTimestamp   string `json:"ModifyTime"`
+	StopWriteTime     string `json:"StopWriteTime"`
+}
+
 type DescribeShardsResponse struct {
 	CommonResponse
-	Shards []*struct {
-		TopicID           string `json:"TopicId"`
-		ShardID           int32  `json:"ShardId"`
-		InclusiveBeginKey string `json:"InclusiveBeginKey"`
-		ExclusiveEndKey   string `json:"ExclusiveEndKey"`
-		Status            string `json:"Status"`
-		ModifyTimestamp   string `json:"ModifyTime"`
-		StopWriteTime     string `json:"StopWriteTime"`
-	} `json:"Shards"`
+	Shards []*ShardInfo `json:"Shards"`
 
 	Total int `json:"Total"`
 }
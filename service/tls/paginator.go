@@ -0,0 +1,350 @@
+package tls
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/volcengine/volc-sdk-golang/base"
+)
+
+const defaultPaginatorPageSize = 100
+
+func paginatorPageSize(size int) int {
+	if size <= 0 {
+		return defaultPaginatorPageSize
+	}
+	return size
+}
+
+// DescribeProjectsPaginator pages through DescribeProjects lazily.
+type DescribeProjectsPaginator struct {
+	paginator *base.Paginator
+	page      []ProjectInfo
+}
+
+func NewDescribeProjectsPaginator(client Client, request *DescribeProjectsRequest) *DescribeProjectsPaginator {
+	req := *request
+	p := &DescribeProjectsPaginator{}
+	p.paginator = base.NewPageNumberPaginator(paginatorPageSize(req.PageSize), func(ctx context.Context, page, size int) (int, int, error) {
+		req.PageNumber, req.PageSize = page, size
+		resp, err := client.DescribeProjectsCtx(ctx, &req)
+		if err != nil {
+			return 0, 0, err
+		}
+		p.page = resp.Projects
+		return len(resp.Projects), int(resp.Total), nil
+	})
+	return p
+}
+
+func (p *DescribeProjectsPaginator) HasMorePages() bool {
+	return p.paginator.HasMorePages()
+}
+
+func (p *DescribeProjectsPaginator) NextPage(ctx context.Context) ([]ProjectInfo, error) {
+	if err := p.paginator.NextPage(ctx); err != nil {
+		return nil, err
+	}
+	return p.page, nil
+}
+
+// DescribeAllProjects collects up to max projects, all of them when max <= 0.
+func DescribeAllProjects(ctx context.Context, client Client, request *DescribeProjectsRequest, max int) ([]ProjectInfo, error) {
+	var all []ProjectInfo
+	p := NewDescribeProjectsPaginator(client, request)
+	err := p.paginator.Collect(ctx, max, func(keep int) {
+		all = append(all, p.page[:keep]...)
+	})
+	return all, err
+}
+
+// DescribeTopicsPaginator pages through DescribeTopics lazily.
+type DescribeTopicsPaginator struct {
+	paginator *base.Paginator
+	page      []*Topic
+}
+
+func NewDescribeTopicsPaginator(client Client, request *DescribeTopicsRequest) *DescribeTopicsPaginator {
+	req := *request
+	p := &DescribeTopicsPaginator{}
+	p.paginator = base.NewPageNumberPaginator(paginatorPageSize(req.PageSize), func(ctx context.Context, page, size int) (int, int, error) {
+		req.PageNumber, req.PageSize = page, size
+		resp, err := client.DescribeTopicsCtx(ctx, &req)
+		if err != nil {
+			return 0, 0, err
+		}
+		p.page = resp.Topics
+		return len(resp.Topics), resp.Total, nil
+	})
+	return p
+}
+
+func (p *DescribeTopicsPaginator) HasMorePages() bool {
+	return p.paginator.HasMorePages()
+}
+
+func (p *DescribeTopicsPaginator) NextPage(ctx context.Context) ([]*Topic, error) {
+	if err := p.paginator.NextPage(ctx); err != nil {
+		return nil, err
+	}
+	return p.page, nil
+}
+
+// DescribeAllTopics collects up to max topics, all of them when max <= 0.
+func DescribeAllTopics(ctx context.Context, client Client, request *DescribeTopicsRequest, max int) ([]*Topic, error) {
+	var all []*Topic
+	p := NewDescribeTopicsPaginator(client, request)
+	err := p.paginator.Collect(ctx, max, func(keep int) {
+		all = append(all, p.page[:keep]...)
+	})
+	return all, err
+}
+
+// DescribeShardsPaginator pages through DescribeShards lazily.
+type DescribeShardsPaginator struct {
+	paginator *base.Paginator
+	page      []*ShardInfo
+}
+
+func NewDescribeShardsPaginator(client Client, request *DescribeShardsRequest) *DescribeShardsPaginator {
+	req := *request
+	p := &DescribeShardsPaginator{}
+	p.paginator = base.NewPageNumberPaginator(paginatorPageSize(req.PageSize), func(ctx context.Context, page, size int) (int, int, error) {
+		req.PageNumber, req.PageSize = page, size
+		resp, err := client.DescribeShardsCtx(ctx, &req)
+		if err != nil {
+			return 0, 0, err
+		}
+		p.page = resp.Shards
+		return len(resp.Shards), resp.Total, nil
+	})
+	return p
+}
+
+func (p *DescribeShardsPaginator) HasMorePages() bool {
+	return p.paginator.HasMorePages()
+}
+
+func (p *DescribeShardsPaginator) NextPage(ctx context.Context) ([]*ShardInfo, error) {
+	if err := p.paginator.NextPage(ctx); err != nil {
+		return nil, err
+	}
+	return p.page, nil
+}
+
+// DescribeAllShards collects up to max shards, all of them when max <= 0.
+func DescribeAllShards(ctx context.Context, client Client, request *DescribeShardsRequest, max int) ([]*ShardInfo, error) {
+	var all []*ShardInfo
+	p := NewDescribeShardsPaginator(client, request)
+	err := p.paginator.Collect(ctx, max, func(keep int) {
+		all = append(all, p.page[:keep]...)
+	})
+	return all, err
+}
+
+// DescribeRulesPaginator pages through DescribeRules lazily.
+type DescribeRulesPaginator struct {
+	paginator *base.Paginator
+	page      []*RuleInfo
+}
+
+func NewDescribeRulesPaginator(client Client, request *DescribeRulesRequest) *DescribeRulesPaginator {
+	req := *request
+	p := &DescribeRulesPaginator{}
+	p.paginator = base.NewPageNumberPaginator(paginatorPageSize(req.PageSize), func(ctx context.Context, page, size int) (int, int, error) {
+		req.PageNumber, req.PageSize = page, size
+		resp, err := client.DescribeRulesCtx(ctx, &req)
+		if err != nil {
+			return 0, 0, err
+		}
+		p.page = resp.RuleInfos
+		return len(resp.RuleInfos), int(resp.Total), nil
+	})
+	return p
+}
+
+func (p *DescribeRulesPaginator) HasMorePages() bool {
+	return p.paginator.HasMorePages()
+}
+
+func (p *DescribeRulesPaginator) NextPage(ctx context.Context) ([]*RuleInfo, error) {
+	if err := p.paginator.NextPage(ctx); err != nil {
+		return nil, err
+	}
+	return p.page, nil
+}
+
+// DescribeAllRules collects up to max rules, all of them when max <= 0.
+func DescribeAllRules(ctx context.Context, client Client, request *DescribeRulesRequest, max int) ([]*RuleInfo, error) {
+	var all []*RuleInfo
+	p := NewDescribeRulesPaginator(client, request)
+	err := p.paginator.Collect(ctx, max, func(keep int) {
+		all = append(all, p.page[:keep]...)
+	})
+	return all, err
+}
+
+// DescribeAlarmsPaginator pages through DescribeAlarms lazily.
+type DescribeAlarmsPaginator struct {
+	paginator *base.Paginator
+	page      []QueryResp
+}
+
+func NewDescribeAlarmsPaginator(client Client, request *DescribeAlarmsRequest) *DescribeAlarmsPaginator {
+	req := *request
+	p := &DescribeAlarmsPaginator{}
+	p.paginator = base.NewPageNumberPaginator(paginatorPageSize(req.PageSize), func(ctx context.Context, page, size int) (int, int, error) {
+		req.PageNumber, req.PageSize = page, size
+		resp, err := client.DescribeAlarmsCtx(ctx, &req)
+		if err != nil {
+			return 0, 0, err
+		}
+		p.page = resp.AlarmPolicies
+		return len(resp.AlarmPolicies), int(resp.Total), nil
+	})
+	return p
+}
+
+func (p *DescribeAlarmsPaginator) HasMorePages() bool {
+	return p.paginator.HasMorePages()
+}
+
+func (p *DescribeAlarmsPaginator) NextPage(ctx context.Context) ([]QueryResp, error) {
+	if err := p.paginator.NextPage(ctx); err != nil {
+		return nil, err
+	}
+	return p.page, nil
+}
+
+// DescribeAllAlarms collects up to max alarms, all of them when max <= 0.
+func DescribeAllAlarms(ctx context.Context, client Client, request *DescribeAlarmsRequest, max int) ([]QueryResp, error) {
+	var all []QueryResp
+	p := NewDescribeAlarmsPaginator(client, request)
+	err := p.paginator.Collect(ctx, max, func(keep int) {
+		all = append(all, p.page[:keep]...)
+	})
+	return all, err
+}
+
+// DescribeDownloadTasksPaginator pages through DescribeDownloadTasks lazily.
+type DescribeDownloadTasksPaginator struct {
+	paginator *base.Paginator
+	page      []*DownloadTaskResp
+}
+
+func NewDescribeDownloadTasksPaginator(client Client, request *DescribeDownloadTasksRequest) *DescribeDownloadTasksPaginator {
+	req := *request
+	size := 0
+	if req.PageSize != nil {
+		size = *req.PageSize
+	}
+	p := &DescribeDownloadTasksPaginator{}
+	p.paginator = base.NewPageNumberPaginator(paginatorPageSize(size), func(ctx context.Context, page, size int) (int, int, error) {
+		req.PageNumber, req.PageSize = &page, &size
+		resp, err := client.DescribeDownloadTasksCtx(ctx, &req)
+		if err != nil {
+			return 0, 0, err
+		}
+		p.page = resp.Tasks
+		return len(resp.Tasks), int(resp.Total), nil
+	})
+	return p
+}
+
+func (p *DescribeDownloadTasksPaginator) HasMorePages() bool {
+	return p.paginator.HasMorePages()
+}
+
+func (p *DescribeDownloadTasksPaginator) NextPage(ctx context.Context) ([]*DownloadTaskResp, error) {
+	if err := p.paginator.NextPage(ctx); err != nil {
+		return nil, err
+	}
+	return p.page, nil
+}
+
+// DescribeAllDownloadTasks collects up to max download tasks, all of them when
+// max <= 0.
+func DescribeAllDownloadTasks(ctx context.Context, client Client, request *DescribeDownloadTasksRequest, max int) ([]*DownloadTaskResp, error) {
+	var all []*DownloadTaskResp
+	p := NewDescribeDownloadTasksPaginator(client, request)
+	err := p.paginator.Collect(ctx, max, func(keep int) {
+		all = append(all, p.page[:keep]...)
+	})
+	return all, err
+}
+
+// SearchLogsIterator streams the logs of a search, following the Context of
+// each page to the next one:
+//
+//	it := tls.NewSearchLogsIterator(client, &tls.SearchLogsRequest{TopicID: topicID, Query: "*", StartTime: start, EndTime: end, Limit: 100})
+//	for it.Next(ctx) {
+//		log := it.Log()
+//	}
+//	if err := it.Err(); err != nil { ... }
+//
+// Analysis queries return a single page, read it with Response.
+type SearchLogsIterator struct {
+	client  Client
+	request SearchLogsRequest
+	resp    *SearchLogsResponse
+	index   int
+	done    bool
+	err     error
+}
+
+func NewSearchLogsIterator(client Client, request *SearchLogsRequest) *SearchLogsIterator {
+	return &SearchLogsIterator{client: client, request: *request}
+}
+
+// Next moves to the next log, fetching the next page when needed. It returns
+// false after the last log or on error, see Err. A failed page is fetched
+// again by the next call.
+func (it *SearchLogsIterator) Next(ctx context.Context) bool {
+	for it.resp == nil || it.index+1 >= len(it.resp.Logs) {
+		if it.done {
+			return false
+		}
+		resp, err := it.client.SearchLogsV2Ctx(ctx, &it.request)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.err = nil
+		// An unchanged Context would return the same page forever.
+		if resp.ListOver || resp.Context == "" || resp.Context == it.request.Context {
+			it.done = true
+		}
+		it.request.Context = resp.Context
+		it.resp, it.index = resp, -1
+	}
+	it.index++
+
+	return true
+}
+
+// Log returns the current log.
+func (it *SearchLogsIterator) Log() map[string]interface{} {
+	return it.resp.Logs[it.index]
+}
+
+// Scan stores the current log into dest, a pointer to a struct, see
+// DecodeRows.
+func (it *SearchLogsIterator) Scan(dest interface{}) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("scan log: dest must be a pointer to a struct, not %T", dest)
+	}
+
+	return decodeRow(it.Log(), v.Elem())
+}
+
+// Response returns the page of the current log, or the last page fetched.
+func (it *SearchLogsIterator) Response() *SearchLogsResponse {
+	return it.resp
+}
+
+// Err returns the error which stopped Next.
+func (it *SearchLogsIterator) Err() error {
+	return it.err
+}
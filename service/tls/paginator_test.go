@@ -0,0 +1,46 @@
+package tls
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestDescribeAllTopics(t *testing.T) {
+	const total = 25
+	var pages []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pageNumber, _ := strconv.Atoi(r.URL.Query().Get("PageNumber"))
+		pageSize, _ := strconv.Atoi(r.URL.Query().Get("PageSize"))
+		pages = append(pages, pageNumber)
+
+		resp := DescribeTopicsResponse{Total: total}
+		for i := (pageNumber - 1) * pageSize; i < pageNumber*pageSize && i < total; i++ {
+			resp.Topics = append(resp.Topics, &Topic{TopicID: fmt.Sprintf("topic-%d", i)})
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "ak", "sk", "", "cn-beijing")
+
+	topics, err := DescribeAllTopics(context.Background(), client, &DescribeTopicsRequest{ProjectID: "project", PageSize: 10}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != total || topics[total-1].TopicID != "topic-24" || len(pages) != 3 {
+		t.Fatalf("unexpected %d topics from pages %v", len(topics), pages)
+	}
+
+	pages = nil
+	topics, err = DescribeAllTopics(context.Background(), client, &DescribeTopicsRequest{ProjectID: "project", PageSize: 10}, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 12 || len(pages) != 2 {
+		t.Fatalf("expect capped result, got %d topics from pages %v", len(topics), pages)
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
+	"github.com/volcengine/volc-sdk-golang/service/tls/metrics"
 	"github.com/volcengine/volc-sdk-golang/service/tls/pb"
 )
 
@@ -41,6 +42,7 @@ type Dispatcher struct {
 	logger         log.Logger
 	threadPool     *ThreadPool
 	producer       *producer
+	metrics        metrics.Metrics
 }
 
 func initDispatcher(config *Config, sender *Sender, logger log.Logger, threadPool *ThreadPool, producer *producer) *Dispatcher {
@@ -56,6 +58,7 @@ func initDispatcher(config *Config, sender *Sender, logger log.Logger, threadPoo
 		logger:         logger,
 		threadPool:     threadPool,
 		producer:       producer,
+		metrics:        sender.metrics,
 	}
 }
 
@@ -63,31 +66,38 @@ func (dispatcher *Dispatcher) IsShutDown() bool {
 	select {
 	case <-dispatcher.stopCh:
 		return true
+	case <-dispatcher.forceQuitCh:
+		return true
 	default:
 		return false
 	}
 }
 
-func (dispatcher *Dispatcher) addOrSendProducerBatch(key string, batchLog *BatchLog, producerBatch *Batch) {
+func (dispatcher *Dispatcher) addOrSendProducerBatch(key string, batchLog *BatchLog, producerBatch *Batch, logSize int64) {
 	totalDataCount := producerBatch.getLogCount() + 1
+	totalDataSize := atomic.LoadInt64(&producerBatch.totalDataSize) + logSize
 
-	if producerBatch.totalDataSize > dispatcher.producerConfig.MaxBatchSize && producerBatch.totalDataSize < 5*1024*1024 && totalDataCount <= dispatcher.producerConfig.MaxBatchCount {
-		producerBatch.addLogToLogGroup(batchLog.Log)
-		if batchLog.Key.CallBackFun != nil {
-			producerBatch.addProducerBatchCallBack(batchLog.Key.CallBackFun)
-		}
+	if totalDataSize > dispatcher.producerConfig.MaxBatchSize && totalDataSize < 5*1024*1024 && totalDataCount <= dispatcher.producerConfig.MaxBatchCount {
+		dispatcher.addLogToProducerBatch(batchLog, producerBatch, logSize)
 		dispatcher.innerSendToServer(key, producerBatch)
-	} else if producerBatch.totalDataSize <= dispatcher.producerConfig.MaxBatchSize && totalDataCount <= dispatcher.producerConfig.MaxBatchCount {
-		producerBatch.addLogToLogGroup(batchLog.Log)
-		if batchLog.Key.CallBackFun != nil {
-			producerBatch.addProducerBatchCallBack(batchLog.Key.CallBackFun)
-		}
+	} else if totalDataSize <= dispatcher.producerConfig.MaxBatchSize && totalDataCount <= dispatcher.producerConfig.MaxBatchCount {
+		dispatcher.addLogToProducerBatch(batchLog, producerBatch, logSize)
 	} else {
 		dispatcher.innerSendToServer(key, producerBatch)
 		dispatcher.createNewProducerBatch(batchLog, key)
 	}
 }
 
+func (dispatcher *Dispatcher) addLogToProducerBatch(batchLog *BatchLog, producerBatch *Batch, logSize int64) {
+	producerBatch.addLogToLogGroup(batchLog.Log)
+	dispatcher.sender.spool.appendLog(producerBatch, batchLog.Log)
+	if batchLog.Key.CallBackFun != nil {
+		producerBatch.addProducerBatchCallBack(batchLog.Key.CallBackFun)
+	}
+	atomic.AddInt64(&producerBatch.totalDataSize, logSize)
+	dispatcher.addPendingBytes(logSize)
+}
+
 func (dispatcher *Dispatcher) run(dispatcherWaitGroup *sync.WaitGroup) {
 	defer dispatcherWaitGroup.Done()
 
@@ -129,6 +139,7 @@ func (dispatcher *Dispatcher) checkBatches(config *Config) {
 	}
 
 	retryProducerBatchList := dispatcher.retryQueue.getRetryBatch(false)
+	dispatcher.metrics.Set(metrics.ProducerRetryQueueLength, float64(dispatcher.retryQueue.length()))
 	if retryProducerBatchList == nil {
 		// If there is nothing to send in the retry queue, just wait for the minimum time that was given to me last time.
 		for sleepMs > 0 {
@@ -195,22 +206,29 @@ func (dispatcher *Dispatcher) handleLogs(batchLog *BatchLog) {
 	dispatcher.lock.Lock()
 
 	if producerBatch, ok := dispatcher.logGroupData[key]; ok {
-		atomic.AddInt64(&producerBatch.totalDataSize, logSize)
-		atomic.AddInt64(&dispatcher.producer.producerLogGroupSize, logSize)
-		dispatcher.addOrSendProducerBatch(key, batchLog, producerBatch)
+		dispatcher.addOrSendProducerBatch(key, batchLog, producerBatch, logSize)
 	} else {
 		dispatcher.createNewProducerBatch(batchLog, key)
-		atomic.AddInt64(&dispatcher.logGroupData[key].totalDataSize, logSize)
-		atomic.AddInt64(&dispatcher.producer.producerLogGroupSize, logSize)
 	}
 
 	dispatcher.lock.Unlock()
 }
 
+func (dispatcher *Dispatcher) addPendingBytes(size int64) {
+	pending := atomic.AddInt64(&dispatcher.producer.producerLogGroupSize, size)
+	dispatcher.metrics.Set(metrics.ProducerPendingBytes, float64(pending))
+}
+
 func (dispatcher *Dispatcher) createNewProducerBatch(batchLog *BatchLog, key string) {
 	level.Debug(dispatcher.logger).Log("msg", "Create a new ProducerBatch")
 
 	newProducerBatch := initProducerBatch(batchLog, dispatcher.producerConfig)
+	// The batch counts its log group size, the sender releases the same amount.
+	dispatcher.addPendingBytes(newProducerBatch.totalDataSize)
+	// Spool the batch right away, it may linger for LingerTime.
+	if err := dispatcher.sender.spool.create(newProducerBatch); err != nil {
+		level.Warn(dispatcher.logger).Log("msg", "spool batch failed, sending it from memory only", "error", err)
+	}
 
 	dispatcher.logGroupData[key] = newProducerBatch
 }
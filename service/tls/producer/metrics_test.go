@@ -0,0 +1,43 @@
+package producer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/volcengine/volc-sdk-golang/service/tls/metrics"
+)
+
+func TestProducerMetrics(t *testing.T) {
+	server := newFakePutLogsServer(true)
+	defer server.Close()
+
+	registry := metrics.NewRegistry()
+	config := GetDefaultProducerConfig()
+	config.Endpoint = server.URL
+	config.Region = "cn-beijing"
+	config.AccessKeyID, config.AccessKeySecret = "ak", "sk"
+	config.LingerTime = 200 * time.Millisecond
+	config.LogLevel = "error"
+	config.Metrics = registry
+	p := NewProducer(config)
+	p.Start()
+	sendTestLog(t, p, "a")
+	sendTestLog(t, p, "b")
+	p.Close()
+
+	var buf bytes.Buffer
+	registry.WritePrometheus(&buf)
+	out := buf.String()
+	for _, line := range []string{
+		`tls_producer_sent_logs_total{topic="topic"} 2`,
+		`tls_producer_send_latency_seconds_count{topic="topic"} 1`,
+		`tls_producer_pending_bytes 0`,
+		`tls_producer_batches_in_flight 0`,
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Fatalf("missing %q in:\n%s", line, out)
+		}
+	}
+}
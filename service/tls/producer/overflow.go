@@ -0,0 +1,194 @@
+package producer
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-kit/kit/log/level"
+	"github.com/volcengine/volc-sdk-golang/service/tls/metrics"
+)
+
+// OverflowPolicy values, deciding what SendLog(s) does while the pending logs
+// exceed TotalSizeLnBytes.
+const (
+	// OverflowBlock waits up to MaxBlockSec for space, then fails with an
+	// *OverflowError. It is the default.
+	OverflowBlock = "block"
+	// OverflowDropNewest drops the logs being sent. SendLog(s) returns nil and
+	// the callback fails with the LogDropped error code.
+	OverflowDropNewest = "drop_newest"
+	// OverflowDropOldest drops the oldest batches that are not being sent
+	// right now, lingering or waiting for a retry, to make space. Their
+	// callbacks fail with the LogDropped error code. If that is not enough,
+	// the logs being sent are dropped as with OverflowDropNewest.
+	OverflowDropOldest = "drop_oldest"
+	// OverflowFailFast fails with an *OverflowError right away.
+	OverflowFailFast = "fail_fast"
+)
+
+// LogDropped is the Attempt.ErrorCode of logs dropped by OverflowPolicy.
+const LogDropped = "LogDropped"
+
+// blockPollInterval is how often a blocked SendLog(s) checks for space.
+const blockPollInterval = 100 * time.Millisecond
+
+// OverflowError is returned by SendLog(s) when there is no space for the logs.
+// With OverflowBlock its message is TimeoutException.
+type OverflowError struct {
+	Policy       string
+	PendingBytes int64
+	Limit        int64
+}
+
+func (e *OverflowError) Error() string {
+	if e.Policy == OverflowBlock {
+		return TimeoutException
+	}
+
+	return fmt.Sprintf("producer is full: %d pending bytes exceed TotalSizeLnBytes %d", e.PendingBytes, e.Limit)
+}
+
+// reserve applies OverflowPolicy before logs are sent. It returns false if
+// the logs must be dropped.
+func (producer *producer) reserve(ctx context.Context) (bool, error) {
+	if producer.hasSpace() {
+		return true, nil
+	}
+
+	switch producer.config.OverflowPolicy {
+	case OverflowFailFast:
+		return false, producer.overflowError()
+	case OverflowDropNewest:
+		return false, nil
+	case OverflowDropOldest:
+		return producer.dispatcher.evictOldest(), nil
+	}
+
+	if err := producer.waitForSpace(ctx); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (producer *producer) waitForSpace(ctx context.Context) error {
+	if producer.config.MaxBlockSec == 0 {
+		return producer.overflowError()
+	}
+
+	var deadline <-chan time.Time
+	if producer.config.MaxBlockSec > 0 {
+		timer := time.NewTimer(time.Duration(producer.config.MaxBlockSec) * time.Second)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+	ticker := time.NewTicker(blockPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-producer.closeCh:
+			return errProducerClosed
+		case <-deadline:
+			return producer.overflowError()
+		case <-ticker.C:
+			if producer.hasSpace() {
+				return nil
+			}
+		}
+	}
+}
+
+func (producer *producer) hasSpace() bool {
+	return atomic.LoadInt64(&producer.producerLogGroupSize) <= producer.config.TotalSizeLnBytes
+}
+
+func (producer *producer) overflowError() error {
+	return &OverflowError{
+		Policy:       producer.config.OverflowPolicy,
+		PendingBytes: atomic.LoadInt64(&producer.producerLogGroupSize),
+		Limit:        producer.config.TotalSizeLnBytes,
+	}
+}
+
+// dropLogs fails the callback of each of logCount logs the policy dropped
+// before they reached the dispatcher.
+func (producer *producer) dropLogs(topic string, logCount int, callBack CallBack) {
+	dropped := producer.addDroppedLogs(topic, logCount)
+	level.Debug(producer.logger).Log("msg", "producer is full, logs dropped", "topic", topic, "count", logCount)
+	if callBack == nil {
+		return
+	}
+
+	for i := 0; i < logCount; i++ {
+		result := newResult()
+		result.Attempts = append(result.Attempts, newDroppedAttempt(producer.config.OverflowPolicy))
+		result.DroppedLogs = dropped
+		callBack.Fail(result)
+	}
+}
+
+// addDroppedLogs counts dropped logs and returns the total so far.
+func (producer *producer) addDroppedLogs(topic string, logCount int) int64 {
+	producer.dispatcher.metrics.Add(metrics.ProducerDroppedLogs, float64(logCount), metrics.LabelTopic, topic)
+
+	return atomic.AddInt64(&producer.droppedLogs, int64(logCount))
+}
+
+func newDroppedAttempt(policy string) *Attempt {
+	return newAttempt(false, "", LogDropped, "dropped by OverflowPolicy "+policy, GetTimeMs(time.Now().UnixNano()))
+}
+
+// evictOldest drops the oldest batches not being sent until the pending logs
+// fit into TotalSizeLnBytes, waiting retries first, then lingering batches.
+// It reports whether there is space now.
+func (dispatcher *Dispatcher) evictOldest() bool {
+	for !dispatcher.producer.hasSpace() {
+		batch := dispatcher.retryQueue.popOldest()
+		if batch == nil {
+			batch = dispatcher.popOldestLingering()
+		}
+		if batch == nil {
+			return false
+		}
+
+		dispatcher.sender.dropBatch(batch)
+		dispatcher.metrics.Set(metrics.ProducerRetryQueueLength, float64(dispatcher.retryQueue.length()))
+	}
+
+	return true
+}
+
+func (dispatcher *Dispatcher) popOldestLingering() *Batch {
+	dispatcher.lock.Lock()
+	defer dispatcher.lock.Unlock()
+
+	var oldestKey string
+	var oldest *Batch
+	for key, batch := range dispatcher.logGroupData {
+		if oldest == nil || batch.createTime.Before(oldest.createTime) {
+			oldestKey, oldest = key, batch
+		}
+	}
+	if oldest != nil {
+		delete(dispatcher.logGroupData, oldestKey)
+	}
+
+	return oldest
+}
+
+// dropBatch fails a batch evicted by OverflowDropOldest.
+func (sender *Sender) dropBatch(batch *Batch) {
+	sender.spool.remove(batch)
+	sender.producer.addDroppedLogs(batch.topic, batch.getLogCount())
+
+	batch.result.SuccessFlag = false
+	if batch.attemptCount < batch.maxReservedAttempts {
+		batch.result.Attempts = append(batch.result.Attempts, newDroppedAttempt(OverflowDropOldest))
+	}
+	sender.FailedCallback(batch)
+}
@@ -0,0 +1,125 @@
+package producer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/volcengine/volc-sdk-golang/service/tls/pb"
+)
+
+type recordingCallBack struct {
+	lock    sync.Mutex
+	results []*Result
+}
+
+func (c *recordingCallBack) Success(result *Result) {
+	c.record(result)
+}
+
+func (c *recordingCallBack) Fail(result *Result) {
+	c.record(result)
+}
+
+func (c *recordingCallBack) record(result *Result) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.results = append(c.results, result)
+}
+
+func (c *recordingCallBack) last() *Result {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if len(c.results) == 0 {
+		return nil
+	}
+	return c.results[len(c.results)-1]
+}
+
+// newFullProducer returns a started producer holding one lingering log, which
+// already exceeds its TotalSizeLnBytes.
+func newFullProducer(t *testing.T, endpoint, policy string, callBack CallBack) *producer {
+	config := GetDefaultProducerConfig()
+	config.Endpoint = endpoint
+	config.Region = "cn-beijing"
+	config.AccessKeyID, config.AccessKeySecret = "ak", "sk"
+	config.LingerTime = time.Hour
+	config.TotalSizeLnBytes = 1
+	config.OverflowPolicy = policy
+	config.LogLevel = "error"
+	p := NewProducer(config).(*producer)
+	p.Start()
+
+	if err := p.SendLog("", "topic", "", "", GenerateLog(1, map[string]string{"message": "old"}), callBack); err != nil {
+		t.Fatal(err)
+	}
+	for deadline := time.Now().Add(5 * time.Second); atomic.LoadInt64(&p.producerLogGroupSize) <= 1; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("log not queued")
+		}
+	}
+	return p
+}
+
+func TestOverflowPolicy(t *testing.T) {
+	server := newFakePutLogsServer(true)
+	defer server.Close()
+	newLog := func() *pb.Log { return GenerateLog(1, map[string]string{"message": "new"}) }
+
+	// Fail-fast returns a typed error right away.
+	p := newFullProducer(t, server.URL, OverflowFailFast, nil)
+	var overflowErr *OverflowError
+	if err := p.SendLog("", "topic", "", "", newLog(), nil); !errors.As(err, &overflowErr) || overflowErr.Limit != 1 {
+		t.Fatalf("expect OverflowError, got %v", err)
+	}
+	p.Close()
+
+	// Block gives up once the context is done, or right away without
+	// MaxBlockSec with the legacy message.
+	p = newFullProducer(t, server.URL, OverflowBlock, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	if err := p.SendLogCtx(ctx, "", "topic", "", "", newLog(), nil); err != context.DeadlineExceeded {
+		t.Fatalf("expect DeadlineExceeded, got %v", err)
+	}
+	cancel()
+	p.config.MaxBlockSec = 0
+	if err := p.SendLog("", "topic", "", "", newLog(), nil); err == nil || err.Error() != TimeoutException {
+		t.Fatalf("expect TimeoutException, got %v", err)
+	}
+	p.Close()
+
+	// Drop-newest fails the callback of the new log.
+	p = newFullProducer(t, server.URL, OverflowDropNewest, nil)
+	dropped := &recordingCallBack{}
+	if err := p.SendLog("", "topic", "", "", newLog(), dropped); err != nil {
+		t.Fatal(err)
+	}
+	if result := dropped.last(); result == nil || result.SuccessFlag || result.Attempts[0].ErrorCode != LogDropped || result.DroppedLogs != 1 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	p.Close()
+
+	// Drop-oldest evicts the lingering log and sends the new one, whose
+	// callback reports the dropped log.
+	server = newFakePutLogsServer(true)
+	defer server.Close()
+	old := &recordingCallBack{}
+	p = newFullProducer(t, server.URL, OverflowDropOldest, old)
+	sent := &recordingCallBack{}
+	if err := p.SendLog("", "topic", "", "", newLog(), sent); err != nil {
+		t.Fatal(err)
+	}
+	if result := old.last(); result == nil || result.SuccessFlag || result.Attempts[0].ErrorCode != LogDropped {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	p.Close()
+	if result := sent.last(); result == nil || !result.SuccessFlag || result.DroppedLogs != 1 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if logs := server.logs(); len(logs) != 1 || logs[0] != "new" {
+		t.Fatalf("unexpected sent logs %v", logs)
+	}
+}
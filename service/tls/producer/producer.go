@@ -1,6 +1,7 @@
 package producer
 
 import (
+	"context"
 	"errors"
 	"github.com/volcengine/volc-sdk-golang/service/tls/common"
 	"sync"
@@ -10,6 +11,7 @@ import (
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	. "github.com/volcengine/volc-sdk-golang/service/tls"
+	"github.com/volcengine/volc-sdk-golang/service/tls/metrics"
 	"github.com/volcengine/volc-sdk-golang/service/tls/pb"
 )
 
@@ -20,7 +22,11 @@ const (
 	int64Max int64 = 0x7FFFFFFFFFFFFFFF
 )
 
+var errProducerClosed = errors.New("the producer is closed")
+
 type producer struct {
+	ctx                  context.Context
+	cancel               context.CancelFunc
 	closeCh              chan struct{}
 	cli                  Client
 	config               *Config
@@ -32,6 +38,9 @@ type producer struct {
 	shardCount           int
 	logger               log.Logger
 	producerLogGroupSize int64
+	droppedLogs          int64
+	spool                *spool
+	router               *shardRouter
 }
 
 func newProducer(producerConfig *Config) *producer {
@@ -43,6 +52,9 @@ func newProducer(producerConfig *Config) *producer {
 	}
 	client := NewClient(producerConfig.Endpoint, producerConfig.AccessKeyID, producerConfig.AccessKeySecret,
 		producerConfig.SecurityToken, producerConfig.Region)
+	if producerConfig.CredentialsProvider != nil {
+		client.SetCredentialsProvider(producerConfig.CredentialsProvider)
+	}
 
 	producerConfig = validateProducerConfig(producerConfig)
 	if producerConfig.MaxBatchCount > 10000 {
@@ -63,16 +75,29 @@ func newProducer(producerConfig *Config) *producer {
 		return errorCodeMap
 	}()
 
+	ctx, cancel := context.WithCancel(context.Background())
 	producer := &producer{
+		ctx:        ctx,
+		cancel:     cancel,
 		cli:        client,
 		config:     producerConfig,
 		shardCount: producerConfig.ShardCount,
 	}
 
-	sender := initSender(producer.cli, newRetryQueue(), producerConfig.MaxSenderCount, logger, errorStatusMap, producer)
+	sender := initSender(ctx, producer.cli, newRetryQueue(), producerConfig.MaxSenderCount, producerConfig.CompressType, logger, errorStatusMap, producer)
+	sender.metrics = metrics.OrNop(producerConfig.Metrics)
 	threadPool := initThreadPool(sender, logger)
 	dispatcher := initDispatcher(producerConfig, sender, logger, threadPool, producer)
 
+	if producerConfig.SpoolDir != "" {
+		spool, err := newSpool(producerConfig, logger)
+		if err != nil {
+			level.Error(logger).Log("msg", "open spool dir failed, spool disabled", "error", err)
+		}
+		producer.spool = spool
+		sender.spool = spool
+	}
+
 	producer.dispatcher = dispatcher
 	producer.threadPool = threadPool
 	producer.senderWaitGroup = &sync.WaitGroup{}
@@ -80,6 +105,7 @@ func newProducer(producerConfig *Config) *producer {
 	producer.dispatcherWaitGroup = &sync.WaitGroup{}
 	producer.logger = logger
 	producer.closeCh = make(chan struct{})
+	producer.router = newShardRouter(client, producerConfig.ShardRefreshInterval, logger)
 
 	return producer
 }
@@ -93,6 +119,22 @@ func validateProducerConfig(producerConfig *Config) *Config {
 	producerConfig.MaxRetryBackoffMs = validateField(producerConfig.MaxRetryBackoffMs, int64(0), int64Max, int64(100)).(int64)
 	producerConfig.TotalSizeLnBytes = validateField(producerConfig.TotalSizeLnBytes, int64(0), int64Max, int64(100*1024*1024)).(int64)
 	producerConfig.LingerTime = validateField(producerConfig.LingerTime, 100*time.Millisecond, time.Duration(int64Max), 2000*time.Millisecond).(time.Duration)
+	switch producerConfig.CompressType {
+	case CompressZstd, CompressSnappy, CompressNone:
+	default:
+		producerConfig.CompressType = CompressLz4
+	}
+	producerConfig.SpoolMaxBytes = validateField(producerConfig.SpoolMaxBytes, int64(0), int64Max, int64(1024*1024*1024)).(int64)
+	producerConfig.SpoolMaxAge = validateField(producerConfig.SpoolMaxAge, time.Duration(0), time.Duration(int64Max), 72*time.Hour).(time.Duration)
+	producerConfig.ShardRefreshInterval = validateField(producerConfig.ShardRefreshInterval, time.Duration(0), time.Duration(int64Max), time.Minute).(time.Duration)
+	switch producerConfig.OverflowPolicy {
+	case OverflowDropNewest, OverflowDropOldest, OverflowFailFast:
+	default:
+		producerConfig.OverflowPolicy = OverflowBlock
+	}
+	if producerConfig.SpoolSyncPolicy != SpoolSyncNever {
+		producerConfig.SpoolSyncPolicy = SpoolSyncAlways
+	}
 
 	return producerConfig
 }
@@ -126,10 +168,20 @@ func validateField(val, min, max, defaultVal interface{}) interface{} {
 }
 
 func (producer *producer) SendLog(shardHash, topic, source string, filename string, log *pb.Log, callBack CallBack) error {
-	err := producer.waitTime()
+	return producer.SendLogCtx(context.Background(), shardHash, topic, source, filename, log, callBack)
+}
+
+// SendLogCtx is SendLog giving up with ctx.Err() once ctx is done while it
+// waits for space.
+func (producer *producer) SendLogCtx(ctx context.Context, shardHash, topic, source string, filename string, log *pb.Log, callBack CallBack) error {
+	ok, err := producer.reserve(ctx)
 	if err != nil {
 		return err
 	}
+	if !ok {
+		producer.dropLogs(topic, 1, callBack)
+		return nil
+	}
 
 	batchLog := &BatchLog{
 		Key: BatchKey{
@@ -142,14 +194,24 @@ func (producer *producer) SendLog(shardHash, topic, source string, filename stri
 		Log: log,
 	}
 
-	return producer.putToDispatcher(batchLog)
+	return producer.putToDispatcher(ctx, batchLog)
 }
 
 func (producer *producer) SendLogs(shardHash, topic, source, filename string, logs *pb.LogGroup, callBack CallBack) error {
-	err := producer.waitTime()
+	return producer.SendLogsCtx(context.Background(), shardHash, topic, source, filename, logs, callBack)
+}
+
+// SendLogsCtx is SendLogs giving up with ctx.Err() once ctx is done while it
+// waits for space.
+func (producer *producer) SendLogsCtx(ctx context.Context, shardHash, topic, source, filename string, logs *pb.LogGroup, callBack CallBack) error {
+	ok, err := producer.reserve(ctx)
 	if err != nil {
 		return err
 	}
+	if !ok {
+		producer.dropLogs(topic, len(logs.Logs), callBack)
+		return nil
+	}
 
 	for _, mlog := range logs.Logs {
 		batchLog := &BatchLog{
@@ -164,7 +226,7 @@ func (producer *producer) SendLogs(shardHash, topic, source, filename string, lo
 			Log: mlog,
 		}
 
-		if err := producer.putToDispatcher(batchLog); err != nil {
+		if err := producer.putToDispatcher(ctx, batchLog); err != nil {
 			return err
 		}
 	}
@@ -172,50 +234,45 @@ func (producer *producer) SendLogs(shardHash, topic, source, filename string, lo
 	return nil
 }
 
-func (producer *producer) waitTime() error {
-	if producer.config.MaxBlockSec > 0 {
-		for i := 0; i < producer.config.MaxBlockSec; i++ {
-			if atomic.LoadInt64(&producer.producerLogGroupSize) <= producer.config.TotalSizeLnBytes {
-				return nil
-			}
-
-			time.Sleep(time.Second)
-		}
+func (producer *producer) SendLogByKey(key, topic, source, filename string, log *pb.Log, callBack CallBack) error {
+	return producer.SendLogByKeyCtx(context.Background(), key, topic, source, filename, log, callBack)
+}
 
-		return errors.New(TimeoutException)
+// SendLogByKeyCtx is SendLogCtx routing the log to the shard whose hash key
+// range holds the MD5 of key. Logs of the same key keep their order.
+func (producer *producer) SendLogByKeyCtx(ctx context.Context, key, topic, source, filename string, log *pb.Log, callBack CallBack) error {
+	shardHash, err := producer.router.route(ctx, topic, key)
+	if err != nil {
+		return err
 	}
 
-	if producer.config.MaxBlockSec == 0 {
-		if atomic.LoadInt64(&producer.producerLogGroupSize) > producer.config.TotalSizeLnBytes {
-			return errors.New(TimeoutException)
-		}
-
-		return nil
-	}
+	return producer.SendLogCtx(ctx, shardHash, topic, source, filename, log, callBack)
+}
 
-	if producer.config.MaxBlockSec < 0 {
-		for {
-			if atomic.LoadInt64(&producer.producerLogGroupSize) <= producer.config.TotalSizeLnBytes {
-				return nil
-			}
+func (producer *producer) SendLogsByKey(key, topic, source, filename string, logs *pb.LogGroup, callBack CallBack) error {
+	return producer.SendLogsByKeyCtx(context.Background(), key, topic, source, filename, logs, callBack)
+}
 
-			time.Sleep(time.Second)
-		}
+// SendLogsByKeyCtx is SendLogsCtx routing the logs like SendLogByKeyCtx.
+func (producer *producer) SendLogsByKeyCtx(ctx context.Context, key, topic, source, filename string, logs *pb.LogGroup, callBack CallBack) error {
+	shardHash, err := producer.router.route(ctx, topic, key)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	return producer.SendLogsCtx(ctx, shardHash, topic, source, filename, logs, callBack)
 }
 
-func (producer *producer) putToDispatcher(batchLog *BatchLog) (err error) {
+func (producer *producer) putToDispatcher(ctx context.Context, batchLog *BatchLog) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			err = errors.New("the producer is closed")
+			err = errProducerClosed
 		}
 	}()
 
 	select {
 	case <-producer.closeCh:
-		err = errors.New("the producer is closed")
+		err = errProducerClosed
 		return err
 	default:
 	}
@@ -226,8 +283,10 @@ func (producer *producer) putToDispatcher(batchLog *BatchLog) (err error) {
 
 	select {
 	case <-producer.closeCh:
-		err = errors.New("the producer is closed")
+		err = errProducerClosed
 		return err
+	case <-ctx.Done():
+		return ctx.Err()
 	case producer.dispatcher.newLogRecvChan <- batchLog:
 	}
 
@@ -235,6 +294,8 @@ func (producer *producer) putToDispatcher(batchLog *BatchLog) (err error) {
 }
 
 func (producer *producer) Start() {
+	producer.replaySpool()
+
 	producer.threadPoolWaitGroup.Add(1)
 	go producer.threadPool.start(producer.senderWaitGroup, producer.threadPoolWaitGroup)
 
@@ -255,6 +316,7 @@ func (producer *producer) Close() {
 	close(producer.threadPool.stopCh)
 	producer.threadPoolWaitGroup.Wait()
 	producer.senderWaitGroup.Wait()
+	producer.cancel()
 
 	level.Info(producer.logger).Log("msg", "producer close finish")
 }
@@ -262,6 +324,8 @@ func (producer *producer) Close() {
 func (producer *producer) ForceClose() {
 	close(producer.closeCh)
 	close(producer.threadPool.sender.stopCh)
+	// Abort in-flight PutLogs instead of waiting for them.
+	producer.cancel()
 
 	close(producer.dispatcher.forceQuitCh)
 	producer.dispatcherWaitGroup.Wait()
@@ -269,6 +333,7 @@ func (producer *producer) ForceClose() {
 	close(producer.threadPool.forceQuitCh)
 	producer.threadPoolWaitGroup.Wait()
 	producer.senderWaitGroup.Wait()
+	producer.spillToSpool()
 
 	level.Info(producer.logger).Log("msg", "producer close finish")
 }
@@ -276,3 +341,82 @@ func (producer *producer) ForceClose() {
 func (producer *producer) ResetAccessKeyToken(accessKeyID, accessKeySecret, securityToken string) {
 	producer.cli.ResetAccessKeyToken(accessKeyID, accessKeySecret, securityToken)
 }
+
+// replaySpool queues the batches a previous run left in the spool. They are
+// sent without callbacks, those did not survive the restart.
+func (producer *producer) replaySpool() {
+	batches := producer.spool.load(producer.config)
+	for _, batch := range batches {
+		producer.dispatcher.addPendingBytes(batch.totalDataSize)
+		producer.dispatcher.retryQueue.addToRetryQueue(batch, producer.logger)
+	}
+	producer.dispatcher.metrics.Set(metrics.ProducerRetryQueueLength, float64(producer.dispatcher.retryQueue.length()))
+
+	if len(batches) > 0 {
+		level.Info(producer.logger).Log("msg", "replay spooled batches", "count", len(batches))
+	}
+}
+
+// spillToSpool persists the batches ForceClose dropped from memory so the
+// next Start sends them.
+func (producer *producer) spillToSpool() {
+	if producer.spool == nil {
+		return
+	}
+
+	// The thread pool has quit, nothing reads taskChan any more: empty it
+	// first and never send to it, the dispatcher would block on a full one.
+	var batches []*Batch
+	for {
+		select {
+		case batch := <-producer.threadPool.taskChan:
+			batches = append(batches, batch)
+			continue
+		default:
+		}
+		break
+	}
+
+	dispatcher := producer.dispatcher
+	dispatcher.lock.Lock()
+	pending := dispatcher.logGroupData
+	dispatcher.logGroupData = make(map[string]*Batch)
+	dispatcher.lock.Unlock()
+
+	// Logs not dispatched yet join their pending batch, or a new one when
+	// the batch is full.
+	config := producer.config
+	for {
+		select {
+		case batchLog, ok := <-dispatcher.newLogRecvChan:
+			if !ok {
+				break
+			}
+			key := dispatcher.getKeyString(batchLog.Key)
+			logSize := int64(GetLogSize(batchLog.Log))
+			if batch, ok := pending[key]; ok {
+				if atomic.LoadInt64(&batch.totalDataSize)+logSize <= config.MaxBatchSize && batch.getLogCount()+1 <= config.MaxBatchCount {
+					dispatcher.addLogToProducerBatch(batchLog, batch, logSize)
+					continue
+				}
+				batches = append(batches, batch)
+			}
+			batch := initProducerBatch(batchLog, config)
+			dispatcher.addPendingBytes(batch.totalDataSize)
+			pending[key] = batch
+			continue
+		default:
+		}
+		break
+	}
+	for _, batch := range pending {
+		batches = append(batches, batch)
+	}
+	batches = append(batches, dispatcher.retryQueue.getRetryBatch(true)...)
+
+	for _, batch := range batches {
+		if err := producer.spool.persist(batch); err != nil {
+			level.Error(producer.logger).Log("msg", "spool batch on force close failed", "error", err)
+		}
+	}
+}
@@ -25,6 +25,9 @@ type Batch struct {
 	shardHash                  *string
 	result                     *Result
 	maxReservedAttempts        int
+	spoolFile                  string
+	spoolSize                  int64
+	spoolSynced                bool
 }
 
 func initProducerBatch(batchLog *BatchLog, config *Config) *Batch {
@@ -37,29 +40,34 @@ func initProducerBatch(batchLog *BatchLog, config *Config) *Batch {
 		ContextFlow: batchLog.Key.ContextFlow,
 	}
 
-	currentTime := time.Now()
+	producerBatch := newBatch(batchLog.Key.Topic, batchLog.Key.ShardHash, logGroup, config)
+
+	if batchLog.Key.CallBackFun != nil {
+		producerBatch.callBackList = append(producerBatch.callBackList, batchLog.Key.CallBackFun)
+	}
+
+	return producerBatch
+}
+
+func newBatch(topic, shardHash string, logGroup *pb.LogGroup, config *Config) *Batch {
 	producerBatch := &Batch{
 		logGroup:                   logGroup,
 		attemptCount:               0,
 		maxRetryIntervalInMs:       config.MaxRetryBackoffMs,
 		callBackList:               []CallBack{},
-		createTime:                 currentTime,
+		createTime:                 time.Now(),
 		maxRetryTimes:              config.Retries,
 		retryBackoffMs:             0,
 		baseRetryBackoffMs:         config.BaseRetryBackoffMs,
 		baseIncreaseRetryBackoffMs: 1000,
-		topic:                      batchLog.Key.Topic,
+		topic:                      topic,
 		result:                     newResult(),
 		maxReservedAttempts:        config.MaxReservedAttempts,
 	}
 
-	producerBatch.shardHash = parseHash(batchLog.Key.ShardHash)
+	producerBatch.shardHash = parseHash(shardHash)
 	producerBatch.totalDataSize = int64(producerBatch.logGroup.Size())
 
-	if batchLog.Key.CallBackFun != nil {
-		producerBatch.callBackList = append(producerBatch.callBackList, batchLog.Key.CallBackFun)
-	}
-
 	return producerBatch
 }
 
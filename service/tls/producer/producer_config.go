@@ -4,25 +4,59 @@ import (
 	"github.com/go-kit/kit/log"
 	"time"
 
+	"github.com/volcengine/volc-sdk-golang/service/tls"
 	"github.com/volcengine/volc-sdk-golang/service/tls/common"
+	"github.com/volcengine/volc-sdk-golang/service/tls/metrics"
 )
 
 const delimiter = "|"
 
 type Config struct {
-	TotalSizeLnBytes      int64
-	MaxSenderCount        int64
-	MaxBlockSec           int
-	MaxBatchSize          int64
-	MaxBatchCount         int
-	LingerTime            time.Duration
-	Retries               int
-	MaxReservedAttempts   int
-	BaseRetryBackoffMs    int64
-	MaxRetryBackoffMs     int64
-	AdjustShardHashFlag   bool
-	ShardCount            int
+	TotalSizeLnBytes int64
+	MaxSenderCount   int64
+	MaxBlockSec      int
+	// OverflowPolicy decides what SendLog(s) does while the pending logs
+	// exceed TotalSizeLnBytes: OverflowBlock (default), OverflowDropNewest,
+	// OverflowDropOldest or OverflowFailFast.
+	OverflowPolicy      string
+	MaxBatchSize        int64
+	MaxBatchCount       int
+	LingerTime          time.Duration
+	Retries             int
+	MaxReservedAttempts int
+	BaseRetryBackoffMs  int64
+	MaxRetryBackoffMs   int64
+	// Deprecated: unused, SendLogByKey routes by the shards DescribeShards
+	// reports.
+	AdjustShardHashFlag bool
+	// Deprecated: unused, SendLogByKey routes by the shards DescribeShards
+	// reports.
+	ShardCount int
+	// ShardRefreshInterval is how often SendLogByKey describes the shards of
+	// a topic again to pick up splits, 1 minute by default.
+	ShardRefreshInterval  time.Duration
 	NoRetryStatusCodeList []int
+	// CompressType is the PutLogs compression: tls.CompressLz4 (default),
+	// tls.CompressZstd, tls.CompressSnappy or tls.CompressNone. Other values
+	// fall back to tls.CompressLz4.
+	CompressType string
+
+	// SpoolDir enables the write-ahead spool: batches are persisted to this
+	// directory as they are created and replayed by the next Start if they
+	// were neither accepted nor reported to a Fail callback. Use one
+	// directory per producer; empty disables it.
+	SpoolDir string
+	// SpoolMaxBytes caps the spool size, 1 GB by default. Batches beyond it
+	// are sent from memory only.
+	SpoolMaxBytes int64
+	// SpoolMaxAge drops spooled batches older than this on replay, 72 hours
+	// by default.
+	SpoolMaxAge time.Duration
+	// SpoolSyncPolicy is SpoolSyncAlways (default) or SpoolSyncNever.
+	SpoolSyncPolicy string
+
+	// Metrics receives the producer metrics, see package metrics.
+	Metrics metrics.Metrics
 
 	common.LoggerConfig
 	common.ClientConfig
@@ -42,6 +76,7 @@ func GetDefaultProducerConfig() *Config {
 		TotalSizeLnBytes:      100 * 1024 * 1024,
 		MaxSenderCount:        50,
 		MaxBlockSec:           60,
+		OverflowPolicy:        OverflowBlock,
 		MaxBatchSize:          512 * 1024,
 		LingerTime:            2000 * time.Millisecond,
 		Retries:               10,
@@ -50,7 +85,9 @@ func GetDefaultProducerConfig() *Config {
 		MaxRetryBackoffMs:     10 * 1000,
 		AdjustShardHashFlag:   true,
 		ShardCount:            2,
+		ShardRefreshInterval:  time.Minute,
 		MaxBatchCount:         4096,
 		NoRetryStatusCodeList: []int{400, 404},
+		CompressType:          tls.CompressLz4,
 	}
 }
@@ -1,12 +1,23 @@
 package producer
 
 import (
+	"context"
+
 	"github.com/volcengine/volc-sdk-golang/service/tls/pb"
 )
 
 type Producer interface {
 	SendLog(shardHash, topic, source, filename string, log *pb.Log, callBack CallBack) error
+	SendLogCtx(ctx context.Context, shardHash, topic, source, filename string, log *pb.Log, callBack CallBack) error
 	SendLogs(shardHash, topic, source, filename string, logs *pb.LogGroup, callBack CallBack) error
+	SendLogsCtx(ctx context.Context, shardHash, topic, source, filename string, logs *pb.LogGroup, callBack CallBack) error
+	// SendLogByKey sends the log to the shard of key instead of a raw shard
+	// hash, see Config.ShardRefreshInterval. The first log of a topic waits
+	// for its shards to be described and fails if that does.
+	SendLogByKey(key, topic, source, filename string, log *pb.Log, callBack CallBack) error
+	SendLogByKeyCtx(ctx context.Context, key, topic, source, filename string, log *pb.Log, callBack CallBack) error
+	SendLogsByKey(key, topic, source, filename string, logs *pb.LogGroup, callBack CallBack) error
+	SendLogsByKeyCtx(ctx context.Context, key, topic, source, filename string, logs *pb.LogGroup, callBack CallBack) error
 	ResetAccessKeyToken(accessKeyID, accessKeySecret, securityToken string)
 	Start()
 	Close()
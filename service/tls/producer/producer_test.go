@@ -12,6 +12,24 @@ import (
 	"github.com/volcengine/volc-sdk-golang/service/tls/pb"
 )
 
+func TestValidateProducerConfigCompressType(t *testing.T) {
+	for compressType, expect := range map[string]string{
+		"":             CompressLz4,
+		CompressLz4:    CompressLz4,
+		CompressZstd:   CompressZstd,
+		CompressSnappy: CompressSnappy,
+		CompressNone:   CompressNone,
+		CompressGz:     CompressLz4,
+		"LZ4":          CompressLz4,
+	} {
+		config := GetDefaultProducerConfig()
+		config.CompressType = compressType
+		if got := validateProducerConfig(config).CompressType; got != expect {
+			t.Errorf("compress type %q validated to %q, expect %q", compressType, got, expect)
+		}
+	}
+}
+
 type SDKProducerTestSuite struct {
 	suite.Suite
 
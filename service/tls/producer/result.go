@@ -21,6 +21,9 @@ func newAttempt(success bool, requestId, errorCode, errorMessage string, timesta
 type Result struct {
 	Attempts    []*Attempt
 	SuccessFlag bool
+	// DroppedLogs is the number of logs the producer dropped because of its
+	// OverflowPolicy, from its start until the callback.
+	DroppedLogs int64
 }
 
 func newResult() *Result {
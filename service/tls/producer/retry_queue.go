@@ -56,6 +56,26 @@ func (q *RetryQueue) getRetryBatch(stopFlag bool) (producerBatchList []*Batch) {
 	return producerBatchList
 }
 
+// popOldest removes the batch due for a retry first, nil if there is none.
+func (q *RetryQueue) popOldest() *Batch {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.Len() == 0 {
+		return nil
+	}
+
+	return heap.Pop(q).(*Batch)
+}
+
+// length is Len for callers outside the heap operations.
+func (q *RetryQueue) length() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return q.Len()
+}
+
 func (q *RetryQueue) Len() int {
 	return len(q.batch)
 }
@@ -0,0 +1,170 @@
+package producer
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	. "github.com/volcengine/volc-sdk-golang/service/tls"
+)
+
+const (
+	// shardStatusReadWrite is the status of shards accepting writes, a split
+	// shard turns read-only.
+	shardStatusReadWrite = "readwrite"
+	// describeShardsTimeout bounds describing the shards of a topic.
+	describeShardsTimeout = 10 * time.Second
+)
+
+// shardRange is a writable shard, which holds the MD5 hash keys from its
+// begin up to the begin of the next shard. begin is in lower case hex for
+// comparison, hashKey as reported by the server.
+type shardRange struct {
+	hashKey string
+	begin   string
+}
+
+type topicShards struct {
+	ranges     []shardRange
+	updateTime time.Time
+	refreshing int32
+	// ready is set on the placeholder of a topic being described for the
+	// first time and closed once done, err is then the describe error.
+	ready chan struct{}
+	err   error
+}
+
+// shardRouter maps routing keys to the hash key of the shard they fall into,
+// so the logs of one shard share batches and the logs of one key stay in
+// order on their shard.
+type shardRouter struct {
+	client   Client
+	interval time.Duration
+	logger   log.Logger
+
+	lock   sync.RWMutex
+	topics map[string]*topicShards
+}
+
+func newShardRouter(client Client, interval time.Duration, logger log.Logger) *shardRouter {
+	return &shardRouter{
+		client:   client,
+		interval: interval,
+		logger:   logger,
+		topics:   make(map[string]*topicShards),
+	}
+}
+
+// route returns the shard hash for key. The first use of a topic waits for
+// its shards to be described, which takes at most describeShardsTimeout, and
+// returns the error when that fails or ctx is done first. The shards are then
+// refreshed in the background once older than the refresh interval.
+func (r *shardRouter) route(ctx context.Context, topic, key string) (string, error) {
+	r.lock.RLock()
+	shards, ok := r.topics[topic]
+	r.lock.RUnlock()
+
+	if !ok {
+		r.lock.Lock()
+		if shards, ok = r.topics[topic]; !ok {
+			// A placeholder makes the first describe the only one in flight.
+			shards = &topicShards{refreshing: 1, ready: make(chan struct{})}
+			r.topics[topic] = shards
+			go r.refresh(topic)
+		}
+		r.lock.Unlock()
+	}
+
+	if shards.ready != nil {
+		select {
+		case <-shards.ready:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+		if shards.err != nil {
+			return "", shards.err
+		}
+		r.lock.RLock()
+		shards = r.topics[topic]
+		r.lock.RUnlock()
+	} else if time.Since(shards.updateTime) > r.interval && atomic.CompareAndSwapInt32(&shards.refreshing, 0, 1) {
+		go r.refresh(topic)
+	}
+
+	if len(shards.ranges) == 0 {
+		return "", nil
+	}
+
+	sum := md5.Sum([]byte(key))
+	return shards.find(hex.EncodeToString(sum[:])).hashKey, nil
+}
+
+// refresh describes the shards of topic. When the first describe fails the
+// topic is forgotten and described again on its next use, later failures
+// keep the shards known so far until the next refresh.
+func (r *shardRouter) refresh(topic string) {
+	ctx, cancel := context.WithTimeout(context.Background(), describeShardsTimeout)
+	defer cancel()
+	infos, err := DescribeAllShards(ctx, r.client, &DescribeShardsRequest{TopicID: topic}, 0)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	// The topic is described by one refresh at a time, so prev is there.
+	prev := r.topics[topic]
+	if prev.ready != nil {
+		// Wake up the callers waiting for the first describe.
+		defer close(prev.ready)
+	}
+	if err != nil {
+		level.Warn(r.logger).Log("msg", "describe shards failed", "topic", topic, "error", err)
+		if prev.ready != nil {
+			prev.err = fmt.Errorf("describe shards of topic %s: %w", topic, err)
+			delete(r.topics, topic)
+			return
+		}
+		// Retry after another interval.
+		r.topics[topic] = &topicShards{updateTime: time.Now(), ranges: prev.ranges}
+		return
+	}
+
+	shards := &topicShards{updateTime: time.Now()}
+	for _, info := range infos {
+		if info.Status != "" && !strings.EqualFold(info.Status, shardStatusReadWrite) {
+			continue
+		}
+		shards.ranges = append(shards.ranges, shardRange{
+			hashKey: info.InclusiveBeginKey,
+			begin:   strings.ToLower(info.InclusiveBeginKey),
+		})
+	}
+	sort.Slice(shards.ranges, func(i, j int) bool {
+		return shards.ranges[i].begin < shards.ranges[j].begin
+	})
+
+	if prev.ready == nil && len(prev.ranges) != len(shards.ranges) {
+		level.Info(r.logger).Log("msg", "shards of topic changed", "topic", topic, "before", len(prev.ranges), "after", len(shards.ranges))
+	}
+	r.topics[topic] = shards
+}
+
+// find returns the range holding hash, the last one whose begin is not after
+// it.
+func (s *topicShards) find(hash string) shardRange {
+	i := sort.Search(len(s.ranges), func(i int) bool {
+		return s.ranges[i].begin > hash
+	})
+	if i == 0 {
+		return s.ranges[0]
+	}
+
+	return s.ranges[i-1]
+}
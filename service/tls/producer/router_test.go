@@ -0,0 +1,207 @@
+package producer
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/volcengine/volc-sdk-golang/service/tls"
+)
+
+type fakeShardServer struct {
+	*httptest.Server
+
+	lock      sync.Mutex
+	shards    []*tls.ShardInfo
+	hashKeys  []string
+	describes int
+	// release, when set, holds DescribeShards until it is closed.
+	release chan struct{}
+	// fail makes DescribeShards fail.
+	fail bool
+}
+
+func newFakeShardServer(shards ...*tls.ShardInfo) *fakeShardServer {
+	s := &fakeShardServer{shards: shards}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		switch r.URL.Path {
+		case tls.PathDescribeShards:
+			s.describes++
+			if release := s.release; release != nil {
+				s.lock.Unlock()
+				<-release
+				s.lock.Lock()
+			}
+			if s.fail {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"errorCode":"TopicNotExist","errorMessage":"topic does not exist"}`))
+				return
+			}
+			json.NewEncoder(w).Encode(tls.DescribeShardsResponse{Shards: s.shards, Total: len(s.shards)})
+		case tls.PathPutLogs:
+			s.hashKeys = append(s.hashKeys, r.Header.Get("x-tls-hashkey"))
+		}
+	}))
+	return s
+}
+
+func (s *fakeShardServer) setFail(fail bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.fail = fail
+}
+
+func (s *fakeShardServer) setShards(shards ...*tls.ShardInfo) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.shards = shards
+}
+
+func shard(id int32, begin, end, status string) *tls.ShardInfo {
+	return &tls.ShardInfo{TopicID: "topic", ShardID: id, InclusiveBeginKey: begin, ExclusiveEndKey: end, Status: status}
+}
+
+// keyWithHashPrefix returns a key whose MD5 starts with the hex digit prefix.
+func keyWithHashPrefix(prefix byte) string {
+	for i := 0; ; i++ {
+		key := string(rune('a'+i%26)) + string(rune('a'+i/26%26)) + string(rune('a'+i/676%26))
+		sum := md5.Sum([]byte(key))
+		if hex.EncodeToString(sum[:])[0] == prefix {
+			return key
+		}
+	}
+}
+
+func TestShardRouter(t *testing.T) {
+	const (
+		low  = "00000000000000000000000000000000"
+		mid  = "80000000000000000000000000000000"
+		high = "C0000000000000000000000000000000"
+		max  = "FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF"
+	)
+	server := newFakeShardServer(shard(0, low, mid, "readwrite"), shard(1, mid, max, "readwrite"))
+	defer server.Close()
+
+	client := tls.NewClient(server.URL, "ak", "sk", "", "cn-beijing")
+	router := newShardRouter(client, time.Hour, log.NewNopLogger())
+	lowKey, midKey, highKey := keyWithHashPrefix('1'), keyWithHashPrefix('9'), keyWithHashPrefix('e')
+	route := func(key string) string {
+		hash, err := router.route(context.Background(), "topic", key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hash
+	}
+
+	// The first use waits for the shards to be described.
+	for key, expect := range map[string]string{lowKey: low, midKey: mid, highKey: mid} {
+		if got := route(key); got != expect {
+			t.Fatalf("key %s routed to %s, expect %s", key, got, expect)
+		}
+	}
+
+	// Shard 1 splits, the read-only parent is skipped once refreshed.
+	server.setShards(shard(0, low, mid, "readwrite"), shard(1, mid, max, "readonly"),
+		shard(2, mid, high, "readwrite"), shard(3, high, max, "readwrite"))
+	router.interval = 0
+	route(lowKey)
+	if !waitForCondition(func() bool { return route(highKey) == high }) {
+		t.Fatal("split not picked up")
+	}
+	if got := route(midKey); got != mid {
+		t.Fatalf("key routed to %s, expect %s", got, mid)
+	}
+}
+
+func TestShardRouterFirstDescribe(t *testing.T) {
+	const begin = "00000000000000000000000000000000"
+	server := newFakeShardServer(shard(0, begin, "FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF", "readwrite"))
+	defer server.Close()
+	release := make(chan struct{})
+	server.release = release
+
+	client := tls.NewClient(server.URL, "ak", "sk", "", "cn-beijing")
+	router := newShardRouter(client, time.Hour, log.NewNopLogger())
+
+	// A caller whose ctx ends first gives up, concurrent first callers wait
+	// for a single pending describe.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := router.route(ctx, "topic", "key"); err != context.DeadlineExceeded {
+		t.Fatalf("expect the deadline of ctx, got %v", err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got, err := router.route(context.Background(), "topic", "key"); got != begin || err != nil {
+				t.Errorf("routed to %q, error %v", got, err)
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	server.lock.Lock()
+	if server.describes != 1 {
+		t.Fatalf("expect a single DescribeShards, got %d", server.describes)
+	}
+	server.lock.Unlock()
+
+	// A failed first describe is returned and tried again on the next use.
+	server.setFail(true)
+	if _, err := router.route(context.Background(), "other", "key"); err == nil {
+		t.Fatal("expect the describe error")
+	}
+	server.setFail(false)
+	if got, err := router.route(context.Background(), "other", "key"); got != begin || err != nil {
+		t.Fatalf("routed to %q, error %v", got, err)
+	}
+}
+
+func TestSendLogByKey(t *testing.T) {
+	const begin = "00000000000000000000000000000000"
+	server := newFakeShardServer(shard(0, begin, "FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF", "readwrite"))
+	defer server.Close()
+
+	config := GetDefaultProducerConfig()
+	config.Endpoint = server.URL
+	config.Region = "cn-beijing"
+	config.AccessKeyID, config.AccessKeySecret = "ak", "sk"
+	config.LingerTime = 100 * time.Millisecond
+	config.LogLevel = "error"
+	p := NewProducer(config)
+	p.Start()
+	for _, key := range []string{"user-1", "user-2", "user-3"} {
+		if err := p.SendLogByKey(key, "topic", "", "", GenerateLog(1, map[string]string{"user": key}), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p.Close()
+
+	// All keys of the only shard share one batch.
+	server.lock.Lock()
+	defer server.lock.Unlock()
+	if len(server.hashKeys) != 1 || server.hashKeys[0] != begin || server.describes != 1 {
+		t.Fatalf("unexpected PutLogs hash keys %v after %d DescribeShards", server.hashKeys, server.describes)
+	}
+}
+
+func waitForCondition(cond func() bool) bool {
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if cond() {
+			return true
+		}
+	}
+	return false
+}
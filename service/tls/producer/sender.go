@@ -1,6 +1,7 @@
 package producer
 
 import (
+	"context"
 	"errors"
 	"math/rand"
 	"sync/atomic"
@@ -9,6 +10,7 @@ import (
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	. "github.com/volcengine/volc-sdk-golang/service/tls"
+	"github.com/volcengine/volc-sdk-golang/service/tls/metrics"
 	"github.com/volcengine/volc-sdk-golang/service/tls/pb"
 )
 
@@ -18,24 +20,32 @@ type CallBack interface {
 }
 
 type Sender struct {
+	ctx                  context.Context
 	stopCh               chan struct{}
 	maxSender            chan int
+	compressType         string
 	client               Client
 	retryQueue           *RetryQueue
 	logger               log.Logger
 	noRetryStatusCodeMap map[int]struct{}
 	producer             *producer
+	spool                *spool
+	metrics              metrics.Metrics
+	inFlight             int64
 }
 
-func initSender(client Client, retryQueue *RetryQueue, maxSenderCount int64, logger log.Logger, errorStatusMap map[int]struct{}, producer *producer) *Sender {
+func initSender(ctx context.Context, client Client, retryQueue *RetryQueue, maxSenderCount int64, compressType string, logger log.Logger, errorStatusMap map[int]struct{}, producer *producer) *Sender {
 	return &Sender{
+		ctx:                  ctx,
 		stopCh:               make(chan struct{}),
 		client:               client,
 		retryQueue:           retryQueue,
 		maxSender:            make(chan int, maxSenderCount),
+		compressType:         compressType,
 		logger:               logger,
 		noRetryStatusCodeMap: errorStatusMap,
 		producer:             producer,
+		metrics:              metrics.Nop{},
 	}
 }
 
@@ -54,7 +64,7 @@ func (sender *Sender) sendToServer(batch *Batch) {
 
 	putLogsReq := &PutLogsRequest{
 		TopicID:      batch.topic,
-		CompressType: "lz4",
+		CompressType: sender.compressType,
 		LogBody: &pb.LogGroupList{
 			LogGroups: []*pb.LogGroup{
 				batch.logGroup,
@@ -66,7 +76,15 @@ func (sender *Sender) sendToServer(batch *Batch) {
 		putLogsReq.HashKey = *batch.shardHash
 	}
 
-	resp, err := sender.client.PutLogs(putLogsReq)
+	if err := sender.spool.persist(batch); err != nil {
+		level.Warn(sender.logger).Log("msg", "spool batch failed, sending it from memory only", "error", err)
+	}
+
+	sender.metrics.Set(metrics.ProducerBatchesInFlight, float64(atomic.AddInt64(&sender.inFlight, 1)))
+	start := time.Now()
+	resp, err := sender.client.PutLogsCtx(sender.ctx, putLogsReq)
+	sender.metrics.Observe(metrics.ProducerSendLatency, metrics.Since(start), metrics.LabelTopic, batch.topic)
+	sender.metrics.Set(metrics.ProducerBatchesInFlight, float64(atomic.AddInt64(&sender.inFlight, -1)))
 	if err == nil {
 		sender.handleSuccess(batch, resp)
 		return
@@ -77,8 +95,13 @@ func (sender *Sender) sendToServer(batch *Batch) {
 
 func (sender *Sender) handleSuccess(batch *Batch, putLogsResp *CommonResponse) {
 	level.Debug(sender.logger).Log("msg", "sendToServer succeeded,Execute successful callback function")
-	defer atomic.AddInt64(&sender.producer.producerLogGroupSize, -batch.totalDataSize)
+	defer sender.releasePendingBytes(batch)
+
+	sender.metrics.Add(metrics.ProducerSentBytes, float64(batch.totalDataSize), metrics.LabelTopic, batch.topic)
+	sender.metrics.Add(metrics.ProducerSentLogs, float64(batch.getLogCount()), metrics.LabelTopic, batch.topic)
+	batch.result.DroppedLogs = atomic.LoadInt64(&sender.producer.droppedLogs)
 
+	sender.spool.remove(batch)
 	batch.result.SuccessFlag = true
 	if batch.attemptCount < batch.maxReservedAttempts {
 		batch.result.Attempts = append(batch.result.Attempts,
@@ -96,15 +119,36 @@ func (sender *Sender) handleFailure(batch *Batch, err error) {
 	_ = level.Info(sender.logger).Log("msg", "sendToServer failed", "error", err)
 
 	noRetryStatusCode := false
+	errorCode := "ClientError"
 	var sdkError *Error
 	tlsErrOk := errors.As(err, &sdkError)
 	if tlsErrOk {
 		_, noRetryStatusCode = sender.noRetryStatusCodeMap[int(sdkError.HTTPCode)]
+		errorCode = sdkError.Code
 	}
+	sender.metrics.Add(metrics.ProducerSendFailures, 1, metrics.LabelTopic, batch.topic, metrics.LabelErrorCode, errorCode)
 
 	noNeedRetry := batch.attemptCount >= batch.maxRetryTimes
 
 	if sender.IsShutDown() || (tlsErrOk && noRetryStatusCode) || noNeedRetry {
+		switch {
+		case tlsErrOk && noRetryStatusCode:
+			// TLS will never accept the batch, replaying it is pointless.
+			sender.spool.remove(batch)
+		case batch.spoolFile != "" && sender.ctx.Err() != nil:
+			// ForceClose aborted the send: like the batches it spills, the
+			// batch is left to the next Start and no failure is reported.
+			level.Warn(sender.logger).Log("msg", "batch kept in spool for replay", "file", batch.spoolFile)
+			sender.releasePendingBytes(batch)
+			return
+		case batch.spoolFile != "" && len(batch.callBackList) == 0:
+			// No callback learns about the failure, the next Start replays it.
+			level.Warn(sender.logger).Log("msg", "batch kept in spool for replay", "file", batch.spoolFile)
+		default:
+			// The callbacks report the failure, a replay would deliver the
+			// batch after all.
+			sender.spool.remove(batch)
+		}
 		sender.addErrorMessageToBatchAttempt(batch, err, false)
 		sender.FailedCallback(batch)
 
@@ -129,17 +173,24 @@ func (sender *Sender) handleFailure(batch *Batch, err error) {
 	batch.nextRetryMs = GetTimeMs(time.Now().UnixNano()) + batch.retryBackoffMs
 
 	sender.retryQueue.addToRetryQueue(batch, sender.logger)
+	sender.metrics.Set(metrics.ProducerRetryQueueLength, float64(sender.retryQueue.length()))
 }
 
 func (sender *Sender) FailedCallback(batch *Batch) {
 	level.Info(sender.logger).Log("msg", "sendToServer failed,Execute failed callback function")
-	defer atomic.AddInt64(&sender.producer.producerLogGroupSize, -batch.totalDataSize)
+	defer sender.releasePendingBytes(batch)
 
+	batch.result.DroppedLogs = atomic.LoadInt64(&sender.producer.droppedLogs)
 	for _, callBack := range batch.callBackList {
 		callBack.Fail(batch.result)
 	}
 }
 
+func (sender *Sender) releasePendingBytes(batch *Batch) {
+	pending := atomic.AddInt64(&sender.producer.producerLogGroupSize, -batch.totalDataSize)
+	sender.metrics.Set(metrics.ProducerPendingBytes, float64(pending))
+}
+
 func (sender *Sender) addErrorMessageToBatchAttempt(producerBatch *Batch, err error, retryInfo bool) {
 	if producerBatch.attemptCount < producerBatch.maxReservedAttempts {
 		tlsError, ok := err.(*Error)
@@ -1,6 +1,7 @@
 package producer
 
 import (
+	"context"
 	"fmt"
 	"github.com/stretchr/testify/suite"
 	"github.com/volcengine/volc-sdk-golang/service/tls"
@@ -30,9 +31,11 @@ func (suite *SDKSenderTestSuite) initSender() {
 	}()
 
 	suite.sender = initSender(
+		context.Background(),
 		newClientWithEnv(),
 		newRetryQueue(),
 		20,
+		producerConfig.CompressType,
 		common.LogConfig(producerConfig.LoggerConfig),
 		errorStatusMap,
 		nil,
@@ -0,0 +1,459 @@
+package producer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/volcengine/volc-sdk-golang/service/tls/pb"
+)
+
+const (
+	// SpoolSyncAlways fsyncs every spooled batch and the spool directory before
+	// the batch is sent, so a sent batch survives a machine crash. Logs still
+	// lingering in a batch are written but not fsynced.
+	SpoolSyncAlways = "always"
+	// SpoolSyncNever leaves flushing to the OS, surviving process crashes only.
+	SpoolSyncNever = "never"
+
+	spoolFileSuffix = ".batch"
+	spoolTempSuffix = ".tmp"
+	spoolVersion    = 1
+)
+
+var (
+	spoolMagic = []byte("TLSB")
+
+	errSpoolFull    = errors.New("spool is full")
+	errSpoolCorrupt = errors.New("corrupt spool file")
+)
+
+// spool is the write-ahead directory of the producer. Every batch is written
+// to its own file when the dispatcher creates it, each log joining it later is
+// appended, and the file is removed once TLS accepted the batch or a callback
+// reported its failure. The files left behind by a crash, a ForceClose or an
+// outage longer than Retries of a batch without callbacks are sent again by
+// the next Start. Delivery is at least once: a batch sent right before a crash
+// is sent twice.
+//
+// A file holds a header with the batch without its logs, then one record per
+// log. A record torn by a crash ends the batch.
+type spool struct {
+	dir      string
+	maxBytes int64
+	maxAge   time.Duration
+	sync     bool
+	logger   log.Logger
+
+	seq  uint64
+	lock sync.Mutex
+	size int64
+}
+
+func newSpool(config *Config, logger log.Logger) (*spool, error) {
+	if err := os.MkdirAll(config.SpoolDir, 0755); err != nil {
+		return nil, err
+	}
+
+	s := &spool{
+		dir:      config.SpoolDir,
+		maxBytes: config.SpoolMaxBytes,
+		maxAge:   config.SpoolMaxAge,
+		sync:     config.SpoolSyncPolicy != SpoolSyncNever,
+		logger:   logger,
+	}
+
+	files, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		if strings.HasSuffix(f.Name(), spoolFileSuffix) {
+			s.size += f.Size()
+		}
+	}
+
+	return s, nil
+}
+
+// persist writes batch to the spool unless it is already there and fsyncs it
+// if the sync policy asks for it. A batch that does not fit into the size cap
+// is sent from memory only.
+func (s *spool) persist(batch *Batch) error {
+	if s == nil {
+		return nil
+	}
+
+	if err := s.create(batch); err != nil {
+		return err
+	}
+
+	return s.syncFile(batch)
+}
+
+// create writes batch to the spool unless it is already there, without
+// fsyncing it. The logs added to batch later are written by appendLog.
+func (s *spool) create(batch *Batch) error {
+	if s == nil || batch.spoolFile != "" {
+		return nil
+	}
+
+	batch.lock.RLock()
+	data, err := encodeSpoolBatch(batch)
+	batch.lock.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	if err := s.reserve(int64(len(data))); err != nil {
+		return err
+	}
+
+	name := filepath.Join(s.dir, fmt.Sprintf("%020d-%010d%s", batch.createTime.UnixNano(), atomic.AddUint64(&s.seq, 1), spoolFileSuffix))
+	if err := writeSpoolFile(name, data); err != nil {
+		s.release(int64(len(data)))
+		return err
+	}
+
+	batch.spoolFile = name
+	batch.spoolSize = int64(len(data))
+	batch.spoolSynced = false
+
+	return nil
+}
+
+// appendLog appends log to the spool file of batch. When that fails the file
+// is removed, a batch missing logs must not be replayed, and batch is sent
+// from memory only.
+func (s *spool) appendLog(batch *Batch, log *pb.Log) {
+	if s == nil || batch.spoolFile == "" {
+		return
+	}
+
+	data, err := encodeSpoolLog(log)
+	if err == nil {
+		err = s.reserve(int64(len(data)))
+	}
+	if err == nil {
+		if err = appendSpoolFile(batch.spoolFile, data); err != nil {
+			s.release(int64(len(data)))
+		}
+	}
+	if err != nil {
+		level.Warn(s.logger).Log("msg", "append to spool file failed, sending the batch from memory only", "file", batch.spoolFile, "error", err)
+		s.remove(batch)
+		return
+	}
+
+	batch.spoolSize += int64(len(data))
+	batch.spoolSynced = false
+}
+
+// syncFile fsyncs the spool file of batch and the spool directory once after
+// the last write.
+func (s *spool) syncFile(batch *Batch) error {
+	if !s.sync || batch.spoolFile == "" || batch.spoolSynced {
+		return nil
+	}
+
+	f, err := os.Open(batch.spoolFile)
+	if err != nil {
+		return err
+	}
+	err = f.Sync()
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	syncDir(s.dir)
+	batch.spoolSynced = true
+
+	return nil
+}
+
+func (s *spool) reserve(size int64) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.maxBytes > 0 && s.size+size > s.maxBytes {
+		return errSpoolFull
+	}
+	s.size += size
+
+	return nil
+}
+
+// writeSpoolFile writes through a temp file so a torn write never looks like a
+// spooled batch.
+func writeSpoolFile(name string, data []byte) error {
+	tmp := name + spoolTempSuffix
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, name); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	return nil
+}
+
+func appendSpoolFile(name string, data []byte) error {
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// remove drops the spool file of batch once it needs no replay.
+func (s *spool) remove(batch *Batch) {
+	if s == nil || batch.spoolFile == "" {
+		return
+	}
+
+	if err := os.Remove(batch.spoolFile); err != nil && !os.IsNotExist(err) {
+		level.Warn(s.logger).Log("msg", "remove spool file failed", "file", batch.spoolFile, "error", err)
+		return
+	}
+
+	s.release(batch.spoolSize)
+	batch.spoolFile = ""
+	batch.spoolSize = 0
+	batch.spoolSynced = false
+}
+
+func (s *spool) release(size int64) {
+	s.lock.Lock()
+	s.size -= size
+	s.lock.Unlock()
+}
+
+// load returns the spooled batches oldest first. Files past the age cap and
+// files that can not be decoded are deleted.
+func (s *spool) load(config *Config) []*Batch {
+	if s == nil {
+		return nil
+	}
+
+	files, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		level.Error(s.logger).Log("msg", "read spool dir failed", "error", err)
+		return nil
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name() < files[j].Name() })
+
+	var batches []*Batch
+	for _, f := range files {
+		name := filepath.Join(s.dir, f.Name())
+		if strings.HasSuffix(f.Name(), spoolTempSuffix) {
+			os.Remove(name)
+			continue
+		}
+		if !strings.HasSuffix(f.Name(), spoolFileSuffix) {
+			continue
+		}
+
+		batch, err := s.loadFile(name, config)
+		if err == nil && s.maxAge > 0 && time.Since(batch.createTime) > s.maxAge {
+			err = fmt.Errorf("older than %v", s.maxAge)
+		}
+		if err != nil {
+			level.Warn(s.logger).Log("msg", "drop spool file", "file", name, "error", err)
+			if os.Remove(name) == nil {
+				s.release(f.Size())
+			}
+			continue
+		}
+
+		batch.spoolSize = f.Size()
+		batch.spoolSynced = true
+		batches = append(batches, batch)
+	}
+
+	return batches
+}
+
+func (s *spool) loadFile(name string, config *Config) (*Batch, error) {
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	batch, n, err := decodeSpoolBatch(data, config)
+	if err != nil {
+		return nil, err
+	}
+	if n < len(data) {
+		level.Warn(s.logger).Log("msg", "ignore torn tail of spool file", "file", name, "bytes", len(data)-n)
+	}
+	batch.spoolFile = name
+
+	return batch, nil
+}
+
+func encodeSpoolBatch(batch *Batch) ([]byte, error) {
+	header := *batch.logGroup
+	header.Logs = nil
+	logGroup, err := header.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	var shardHash string
+	if batch.shardHash != nil {
+		shardHash = *batch.shardHash
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, batch.logGroup.Size()+len(batch.topic)+len(shardHash)+64))
+	buf.Write(spoolMagic)
+	buf.WriteByte(spoolVersion)
+	writeSpoolBytes(buf, []byte(batch.topic))
+	writeSpoolBytes(buf, []byte(shardHash))
+	var n [binary.MaxVarintLen64]byte
+	buf.Write(n[:binary.PutVarint(n[:], batch.createTime.UnixNano())])
+	writeSpoolBytes(buf, logGroup)
+	writeSpoolSum(buf, buf.Bytes())
+
+	for _, log := range batch.logGroup.Logs {
+		record, err := encodeSpoolLog(log)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(record)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func encodeSpoolLog(log *pb.Log) ([]byte, error) {
+	data, err := log.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, len(data)+binary.MaxVarintLen64+4))
+	writeSpoolBytes(buf, data)
+	writeSpoolSum(buf, data)
+
+	return buf.Bytes(), nil
+}
+
+// decodeSpoolBatch returns the batch in data and the number of bytes it was
+// read from. Decoding stops at the first torn or corrupt log record.
+func decodeSpoolBatch(data []byte, config *Config) (*Batch, int, error) {
+	if len(data) < len(spoolMagic)+1 || !bytes.Equal(data[:len(spoolMagic)], spoolMagic) || data[len(spoolMagic)] != spoolVersion {
+		return nil, 0, errSpoolCorrupt
+	}
+
+	r := bytes.NewReader(data[len(spoolMagic)+1:])
+	topic, err := readSpoolBytes(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	shardHash, err := readSpoolBytes(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	createTime, err := binary.ReadVarint(r)
+	if err != nil {
+		return nil, 0, errSpoolCorrupt
+	}
+	rawLogGroup, err := readSpoolBytes(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	n := len(data) - r.Len()
+	if !readSpoolSum(r, data[:n]) {
+		return nil, 0, errSpoolCorrupt
+	}
+
+	logGroup := &pb.LogGroup{}
+	if err := logGroup.Unmarshal(rawLogGroup); err != nil {
+		return nil, 0, err
+	}
+
+	for {
+		n = len(data) - r.Len()
+		rawLog, err := readSpoolBytes(r)
+		if err != nil || !readSpoolSum(r, rawLog) {
+			break
+		}
+		log := &pb.Log{}
+		if err := log.Unmarshal(rawLog); err != nil {
+			break
+		}
+		logGroup.Logs = append(logGroup.Logs, log)
+	}
+	if len(logGroup.Logs) == 0 {
+		return nil, 0, errSpoolCorrupt
+	}
+
+	batch := newBatch(string(topic), string(shardHash), logGroup, config)
+	batch.createTime = time.Unix(0, createTime)
+
+	return batch, n, nil
+}
+
+func writeSpoolBytes(buf *bytes.Buffer, b []byte) {
+	var n [binary.MaxVarintLen64]byte
+	buf.Write(n[:binary.PutUvarint(n[:], uint64(len(b)))])
+	buf.Write(b)
+}
+
+func readSpoolBytes(r *bytes.Reader) ([]byte, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil || n > uint64(r.Len()) {
+		return nil, errSpoolCorrupt
+	}
+	b := make([]byte, n)
+	r.Read(b)
+
+	return b, nil
+}
+
+func writeSpoolSum(buf *bytes.Buffer, b []byte) {
+	var sum [4]byte
+	binary.BigEndian.PutUint32(sum[:], crc32.ChecksumIEEE(b))
+	buf.Write(sum[:])
+}
+
+func readSpoolSum(r *bytes.Reader, b []byte) bool {
+	var sum [4]byte
+	if _, err := io.ReadFull(r, sum[:]); err != nil {
+		return false
+	}
+
+	return crc32.ChecksumIEEE(b) == binary.BigEndian.Uint32(sum[:])
+}
+
+// syncDir makes renames and removals in dir durable, best effort: not every
+// platform can fsync a directory.
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+}
@@ -0,0 +1,282 @@
+package producer
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/pierrec/lz4"
+	"github.com/volcengine/volc-sdk-golang/service/tls/pb"
+)
+
+// fakePutLogsServer accepts PutLogs while ok is set and answers 403 otherwise,
+// which the producer neither retries nor treats as final.
+type fakePutLogsServer struct {
+	*httptest.Server
+
+	lock     sync.Mutex
+	ok       bool
+	received []string
+}
+
+func newFakePutLogsServer(ok bool) *fakePutLogsServer {
+	s := &fakePutLogsServer{ok: ok}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		if !s.ok {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errorCode":"Forbidden","errorMessage":"outage"}`))
+			return
+		}
+
+		body, _ := ioutil.ReadAll(r.Body)
+		rawSize, _ := strconv.Atoi(r.Header.Get("x-tls-bodyrawsize"))
+		raw := make([]byte, rawSize)
+		if _, err := lz4.UncompressBlock(body, raw); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		list := &pb.LogGroupList{}
+		list.Unmarshal(raw)
+		for _, group := range list.LogGroups {
+			for _, log := range group.Logs {
+				s.received = append(s.received, log.Contents[0].Value)
+			}
+		}
+	}))
+	return s
+}
+
+func (s *fakePutLogsServer) logs() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]string(nil), s.received...)
+}
+
+func newSpoolProducer(endpoint, dir string) Producer {
+	config := GetDefaultProducerConfig()
+	config.Endpoint = endpoint
+	config.Region = "cn-beijing"
+	config.AccessKeyID, config.AccessKeySecret = "ak", "sk"
+	config.LingerTime = 200 * time.Millisecond
+	config.Retries = 0
+	config.SpoolDir = dir
+	config.LogLevel = "error"
+	return NewProducer(config)
+}
+
+func spoolFiles(t *testing.T, dir string) []string {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+spoolFileSuffix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func sendTestLog(t *testing.T, p Producer, value string) {
+	err := p.SendLog("", "topic", "127.0.0.1", "", GenerateLog(time.Now().Unix(), map[string]string{"message": value}), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSpoolReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tls-spool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Batches without callbacks rejected during an outage stay in the spool.
+	server := newFakePutLogsServer(false)
+	defer server.Close()
+	p := newSpoolProducer(server.URL, dir)
+	p.Start()
+	sendTestLog(t, p, "outage")
+	p.Close()
+	if files := spoolFiles(t, dir); len(files) != 1 {
+		t.Fatalf("expect 1 spooled batch, got %v", files)
+	}
+
+	// Batches still lingering in memory are spilled by ForceClose.
+	p = newSpoolProducer(server.URL, dir)
+	p.(*producer).config.LingerTime = time.Hour
+	sendTestLog(t, p, "force close")
+	p.Start()
+	time.Sleep(100 * time.Millisecond)
+	p.ForceClose()
+	if files := spoolFiles(t, dir); len(files) != 2 {
+		t.Fatalf("expect 2 spooled batches, got %v", files)
+	}
+
+	// The next Start replays both and clears the spool.
+	server.lock.Lock()
+	server.ok = true
+	server.lock.Unlock()
+	p = newSpoolProducer(server.URL, dir)
+	p.Start()
+	p.Close()
+	logs := server.logs()
+	sort.Strings(logs)
+	if len(logs) != 2 || logs[0] != "force close" || logs[1] != "outage" {
+		t.Fatalf("unexpected replayed logs %v", logs)
+	}
+	if files := spoolFiles(t, dir); len(files) != 0 {
+		t.Fatalf("expect an empty spool, got %v", files)
+	}
+}
+
+func TestSpoolForceCloseFullTaskChan(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tls-spool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	p := newSpoolProducer("http://127.0.0.1:1", dir).(*producer)
+	p.config.MaxBatchCount = 1
+	newLog := func(value string) *BatchLog {
+		return &BatchLog{Key: BatchKey{Topic: "topic"}, Log: GenerateLog(time.Now().Unix(), map[string]string{"message": value})}
+	}
+
+	// A lingering batch, a full task channel nobody reads any more and logs
+	// which overflow the lingering batch.
+	p.dispatcher.handleLogs(newLog("lingering"))
+	for i := 0; i < cap(p.threadPool.taskChan); i++ {
+		p.threadPool.taskChan <- initProducerBatch(newLog("queued"), p.config)
+	}
+	p.dispatcher.newLogRecvChan <- newLog("received 1")
+	p.dispatcher.newLogRecvChan <- newLog("received 2")
+
+	done := make(chan struct{})
+	go func() {
+		p.ForceClose()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("ForceClose blocked on the full task channel")
+	}
+	if files := spoolFiles(t, dir); len(files) != cap(p.threadPool.taskChan)+3 {
+		t.Fatalf("expect %d spooled batches, got %d", cap(p.threadPool.taskChan)+3, len(files))
+	}
+}
+
+func TestSpoolCaps(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tls-spool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// A torn file left by a crash.
+	ioutil.WriteFile(filepath.Join(dir, "0-0"+spoolFileSuffix), []byte("garbage"), 0644)
+
+	config := GetDefaultProducerConfig()
+	config.SpoolDir = dir
+	validateProducerConfig(config)
+	s, err := newSpool(config, log.NewNopLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newTestBatch := func(value string, created time.Time) *Batch {
+		batch := newBatch("topic", "", &pb.LogGroup{Logs: []*pb.Log{GenerateLog(1, map[string]string{"message": value})}}, config)
+		batch.createTime = created
+		return batch
+	}
+	old, fresh := newTestBatch("old", time.Now().Add(-100*time.Hour)), newTestBatch("fresh", time.Now())
+	if err := s.persist(old); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.persist(fresh); err != nil {
+		t.Fatal(err)
+	}
+
+	// The size cap rejects new batches once reached.
+	s.maxBytes = s.size
+	if err := s.persist(newTestBatch("full", time.Now())); err != errSpoolFull {
+		t.Fatalf("expect errSpoolFull, got %v", err)
+	}
+
+	// Corrupt files and batches past the age cap are dropped on load.
+	batches := s.load(config)
+	if len(batches) != 1 || batches[0].logGroup.Logs[0].Contents[0].Value != "fresh" || batches[0].topic != "topic" {
+		t.Fatalf("unexpected batches %v", batches)
+	}
+	if files := spoolFiles(t, dir); len(files) != 1 || s.size != fresh.spoolSize {
+		t.Fatalf("unexpected spool %v of %d bytes", files, s.size)
+	}
+}
+
+func TestSpoolLingeringBatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tls-spool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	p := newSpoolProducer("http://127.0.0.1:1", dir).(*producer)
+	for _, value := range []string{"first", "second"} {
+		p.dispatcher.handleLogs(&BatchLog{Key: BatchKey{Topic: "topic"}, Log: GenerateLog(1, map[string]string{"message": value})})
+	}
+
+	// The batch is on disk while it lingers, a crash now loses nothing.
+	files := spoolFiles(t, dir)
+	if len(files) != 1 {
+		t.Fatalf("expect 1 spooled batch, got %v", files)
+	}
+
+	// A log record torn by the crash is ignored.
+	f, err := os.OpenFile(files[0], os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte{0x20, 0x01})
+	f.Close()
+
+	s, err := newSpool(p.config, log.NewNopLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	batches := s.load(p.config)
+	if len(batches) != 1 || len(batches[0].logGroup.Logs) != 2 || batches[0].logGroup.Logs[1].Contents[0].Value != "second" {
+		t.Fatalf("unexpected batches %v", batches)
+	}
+}
+
+func TestSpoolFailureReported(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tls-spool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	server := newFakePutLogsServer(false)
+	defer server.Close()
+	p := newSpoolProducer(server.URL, dir)
+	p.Start()
+	callBack := &recordingCallBack{}
+	if err := p.SendLog("", "topic", "127.0.0.1", "", GenerateLog(1, map[string]string{"message": "failed"}), callBack); err != nil {
+		t.Fatal(err)
+	}
+	p.Close()
+
+	// The callback reported the failure, so the batch is not replayed.
+	if result := callBack.last(); result == nil || result.SuccessFlag {
+		t.Fatalf("expect a failure callback, got %v", result)
+	}
+	if files := spoolFiles(t, dir); len(files) != 0 {
+		t.Fatalf("expect an empty spool, got %v", files)
+	}
+}
@@ -1,6 +1,7 @@
 package tls
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"io/ioutil"
@@ -9,6 +10,10 @@ import (
 )
 
 func (c *LsClient) CreateProject(request *CreateProjectRequest) (r *CreateProjectResponse, e error) {
+	return c.CreateProjectCtx(context.Background(), request)
+}
+
+func (c *LsClient) CreateProjectCtx(ctx context.Context, request *CreateProjectRequest) (r *CreateProjectResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -22,7 +27,7 @@ func (c *LsClient) CreateProject(request *CreateProjectRequest) (r *CreateProjec
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPost, PathCreateProject, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPost, PathCreateProject, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -43,6 +48,10 @@ func (c *LsClient) CreateProject(request *CreateProjectRequest) (r *CreateProjec
 }
 
 func (c *LsClient) DeleteProject(request *DeleteProjectRequest) (r *CommonResponse, e error) {
+	return c.DeleteProjectCtx(context.Background(), request)
+}
+
+func (c *LsClient) DeleteProjectCtx(ctx context.Context, request *DeleteProjectRequest) (r *CommonResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -60,7 +69,7 @@ func (c *LsClient) DeleteProject(request *DeleteProjectRequest) (r *CommonRespon
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodDelete, PathDeleteProject, nil, c.assembleHeader(request.CommonRequest, reqHeaders), jsonBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodDelete, PathDeleteProject, nil, c.assembleHeader(request.CommonRequest, reqHeaders), jsonBody)
 	if err != nil {
 		return nil, err
 	}
@@ -81,6 +90,10 @@ func (c *LsClient) DeleteProject(request *DeleteProjectRequest) (r *CommonRespon
 }
 
 func (c *LsClient) DescribeProject(request *DescribeProjectRequest) (r *DescribeProjectResponse, e error) {
+	return c.DescribeProjectCtx(context.Background(), request)
+}
+
+func (c *LsClient) DescribeProjectCtx(ctx context.Context, request *DescribeProjectRequest) (r *DescribeProjectResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -96,7 +109,7 @@ func (c *LsClient) DescribeProject(request *DescribeProjectRequest) (r *Describe
 	body := map[string]string{}
 	bytesBody, err := json.Marshal(body)
 
-	rawResponse, err := c.Request(http.MethodGet, PathDescribeProject, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodGet, PathDescribeProject, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -118,6 +131,10 @@ func (c *LsClient) DescribeProject(request *DescribeProjectRequest) (r *Describe
 }
 
 func (c *LsClient) DescribeProjects(request *DescribeProjectsRequest) (r *DescribeProjectsResponse, e error) {
+	return c.DescribeProjectsCtx(context.Background(), request)
+}
+
+func (c *LsClient) DescribeProjectsCtx(ctx context.Context, request *DescribeProjectsRequest) (r *DescribeProjectsResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -160,7 +177,7 @@ func (c *LsClient) DescribeProjects(request *DescribeProjectsRequest) (r *Descri
 	body := map[string]string{}
 	bytesBody, err := json.Marshal(body)
 
-	rawResponse, err := c.Request(http.MethodGet, PathDescribeProjects, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodGet, PathDescribeProjects, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -182,6 +199,10 @@ func (c *LsClient) DescribeProjects(request *DescribeProjectsRequest) (r *Descri
 }
 
 func (c *LsClient) ModifyProject(request *ModifyProjectRequest) (r *CommonResponse, e error) {
+	return c.ModifyProjectCtx(context.Background(), request)
+}
+
+func (c *LsClient) ModifyProjectCtx(ctx context.Context, request *ModifyProjectRequest) (r *CommonResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -207,7 +228,7 @@ func (c *LsClient) ModifyProject(request *ModifyProjectRequest) (r *CommonRespon
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPut, PathModifyProject, nil, c.assembleHeader(request.CommonRequest, reqHeaders), jsonBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPut, PathModifyProject, nil, c.assembleHeader(request.CommonRequest, reqHeaders), jsonBody)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,216 @@
+package tls
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// plainSearchKey matches the field names usable in a query without quotes.
+var plainSearchKey = regexp.MustCompile(`^[A-Za-z0-9_.\-]+$`)
+
+// Query builds the Query of a SearchLogsRequest, a search statement and an
+// optional SQL analysis statement:
+//
+//	query := tls.And(tls.Field("status", "500"), tls.Not(tls.Match("health check"))).
+//		Pipe(tls.Select(tls.Ident("host"), "count(*) AS cnt").GroupBy(tls.Ident("host")).OrderBy("cnt DESC").Limit(10))
+//	request := &tls.SearchLogsRequest{TopicID: topicID, Query: query.String(), ...}
+//
+// Values are quoted and escaped, so they match literally.
+type Query struct {
+	search   string
+	compound bool
+	analysis *Analysis
+}
+
+// All matches every log.
+func All() Query {
+	return Query{search: "*"}
+}
+
+// RawQuery uses search as it is, it must be a valid search statement.
+func RawQuery(search string) Query {
+	return Query{search: search, compound: true}
+}
+
+// Match matches logs containing text in any field.
+func Match(text string) Query {
+	return Query{search: quoteSearchValue(text)}
+}
+
+// Field matches logs whose field key is value.
+func Field(key, value string) Query {
+	return Query{search: quoteSearchKey(key) + ":" + quoteSearchValue(value)}
+}
+
+// FieldPrefix matches logs whose field key starts with prefix.
+func FieldPrefix(key, prefix string) Query {
+	return Query{search: quoteSearchKey(key) + ":" + escapeSearchValue(prefix) + "*"}
+}
+
+// And matches logs matching all queries.
+func And(queries ...Query) Query {
+	return join(" AND ", queries)
+}
+
+// Or matches logs matching any of queries.
+func Or(queries ...Query) Query {
+	return join(" OR ", queries)
+}
+
+// Not matches logs not matching q.
+func Not(q Query) Query {
+	return Query{search: "NOT " + q.group()}
+}
+
+// join combines the non-empty queries with op.
+func join(op string, queries []Query) Query {
+	parts := make([]string, 0, len(queries))
+	var last Query
+	for _, q := range queries {
+		if q.search != "" {
+			parts = append(parts, q.group())
+			last = q
+		}
+	}
+	if len(parts) <= 1 {
+		return Query{search: last.search, compound: last.compound}
+	}
+
+	return Query{search: strings.Join(parts, op), compound: true}
+}
+
+// group returns the search statement, in parentheses if it combines several.
+func (q Query) group() string {
+	if q.compound {
+		return "(" + q.search + ")"
+	}
+
+	return q.search
+}
+
+// Pipe adds an analysis statement to the query.
+func (q Query) Pipe(analysis *Analysis) Query {
+	q.analysis = analysis
+	return q
+}
+
+// String returns the Query of a SearchLogsRequest. An empty search statement
+// matches every log.
+func (q Query) String() string {
+	search := q.search
+	if search == "" {
+		search = "*"
+	}
+	if q.analysis == nil {
+		return search
+	}
+
+	return search + " | " + q.analysis.String()
+}
+
+// quoteSearchValue quotes value for a search statement.
+func quoteSearchValue(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+// escapeSearchValue escapes the characters of value with a special meaning in
+// a search statement, for use outside of quotes.
+func escapeSearchValue(value string) string {
+	var b strings.Builder
+	for _, c := range value {
+		if !(c == '_' || c == '.' || c == '-' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c > 0x7f) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(c)
+	}
+
+	return b.String()
+}
+
+func quoteSearchKey(key string) string {
+	if plainSearchKey.MatchString(key) {
+		return key
+	}
+
+	return quoteSearchValue(key)
+}
+
+// Analysis builds the SQL analysis statement of a Query. Expressions are used
+// as they are, quote names with Ident and literals with SQLString.
+type Analysis struct {
+	selects []string
+	where   string
+	groupBy []string
+	having  string
+	orderBy []string
+	limit   int
+}
+
+// Select starts an analysis statement selecting exprs.
+func Select(exprs ...string) *Analysis {
+	return &Analysis{selects: exprs}
+}
+
+func (a *Analysis) Where(cond string) *Analysis {
+	a.where = cond
+	return a
+}
+
+func (a *Analysis) GroupBy(exprs ...string) *Analysis {
+	a.groupBy = exprs
+	return a
+}
+
+func (a *Analysis) Having(cond string) *Analysis {
+	a.having = cond
+	return a
+}
+
+func (a *Analysis) OrderBy(exprs ...string) *Analysis {
+	a.orderBy = exprs
+	return a
+}
+
+// Limit caps the rows of the result, 0 keeps the server default.
+func (a *Analysis) Limit(n int) *Analysis {
+	a.limit = n
+	return a
+}
+
+func (a *Analysis) String() string {
+	selects := a.selects
+	if len(selects) == 0 {
+		selects = []string{"*"}
+	}
+
+	var b strings.Builder
+	b.WriteString("SELECT " + strings.Join(selects, ", "))
+	if a.where != "" {
+		b.WriteString(" WHERE " + a.where)
+	}
+	if len(a.groupBy) > 0 {
+		b.WriteString(" GROUP BY " + strings.Join(a.groupBy, ", "))
+	}
+	if a.having != "" {
+		b.WriteString(" HAVING " + a.having)
+	}
+	if len(a.orderBy) > 0 {
+		b.WriteString(" ORDER BY " + strings.Join(a.orderBy, ", "))
+	}
+	if a.limit > 0 {
+		b.WriteString(" LIMIT " + strconv.Itoa(a.limit))
+	}
+
+	return b.String()
+}
+
+// Ident quotes a field name for an analysis statement.
+func Ident(name string) string {
+	return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+}
+
+// SQLString quotes a string literal for an analysis statement.
+func SQLString(value string) string {
+	return "'" + strings.Replace(value, "'", "''", -1) + "'"
+}
@@ -0,0 +1,33 @@
+package tls
+
+import "testing"
+
+func TestQuery(t *testing.T) {
+	cases := []struct {
+		query  Query
+		expect string
+	}{
+		{Query{}, "*"},
+		{All(), "*"},
+		{Match(`say "hi"\now`), `"say \"hi\"\\now"`},
+		{Field("status", "500"), `status:"500"`},
+		{Field("user name", "a"), `"user name":"a"`},
+		{FieldPrefix("path", "/api v1"), `path:\/api\ v1*`},
+		{And(Field("a", "1"), Or(Field("b", "2"), Field("c", "3"))), `a:"1" AND (b:"2" OR c:"3")`},
+		{And(Field("a", "1"), Not(Or(Match("x"), Match("y")))), `a:"1" AND NOT ("x" OR "y")`},
+		{And(Query{}, Field("a", "1")), `a:"1"`},
+		{Or(), "*"},
+		{RawQuery("a:1 OR b:2").Pipe(Select("count(*) AS cnt")), "a:1 OR b:2 | SELECT count(*) AS cnt"},
+		{
+			Field("level", "ERROR").Pipe(Select(Ident("host"), "count(*) AS cnt").
+				Where(Ident("region") + " = " + SQLString("cn's")).
+				GroupBy(Ident("host")).Having("cnt > 10").OrderBy("cnt DESC").Limit(5)),
+			`level:"ERROR" | SELECT "host", count(*) AS cnt WHERE "region" = 'cn''s' GROUP BY "host" HAVING cnt > 10 ORDER BY cnt DESC LIMIT 5`,
+		},
+	}
+	for _, c := range cases {
+		if got := c.query.String(); got != c.expect {
+			t.Errorf("expected %s, got %s", c.expect, got)
+		}
+	}
+}
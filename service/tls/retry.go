@@ -5,14 +5,36 @@ import (
 	"math"
 	"math/rand"
 	"net"
+	"net/http"
 	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/volcengine/volc-sdk-golang/base"
 )
 
 type ConditionOperation func() error
 
+// newRetryAttempt describes the outcome of one request to a base.RetryPolicy.
+func newRetryAttempt(uri string, err error) *base.RetryAttempt {
+	attempt := &base.RetryAttempt{Api: uri, Err: err}
+	switch e := err.(type) {
+	case nil:
+	case *Error:
+		attempt.StatusCode = int(e.HTTPCode)
+		attempt.Header = e.RespHeader
+		if e.Code == ErrExceedQPSLimit {
+			attempt.StatusCode = http.StatusTooManyRequests
+		}
+	case *BadResponseError:
+		attempt.StatusCode = e.HTTPCode
+		attempt.Header = e.RespHeader
+	default:
+		attempt.Transport = true
+	}
+	return attempt
+}
+
 func needRetry(err *Error) bool {
 	if err == nil {
 		return false
@@ -70,12 +92,16 @@ func RetryWithCondition(ctx context.Context, o ConditionOperation) error {
 				}
 			}
 
-			var retrySleepInterval = time.Duration(math.Floor(rand.Float64() * float64(defaultRetryCounter) * float64(defaultRetryInterval)))
+			var retrySleepInterval = time.Duration(math.Floor(rand.Float64() * float64(atomic.LoadInt32(&defaultRetryCounter)) * float64(defaultRetryInterval)))
 			var maxSleepInterval = time.Until(expectedQuitTime)
 			if retrySleepInterval > maxSleepInterval {
 				retrySleepInterval = maxSleepInterval
 			}
-			time.Sleep(retrySleepInterval)
+			select {
+			case <-ctx.Done():
+				return errors.Wrapf(ctx.Err(), "stopped retrying err: %v", err)
+			case <-time.After(retrySleepInterval):
+			}
 
 			if tryCount >= 5 || defaultRetryCounterMaximum <= 0 || time.Now().After(expectedQuitTime) {
 				// 重试超过5次或已经超出预期超时, 直接返回最近一次请求结果
@@ -0,0 +1,99 @@
+package tls
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/volcengine/volc-sdk-golang/base"
+)
+
+func TestLsClient_RetryPolicy(t *testing.T) {
+	var served int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served++
+		if served < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"errorCode":"ExceedQPSLimit","errorMessage":"qps limit"}`))
+			return
+		}
+		w.Write([]byte(`{"Total":0,"Projects":[]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "ak", "sk", "", "cn-beijing").(*LsClient)
+	client.SetRetryPolicy(base.NewExponentialRetryPolicy(2, time.Millisecond, time.Millisecond))
+	if _, err := client.DescribeProjects(&DescribeProjectsRequest{}); err == nil || served != 2 {
+		t.Fatalf("expect failure after 2 attempts, got err=%v after %d", err, served)
+	}
+
+	served = 0
+	client.SetRetryPolicy(base.NewExponentialRetryPolicy(3, time.Millisecond, time.Millisecond))
+	if _, err := client.DescribeProjects(&DescribeProjectsRequest{}); err != nil || served != 3 {
+		t.Fatalf("expect success after 3 attempts, got err=%v after %d", err, served)
+	}
+}
+
+func TestLsClient_RetryAfter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "3")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"errorCode":"ExceedQPSLimit","errorMessage":"qps limit"}`))
+	}))
+	defer server.Close()
+
+	var delay time.Duration
+	policy := base.NewExponentialRetryPolicy(2, time.Millisecond, 10*time.Second)
+	client := NewClient(server.URL, "ak", "sk", "", "cn-beijing")
+	client.SetRetryPolicy(base.RetryPolicyFunc(func(attempt *base.RetryAttempt) (time.Duration, bool) {
+		delay, _ = policy.ShouldRetry(attempt)
+		return 0, false
+	}))
+	if _, err := client.DescribeProjects(&DescribeProjectsRequest{}); err == nil {
+		t.Fatal("expect the 429 to be returned")
+	}
+	if delay != 3*time.Second {
+		t.Fatalf("expect the Retry-After of the 429 to be honored, got a delay of %v", delay)
+	}
+}
+
+func TestLsClient_Context(t *testing.T) {
+	served := make(chan struct{}, 16)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served <- struct{}{}
+		if r.URL.Path == PathSearchLogs {
+			// Hang until the client gives up.
+			ioutil.ReadAll(r.Body)
+			<-r.Context().Done()
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"errorCode":"ServiceUnavailable","errorMessage":"busy"}`))
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "ak", "sk", "", "cn-beijing")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := client.SearchLogsV2Ctx(ctx, &SearchLogsRequest{TopicID: "topic", Query: "*", StartTime: 1, EndTime: 2})
+	if err == nil || time.Since(start) > 5*time.Second {
+		t.Fatalf("expect in-flight search to be cancelled, got err=%v after %v", err, time.Since(start))
+	}
+
+	// Cancelling also stops waiting between retries.
+	ctx, cancel = context.WithCancel(context.Background())
+	go func() {
+		<-served
+		<-served
+		cancel()
+	}()
+	_, err = client.DescribeProjectsCtx(ctx, &DescribeProjectsRequest{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expect context.Canceled, got %v", err)
+	}
+}
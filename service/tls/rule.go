@@ -1,6 +1,7 @@
 package tls
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
@@ -8,6 +9,10 @@ import (
 )
 
 func (c *LsClient) CreateRule(request *CreateRuleRequest) (r *CreateRuleResponse, e error) {
+	return c.CreateRuleCtx(context.Background(), request)
+}
+
+func (c *LsClient) CreateRuleCtx(ctx context.Context, request *CreateRuleRequest) (r *CreateRuleResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -21,7 +26,7 @@ func (c *LsClient) CreateRule(request *CreateRuleRequest) (r *CreateRuleResponse
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPost, PathCreateRule, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPost, PathCreateRule, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -44,6 +49,10 @@ func (c *LsClient) CreateRule(request *CreateRuleRequest) (r *CreateRuleResponse
 }
 
 func (c *LsClient) DeleteRule(request *DeleteRuleRequest) (r *CommonResponse, e error) {
+	return c.DeleteRuleCtx(context.Background(), request)
+}
+
+func (c *LsClient) DeleteRuleCtx(ctx context.Context, request *DeleteRuleRequest) (r *CommonResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -57,7 +66,7 @@ func (c *LsClient) DeleteRule(request *DeleteRuleRequest) (r *CommonResponse, e
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodDelete, PathDeleteRule, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodDelete, PathDeleteRule, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -79,6 +88,10 @@ func (c *LsClient) DeleteRule(request *DeleteRuleRequest) (r *CommonResponse, e
 }
 
 func (c *LsClient) ModifyRule(request *ModifyRuleRequest) (r *CommonResponse, e error) {
+	return c.ModifyRuleCtx(context.Background(), request)
+}
+
+func (c *LsClient) ModifyRuleCtx(ctx context.Context, request *ModifyRuleRequest) (r *CommonResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -92,7 +105,7 @@ func (c *LsClient) ModifyRule(request *ModifyRuleRequest) (r *CommonResponse, e
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPut, PathModifyRule, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPut, PathModifyRule, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -114,6 +127,10 @@ func (c *LsClient) ModifyRule(request *ModifyRuleRequest) (r *CommonResponse, e
 }
 
 func (c *LsClient) DescribeRule(request *DescribeRuleRequest) (r *DescribeRuleResponse, e error) {
+	return c.DescribeRuleCtx(context.Background(), request)
+}
+
+func (c *LsClient) DescribeRuleCtx(ctx context.Context, request *DescribeRuleRequest) (r *DescribeRuleResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -129,7 +146,7 @@ func (c *LsClient) DescribeRule(request *DescribeRuleRequest) (r *DescribeRuleRe
 	body := map[string]string{}
 	bytesBody, err := json.Marshal(body)
 
-	rawResponse, err := c.Request(http.MethodGet, PathDescribeRule, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodGet, PathDescribeRule, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -152,6 +169,10 @@ func (c *LsClient) DescribeRule(request *DescribeRuleRequest) (r *DescribeRuleRe
 }
 
 func (c *LsClient) DescribeRules(request *DescribeRulesRequest) (r *DescribeRulesResponse, e error) {
+	return c.DescribeRulesCtx(context.Background(), request)
+}
+
+func (c *LsClient) DescribeRulesCtx(ctx context.Context, request *DescribeRulesRequest) (r *DescribeRulesResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -191,7 +212,7 @@ func (c *LsClient) DescribeRules(request *DescribeRulesRequest) (r *DescribeRule
 	body := map[string]string{}
 	bytesBody, err := json.Marshal(body)
 
-	rawResponse, err := c.Request(http.MethodGet, PathDescribeRules, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodGet, PathDescribeRules, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 
 	if err != nil {
 		return nil, err
@@ -214,6 +235,10 @@ func (c *LsClient) DescribeRules(request *DescribeRulesRequest) (r *DescribeRule
 }
 
 func (c *LsClient) ApplyRuleToHostGroups(request *ApplyRuleToHostGroupsRequest) (r *CommonResponse, e error) {
+	return c.ApplyRuleToHostGroupsCtx(context.Background(), request)
+}
+
+func (c *LsClient) ApplyRuleToHostGroupsCtx(ctx context.Context, request *ApplyRuleToHostGroupsRequest) (r *CommonResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -227,7 +252,7 @@ func (c *LsClient) ApplyRuleToHostGroups(request *ApplyRuleToHostGroupsRequest)
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPut, PathApplyRuleToHostGroups, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPut, PathApplyRuleToHostGroups, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -249,6 +274,10 @@ func (c *LsClient) ApplyRuleToHostGroups(request *ApplyRuleToHostGroupsRequest)
 }
 
 func (c *LsClient) DeleteRuleFromHostGroups(request *DeleteRuleFromHostGroupsRequest) (r *CommonResponse, e error) {
+	return c.DeleteRuleFromHostGroupsCtx(context.Background(), request)
+}
+
+func (c *LsClient) DeleteRuleFromHostGroupsCtx(ctx context.Context, request *DeleteRuleFromHostGroupsRequest) (r *CommonResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -262,7 +291,7 @@ func (c *LsClient) DeleteRuleFromHostGroups(request *DeleteRuleFromHostGroupsReq
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPut, PathDeleteRuleFromHostGroups, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPut, PathDeleteRuleFromHostGroups, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,191 @@
+package tls
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DecodeRows stores rows of SearchLogs, logs or analysis results, into out,
+// a pointer to a slice of structs or struct pointers. A field takes the
+// column named by its `tls` tag, or its name if it has none; "-" skips it.
+// Columns are converted to the type of the field, which may be a string, a
+// bool, a number, a pointer to one of them, left nil for null, or anything
+// else json can decode the column into:
+//
+//	var rows []struct {
+//		Host  string `tls:"host"`
+//		Count int64  `tls:"cnt"`
+//	}
+//	err := tls.DecodeRows(resp.AnalysisResult.Data, &rows)
+func DecodeRows(rows []map[string]interface{}, out interface{}) error {
+	slice := reflect.ValueOf(out)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("decode rows: out must be a pointer to a slice, not %T", out)
+	}
+	slice = slice.Elem()
+	elemType := slice.Type().Elem()
+	structType := elemType
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("decode rows: %s is not a struct", elemType)
+	}
+
+	result := reflect.MakeSlice(slice.Type(), 0, len(rows))
+	for i, row := range rows {
+		elem := reflect.New(structType)
+		if err := decodeRow(row, elem.Elem()); err != nil {
+			return fmt.Errorf("decode rows: row %d: %w", i, err)
+		}
+		if elemType.Kind() != reflect.Ptr {
+			elem = elem.Elem()
+		}
+		result = reflect.Append(result, elem)
+	}
+	slice.Set(result)
+
+	return nil
+}
+
+// Decode stores the rows of the analysis result into out, see DecodeRows.
+func (r *AnalysisResult) Decode(out interface{}) error {
+	return DecodeRows(r.Data, out)
+}
+
+// DecodeLogs stores the logs of the response into out, see DecodeRows.
+func (r *SearchLogsResponse) DecodeLogs(out interface{}) error {
+	return DecodeRows(r.Logs, out)
+}
+
+func decodeRow(row map[string]interface{}, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		column := field.Name
+		if tag, ok := field.Tag.Lookup("tls"); ok {
+			column = strings.Split(tag, ",")[0]
+		}
+		if column == "-" {
+			continue
+		}
+		value, ok := row[column]
+		if !ok {
+			continue
+		}
+		if err := decodeColumn(value, v.Field(i)); err != nil {
+			return fmt.Errorf("column %s: %w", column, err)
+		}
+	}
+
+	return nil
+}
+
+// decodeColumn converts value, as decoded from the response, to the type of v.
+// Numbers may come as json.Number, float64 or numeric strings.
+func decodeColumn(value interface{}, v reflect.Value) error {
+	if value == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	if v.Kind() == reflect.Ptr {
+		elem := reflect.New(v.Type().Elem())
+		if err := decodeColumn(value, elem.Elem()); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+
+	text := columnText(value)
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(text)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(text, 10, v.Type().Bits())
+		if err != nil {
+			// Integral values may be rendered as floats, such as 1e+06.
+			f, fErr := strconv.ParseFloat(text, 64)
+			if fErr != nil || f != float64(int64(f)) {
+				return err
+			}
+			n = int64(f)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(text, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(text, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		if s, ok := value.(string); ok && json.Valid([]byte(s)) {
+			// Nested values are returned as JSON text.
+			data = []byte(s)
+		}
+		return json.Unmarshal(data, v.Addr().Interface())
+	}
+
+	return nil
+}
+
+func columnText(value interface{}) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case json.Number:
+		return value.String()
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// HistogramBucket is a DescribeHistogramV1 bucket with its times decoded.
+type HistogramBucket struct {
+	Start time.Time
+	End   time.Time
+	Count int64
+	// Complete is false if the count may be missing logs and the histogram
+	// should be described again.
+	Complete bool
+}
+
+// Buckets returns the histogram buckets, whose times are in milliseconds.
+func (r *DescribeHistogramV1Response) Buckets() []HistogramBucket {
+	buckets := make([]HistogramBucket, 0, len(r.HistogramInfos))
+	for _, info := range r.HistogramInfos {
+		buckets = append(buckets, HistogramBucket{
+			Start:    time.Unix(0, info.StartTime*int64(time.Millisecond)),
+			End:      time.Unix(0, info.EndTime*int64(time.Millisecond)),
+			Count:    info.Count,
+			Complete: !strings.EqualFold(info.ResultStatus, "incomplete"),
+		})
+	}
+
+	return buckets
+}
@@ -0,0 +1,112 @@
+package tls
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeRows(t *testing.T) {
+	var resp SearchLogsResponse
+	decoder := json.NewDecoder(strings.NewReader(`{"AnalysisResult": {"Data": [
+		{"host": "a", "cnt": 3, "avg": "1.5", "ok": "true", "tags": "[\"x\",\"y\"]", "missing": null},
+		{"host": "b", "cnt": "1e+06", "avg": 2, "ok": false, "tags": null, "missing": 7}
+	]}}`))
+	decoder.UseNumber()
+	if err := decoder.Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+
+	type row struct {
+		Host    string   `tls:"host"`
+		Count   int64    `tls:"cnt"`
+		Avg     float64  `tls:"avg"`
+		OK      bool     `tls:"ok"`
+		Tags    []string `tls:"tags"`
+		Missing *int     `tls:"missing"`
+		Skipped string   `tls:"-"`
+	}
+	var rows []row
+	if err := resp.AnalysisResult.Decode(&rows); err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[0].Host != "a" || rows[0].Count != 3 || rows[0].Avg != 1.5 || !rows[0].OK ||
+		len(rows[0].Tags) != 2 || rows[0].Missing != nil {
+		t.Fatalf("unexpected first row %+v", rows[0])
+	}
+	if rows[1].Count != 1000000 || rows[1].Avg != 2 || rows[1].OK || rows[1].Tags != nil || *rows[1].Missing != 7 {
+		t.Fatalf("unexpected second row %+v", rows[1])
+	}
+
+	var pointers []*row
+	if err := DecodeRows(resp.AnalysisResult.Data, &pointers); err != nil || len(pointers) != 2 || pointers[1].Host != "b" {
+		t.Fatalf("unexpected rows %v, err %v", pointers, err)
+	}
+
+	var bad []struct {
+		Count int `tls:"host"`
+	}
+	if err := resp.AnalysisResult.Decode(&bad); err == nil {
+		t.Fatal("expected an error decoding a string into an int")
+	}
+	if err := DecodeRows(resp.AnalysisResult.Data, rows); err == nil {
+		t.Fatal("expected an error decoding into a non-pointer")
+	}
+}
+
+func TestSearchLogsIterator(t *testing.T) {
+	const total = 25
+	var contexts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req SearchLogsRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		contexts = append(contexts, req.Context)
+
+		offset, _ := strconv.Atoi(req.Context)
+		resp := SearchLogsResponse{ListOver: true}
+		for i := offset; i < offset+req.Limit && i < total; i++ {
+			resp.Logs = append(resp.Logs, map[string]interface{}{"seq": i})
+		}
+		if offset+req.Limit < total {
+			resp.ListOver, resp.Context = false, strconv.Itoa(offset+req.Limit)
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "ak", "sk", "", "cn-beijing")
+
+	it := NewSearchLogsIterator(client, &SearchLogsRequest{TopicID: "topic", Query: "*", Limit: 10})
+	var seqs []int
+	for it.Next(context.Background()) {
+		var log struct {
+			Seq int `tls:"seq"`
+		}
+		if err := it.Scan(&log); err != nil {
+			t.Fatal(err)
+		}
+		seqs = append(seqs, log.Seq)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(seqs) != total || seqs[total-1] != total-1 || len(contexts) != 3 || contexts[2] != "20" {
+		t.Fatalf("unexpected logs %v from pages %v", seqs, contexts)
+	}
+}
+
+func TestHistogramBuckets(t *testing.T) {
+	resp := &DescribeHistogramV1Response{HistogramInfos: []HistogramInfoV1{
+		{StartTime: 1600000000000, EndTime: 1600000060000, Count: 3, ResultStatus: "complete"},
+		{StartTime: 1600000060000, EndTime: 1600000120000, Count: 1, ResultStatus: "incomplete"},
+	}}
+	buckets := resp.Buckets()
+	if len(buckets) != 2 || !buckets[0].Start.Equal(time.Unix(1600000000, 0)) || buckets[0].End.Sub(buckets[0].Start) != time.Minute ||
+		buckets[0].Count != 3 || !buckets[0].Complete || buckets[1].Complete {
+		t.Fatalf("unexpected buckets %+v", buckets)
+	}
+}
@@ -1,6 +1,7 @@
 package tls
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
@@ -8,6 +9,10 @@ import (
 )
 
 func (c *LsClient) DescribeShards(request *DescribeShardsRequest) (r *DescribeShardsResponse, e error) {
+	return c.DescribeShardsCtx(context.Background(), request)
+}
+
+func (c *LsClient) DescribeShardsCtx(ctx context.Context, request *DescribeShardsRequest) (r *DescribeShardsResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -31,7 +36,7 @@ func (c *LsClient) DescribeShards(request *DescribeShardsRequest) (r *DescribeSh
 	body := map[string]string{}
 	bytesBody, err := json.Marshal(body)
 
-	rawResponse, err := c.Request(http.MethodGet, PathDescribeShards, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodGet, PathDescribeShards, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
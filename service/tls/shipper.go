@@ -1,6 +1,7 @@
 package tls
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
@@ -8,6 +9,10 @@ import (
 )
 
 func (c *LsClient) CreateShipper(request *CreateShipperRequest) (*CreateShipperResponse, error) {
+	return c.CreateShipperCtx(context.Background(), request)
+}
+
+func (c *LsClient) CreateShipperCtx(ctx context.Context, request *CreateShipperRequest) (*CreateShipperResponse, error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -21,7 +26,7 @@ func (c *LsClient) CreateShipper(request *CreateShipperRequest) (*CreateShipperR
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPost, PathCreateShipper, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPost, PathCreateShipper, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -42,6 +47,10 @@ func (c *LsClient) CreateShipper(request *CreateShipperRequest) (*CreateShipperR
 }
 
 func (c *LsClient) DeleteShipper(request *DeleteShipperRequest) (*DeleteShipperResponse, error) {
+	return c.DeleteShipperCtx(context.Background(), request)
+}
+
+func (c *LsClient) DeleteShipperCtx(ctx context.Context, request *DeleteShipperRequest) (*DeleteShipperResponse, error) {
 	reqHeaders := map[string]string{
 		"Content-Type": "application/json",
 	}
@@ -51,7 +60,7 @@ func (c *LsClient) DeleteShipper(request *DeleteShipperRequest) (*DeleteShipperR
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodDelete, PathDeleteShipper, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodDelete, PathDeleteShipper, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -72,6 +81,10 @@ func (c *LsClient) DeleteShipper(request *DeleteShipperRequest) (*DeleteShipperR
 }
 
 func (c *LsClient) ModifyShipper(request *ModifyShipperRequest) (*ModifyShipperResponse, error) {
+	return c.ModifyShipperCtx(context.Background(), request)
+}
+
+func (c *LsClient) ModifyShipperCtx(ctx context.Context, request *ModifyShipperRequest) (*ModifyShipperResponse, error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -85,7 +98,7 @@ func (c *LsClient) ModifyShipper(request *ModifyShipperRequest) (*ModifyShipperR
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPut, PathModifyShipper, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPut, PathModifyShipper, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -106,6 +119,10 @@ func (c *LsClient) ModifyShipper(request *ModifyShipperRequest) (*ModifyShipperR
 }
 
 func (c *LsClient) DescribeShipper(request *DescribeShipperRequest) (*DescribeShipperResponse, error) {
+	return c.DescribeShipperCtx(context.Background(), request)
+}
+
+func (c *LsClient) DescribeShipperCtx(ctx context.Context, request *DescribeShipperRequest) (*DescribeShipperResponse, error) {
 	reqHeaders := map[string]string{
 		"Content-Type": "application/json",
 	}
@@ -114,7 +131,7 @@ func (c *LsClient) DescribeShipper(request *DescribeShipperRequest) (*DescribeSh
 		"ShipperId": request.ShipperId,
 	}
 
-	rawResponse, err := c.Request(http.MethodGet, PathDescribeShipper, params, c.assembleHeader(request.CommonRequest, reqHeaders), nil)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodGet, PathDescribeShipper, params, c.assembleHeader(request.CommonRequest, reqHeaders), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -136,6 +153,10 @@ func (c *LsClient) DescribeShipper(request *DescribeShipperRequest) (*DescribeSh
 }
 
 func (c *LsClient) DescribeShippers(request *DescribeShippersRequest) (*DescribeShippersResponse, error) {
+	return c.DescribeShippersCtx(context.Background(), request)
+}
+
+func (c *LsClient) DescribeShippersCtx(ctx context.Context, request *DescribeShippersRequest) (*DescribeShippersResponse, error) {
 	reqHeaders := map[string]string{
 		"Content-Type": "application/json",
 	}
@@ -173,7 +194,7 @@ func (c *LsClient) DescribeShippers(request *DescribeShippersRequest) (*Describe
 		params["PageSize"] = strconv.Itoa(request.PageSize)
 	}
 
-	rawResponse, err := c.Request(http.MethodGet, PathDescribeShippers, params, c.assembleHeader(request.CommonRequest, reqHeaders), nil)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodGet, PathDescribeShippers, params, c.assembleHeader(request.CommonRequest, reqHeaders), nil)
 	if err != nil {
 		return nil, err
 	}
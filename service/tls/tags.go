@@ -1,12 +1,17 @@
 package tls
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
 )
 
 func (c *LsClient) AddTagsToResource(request *AddTagsToResourceRequest) (*CommonResponse, error) {
+	return c.AddTagsToResourceCtx(context.Background(), request)
+}
+
+func (c *LsClient) AddTagsToResourceCtx(ctx context.Context, request *AddTagsToResourceRequest) (*CommonResponse, error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -20,7 +25,7 @@ func (c *LsClient) AddTagsToResource(request *AddTagsToResourceRequest) (*Common
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPost, PathAddTagsToResource, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPost, PathAddTagsToResource, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -42,6 +47,10 @@ func (c *LsClient) AddTagsToResource(request *AddTagsToResourceRequest) (*Common
 }
 
 func (c *LsClient) RemoveTagsFromResource(request *RemoveTagsFromResourceRequest) (*CommonResponse, error) {
+	return c.RemoveTagsFromResourceCtx(context.Background(), request)
+}
+
+func (c *LsClient) RemoveTagsFromResourceCtx(ctx context.Context, request *RemoveTagsFromResourceRequest) (*CommonResponse, error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -55,7 +64,7 @@ func (c *LsClient) RemoveTagsFromResource(request *RemoveTagsFromResourceRequest
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPost, PathRemoveTagsFromResource, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPost, PathRemoveTagsFromResource, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
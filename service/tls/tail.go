@@ -0,0 +1,459 @@
+package tls
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/volcengine/volc-sdk-golang/service/tls/pb"
+)
+
+const (
+	TailFromBegin = "begin"
+	TailFromEnd   = "end"
+
+	shardStatusReadOnly = "readonly"
+
+	defaultTailOrderWindow          = time.Second
+	defaultTailPollInterval         = time.Second
+	defaultTailShardRefreshInterval = 30 * time.Second
+	defaultTailLogGroupCount        = 100
+	tailBatchQueueSize              = 16
+)
+
+// TailOptions configures Tail, the zero value follows new logs of every
+// shard.
+type TailOptions struct {
+	// From is where the shards of the topic are read from: TailFromEnd, by
+	// default, TailFromBegin or a unix timestamp in seconds. Shards created by
+	// splits later on are read from their beginning.
+	From string
+	// Filters keep the logs matching all of them.
+	Filters []TailFilter
+	// MaxLogsPerSecond caps the logs delivered, the logs above it are dropped
+	// and counted by TailStream.Dropped. 0 delivers all of them.
+	MaxLogsPerSecond int
+	// OrderWindow is how long logs are held back to deliver the logs of all
+	// shards ordered by time, 1s by default. Logs arriving later than that may
+	// be delivered out of order.
+	OrderWindow time.Duration
+	// PollInterval is how long a shard without new logs waits before it is
+	// read again, 1s by default.
+	PollInterval time.Duration
+	// ShardRefreshInterval is how often the shards are described to pick up
+	// new shards, 30s by default.
+	ShardRefreshInterval time.Duration
+	// LogGroupCount is the most log groups read from a shard at once, 100 by
+	// default.
+	LogGroupCount int
+}
+
+// TailFilter matches logs with a content Key whose value is Value, or
+// contains it if Contains is set. An empty Key matches any content.
+type TailFilter struct {
+	Key      string
+	Value    string
+	Contains bool
+}
+
+func (f TailFilter) match(log *pb.Log) bool {
+	for _, content := range log.Contents {
+		if f.Key != "" && content.Key != f.Key {
+			continue
+		}
+		if content.Value == f.Value || f.Contains && strings.Contains(content.Value, f.Value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TailLog is a log delivered by Tail along with its log group metadata.
+type TailLog struct {
+	ShardID  int
+	Time     time.Time
+	Source   string
+	FileName string
+	Tags     []*pb.LogTag
+	Log      *pb.Log
+
+	arrival time.Time
+}
+
+// Value returns the value of the content key of the log.
+func (l *TailLog) Value(key string) (string, bool) {
+	for _, content := range l.Log.Contents {
+		if content.Key == key {
+			return content.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// TailStream delivers the logs of Tail.
+type TailStream struct {
+	logs    chan *TailLog
+	dropped int64
+
+	lock sync.Mutex
+	err  error
+}
+
+// Logs returns the channel of the logs, which is closed once the context of
+// Tail is cancelled or reading the topic failed.
+func (s *TailStream) Logs() <-chan *TailLog {
+	return s.logs
+}
+
+// Err returns the error which stopped the stream, nil if it was cancelled.
+// It is set once Logs is closed.
+func (s *TailStream) Err() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.err
+}
+
+// Dropped returns how many logs were dropped by TailOptions.MaxLogsPerSecond.
+func (s *TailStream) Dropped() int64 {
+	return atomic.LoadInt64(&s.dropped)
+}
+
+// Tail follows the logs written to a topic, like tail -f. The shards are read
+// in parallel and their logs delivered in one stream ordered by time, see
+// TailOptions.OrderWindow. The stream ends when ctx is cancelled.
+func Tail(ctx context.Context, client Client, topicID string, opts *TailOptions) (*TailStream, error) {
+	t := newTailer(client, topicID, opts)
+	shards, err := DescribeAllShards(ctx, client, &DescribeShardsRequest{TopicID: topicID}, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(shards) == 0 {
+		return nil, errors.New("topic " + topicID + " has no shards")
+	}
+
+	cursors := make(map[int]string, len(shards))
+	for _, shard := range shards {
+		resp, err := client.DescribeCursorCtx(ctx, &DescribeCursorRequest{TopicID: topicID, ShardID: int(shard.ShardID), From: t.opts.From})
+		if err != nil {
+			return nil, err
+		}
+		cursors[int(shard.ShardID)] = resp.Cursor
+	}
+
+	ctx, t.cancel = context.WithCancel(ctx)
+	for _, shard := range shards {
+		t.addShard(ctx, shard, cursors[int(shard.ShardID)])
+	}
+	t.wg.Add(1)
+	go t.refreshShards(ctx)
+	go t.merge(ctx)
+
+	return t.stream, nil
+}
+
+type tailer struct {
+	client  Client
+	topicID string
+	opts    TailOptions
+	stream  *TailStream
+	cancel  context.CancelFunc
+	batches chan []*TailLog
+	// wg tracks the goroutines sending to batches.
+	wg sync.WaitGroup
+
+	lock     sync.Mutex
+	readOnly map[int]bool
+}
+
+func newTailer(client Client, topicID string, opts *TailOptions) *tailer {
+	t := &tailer{
+		client:   client,
+		topicID:  topicID,
+		stream:   &TailStream{logs: make(chan *TailLog)},
+		batches:  make(chan []*TailLog, tailBatchQueueSize),
+		readOnly: make(map[int]bool),
+	}
+	if opts != nil {
+		t.opts = *opts
+	}
+	if t.opts.From == "" {
+		t.opts.From = TailFromEnd
+	}
+	if t.opts.OrderWindow <= 0 {
+		t.opts.OrderWindow = defaultTailOrderWindow
+	}
+	if t.opts.PollInterval <= 0 {
+		t.opts.PollInterval = defaultTailPollInterval
+	}
+	if t.opts.ShardRefreshInterval <= 0 {
+		t.opts.ShardRefreshInterval = defaultTailShardRefreshInterval
+	}
+	if t.opts.LogGroupCount <= 0 {
+		t.opts.LogGroupCount = defaultTailLogGroupCount
+	}
+
+	return t
+}
+
+// fail stops the stream with err.
+func (t *tailer) fail(err error) {
+	t.stream.lock.Lock()
+	if t.stream.err == nil {
+		t.stream.err = err
+	}
+	t.stream.lock.Unlock()
+
+	t.cancel()
+}
+
+// addShard starts reading a shard from cursor. It is called by Tail and
+// refreshShards, so wg never drops to zero while shards are added.
+func (t *tailer) addShard(ctx context.Context, shard *ShardInfo, cursor string) {
+	t.lock.Lock()
+	t.readOnly[int(shard.ShardID)] = shard.Status == shardStatusReadOnly
+	t.lock.Unlock()
+
+	t.wg.Add(1)
+	go t.poll(ctx, int(shard.ShardID), cursor)
+}
+
+// poll reads a shard until ctx is done or, once it is read-only, its last
+// log was read.
+func (t *tailer) poll(ctx context.Context, shardID int, cursor string) {
+	defer t.wg.Done()
+
+	compression := CompressLz4
+	for {
+		resp, err := t.client.ConsumeLogsCtx(ctx, &ConsumeLogsRequest{
+			TopicID:       t.topicID,
+			ShardID:       shardID,
+			Cursor:        cursor,
+			LogGroupCount: &t.opts.LogGroupCount,
+			Compression:   &compression,
+		})
+		if err != nil {
+			if ctx.Err() == nil {
+				t.fail(fmt.Errorf("consume shard %d: %w", shardID, err))
+			}
+			return
+		}
+		if resp.Cursor != "" {
+			cursor = resp.Cursor
+		}
+
+		if resp.Count > 0 {
+			if logs := t.filter(shardID, resp.Logs); len(logs) > 0 {
+				select {
+				case t.batches <- logs:
+				case <-ctx.Done():
+					return
+				}
+			}
+			continue
+		}
+
+		t.lock.Lock()
+		drained := t.readOnly[shardID]
+		t.lock.Unlock()
+		if drained {
+			return
+		}
+
+		timer := time.NewTimer(t.opts.PollInterval)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+func (t *tailer) filter(shardID int, list *pb.LogGroupList) []*TailLog {
+	if list == nil {
+		return nil
+	}
+
+	var logs []*TailLog
+	now := time.Now()
+	for _, group := range list.LogGroups {
+		for _, log := range group.Logs {
+			if !t.match(log) {
+				continue
+			}
+			logs = append(logs, &TailLog{
+				ShardID:  shardID,
+				Time:     LogTime(log),
+				Source:   group.Source,
+				FileName: group.FileName,
+				Tags:     group.LogTags,
+				Log:      log,
+				arrival:  now,
+			})
+		}
+	}
+
+	return logs
+}
+
+func (t *tailer) match(log *pb.Log) bool {
+	for _, f := range t.opts.Filters {
+		if !f.match(log) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// refreshShards starts reading the shards created after Tail started and
+// marks the shards which became read-only.
+func (t *tailer) refreshShards(ctx context.Context) {
+	defer t.wg.Done()
+
+	ticker := time.NewTicker(t.opts.ShardRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		shards, err := DescribeAllShards(ctx, t.client, &DescribeShardsRequest{TopicID: t.topicID}, 0)
+		if err != nil {
+			if ctx.Err() == nil {
+				t.fail(fmt.Errorf("describe shards: %w", err))
+			}
+			return
+		}
+		for _, shard := range shards {
+			t.lock.Lock()
+			_, known := t.readOnly[int(shard.ShardID)]
+			if known {
+				t.readOnly[int(shard.ShardID)] = shard.Status == shardStatusReadOnly
+			}
+			t.lock.Unlock()
+			if known {
+				continue
+			}
+
+			resp, err := t.client.DescribeCursorCtx(ctx, &DescribeCursorRequest{TopicID: t.topicID, ShardID: int(shard.ShardID), From: TailFromBegin})
+			if err != nil {
+				if ctx.Err() == nil {
+					t.fail(fmt.Errorf("describe cursor of shard %d: %w", shard.ShardID, err))
+				}
+				return
+			}
+			t.addShard(ctx, shard, resp.Cursor)
+		}
+	}
+}
+
+// merge delivers the logs read from the shards ordered by time, each held
+// back OrderWindow after it was read, until ctx is done.
+func (t *tailer) merge(ctx context.Context) {
+	defer close(t.stream.logs)
+
+	// The stream is closed once no goroutine of the tail is left.
+	defer t.wg.Wait()
+
+	limiter := newTailLimiter(t.opts.MaxLogsPerSecond)
+	tick := t.opts.OrderWindow / 4
+	if tick < time.Millisecond {
+		tick = time.Millisecond
+	}
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	pending := &tailHeap{}
+	for {
+		select {
+		case logs := <-t.batches:
+			for _, log := range logs {
+				heap.Push(pending, log)
+			}
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		for pending.Len() > 0 && time.Since((*pending)[0].arrival) >= t.opts.OrderWindow {
+			log := heap.Pop(pending).(*TailLog)
+			if !limiter.allow() {
+				atomic.AddInt64(&t.stream.dropped, 1)
+				continue
+			}
+			select {
+			case t.stream.logs <- log:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// tailHeap orders logs by time, then by arrival.
+type tailHeap []*TailLog
+
+func (h tailHeap) Len() int { return len(h) }
+
+func (h tailHeap) Less(i, j int) bool {
+	if !h[i].Time.Equal(h[j].Time) {
+		return h[i].Time.Before(h[j].Time)
+	}
+
+	return h[i].arrival.Before(h[j].arrival)
+}
+
+func (h tailHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *tailHeap) Push(x interface{}) { *h = append(*h, x.(*TailLog)) }
+
+func (h *tailHeap) Pop() interface{} {
+	old := *h
+	log := old[len(old)-1]
+	*h = old[:len(old)-1]
+
+	return log
+}
+
+// tailLimiter is a token bucket refilled with rate tokens per second, holding
+// at most one second of them.
+type tailLimiter struct {
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+func newTailLimiter(rate int) *tailLimiter {
+	return &tailLimiter{rate: float64(rate), tokens: float64(rate), last: time.Now()}
+}
+
+func (l *tailLimiter) allow() bool {
+	if l.rate <= 0 {
+		return true
+	}
+
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.rate {
+		l.tokens = l.rate
+	}
+	l.last = now
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+
+	return true
+}
@@ -0,0 +1,273 @@
+package tlstest
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/volcengine/volc-sdk-golang/service/tls"
+)
+
+// errConsumerGroupNotExist is the error code of APIs naming an unknown
+// consumer group.
+const errConsumerGroupNotExist = "ConsumerGroupNotExist"
+
+// consumerGroup hands the shards of its topics out to the consumers whose
+// heartbeat did not expire, evenly and in order of their names.
+type consumerGroup struct {
+	projectID      string
+	name           string
+	topicIDs       []string
+	heartbeatTTL   int
+	orderedConsume bool
+	heartbeats     map[string]time.Time
+	// checkpoints are keyed by checkpointKey.
+	checkpoints map[string]string
+}
+
+func groupKey(projectID, name string) string {
+	return projectID + "/" + name
+}
+
+func checkpointKey(topicID string, shardID int) string {
+	return topicID + "/" + strconv.Itoa(shardID)
+}
+
+// ExpireConsumer ends the heartbeat lease of a consumer as if its heartbeats
+// stopped for HeartbeatTTL. Its ConsumeLogs requests fail with
+// tls.ErrConsumerHeartbeatExpired until its next heartbeat.
+func (s *Server) ExpireConsumer(projectID, consumerGroupName, consumerName string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if group, ok := s.groups[groupKey(projectID, consumerGroupName)]; ok {
+		delete(group.heartbeats, consumerName)
+	}
+}
+
+// Checkpoint returns the checkpoint a consumer group committed for a shard.
+func (s *Server) Checkpoint(projectID, consumerGroupName, topicID string, shardID int) string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	group, ok := s.groups[groupKey(projectID, consumerGroupName)]
+	if !ok {
+		return ""
+	}
+
+	return group.checkpoints[checkpointKey(topicID, shardID)]
+}
+
+// consumingGroup returns the group named in the headers of a ConsumeLogs
+// request of topicID, which does not name its project.
+func (s *Server) consumingGroup(name, topicID string) *consumerGroup {
+	if name == "" {
+		return nil
+	}
+	for _, group := range s.groups {
+		if group.name != name {
+			continue
+		}
+		for _, id := range group.topicIDs {
+			if id == topicID {
+				return group
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) group(w http.ResponseWriter, projectID, name string) *consumerGroup {
+	group, ok := s.groups[groupKey(projectID, name)]
+	if !ok {
+		writeError(w, http.StatusNotFound, errConsumerGroupNotExist, "consumer group "+name+" does not exist")
+	}
+
+	return group
+}
+
+func (g *consumerGroup) alive(consumerName string, now time.Time) bool {
+	last, ok := g.heartbeats[consumerName]
+
+	return ok && now.Sub(last) <= time.Duration(g.heartbeatTTL)*time.Second
+}
+
+// assign returns the shards of a consumer among the alive ones.
+func (g *consumerGroup) assign(topics map[string]*topic, consumerName string, now time.Time) []*tls.ConsumeShard {
+	var consumers []string
+	for name := range g.heartbeats {
+		if g.alive(name, now) {
+			consumers = append(consumers, name)
+		} else {
+			delete(g.heartbeats, name)
+		}
+	}
+	sort.Strings(consumers)
+	index := sort.SearchStrings(consumers, consumerName)
+
+	var shards []*tls.ConsumeShard
+	var i int
+	for _, topicID := range g.topicIDs {
+		t, ok := topics[topicID]
+		if !ok {
+			continue
+		}
+		for _, sh := range t.shards {
+			if i%len(consumers) == index {
+				shards = append(shards, &tls.ConsumeShard{TopicID: topicID, ShardID: sh.id})
+			}
+			i++
+		}
+	}
+
+	return shards
+}
+
+func (s *Server) createConsumerGroup(w http.ResponseWriter, r *http.Request) {
+	var req tls.CreateConsumerGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeInvalidArgument(w, err.Error())
+		return
+	}
+	if _, ok := s.groups[groupKey(req.ProjectID, req.ConsumerGroupName)]; ok {
+		writeError(w, http.StatusConflict, tls.ErrConsumerGroupAlreadyExists, "consumer group "+req.ConsumerGroupName+" already exists")
+		return
+	}
+	for _, topicID := range req.TopicIDList {
+		if s.topic(w, topicID) == nil {
+			return
+		}
+	}
+	if req.HeartbeatTTL <= 0 {
+		writeInvalidArgument(w, "invalid HeartbeatTTL "+strconv.Itoa(req.HeartbeatTTL))
+		return
+	}
+
+	s.groups[groupKey(req.ProjectID, req.ConsumerGroupName)] = &consumerGroup{
+		projectID:      req.ProjectID,
+		name:           req.ConsumerGroupName,
+		topicIDs:       req.TopicIDList,
+		heartbeatTTL:   req.HeartbeatTTL,
+		orderedConsume: req.OrderedConsume,
+		heartbeats:     make(map[string]time.Time),
+		checkpoints:    make(map[string]string),
+	}
+
+	writeJSON(w, &tls.CreateConsumerGroupResponse{})
+}
+
+func (s *Server) describeConsumerGroups(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	resp := &tls.DescribeConsumerGroupsResponse{ConsumerGroups: []*tls.ConsumerGroupResp{}}
+	for _, group := range s.groups {
+		if projectID := query.Get("ProjectId"); projectID != "" && group.projectID != projectID {
+			continue
+		}
+		if name := query.Get("ConsumerGroupName"); name != "" && group.name != name {
+			continue
+		}
+		resp.ConsumerGroups = append(resp.ConsumerGroups, &tls.ConsumerGroupResp{
+			ProjectID:         group.projectID,
+			ConsumerGroupName: group.name,
+			HeartbeatTTL:      group.heartbeatTTL,
+			OrderedConsume:    group.orderedConsume,
+		})
+	}
+	sort.Slice(resp.ConsumerGroups, func(i, j int) bool {
+		return resp.ConsumerGroups[i].ConsumerGroupName < resp.ConsumerGroups[j].ConsumerGroupName
+	})
+
+	writeJSON(w, resp)
+}
+
+func (s *Server) consumerHeartbeat(w http.ResponseWriter, r *http.Request) {
+	var req tls.ConsumerHeartbeatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeInvalidArgument(w, err.Error())
+		return
+	}
+	group := s.group(w, req.ProjectID, req.ConsumerGroupName)
+	if group == nil {
+		return
+	}
+
+	now := time.Now()
+	group.heartbeats[req.ConsumerName] = now
+
+	writeJSON(w, &tls.ConsumerHeartbeatResponse{Shards: group.assign(s.topics, req.ConsumerName, now)})
+}
+
+func (s *Server) describeCheckPoint(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ConsumerGroupName string
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeInvalidArgument(w, err.Error())
+		return
+	}
+	query := r.URL.Query()
+	group := s.group(w, query.Get("ProjectId"), req.ConsumerGroupName)
+	if group == nil {
+		return
+	}
+	shardID, err := strconv.Atoi(query.Get("ShardId"))
+	if err != nil {
+		writeInvalidArgument(w, "invalid ShardId "+query.Get("ShardId"))
+		return
+	}
+
+	writeJSON(w, &tls.DescribeCheckPointResponse{
+		ShardID:    int32(shardID),
+		Checkpoint: group.checkpoints[checkpointKey(query.Get("TopicId"), shardID)],
+	})
+}
+
+func (s *Server) modifyCheckPoint(w http.ResponseWriter, r *http.Request) {
+	var req tls.ModifyCheckPointRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeInvalidArgument(w, err.Error())
+		return
+	}
+	group := s.group(w, req.ProjectID, req.ConsumerGroupName)
+	if group == nil {
+		return
+	}
+
+	group.checkpoints[checkpointKey(req.TopicID, req.ShardID)] = req.Checkpoint
+
+	writeJSON(w, struct{}{})
+}
+
+func (s *Server) resetCheckPoint(w http.ResponseWriter, r *http.Request) {
+	var req tls.ResetCheckPointRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeInvalidArgument(w, err.Error())
+		return
+	}
+	group := s.group(w, req.ProjectID, req.ConsumerGroupName)
+	if group == nil {
+		return
+	}
+
+	checkpoints := make(map[string]string)
+	for _, topicID := range group.topicIDs {
+		t, ok := s.topics[topicID]
+		if !ok {
+			continue
+		}
+		for _, sh := range t.shards {
+			cursor, err := sh.cursorFrom(req.Position)
+			if err != nil {
+				writeInvalidArgument(w, err.Error())
+				return
+			}
+			checkpoints[checkpointKey(topicID, sh.id)] = cursor
+		}
+	}
+	group.checkpoints = checkpoints
+
+	writeJSON(w, struct{}{})
+}
@@ -0,0 +1,189 @@
+package tlstest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/volcengine/volc-sdk-golang/service/tls"
+)
+
+// downloadPathPrefix serves the results of download tasks, DescribeDownloadUrl
+// points to it.
+const downloadPathPrefix = "/tlstest/download/"
+
+// Statuses of download tasks. A task is generating when it is described the
+// first time and generated afterwards.
+const (
+	DownloadTaskGenerating = "generating"
+	DownloadTaskSuccess    = "success"
+	DownloadTaskFailed     = "generate_fail"
+)
+
+type downloadTask struct {
+	info *tls.DownloadTaskResp
+	// result is the file to download, nil if generating it failed.
+	result    []byte
+	described bool
+}
+
+func (s *Server) createDownloadTask(w http.ResponseWriter, r *http.Request) {
+	var req tls.CreateDownloadTaskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeInvalidArgument(w, err.Error())
+		return
+	}
+	t := s.topic(w, req.TopicID)
+	if t == nil {
+		return
+	}
+
+	s.taskID++
+	task := &downloadTask{info: &tls.DownloadTaskResp{
+		TaskId:      fmt.Sprintf("task-%d", s.taskID),
+		TaskName:    req.TaskName,
+		TopicId:     req.TopicID,
+		Query:       req.Query,
+		StartTime:   time.Unix(0, toMillis(req.StartTime)*int64(time.Millisecond)).Format("2006-01-02 15:04:05"),
+		EndTime:     time.Unix(0, toMillis(req.EndTime)*int64(time.Millisecond)).Format("2006-01-02 15:04:05"),
+		Compression: req.Compression,
+		DataFormat:  req.DataFormat,
+		TaskStatus:  DownloadTaskGenerating,
+		CreateTime:  time.Now().Format("2006-01-02 15:04:05"),
+	}}
+	// Queries the server can not search fail while the task is generated.
+	if terms, err := parseQuery(req.Query); err == nil {
+		hits := t.search(terms, req.StartTime, req.EndTime, req.Sort)
+		if req.Limit > 0 && len(hits) > req.Limit {
+			hits = hits[:req.Limit]
+		}
+		task.result, task.info.LogSize = downloadResult(hits, req.DataFormat, req.Compression)
+		task.info.LogCount = int64(len(hits))
+	}
+	s.tasks = append(s.tasks, task)
+
+	writeJSON(w, &tls.CreateDownloadTaskResponse{TaskId: task.info.TaskId})
+}
+
+// downloadResult returns the file of hits in format, compressed as asked,
+// and its uncompressed size.
+func downloadResult(hits []searchHit, format, compression string) ([]byte, int64) {
+	var raw bytes.Buffer
+	if strings.EqualFold(format, "csv") {
+		var columns []string
+		seen := make(map[string]bool)
+		for _, hit := range hits {
+			for key := range hit.fields() {
+				if !seen[key] {
+					seen[key] = true
+					columns = append(columns, key)
+				}
+			}
+		}
+		sort.Strings(columns)
+		writer := csv.NewWriter(&raw)
+		writer.Write(columns)
+		for _, hit := range hits {
+			fields := hit.fields()
+			row := make([]string, len(columns))
+			for i, column := range columns {
+				if value, ok := fields[column]; ok {
+					row[i] = fmt.Sprint(value)
+				}
+			}
+			writer.Write(row)
+		}
+		writer.Flush()
+	} else {
+		encoder := json.NewEncoder(&raw)
+		for _, hit := range hits {
+			encoder.Encode(hit.fields())
+		}
+	}
+
+	if !strings.EqualFold(compression, "gzip") {
+		return raw.Bytes(), int64(raw.Len())
+	}
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	writer.Write(raw.Bytes())
+	writer.Close()
+
+	return compressed.Bytes(), int64(raw.Len())
+}
+
+func (s *Server) describeDownloadTasks(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if t := s.topic(w, query.Get("TopicId")); t == nil {
+		return
+	}
+
+	var matched []*downloadTask
+	for _, task := range s.tasks {
+		if task.info.TopicId != query.Get("TopicId") || (query.Get("TaskName") != "" && task.info.TaskName != query.Get("TaskName")) {
+			continue
+		}
+		matched = append(matched, task)
+	}
+
+	pageNumber, pageSize := page(r, 20)
+	resp := &tls.DescribeDownloadTasksResponse{Total: int64(len(matched)), Tasks: []*tls.DownloadTaskResp{}}
+	for i := (pageNumber - 1) * pageSize; i < len(matched) && i < pageNumber*pageSize; i++ {
+		task := matched[i]
+		if task.described {
+			task.info.TaskStatus = DownloadTaskSuccess
+			if task.result == nil {
+				task.info.TaskStatus = DownloadTaskFailed
+			}
+		}
+		task.described = true
+		info := *task.info
+		resp.Tasks = append(resp.Tasks, &info)
+	}
+
+	writeJSON(w, resp)
+}
+
+func (s *Server) describeDownloadUrl(w http.ResponseWriter, r *http.Request) {
+	task := s.downloadTask(w, r.URL.Query().Get("TaskId"))
+	if task == nil {
+		return
+	}
+	if task.info.TaskStatus != DownloadTaskSuccess {
+		writeInvalidArgument(w, "task "+task.info.TaskId+" is "+task.info.TaskStatus)
+		return
+	}
+
+	writeJSON(w, &tls.DescribeDownloadUrlResponse{DownloadUrl: s.URL + downloadPathPrefix + task.info.TaskId})
+}
+
+func (s *Server) download(w http.ResponseWriter, r *http.Request) {
+	task := s.downloadTask(w, strings.TrimPrefix(r.URL.Path, downloadPathPrefix))
+	if task == nil {
+		return
+	}
+	if task.info.TaskStatus != DownloadTaskSuccess {
+		writeError(w, http.StatusNotFound, tls.ErrInvalidParam, "task "+task.info.TaskId+" is "+task.info.TaskStatus)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Write(task.result)
+}
+
+func (s *Server) downloadTask(w http.ResponseWriter, taskID string) *downloadTask {
+	for _, task := range s.tasks {
+		if task.info.TaskId == taskID {
+			return task
+		}
+	}
+	writeInvalidArgument(w, "task "+taskID+" does not exist")
+
+	return nil
+}
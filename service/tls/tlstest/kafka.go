@@ -0,0 +1,43 @@
+package tlstest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/volcengine/volc-sdk-golang/service/tls"
+)
+
+// KafkaTopic is the Kafka topic DescribeKafkaConsumer returns for a topic.
+func KafkaTopic(topicID string) string {
+	return "out-" + topicID
+}
+
+func (s *Server) setKafkaConsumer(w http.ResponseWriter, r *http.Request, enabled bool) {
+	var req struct {
+		TopicID string `json:"TopicId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeInvalidArgument(w, err.Error())
+		return
+	}
+	t := s.topic(w, req.TopicID)
+	if t == nil {
+		return
+	}
+	t.kafka = enabled
+
+	writeJSON(w, &tls.CommonResponse{})
+}
+
+func (s *Server) describeKafkaConsumer(w http.ResponseWriter, r *http.Request) {
+	t := s.topic(w, r.URL.Query().Get("TopicId"))
+	if t == nil {
+		return
+	}
+
+	resp := &tls.DescribeKafkaConsumerResponse{AllowConsume: t.kafka}
+	if t.kafka {
+		resp.ConsumeTopic = KafkaTopic(t.id)
+	}
+	writeJSON(w, resp)
+}
@@ -0,0 +1,814 @@
+package tlstest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/volcengine/volc-sdk-golang/service/tls"
+)
+
+// decode reads the JSON body of r into v, it answers the request and returns
+// false if the body is invalid.
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeInvalidArgument(w, err.Error())
+		return false
+	}
+
+	return true
+}
+
+// nextID returns a new resource id starting with prefix.
+func (s *Server) nextID(prefix string) string {
+	s.resourceID++
+
+	return fmt.Sprintf("%s-%d", prefix, s.resourceID)
+}
+
+// pageRange returns the indexes of the page asked by r in a list of n items.
+func pageRange(r *http.Request, n int) (int, int) {
+	pageNumber, pageSize := page(r, 20)
+	from, to := (pageNumber-1)*pageSize, pageNumber*pageSize
+	if from > n {
+		from = n
+	}
+	if to > n {
+		to = n
+	}
+
+	return from, to
+}
+
+// matchName tells whether name matches the filter of a Describe API, exactly
+// if fullName is "true" and as a substring otherwise.
+func matchName(name, filter, fullName string) bool {
+	if filter == "" {
+		return true
+	}
+	if fullName == "true" {
+		return name == filter
+	}
+
+	return strings.Contains(name, filter)
+}
+
+func now() string {
+	return time.Now().Format("2006-01-02 15:04:05")
+}
+
+func (s *Server) project(w http.ResponseWriter, projectID string) *tls.ProjectInfo {
+	for _, project := range s.projects {
+		if project.ProjectID == projectID {
+			return project
+		}
+	}
+	writeError(w, http.StatusNotFound, tls.ErrProjectNotExists, "project "+projectID+" does not exist")
+
+	return nil
+}
+
+// projectTopics returns the topics of a project ordered by id.
+func (s *Server) projectTopics(projectID string) []*topic {
+	var topics []*topic
+	for _, t := range s.topics {
+		if t.info.ProjectID == projectID {
+			topics = append(topics, t)
+		}
+	}
+	sort.Slice(topics, func(i, j int) bool { return topics[i].id < topics[j].id })
+
+	return topics
+}
+
+func (s *Server) createProject(w http.ResponseWriter, r *http.Request) {
+	var req tls.CreateProjectRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if err := req.CheckValidation(); err != nil {
+		writeInvalidArgument(w, err.Error())
+		return
+	}
+	for _, project := range s.projects {
+		if project.ProjectName == req.ProjectName {
+			writeError(w, http.StatusConflict, tls.ErrProjectAlreadyExists, "project "+req.ProjectName+" already exists")
+			return
+		}
+	}
+
+	project := &tls.ProjectInfo{
+		ProjectID:       s.nextID("project"),
+		ProjectName:     req.ProjectName,
+		Description:     req.Description,
+		CreateTimestamp: now(),
+		IamProjectName:  "default",
+		Tags:            req.Tags,
+	}
+	if req.IamProjectName != nil {
+		project.IamProjectName = *req.IamProjectName
+	}
+	s.projects = append(s.projects, project)
+
+	writeJSON(w, &tls.CreateProjectResponse{ProjectID: project.ProjectID})
+}
+
+func (s *Server) modifyProject(w http.ResponseWriter, r *http.Request) {
+	var req tls.ModifyProjectRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	project := s.project(w, req.ProjectID)
+	if project == nil {
+		return
+	}
+	if req.ProjectName != nil {
+		project.ProjectName = *req.ProjectName
+	}
+	if req.Description != nil {
+		project.Description = *req.Description
+	}
+
+	writeJSON(w, &tls.CommonResponse{})
+}
+
+func (s *Server) deleteProject(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ProjectID string `json:"ProjectId"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	if s.project(w, req.ProjectID) == nil {
+		return
+	}
+	if len(s.projectTopics(req.ProjectID)) > 0 {
+		writeInvalidArgument(w, "project "+req.ProjectID+" still has topics")
+		return
+	}
+	for i, project := range s.projects {
+		if project.ProjectID == req.ProjectID {
+			s.projects = append(s.projects[:i:i], s.projects[i+1:]...)
+			break
+		}
+	}
+
+	writeJSON(w, &tls.CommonResponse{})
+}
+
+func (s *Server) describeProjects(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var matched []tls.ProjectInfo
+	for _, project := range s.projects {
+		if !matchName(project.ProjectName, query.Get("ProjectName"), query.Get("IsFullName")) ||
+			query.Get("ProjectId") != "" && project.ProjectID != query.Get("ProjectId") {
+			continue
+		}
+		info := *project
+		info.TopicCount = int64(len(s.projectTopics(project.ProjectID)))
+		matched = append(matched, info)
+	}
+
+	from, to := pageRange(r, len(matched))
+	writeJSON(w, &tls.DescribeProjectsResponse{Projects: matched[from:to], Total: int64(len(matched))})
+}
+
+func (s *Server) createTopic(w http.ResponseWriter, r *http.Request) {
+	var req tls.CreateTopicRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if err := req.CheckValidation(); err != nil {
+		writeInvalidArgument(w, err.Error())
+		return
+	}
+	if s.project(w, req.ProjectID) == nil {
+		return
+	}
+	for _, t := range s.projectTopics(req.ProjectID) {
+		if t.info.TopicName == req.TopicName {
+			writeError(w, http.StatusConflict, tls.ErrTopicAlreadyExist, "topic "+req.TopicName+" already exists")
+			return
+		}
+	}
+
+	info := &tls.Topic{
+		TopicName:       req.TopicName,
+		ProjectID:       req.ProjectID,
+		TopicID:         s.nextID("topic"),
+		Ttl:             req.Ttl,
+		CreateTimestamp: now(),
+		Description:     req.Description,
+		AutoSplit:       req.AutoSplit,
+		Tags:            req.Tags,
+	}
+	info.ModifyTimestamp = info.CreateTimestamp
+	modifyTopicInfo(info, &tls.ModifyTopicRequest{
+		MaxSplitShard:  req.MaxSplitShard,
+		EnableTracking: req.EnableTracking,
+		TimeKey:        req.TimeKey,
+		TimeFormat:     req.TimeFormat,
+		LogPublicIP:    req.LogPublicIP,
+		EnableHotTtl:   req.EnableHotTtl,
+		HotTtl:         req.HotTtl,
+		ColdTtl:        req.ColdTtl,
+		ArchiveTtl:     req.ArchiveTtl,
+	})
+	s.topics[info.TopicID] = newTopic(info.TopicID, req.ShardCount, info)
+
+	writeJSON(w, &tls.CreateTopicResponse{TopicID: info.TopicID})
+}
+
+// modifyTopicInfo sets the fields of req which are not nil.
+func modifyTopicInfo(info *tls.Topic, req *tls.ModifyTopicRequest) {
+	if req.TopicName != nil {
+		info.TopicName = *req.TopicName
+	}
+	if req.Ttl != nil {
+		info.Ttl = *req.Ttl
+	}
+	if req.Description != nil {
+		info.Description = *req.Description
+	}
+	if req.MaxSplitShard != nil {
+		info.MaxSplitShard = *req.MaxSplitShard
+	}
+	if req.AutoSplit != nil {
+		info.AutoSplit = *req.AutoSplit
+	}
+	if req.EnableTracking != nil {
+		info.EnableTracking = *req.EnableTracking
+	}
+	if req.TimeKey != nil {
+		info.TimeKey = *req.TimeKey
+	}
+	if req.TimeFormat != nil {
+		info.TimeFormat = *req.TimeFormat
+	}
+	if req.LogPublicIP != nil {
+		info.LogPublicIP = *req.LogPublicIP
+	}
+	if req.EnableHotTtl != nil {
+		info.EnableHotTtl = *req.EnableHotTtl
+	}
+	if req.HotTtl != nil {
+		info.HotTtl = *req.HotTtl
+	}
+	if req.ColdTtl != nil {
+		info.ColdTtl = *req.ColdTtl
+	}
+	if req.ArchiveTtl != nil {
+		info.ArchiveTtl = *req.ArchiveTtl
+	}
+}
+
+func (s *Server) modifyTopic(w http.ResponseWriter, r *http.Request) {
+	var req tls.ModifyTopicRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	t := s.topic(w, req.TopicID)
+	if t == nil {
+		return
+	}
+	modifyTopicInfo(t.info, &req)
+	t.info.ModifyTimestamp = now()
+
+	writeJSON(w, &tls.CommonResponse{})
+}
+
+func (s *Server) deleteTopic(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TopicID string `json:"TopicId"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	if s.topic(w, req.TopicID) == nil {
+		return
+	}
+	delete(s.topics, req.TopicID)
+	rules := s.rules[:0]
+	for _, rule := range s.rules {
+		if rule.TopicID != req.TopicID {
+			rules = append(rules, rule)
+		}
+	}
+	s.rules = rules
+
+	writeJSON(w, &tls.CommonResponse{})
+}
+
+func (s *Server) describeTopics(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var ids []string
+	for id := range s.topics {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var matched []*tls.Topic
+	for _, id := range ids {
+		info := s.topics[id].info
+		if query.Get("ProjectId") != "" && info.ProjectID != query.Get("ProjectId") ||
+			query.Get("TopicId") != "" && info.TopicID != query.Get("TopicId") ||
+			!matchName(info.TopicName, query.Get("TopicName"), query.Get("IsFullName")) {
+			continue
+		}
+		topic := *info
+		matched = append(matched, &topic)
+	}
+
+	from, to := pageRange(r, len(matched))
+	writeJSON(w, &tls.DescribeTopicsResponse{Topics: matched[from:to], Total: len(matched)})
+}
+
+// indexedTopic returns the topic of topicID if it has an index.
+func (s *Server) indexedTopic(w http.ResponseWriter, topicID string) *topic {
+	t := s.topic(w, topicID)
+	if t == nil {
+		return nil
+	}
+	if t.index == nil {
+		writeError(w, http.StatusNotFound, tls.ErrIndexNotExists, "topic "+topicID+" has no index")
+		return nil
+	}
+
+	return t
+}
+
+func (s *Server) createIndex(w http.ResponseWriter, r *http.Request) {
+	var req tls.CreateIndexRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	t := s.topic(w, req.TopicID)
+	if t == nil {
+		return
+	}
+	if t.index != nil {
+		writeError(w, http.StatusConflict, tls.ErrIndexAlreadyExists, "topic "+req.TopicID+" already has an index")
+		return
+	}
+	t.index = &tls.DescribeIndexResponse{
+		TopicID:           req.TopicID,
+		FullText:          req.FullText,
+		KeyValue:          req.KeyValue,
+		UserInnerKeyValue: req.UserInnerKeyValue,
+		CreateTime:        now(),
+	}
+	t.index.ModifyTime = t.index.CreateTime
+
+	writeJSON(w, &tls.CreateIndexResponse{TopicID: req.TopicID})
+}
+
+func (s *Server) modifyIndex(w http.ResponseWriter, r *http.Request) {
+	var req tls.ModifyIndexRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	t := s.indexedTopic(w, req.TopicID)
+	if t == nil {
+		return
+	}
+	t.index.FullText = req.FullText
+	t.index.KeyValue = req.KeyValue
+	t.index.UserInnerKeyValue = req.UserInnerKeyValue
+	t.index.ModifyTime = now()
+
+	writeJSON(w, &tls.CommonResponse{})
+}
+
+func (s *Server) deleteIndex(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TopicID string `json:"TopicId"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	t := s.indexedTopic(w, req.TopicID)
+	if t == nil {
+		return
+	}
+	t.index = nil
+
+	writeJSON(w, &tls.CommonResponse{})
+}
+
+func (s *Server) describeIndex(w http.ResponseWriter, r *http.Request) {
+	t := s.indexedTopic(w, r.URL.Query().Get("TopicId"))
+	if t == nil {
+		return
+	}
+
+	// The SDK expects both lists of keys, even empty.
+	index := *t.index
+	if index.KeyValue == nil {
+		index.KeyValue = &[]tls.KeyValueInfo{}
+	}
+	if index.UserInnerKeyValue == nil {
+		index.UserInnerKeyValue = &[]tls.KeyValueInfo{}
+	}
+
+	writeJSON(w, &index)
+}
+
+func (s *Server) rule(w http.ResponseWriter, ruleID string) (int, *tls.RuleInfo) {
+	for i, rule := range s.rules {
+		if rule.RuleID == ruleID {
+			return i, rule
+		}
+	}
+	writeInvalidArgument(w, "rule "+ruleID+" does not exist")
+
+	return -1, nil
+}
+
+func (s *Server) createRule(w http.ResponseWriter, r *http.Request) {
+	var req tls.CreateRuleRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	t := s.topic(w, req.TopicID)
+	if t == nil {
+		return
+	}
+	for _, rule := range s.rules {
+		if rule.TopicID == req.TopicID && rule.RuleName == req.RuleName {
+			writeInvalidArgument(w, "rule "+req.RuleName+" already exists")
+			return
+		}
+	}
+
+	rule := &tls.RuleInfo{
+		TopicID:    req.TopicID,
+		TopicName:  t.info.TopicName,
+		RuleID:     s.nextID("rule"),
+		RuleName:   req.RuleName,
+		CreateTime: now(),
+	}
+	rule.ModifyTime = rule.CreateTime
+	modifyRuleInfo(rule, &tls.ModifyRuleRequest{
+		Paths:          req.Paths,
+		LogType:        req.LogType,
+		ExtractRule:    req.ExtractRule,
+		ExcludePaths:   req.ExcludePaths,
+		UserDefineRule: req.UserDefineRule,
+		LogSample:      req.LogSample,
+		InputType:      req.InputType,
+		ContainerRule:  req.ContainerRule,
+	})
+	s.rules = append(s.rules, rule)
+
+	writeJSON(w, &tls.CreateRuleResponse{RuleID: rule.RuleID})
+}
+
+// modifyRuleInfo sets the fields of req which are not nil.
+func modifyRuleInfo(rule *tls.RuleInfo, req *tls.ModifyRuleRequest) {
+	if req.RuleName != nil {
+		rule.RuleName = *req.RuleName
+	}
+	if req.Paths != nil {
+		rule.Paths = *req.Paths
+	}
+	if req.LogType != nil {
+		rule.LogType = *req.LogType
+	}
+	if req.ExtractRule != nil {
+		rule.ExtractRule = *req.ExtractRule
+	}
+	if req.ExcludePaths != nil {
+		rule.ExcludePaths = *req.ExcludePaths
+	}
+	if req.UserDefineRule != nil {
+		rule.UserDefineRule = *req.UserDefineRule
+	}
+	if req.LogSample != nil {
+		rule.LogSample = *req.LogSample
+	}
+	if req.InputType != nil {
+		rule.InputType = *req.InputType
+	}
+	if req.ContainerRule != nil {
+		rule.ContainerRule = *req.ContainerRule
+	}
+}
+
+func (s *Server) modifyRule(w http.ResponseWriter, r *http.Request) {
+	var req tls.ModifyRuleRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	_, rule := s.rule(w, req.RuleID)
+	if rule == nil {
+		return
+	}
+	modifyRuleInfo(rule, &req)
+	rule.ModifyTime = now()
+
+	writeJSON(w, &tls.CommonResponse{})
+}
+
+func (s *Server) deleteRule(w http.ResponseWriter, r *http.Request) {
+	var req tls.DeleteRuleRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	i, rule := s.rule(w, req.RuleID)
+	if rule == nil {
+		return
+	}
+	s.rules = append(s.rules[:i:i], s.rules[i+1:]...)
+	delete(s.ruleHostGroups, rule.RuleID)
+
+	writeJSON(w, &tls.CommonResponse{})
+}
+
+func (s *Server) describeRules(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if s.project(w, query.Get("ProjectId")) == nil {
+		return
+	}
+
+	matched := []*tls.RuleInfo{}
+	for _, rule := range s.rules {
+		if t := s.topics[rule.TopicID]; t.info.ProjectID != query.Get("ProjectId") ||
+			query.Get("TopicId") != "" && rule.TopicID != query.Get("TopicId") ||
+			query.Get("RuleId") != "" && rule.RuleID != query.Get("RuleId") ||
+			!matchName(rule.RuleName, query.Get("RuleName"), "") {
+			continue
+		}
+		info := *rule
+		matched = append(matched, &info)
+	}
+
+	from, to := pageRange(r, len(matched))
+	writeJSON(w, &tls.DescribeRulesResponse{RuleInfos: matched[from:to], Total: int64(len(matched))})
+}
+
+func (s *Server) describeRule(w http.ResponseWriter, r *http.Request) {
+	_, rule := s.rule(w, r.URL.Query().Get("RuleId"))
+	if rule == nil {
+		return
+	}
+
+	t := s.topics[rule.TopicID]
+	project := s.project(w, t.info.ProjectID)
+	if project == nil {
+		return
+	}
+	info := *rule
+	resp := &tls.DescribeRuleResponse{
+		ProjectID:      project.ProjectID,
+		ProjectName:    project.ProjectName,
+		TopicID:        rule.TopicID,
+		TopicName:      t.info.TopicName,
+		RuleInfo:       &info,
+		HostGroupInfos: []*tls.HostGroupInfo{},
+	}
+	for _, id := range s.ruleHostGroups[rule.RuleID] {
+		resp.HostGroupInfos = append(resp.HostGroupInfos, &tls.HostGroupInfo{HostGroupID: id})
+	}
+
+	writeJSON(w, resp)
+}
+
+func (s *Server) applyRuleToHostGroups(w http.ResponseWriter, r *http.Request) {
+	var req tls.ApplyRuleToHostGroupsRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if _, rule := s.rule(w, req.RuleID); rule == nil {
+		return
+	}
+
+	groups := s.ruleHostGroups[req.RuleID]
+	for _, id := range req.HostGroupIDs {
+		bound := false
+		for _, group := range groups {
+			bound = bound || group == id
+		}
+		if !bound {
+			groups = append(groups, id)
+		}
+	}
+	sort.Strings(groups)
+	s.ruleHostGroups[req.RuleID] = groups
+
+	writeJSON(w, &tls.CommonResponse{})
+}
+
+func (s *Server) deleteRuleFromHostGroups(w http.ResponseWriter, r *http.Request) {
+	var req tls.DeleteRuleFromHostGroupsRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if _, rule := s.rule(w, req.RuleID); rule == nil {
+		return
+	}
+
+	removed := make(map[string]bool, len(req.HostGroupIDs))
+	for _, id := range req.HostGroupIDs {
+		removed[id] = true
+	}
+	var groups []string
+	for _, id := range s.ruleHostGroups[req.RuleID] {
+		if !removed[id] {
+			groups = append(groups, id)
+		}
+	}
+	s.ruleHostGroups[req.RuleID] = groups
+
+	writeJSON(w, &tls.CommonResponse{})
+}
+
+func (s *Server) alarm(w http.ResponseWriter, alarmID string) (int, *tls.QueryResp) {
+	for i, alarm := range s.alarms {
+		if alarm.AlarmID == alarmID {
+			return i, alarm
+		}
+	}
+	writeInvalidArgument(w, "alarm "+alarmID+" does not exist")
+
+	return -1, nil
+}
+
+// alarmQueries checks that the topics queried by an alarm exist and fills in
+// their names.
+func (s *Server) alarmQueries(w http.ResponseWriter, queries []tls.QueryRequest) bool {
+	for i := range queries {
+		t := s.topic(w, queries[i].TopicID)
+		if t == nil {
+			return false
+		}
+		queries[i].TopicName = t.info.TopicName
+	}
+
+	return true
+}
+
+func notifyGroups(ids []string) []tls.NotifyGroupsInfo {
+	groups := make([]tls.NotifyGroupsInfo, 0, len(ids))
+	for _, id := range ids {
+		groups = append(groups, tls.NotifyGroupsInfo{NotifyGroupID: id})
+	}
+
+	return groups
+}
+
+func (s *Server) createAlarm(w http.ResponseWriter, r *http.Request) {
+	var req tls.CreateAlarmRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if err := req.CheckValidation(); err != nil {
+		writeInvalidArgument(w, err.Error())
+		return
+	}
+	if s.project(w, req.ProjectID) == nil || !s.alarmQueries(w, req.QueryRequest) {
+		return
+	}
+	for _, alarm := range s.alarms {
+		if alarm.ProjectID == req.ProjectID && alarm.AlarmName == req.AlarmName {
+			writeInvalidArgument(w, "alarm "+req.AlarmName+" already exists")
+			return
+		}
+	}
+
+	alarm := &tls.QueryResp{
+		AlarmID:            s.nextID("alarm"),
+		AlarmName:          req.AlarmName,
+		ProjectID:          req.ProjectID,
+		Status:             true,
+		QueryRequest:       req.QueryRequest,
+		RequestCycle:       req.RequestCycle,
+		Condition:          req.Condition,
+		TriggerPeriod:      req.TriggerPeriod,
+		AlarmPeriod:        req.AlarmPeriod,
+		AlarmNotifyGroup:   notifyGroups(req.AlarmNotifyGroup),
+		CreateTimestamp:    now(),
+		JoinConfigurations: req.JoinConfigurations,
+		TriggerConditions:  req.TriggerConditions,
+	}
+	alarm.ModifyTimestamp = alarm.CreateTimestamp
+	if req.Status != nil {
+		alarm.Status = *req.Status
+	}
+	if req.UserDefineMsg != nil {
+		alarm.UserDefineMsg = *req.UserDefineMsg
+	}
+	if req.Severity != nil {
+		alarm.Severity = *req.Severity
+	}
+	if req.AlarmPeriodDetail != nil {
+		alarm.AlarmPeriodDetail = *req.AlarmPeriodDetail
+	}
+	s.alarms = append(s.alarms, alarm)
+
+	writeJSON(w, &tls.CreateAlarmResponse{AlarmID: alarm.AlarmID})
+}
+
+func (s *Server) modifyAlarm(w http.ResponseWriter, r *http.Request) {
+	var req tls.ModifyAlarmRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	_, alarm := s.alarm(w, req.AlarmID)
+	if alarm == nil {
+		return
+	}
+	if req.QueryRequest != nil && !s.alarmQueries(w, *req.QueryRequest) {
+		return
+	}
+
+	if req.AlarmName != nil {
+		alarm.AlarmName = *req.AlarmName
+	}
+	if req.Status != nil {
+		alarm.Status = *req.Status
+	}
+	if req.QueryRequest != nil {
+		alarm.QueryRequest = *req.QueryRequest
+	}
+	if req.RequestCycle != nil {
+		alarm.RequestCycle = *req.RequestCycle
+	}
+	if req.Condition != nil {
+		alarm.Condition = *req.Condition
+	}
+	if req.TriggerPeriod != nil {
+		alarm.TriggerPeriod = *req.TriggerPeriod
+	}
+	if req.AlarmPeriod != nil {
+		alarm.AlarmPeriod = *req.AlarmPeriod
+	}
+	if req.AlarmNotifyGroup != nil {
+		alarm.AlarmNotifyGroup = notifyGroups(*req.AlarmNotifyGroup)
+	}
+	if req.UserDefineMsg != nil {
+		alarm.UserDefineMsg = *req.UserDefineMsg
+	}
+	if req.Severity != nil {
+		alarm.Severity = *req.Severity
+	}
+	if req.AlarmPeriodDetail != nil {
+		alarm.AlarmPeriodDetail = *req.AlarmPeriodDetail
+	}
+	if req.JoinConfigurations != nil {
+		alarm.JoinConfigurations = req.JoinConfigurations
+	}
+	if req.TriggerConditions != nil {
+		alarm.TriggerConditions = req.TriggerConditions
+	}
+	alarm.ModifyTimestamp = now()
+
+	writeJSON(w, &tls.CommonResponse{})
+}
+
+func (s *Server) deleteAlarm(w http.ResponseWriter, r *http.Request) {
+	var req tls.DeleteAlarmRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	i, alarm := s.alarm(w, req.AlarmID)
+	if alarm == nil {
+		return
+	}
+	s.alarms = append(s.alarms[:i:i], s.alarms[i+1:]...)
+
+	writeJSON(w, &tls.CommonResponse{})
+}
+
+func (s *Server) describeAlarms(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if s.project(w, query.Get("ProjectId")) == nil {
+		return
+	}
+
+	matched := []tls.QueryResp{}
+	for _, alarm := range s.alarms {
+		if alarm.ProjectID != query.Get("ProjectId") ||
+			query.Get("AlarmId") != "" && alarm.AlarmID != query.Get("AlarmId") ||
+			!matchName(alarm.AlarmName, query.Get("AlarmName"), "") ||
+			query.Get("TopicId") != "" && !queriesTopic(alarm, query.Get("TopicId")) {
+			continue
+		}
+		matched = append(matched, *alarm)
+	}
+
+	from, to := pageRange(r, len(matched))
+	writeJSON(w, &tls.DescribeAlarmsResponse{AlarmPolicies: matched[from:to], Total: len(matched)})
+}
+
+func queriesTopic(alarm *tls.QueryResp, topicID string) bool {
+	for _, query := range alarm.QueryRequest {
+		if query.TopicID == topicID {
+			return true
+		}
+	}
+
+	return false
+}
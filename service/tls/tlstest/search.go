@@ -0,0 +1,249 @@
+package tlstest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/volcengine/volc-sdk-golang/service/tls"
+	"github.com/volcengine/volc-sdk-golang/service/tls/pb"
+)
+
+const defaultSearchLimit = 100
+
+// term is one condition of a search query. An empty key matches the value in
+// any content.
+type term struct {
+	key, value string
+	not        bool
+}
+
+// parseQuery parses the subset of the search syntax the server supports:
+// "*", key:value and full text terms, optionally quoted, joined by AND, which
+// may be left out, each negated by NOT. A trailing * of a value matches any
+// suffix. Analysis statements after | and OR are rejected.
+func parseQuery(query string) ([]term, error) {
+	if strings.Contains(query, "|") {
+		return nil, errors.New("tlstest does not support analysis statements")
+	}
+
+	tokens, err := tokenize(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var terms []term
+	var not bool
+	for _, token := range tokens {
+		switch {
+		case token == "*" && !not:
+		case strings.EqualFold(token, "AND"):
+		case strings.EqualFold(token, "NOT"):
+			not = !not
+		case strings.EqualFold(token, "OR"):
+			return nil, errors.New("tlstest does not support OR")
+		default:
+			t := term{value: token, not: not}
+			if i := strings.Index(token, ":"); i > 0 && !strings.HasPrefix(token, `"`) {
+				t.key, t.value = token[:i], token[i+1:]
+			}
+			if t.value, err = unquote(t.value); err != nil {
+				return nil, err
+			}
+			terms = append(terms, t)
+			not = false
+		}
+	}
+	if not {
+		return nil, errors.New("NOT at the end of the query")
+	}
+
+	return terms, nil
+}
+
+// tokenize splits query at spaces outside of double quotes.
+func tokenize(query string) ([]string, error) {
+	var tokens []string
+	var token strings.Builder
+	var quoted, escaped bool
+	for _, c := range query {
+		switch {
+		case escaped:
+			escaped = false
+		case quoted && c == '\\':
+			escaped = true
+		case c == '"':
+			quoted = !quoted
+		case !quoted && (c == ' ' || c == '\t' || c == '\n'):
+			if token.Len() > 0 {
+				tokens = append(tokens, token.String())
+				token.Reset()
+			}
+			continue
+		}
+		token.WriteRune(c)
+	}
+	if quoted {
+		return nil, errors.New("unterminated quote")
+	}
+	if token.Len() > 0 {
+		tokens = append(tokens, token.String())
+	}
+
+	return tokens, nil
+}
+
+func unquote(value string) (string, error) {
+	if !strings.HasPrefix(value, `"`) {
+		return value, nil
+	}
+
+	return strconv.Unquote(value)
+}
+
+func (t term) match(log *pb.Log) bool {
+	matched := false
+	for _, content := range log.Contents {
+		if t.key != "" && content.Key != t.key {
+			continue
+		}
+		if t.matchValue(content.Value) {
+			matched = true
+			break
+		}
+	}
+
+	return matched != t.not
+}
+
+func (t term) matchValue(value string) bool {
+	if t.key == "" {
+		return strings.Contains(value, strings.TrimSuffix(t.value, "*"))
+	}
+	if strings.HasSuffix(t.value, "*") {
+		return strings.HasPrefix(value, strings.TrimSuffix(t.value, "*"))
+	}
+
+	return value == t.value
+}
+
+// toMillis takes a unix time in seconds or milliseconds.
+func toMillis(t int64) int64 {
+	if t > 1e11 || t < -1e11 {
+		return t
+	}
+
+	return t * 1000
+}
+
+type searchHit struct {
+	group *pb.LogGroup
+	log   *pb.Log
+	time  int64
+}
+
+func (s *Server) searchLogs(w http.ResponseWriter, r *http.Request) {
+	var req tls.SearchLogsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeInvalidArgument(w, err.Error())
+		return
+	}
+	t := s.topic(w, req.TopicID)
+	if t == nil {
+		return
+	}
+	terms, err := parseQuery(req.Query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, tls.ErrSearchSyntaxError, err.Error())
+		return
+	}
+	offset := 0
+	if req.Context != "" {
+		if offset, err = strconv.Atoi(req.Context); err != nil || offset < 0 {
+			writeInvalidArgument(w, fmt.Sprintf("invalid Context %q", req.Context))
+			return
+		}
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+
+	hits := t.search(terms, req.StartTime, req.EndTime, req.Sort)
+
+	resp := &tls.SearchLogsResponse{
+		Status:   "complete",
+		HitCount: len(hits),
+		Limit:    limit,
+		Logs:     []map[string]interface{}{},
+		ListOver: true,
+	}
+	for i := offset; i < len(hits) && i < offset+limit; i++ {
+		resp.Logs = append(resp.Logs, hits[i].fields())
+	}
+	resp.Count = len(resp.Logs)
+	if offset+limit < len(hits) {
+		resp.ListOver = false
+		resp.Context = strconv.Itoa(offset + limit)
+	}
+
+	writeJSON(w, resp)
+}
+
+// search returns the logs of the topic matching terms within [start, end),
+// end 0 is unbounded, sorted by time "asc" or descending.
+func (t *topic) search(terms []term, start, end int64, order string) []searchHit {
+	start, end = toMillis(start), toMillis(end)
+	var hits []searchHit
+	for _, sh := range t.shards {
+		for _, stored := range sh.groups {
+			for _, log := range stored.group.Logs {
+				logTime := toMillis(log.Time)
+				if logTime < start || (end != 0 && logTime >= end) || !matchAll(terms, log) {
+					continue
+				}
+				hits = append(hits, searchHit{group: stored.group, log: log, time: logTime})
+			}
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		if strings.EqualFold(order, "asc") {
+			return hits[i].time < hits[j].time
+		}
+		return hits[i].time > hits[j].time
+	})
+
+	return hits
+}
+
+func matchAll(terms []term, log *pb.Log) bool {
+	for _, t := range terms {
+		if !t.match(log) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// fields returns a log as the SearchLogs API does, with its contents, the
+// log group metadata and __time__ in milliseconds.
+func (hit searchHit) fields() map[string]interface{} {
+	fields := map[string]interface{}{
+		"__time__":   hit.time,
+		"__source__": hit.group.Source,
+		"__path__":   hit.group.FileName,
+	}
+	for _, tag := range hit.group.LogTags {
+		fields["__tag__"+tag.Key+"__"] = tag.Value
+	}
+	for _, content := range hit.log.Contents {
+		fields[content.Key] = content.Value
+	}
+
+	return fields
+}
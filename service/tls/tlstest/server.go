@@ -0,0 +1,261 @@
+// Package tlstest provides an in-process TLS server for tests of code built on
+// the SDK. It keeps the logs in memory and implements the APIs used to write,
+// consume and search them:
+//
+//   - PutLogs, DescribeShards, DescribeCursor and ConsumeLogs
+//   - CreateConsumerGroup, DescribeConsumerGroups, ConsumerHeartbeat,
+//     DescribeCheckPoint, ModifyCheckPoint and ResetCheckPoint
+//   - OpenKafkaConsumer, CloseKafkaConsumer and DescribeKafkaConsumer, which
+//     only track whether Kafka consumption is enabled
+//   - SearchLogs with "*", key:value and full text terms, optionally quoted,
+//     joined by AND and negated by NOT, without analysis statements
+//   - CreateDownloadTask, DescribeDownloadTasks and DescribeDownloadUrl, with
+//     results in JSON lines or CSV served by the server itself
+//   - Create, Modify, Delete and Describe of projects, topics, indexes, rules
+//     and alarms, which are stored as they are sent
+//   - DescribeRule, ApplyRuleToHostGroups and DeleteRuleFromHostGroups, which
+//     bind rules to host group ids without checking that the groups exist
+//
+// Requests are not authenticated. Faults can be injected with InjectFault and
+// ExpireConsumer, shards can be split with SplitShard.
+package tlstest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/volcengine/volc-sdk-golang/service/tls"
+)
+
+// Region, AccessKeyID and AccessKeySecret are used by Server.NewClient, the
+// server accepts any credentials.
+const (
+	Region          = "cn-beijing"
+	AccessKeyID     = "tlstest-ak"
+	AccessKeySecret = "tlstest-sk"
+)
+
+// Fault makes requests fail with an error response instead of being served.
+type Fault struct {
+	// Path is the API to fail, such as tls.PathPutLogs. Empty fails every API.
+	Path string
+	// HTTPCode is the status of the response, for example
+	// http.StatusTooManyRequests or http.StatusInternalServerError.
+	HTTPCode int
+	// ErrorCode is the errorCode of the response, tls.ErrInternalServerError
+	// if empty.
+	ErrorCode string
+	// Times is how many requests fail, 0 fails them until ClearFaults.
+	Times int
+}
+
+// Server is a TLS server for tests. Point tls.NewClient at its URL, or use
+// NewClient.
+type Server struct {
+	*httptest.Server
+
+	lock      sync.Mutex
+	topics    map[string]*topic
+	groups    map[string]*consumerGroup
+	faults    []*Fault
+	requests  map[string]int
+	requestID int64
+	tasks     []*downloadTask
+	taskID    int
+
+	projects       []*tls.ProjectInfo
+	rules          []*tls.RuleInfo
+	ruleHostGroups map[string][]string
+	alarms         []*tls.QueryResp
+	resourceID     int
+}
+
+// NewServer starts a server without topics. The caller should call Close when
+// finished.
+func NewServer() *Server {
+	s := &Server{
+		topics:         make(map[string]*topic),
+		groups:         make(map[string]*consumerGroup),
+		requests:       make(map[string]int),
+		ruleHostGroups: make(map[string][]string),
+	}
+	s.Server = httptest.NewServer(s)
+
+	return s
+}
+
+// NewClient returns a client of the server.
+func (s *Server) NewClient() tls.Client {
+	return tls.NewClient(s.URL, AccessKeyID, AccessKeySecret, "", Region)
+}
+
+// InjectFault fails the next requests of fault.Path. Faults are matched in the
+// order they were injected.
+func (s *Server) InjectFault(fault Fault) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if fault.ErrorCode == "" {
+		fault.ErrorCode = tls.ErrInternalServerError
+	}
+	s.faults = append(s.faults, &fault)
+}
+
+// ClearFaults removes the faults which did not run out yet.
+func (s *Server) ClearFaults() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.faults = nil
+}
+
+// Requests returns how many requests of path the server received, including
+// failed ones.
+func (s *Server) Requests(path string) int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.requests[path]
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.requestID++
+	w.Header().Set(tls.RequestIDHeader, fmt.Sprintf("tlstest-%d", s.requestID))
+	s.requests[r.URL.Path]++
+
+	if fault := s.fault(r.URL.Path); fault != nil {
+		writeError(w, fault.HTTPCode, fault.ErrorCode, "injected fault")
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, downloadPathPrefix) {
+		s.download(w, r)
+		return
+	}
+
+	switch r.URL.Path {
+	case tls.PathPutLogs:
+		s.putLogs(w, r)
+	case tls.PathDescribeShards:
+		s.describeShards(w, r)
+	case tls.PathDescribeCursor:
+		s.describeCursor(w, r)
+	case tls.PathConsumeLogs:
+		s.consumeLogs(w, r)
+	case tls.PathSearchLogs:
+		s.searchLogs(w, r)
+	case tls.PathCreateConsumerGroup:
+		s.createConsumerGroup(w, r)
+	case tls.PathDescribeConsumerGroups:
+		s.describeConsumerGroups(w, r)
+	case tls.PathConsumerHeartbeat:
+		s.consumerHeartbeat(w, r)
+	case tls.PathDescribeCheckPoint:
+		s.describeCheckPoint(w, r)
+	case tls.PathModifyCheckPoint:
+		s.modifyCheckPoint(w, r)
+	case tls.PathResetCheckPoint:
+		s.resetCheckPoint(w, r)
+	case tls.PathCreateDownloadTask:
+		s.createDownloadTask(w, r)
+	case tls.PathDescribeDownloadTasks:
+		s.describeDownloadTasks(w, r)
+	case tls.PathDescribeDownloadUrl:
+		s.describeDownloadUrl(w, r)
+	case tls.PathOpenKafkaConsumer:
+		s.setKafkaConsumer(w, r, true)
+	case tls.PathCloseKafkaConsumer:
+		s.setKafkaConsumer(w, r, false)
+	case tls.PathDescribeKafkaConsumer:
+		s.describeKafkaConsumer(w, r)
+	case tls.PathCreateProject:
+		s.createProject(w, r)
+	case tls.PathModifyProject:
+		s.modifyProject(w, r)
+	case tls.PathDeleteProject:
+		s.deleteProject(w, r)
+	case tls.PathDescribeProjects:
+		s.describeProjects(w, r)
+	case tls.PathCreateTopic:
+		s.createTopic(w, r)
+	case tls.PathModifyTopic:
+		s.modifyTopic(w, r)
+	case tls.PathDeleteTopic:
+		s.deleteTopic(w, r)
+	case tls.PathDescribeTopics:
+		s.describeTopics(w, r)
+	case tls.PathCreateIndex:
+		s.createIndex(w, r)
+	case tls.PathModifyIndex:
+		s.modifyIndex(w, r)
+	case tls.PathDeleteIndex:
+		s.deleteIndex(w, r)
+	case tls.PathDescribeIndex:
+		s.describeIndex(w, r)
+	case tls.PathCreateRule:
+		s.createRule(w, r)
+	case tls.PathModifyRule:
+		s.modifyRule(w, r)
+	case tls.PathDeleteRule:
+		s.deleteRule(w, r)
+	case tls.PathDescribeRules:
+		s.describeRules(w, r)
+	case tls.PathDescribeRule:
+		s.describeRule(w, r)
+	case tls.PathApplyRuleToHostGroups:
+		s.applyRuleToHostGroups(w, r)
+	case tls.PathDeleteRuleFromHostGroups:
+		s.deleteRuleFromHostGroups(w, r)
+	case tls.PathCreateAlarm:
+		s.createAlarm(w, r)
+	case tls.PathModifyAlarm:
+		s.modifyAlarm(w, r)
+	case tls.PathDeleteAlarm:
+		s.deleteAlarm(w, r)
+	case tls.PathDescribeAlarms:
+		s.describeAlarms(w, r)
+	default:
+		writeError(w, http.StatusNotFound, tls.ErrNotSupport, "tlstest does not implement "+r.URL.Path)
+	}
+}
+
+// fault returns the first fault matching path and counts it down.
+func (s *Server) fault(path string) *Fault {
+	for i, fault := range s.faults {
+		if fault.Path != "" && fault.Path != path {
+			continue
+		}
+		if fault.Times > 0 {
+			fault.Times--
+			if fault.Times == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+
+		return fault
+	}
+
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, httpCode int, errorCode, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpCode)
+	json.NewEncoder(w).Encode(map[string]string{"errorCode": errorCode, "errorMessage": message})
+}
+
+func writeInvalidArgument(w http.ResponseWriter, message string) {
+	writeError(w, http.StatusBadRequest, tls.ErrInvalidParam, message)
+}
@@ -0,0 +1,589 @@
+package tlstest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/volcengine/volc-sdk-golang/service/tls"
+	"github.com/volcengine/volc-sdk-golang/service/tls/consumer"
+	"github.com/volcengine/volc-sdk-golang/service/tls/pb"
+	"github.com/volcengine/volc-sdk-golang/service/tls/producer"
+)
+
+func newLogGroupList(values ...string) *pb.LogGroupList {
+	group := &pb.LogGroup{Source: "127.0.0.1"}
+	for _, v := range values {
+		group.Logs = append(group.Logs, &pb.Log{Time: time.Now().Unix(), Contents: []*pb.LogContent{{Key: "message", Value: v}}})
+	}
+	return &pb.LogGroupList{LogGroups: []*pb.LogGroup{group}}
+}
+
+func TestPutAndConsumeLogs(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.CreateTopic("topic", 2)
+	client := server.NewClient()
+
+	shards, err := tls.DescribeAllShards(context.Background(), client, &tls.DescribeShardsRequest{TopicID: "topic"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shards) != 2 || shards[1].InclusiveBeginKey != "80000000000000000000000000000000" {
+		t.Fatalf("unexpected shards %+v", shards)
+	}
+
+	for _, compressType := range []string{tls.CompressLz4, tls.CompressGz, tls.CompressZstd, tls.CompressSnappy, tls.CompressNone} {
+		_, err := client.PutLogs(&tls.PutLogsRequest{
+			TopicID:      "topic",
+			HashKey:      "90000000000000000000000000000000",
+			CompressType: compressType,
+			LogBody:      newLogGroupList(compressType),
+		})
+		if err != nil {
+			t.Fatalf("put %s logs: %v", compressType, err)
+		}
+	}
+
+	cursor, err := client.DescribeCursor(&tls.DescribeCursorRequest{TopicID: "topic", ShardID: 1, From: "begin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	count, compression := 3, tls.CompressLz4
+	resp, err := client.ConsumeLogs(&tls.ConsumeLogsRequest{TopicID: "topic", ShardID: 1, Cursor: cursor.Cursor, LogGroupCount: &count, Compression: &compression})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Count != 3 || resp.Logs.LogGroups[1].Logs[0].Contents[0].Value != tls.CompressGz {
+		t.Fatalf("unexpected logs %v", resp.Logs)
+	}
+	resp, err = client.ConsumeLogs(&tls.ConsumeLogsRequest{TopicID: "topic", ShardID: 1, Cursor: resp.Cursor})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Count != 2 || resp.Logs.LogGroups[1].Logs[0].Contents[0].Value != tls.CompressNone {
+		t.Fatalf("unexpected logs %v", resp.Logs)
+	}
+
+	end, err := client.DescribeCursor(&tls.DescribeCursorRequest{TopicID: "topic", ShardID: 1, From: "end"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if end.Cursor != resp.Cursor {
+		t.Fatalf("end cursor %q, consumed up to %q", end.Cursor, resp.Cursor)
+	}
+
+	_, err = client.PutLogs(&tls.PutLogsRequest{TopicID: "missing", LogBody: newLogGroupList("a")})
+	if clientErr := tls.NewClientError(err); clientErr.HTTPCode != http.StatusNotFound || clientErr.Code != tls.ErrTopicNotExists {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestInjectFault(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.CreateTopic("topic", 1)
+	client := server.NewClient()
+
+	// The client retries throttled requests.
+	server.InjectFault(Fault{Path: tls.PathPutLogs, HTTPCode: http.StatusTooManyRequests, ErrorCode: tls.ErrExceedQPSLimit, Times: 1})
+	if _, err := client.PutLogs(&tls.PutLogsRequest{TopicID: "topic", LogBody: newLogGroupList("a")}); err != nil {
+		t.Fatal(err)
+	}
+	if n := server.Requests(tls.PathPutLogs); n != 2 {
+		t.Fatalf("expected a retry, got %d requests", n)
+	}
+
+	server.InjectFault(Fault{Path: tls.PathDescribeShards, HTTPCode: http.StatusBadRequest})
+	for i := 0; i < 2; i++ {
+		_, err := client.DescribeShards(&tls.DescribeShardsRequest{TopicID: "topic"})
+		if clientErr := tls.NewClientError(err); clientErr.HTTPCode != http.StatusBadRequest || clientErr.Code != tls.ErrInternalServerError {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+	server.ClearFaults()
+	if _, err := client.DescribeShards(&tls.DescribeShardsRequest{TopicID: "topic"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConsumerGroup(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.CreateTopic("topic", 4)
+	client := server.NewClient()
+
+	if _, err := client.CreateConsumerGroup(&tls.CreateConsumerGroupRequest{ProjectID: "project", TopicIDList: []string{"topic"}, ConsumerGroupName: "group", HeartbeatTTL: 60}); err != nil {
+		t.Fatal(err)
+	}
+	heartbeat := func(name string) []*tls.ConsumeShard {
+		resp, err := client.ConsumerHeartbeat(&tls.ConsumerHeartbeatRequest{ProjectID: "project", ConsumerGroupName: "group", ConsumerName: name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Shards
+	}
+	if shards := heartbeat("a"); len(shards) != 4 {
+		t.Fatalf("expected all shards, got %d", len(shards))
+	}
+	heartbeat("b")
+	if shards := heartbeat("a"); len(shards) != 2 {
+		t.Fatalf("expected half of the shards, got %d", len(shards))
+	}
+
+	group, name := "group", "a"
+	consume := func() error {
+		_, err := client.ConsumeLogs(&tls.ConsumeLogsRequest{TopicID: "topic", ShardID: 0, Cursor: "0", ConsumerGroupName: &group, ConsumerName: &name})
+		return err
+	}
+	if err := consume(); err != nil {
+		t.Fatal(err)
+	}
+	server.ExpireConsumer("project", "group", "a")
+	if shards := heartbeat("b"); len(shards) != 4 {
+		t.Fatalf("expected the shards of the expired consumer, got %d", len(shards))
+	}
+	if err := consume(); tls.NewClientError(err).Code != tls.ErrConsumerHeartbeatExpired {
+		t.Fatalf("unexpected error %v", err)
+	}
+	heartbeat("a")
+	if err := consume(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.ModifyCheckPoint(&tls.ModifyCheckPointRequest{ProjectID: "project", TopicID: "topic", ConsumerGroupName: "group", ShardID: 2, Checkpoint: "0"}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.DescribeCheckPoint(&tls.DescribeCheckPointRequest{ProjectID: "project", TopicID: "topic", ConsumerGroupName: "group", ShardID: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Checkpoint != "0" || server.Checkpoint("project", "group", "topic", 2) != "0" {
+		t.Fatalf("unexpected checkpoint %q", resp.Checkpoint)
+	}
+}
+
+func TestSearchLogs(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.CreateTopic("topic", 1)
+	client := server.NewClient()
+
+	logs := &pb.LogGroupList{LogGroups: []*pb.LogGroup{{FileName: "/var/log/app.log"}}}
+	for i, level := range []string{"INFO", "ERROR", "ERROR", "WARN"} {
+		logs.LogGroups[0].Logs = append(logs.LogGroups[0].Logs, &pb.Log{
+			Time:     int64(1000 + i),
+			Contents: []*pb.LogContent{{Key: "level", Value: level}, {Key: "message", Value: "request " + strconv.Itoa(i) + " done"}},
+		})
+	}
+	if _, err := client.PutLogs(&tls.PutLogsRequest{TopicID: "topic", LogBody: logs}); err != nil {
+		t.Fatal(err)
+	}
+
+	search := func(query string, limit int, context string) *tls.SearchLogsResponse {
+		resp, err := client.SearchLogsV2(&tls.SearchLogsRequest{TopicID: "topic", Query: query, StartTime: 1000, EndTime: 1004, Limit: limit, Context: context, Sort: "asc"})
+		if err != nil {
+			t.Fatalf("search %q: %v", query, err)
+		}
+		return resp
+	}
+	for query, hits := range map[string]int{
+		"*":                           4,
+		"level:ERROR":                 2,
+		`level:ERROR AND "request 2"`: 1,
+		"NOT level:ERROR":             2,
+		"level:WA*":                   1,
+		"done":                        4,
+		"missing":                     0,
+	} {
+		if resp := search(query, 0, ""); resp.HitCount != hits || resp.Count != hits {
+			t.Fatalf("query %q: expected %d hits, got %d", query, hits, resp.HitCount)
+		}
+	}
+
+	first := search("*", 3, "")
+	if first.ListOver || first.Logs[0]["__path__"] != "/var/log/app.log" || first.Logs[0]["level"] != "INFO" {
+		t.Fatalf("unexpected first page %+v", first)
+	}
+	second := search("*", 3, first.Context)
+	if !second.ListOver || second.Count != 1 || second.Logs[0]["level"] != "WARN" {
+		t.Fatalf("unexpected second page %+v", second)
+	}
+
+	_, err := client.SearchLogsV2(&tls.SearchLogsRequest{TopicID: "topic", Query: "* | select count(*)", EndTime: 1})
+	if tls.NewClientError(err).Code != tls.ErrSearchSyntaxError {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+// TestPipeline runs the producer and the consumer against the server.
+func TestPipeline(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.CreateTopic("topic", 2)
+
+	producerConfig := producer.GetDefaultProducerConfig()
+	producerConfig.Endpoint = server.URL
+	producerConfig.Region = Region
+	producerConfig.AccessKeyID, producerConfig.AccessKeySecret = AccessKeyID, AccessKeySecret
+	producerConfig.LingerTime = 10 * time.Millisecond
+	producerConfig.LogLevel = "error"
+	p := producer.NewProducer(producerConfig)
+	p.Start()
+	for i := 0; i < 100; i++ {
+		log := producer.GenerateLog(time.Now().Unix(), map[string]string{"message": strconv.Itoa(i)})
+		if err := p.SendLogByKey(strconv.Itoa(i%10), "topic", "", "", log, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p.Close()
+
+	// Throttle the first fetches, the consumer backs off and fetches again.
+	server.InjectFault(Fault{Path: tls.PathConsumeLogs, HTTPCode: http.StatusTooManyRequests, ErrorCode: tls.ErrExceedQPSLimit, Times: 2})
+
+	var lock sync.Mutex
+	consumed := make(map[string]bool)
+	consumerConfig := consumer.GetDefaultConsumerConfig()
+	consumerConfig.Endpoint = server.URL
+	consumerConfig.Region = Region
+	consumerConfig.AccessKeyID, consumerConfig.AccessKeySecret = AccessKeyID, AccessKeySecret
+	consumerConfig.ProjectID = "project"
+	consumerConfig.TopicIDList = []string{"topic"}
+	consumerConfig.ConsumerGroupName = "group"
+	consumerConfig.ConsumerName = "consumer"
+	consumerConfig.HeartbeatIntervalInSecond = 1
+	consumerConfig.DataFetchIntervalInMillisecond = 10
+	consumerConfig.FlushCheckpointIntervalSecond = 0
+	consumerConfig.LogLevel = "error"
+	c, err := consumer.NewConsumer(context.Background(), consumerConfig, func(topicID string, shardID int, l *pb.LogGroupList) {
+		lock.Lock()
+		defer lock.Unlock()
+		for _, group := range l.LogGroups {
+			for _, log := range group.Logs {
+				consumed[log.Contents[0].Value] = true
+			}
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Stop()
+
+	for deadline := time.Now().Add(10 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		lock.Lock()
+		n := len(consumed)
+		lock.Unlock()
+		if n == 100 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("consumed %d of 100 logs", n)
+		}
+	}
+
+	// The checkpoint is committed after the callback returns.
+	for deadline := time.Now().Add(10 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		var groups int
+		for shardID := 0; shardID < 2; shardID++ {
+			checkpoint, _ := strconv.Atoi(server.Checkpoint("project", "group", "topic", shardID))
+			groups += checkpoint
+		}
+		if groups == len(server.LogGroups("topic")) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("checkpoints cover %d of %d log groups", groups, len(server.LogGroups("topic")))
+		}
+	}
+}
+
+func TestTail(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.CreateTopic("topic", 2)
+	client := server.NewClient()
+
+	base := time.Now().UnixNano() / int64(time.Millisecond)
+	put := func(hashKey string, offset int64, level, message string) {
+		group := &pb.LogGroup{Source: "127.0.0.1", Logs: []*pb.Log{{
+			Time:     base + offset,
+			Contents: []*pb.LogContent{{Key: "level", Value: level}, {Key: "message", Value: message}},
+		}}}
+		_, err := client.PutLogs(&tls.PutLogsRequest{TopicID: "topic", HashKey: hashKey, LogBody: &pb.LogGroupList{LogGroups: []*pb.LogGroup{group}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	put("10000000000000000000000000000000", 0, "error", "before")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := tls.Tail(ctx, client, "topic", &tls.TailOptions{
+		Filters:              []tls.TailFilter{{Key: "level", Value: "error"}},
+		OrderWindow:          200 * time.Millisecond,
+		PollInterval:         10 * time.Millisecond,
+		ShardRefreshInterval: 20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := func() string {
+		select {
+		case log, ok := <-stream.Logs():
+			if !ok {
+				t.Fatalf("stream closed: %v", stream.Err())
+			}
+			message, _ := log.Value("message")
+			return message
+		case <-time.After(5 * time.Second):
+			t.Fatal("no log")
+		}
+		return ""
+	}
+
+	// Logs of both shards are ordered by time, filtered logs are skipped.
+	put("90000000000000000000000000000000", 2, "error", "second")
+	put("10000000000000000000000000000000", 1, "error", "first")
+	put("10000000000000000000000000000000", 3, "info", "filtered")
+	if first, second := next(), next(); first != "first" || second != "second" {
+		t.Fatalf("got %q, %q", first, second)
+	}
+
+	// Logs of a shard created by a split are picked up.
+	low, _, err := server.SplitShard("topic", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if low != 2 {
+		t.Fatalf("split into shard %d", low)
+	}
+	put("10000000000000000000000000000000", 4, "error", "split")
+	if message := next(); message != "split" {
+		t.Fatalf("got %q", message)
+	}
+
+	cancel()
+	for range stream.Logs() {
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Logs over MaxLogsPerSecond are dropped.
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	limited, err := tls.Tail(ctx, client, "topic", &tls.TailOptions{
+		From:             tls.TailFromBegin,
+		MaxLogsPerSecond: 1,
+		OrderWindow:      10 * time.Millisecond,
+		PollInterval:     10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-limited.Logs()
+	for deadline := time.Now().Add(5 * time.Second); limited.Dropped() < 4; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("dropped %d logs, want 4", limited.Dropped())
+		}
+	}
+
+	if _, err := tls.Tail(context.Background(), client, "missing", nil); err == nil {
+		t.Fatal("tailed a missing topic")
+	}
+}
+
+func TestExport(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.CreateTopic("topic", 2)
+	client := server.NewClient()
+	if _, err := client.PutLogs(&tls.PutLogsRequest{TopicID: "topic", LogBody: newLogGroupList("a", "b", "c")}); err != nil {
+		t.Fatal(err)
+	}
+
+	var stages []tls.ExportStage
+	var out bytes.Buffer
+	result, err := tls.Export(context.Background(), client, &tls.ExportRequest{
+		TopicID:      "topic",
+		Query:        "NOT message:b",
+		PollInterval: 10 * time.Millisecond,
+		Progress: func(progress tls.ExportProgress) {
+			stages = append(stages, progress.Stage)
+		},
+	}, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(out.String(), "\n"); lines != 2 || result.LogCount != 2 || result.BytesDownloaded == 0 {
+		t.Fatalf("got %d lines, result %+v", lines, result)
+	}
+	want := []tls.ExportStage{tls.ExportStageCreated, tls.ExportStageGenerating, tls.ExportStageDownloading, tls.ExportStageDone}
+	if !reflect.DeepEqual(stages, want) {
+		t.Fatalf("got stages %v", stages)
+	}
+
+	var rows []struct {
+		Message string `tls:"message"`
+		Source  string `tls:"__source__"`
+	}
+	result, err = tls.Export(context.Background(), client, &tls.ExportRequest{
+		TopicID:      "topic",
+		DataFormat:   tls.ExportFormatCSV,
+		Compression:  tls.ExportCompressionNone,
+		Sort:         "desc",
+		PollInterval: 10 * time.Millisecond,
+	}, &rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Records != 3 || len(rows) != 3 || rows[0].Source != "127.0.0.1" {
+		t.Fatalf("got rows %+v", rows)
+	}
+
+	var messages []string
+	_, err = tls.Export(context.Background(), client, &tls.ExportRequest{TopicID: "topic", PollInterval: 10 * time.Millisecond}, func(record map[string]string) error {
+		messages = append(messages, record["message"])
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 3 {
+		t.Fatalf("got messages %v", messages)
+	}
+
+	_, err = tls.Export(context.Background(), client, &tls.ExportRequest{TopicID: "topic", Query: "* | SELECT count(*)", PollInterval: 10 * time.Millisecond}, &out)
+	if !errors.Is(err, tls.ErrDownloadTaskFailed) {
+		t.Fatalf("got error %v, want a failed task", err)
+	}
+
+	// The task is found past the first page of the tasks with its name.
+	for i := 0; i < 120; i++ {
+		if _, err := client.CreateDownloadTask(&tls.CreateDownloadTaskRequest{TopicID: "topic", TaskName: "shared", Query: "*", Compression: tls.ExportCompressionGzip, DataFormat: tls.ExportFormatJSON, Limit: 10, Sort: "asc"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	out.Reset()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	result, err = tls.Export(ctx, client, &tls.ExportRequest{TopicID: "topic", TaskName: "shared", PollInterval: 10 * time.Millisecond}, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.LogCount != 3 {
+		t.Fatalf("got result %+v", result)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = tls.Export(ctx, client, &tls.ExportRequest{TopicID: "topic", PollInterval: time.Minute}, &out)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got error %v, want a timeout", err)
+	}
+}
+
+func TestResources(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.NewClient()
+
+	project, err := client.CreateProject(&tls.CreateProjectRequest{ProjectName: "app", Region: Region})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CreateProject(&tls.CreateProjectRequest{ProjectName: "app", Region: Region}); tls.NewClientError(err).Code != tls.ErrProjectAlreadyExists {
+		t.Fatalf("got error %v creating a project twice", err)
+	}
+	topic, err := client.CreateTopic(&tls.CreateTopicRequest{ProjectID: project.ProjectID, TopicName: "nginx", Ttl: 30, ShardCount: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Created topics take logs like the ones of CreateTopic.
+	if _, err := client.PutLogsV2(&tls.PutLogsV2Request{TopicID: topic.TopicID, Logs: []tls.Log{{Contents: []tls.LogContent{{Key: "message", Value: "hello"}}}}}); err != nil {
+		t.Fatal(err)
+	}
+	ttl := uint16(7)
+	if _, err := client.ModifyTopic(&tls.ModifyTopicRequest{TopicID: topic.TopicID, Ttl: &ttl}); err != nil {
+		t.Fatal(err)
+	}
+	topics, err := tls.DescribeAllTopics(context.Background(), client, &tls.DescribeTopicsRequest{ProjectID: project.ProjectID}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 1 || topics[0].TopicName != "nginx" || topics[0].Ttl != 7 || topics[0].ShardCount != 2 {
+		t.Fatalf("got topics %+v", topics)
+	}
+
+	if _, err := client.DescribeIndex(&tls.DescribeIndexRequest{TopicID: topic.TopicID}); tls.NewClientError(err).Code != tls.ErrIndexNotExists {
+		t.Fatalf("got error %v describing a missing index", err)
+	}
+	if _, err := client.CreateIndex(&tls.CreateIndexRequest{TopicID: topic.TopicID, FullText: &tls.FullTextInfo{Delimiter: ","}}); err != nil {
+		t.Fatal(err)
+	}
+	index, err := client.DescribeIndex(&tls.DescribeIndexRequest{TopicID: topic.TopicID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if index.FullText.Delimiter != "," || len(*index.KeyValue) != 0 {
+		t.Fatalf("got index %+v", index)
+	}
+
+	logType := "minimalist_log"
+	rule, err := client.CreateRule(&tls.CreateRuleRequest{TopicID: topic.TopicID, RuleName: "access", LogType: &logType})
+	if err != nil {
+		t.Fatal(err)
+	}
+	alarm, err := client.CreateAlarm(&tls.CreateAlarmRequest{
+		AlarmName:        "errors",
+		ProjectID:        project.ProjectID,
+		QueryRequest:     tls.QueryRequests{{TopicID: topic.TopicID, Query: "*", Number: 1}},
+		RequestCycle:     tls.RequestCycle{Type: "Period", Time: 10},
+		Condition:        "$1.count > 0",
+		AlarmPeriod:      60,
+		AlarmNotifyGroup: []string{"group"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules, err := tls.DescribeAllRules(context.Background(), client, &tls.DescribeRulesRequest{ProjectID: project.ProjectID}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alarms, err := tls.DescribeAllAlarms(context.Background(), client, &tls.DescribeAlarmsRequest{ProjectID: project.ProjectID}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 1 || rules[0].RuleID != rule.RuleID || rules[0].LogType != logType {
+		t.Fatalf("got rules %+v", rules)
+	}
+	if len(alarms) != 1 || alarms[0].AlarmID != alarm.AlarmID || alarms[0].QueryRequest[0].TopicName != "nginx" || alarms[0].AlarmNotifyGroup[0].NotifyGroupID != "group" {
+		t.Fatalf("got alarms %+v", alarms)
+	}
+
+	// Projects are deleted once empty.
+	if _, err := client.DeleteProject(&tls.DeleteProjectRequest{ProjectID: project.ProjectID}); err == nil {
+		t.Fatal("deleted a project with topics")
+	}
+	if _, err := client.DeleteTopic(&tls.DeleteTopicRequest{TopicID: topic.TopicID}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.DeleteProject(&tls.DeleteProjectRequest{ProjectID: project.ProjectID}); err != nil {
+		t.Fatal(err)
+	}
+	projects, err := tls.DescribeAllProjects(context.Background(), client, &tls.DescribeProjectsRequest{}, 0)
+	if err != nil || len(projects) != 0 {
+		t.Fatalf("got projects %+v, error %v", projects, err)
+	}
+}
@@ -0,0 +1,425 @@
+package tlstest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4"
+	"github.com/volcengine/volc-sdk-golang/service/tls"
+	"github.com/volcengine/volc-sdk-golang/service/tls/pb"
+)
+
+const (
+	defaultLogGroupCount = 100
+	maxLogGroupCount     = 1000
+	maxHashKey           = "ffffffffffffffffffffffffffffffff"
+)
+
+type topic struct {
+	id     string
+	shards []*shard
+	// next is the shard of the next PutLogs without hash key.
+	next int
+	// kafka tells whether Kafka consumption is enabled.
+	kafka bool
+	// info is described by DescribeTopics, index by DescribeIndex, nil
+	// without index.
+	info  *tls.Topic
+	index *tls.DescribeIndexResponse
+}
+
+// shard holds the log groups written to it, the cursor of a log group is its
+// index.
+type shard struct {
+	id         int
+	begin, end string
+	groups     []*storedGroup
+	// readOnly is set once the shard is split, it keeps its logs but takes no
+	// more writes.
+	readOnly bool
+}
+
+type storedGroup struct {
+	group       *pb.LogGroup
+	receiveTime time.Time
+}
+
+// CreateTopic adds a topic whose shards split the hash key space evenly, at
+// least one. It replaces an existing topic of the same id.
+func (s *Server) CreateTopic(topicID string, shardCount int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.topics[topicID] = newTopic(topicID, shardCount, &tls.Topic{TopicID: topicID, TopicName: topicID})
+}
+
+// newTopic returns a topic described by info whose shards split the hash key
+// space evenly.
+func newTopic(topicID string, shardCount int, info *tls.Topic) *topic {
+	if shardCount < 1 {
+		shardCount = 1
+	}
+	t := &topic{id: topicID, info: info}
+	space := new(big.Int).Lsh(big.NewInt(1), 128)
+	for i := 0; i < shardCount; i++ {
+		t.shards = append(t.shards, &shard{id: i, begin: hashKeyAt(space, i, shardCount), end: maxHashKey})
+		if i > 0 {
+			t.shards[i-1].end = t.shards[i].begin
+		}
+	}
+	info.ShardCount = int32(shardCount)
+
+	return t
+}
+
+// SplitShard splits a shard of a topic in two halves of its hash key range,
+// which are added as new shards, and makes the shard read-only. It returns
+// the ids of the new shards.
+func (s *Server) SplitShard(topicID string, shardID int) (int, int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	t, ok := s.topics[topicID]
+	if !ok {
+		return 0, 0, fmt.Errorf("topic %s does not exist", topicID)
+	}
+	if shardID < 0 || shardID >= len(t.shards) || t.shards[shardID].readOnly {
+		return 0, 0, fmt.Errorf("shard %d of topic %s is not writable", shardID, topicID)
+	}
+
+	sh := t.shards[shardID]
+	begin, _ := new(big.Int).SetString(sh.begin, 16)
+	end, _ := new(big.Int).SetString(sh.end, 16)
+	middle := fmt.Sprintf("%032x", new(big.Int).Rsh(new(big.Int).Add(begin, end), 1))
+	low := &shard{id: len(t.shards), begin: sh.begin, end: middle}
+	high := &shard{id: len(t.shards) + 1, begin: middle, end: sh.end}
+	t.shards = append(t.shards, low, high)
+	sh.readOnly = true
+
+	return low.id, high.id, nil
+}
+
+// hashKeyAt returns the hash key i/n of the way through space.
+func hashKeyAt(space *big.Int, i, n int) string {
+	key := new(big.Int).Mul(space, big.NewInt(int64(i)))
+	key.Div(key, big.NewInt(int64(n)))
+
+	return fmt.Sprintf("%032x", key)
+}
+
+// LogGroups returns the log groups written to the topic, shard by shard.
+func (s *Server) LogGroups(topicID string) []*pb.LogGroup {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	t, ok := s.topics[topicID]
+	if !ok {
+		return nil
+	}
+
+	var groups []*pb.LogGroup
+	for _, sh := range t.shards {
+		for _, stored := range sh.groups {
+			groups = append(groups, stored.group)
+		}
+	}
+
+	return groups
+}
+
+func (s *Server) topic(w http.ResponseWriter, topicID string) *topic {
+	t, ok := s.topics[topicID]
+	if !ok {
+		writeError(w, http.StatusNotFound, tls.ErrTopicNotExists, "topic "+topicID+" does not exist")
+	}
+
+	return t
+}
+
+func (s *Server) shard(w http.ResponseWriter, r *http.Request) *shard {
+	t := s.topic(w, r.URL.Query().Get("TopicId"))
+	if t == nil {
+		return nil
+	}
+
+	shardID, err := strconv.Atoi(r.URL.Query().Get("ShardId"))
+	if err != nil || shardID < 0 || shardID >= len(t.shards) {
+		writeInvalidArgument(w, "invalid ShardId "+r.URL.Query().Get("ShardId"))
+		return nil
+	}
+
+	return t.shards[shardID]
+}
+
+func (s *Server) putLogs(w http.ResponseWriter, r *http.Request) {
+	t := s.topic(w, r.URL.Query().Get("TopicId"))
+	if t == nil {
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeInvalidArgument(w, err.Error())
+		return
+	}
+	list, err := decodeLogGroupList(body, r.Header.Get("x-tls-compresstype"), r.Header.Get("x-tls-bodyrawsize"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, tls.ErrDeserializeFailed, err.Error())
+		return
+	}
+
+	var target *shard
+	if hashKey := strings.ToLower(r.Header.Get("x-tls-hashkey")); hashKey != "" {
+		if _, err := hex.DecodeString(hashKey); err != nil || len(hashKey) != len(maxHashKey) {
+			writeInvalidArgument(w, "invalid hash key "+hashKey)
+			return
+		}
+		for _, sh := range t.writableShards() {
+			if sh.begin <= hashKey && (hashKey < sh.end || sh.end == maxHashKey) {
+				target = sh
+			}
+		}
+	} else {
+		writable := t.writableShards()
+		target = writable[t.next%len(writable)]
+		t.next++
+	}
+
+	now := time.Now()
+	for _, group := range list.LogGroups {
+		target.groups = append(target.groups, &storedGroup{group: group, receiveTime: now})
+	}
+
+	writeJSON(w, struct{}{})
+}
+
+func (t *topic) writableShards() []*shard {
+	var writable []*shard
+	for _, sh := range t.shards {
+		if !sh.readOnly {
+			writable = append(writable, sh)
+		}
+	}
+
+	return writable
+}
+
+func decodeLogGroupList(body []byte, compressType, rawSize string) (*pb.LogGroupList, error) {
+	var err error
+	switch strings.ToLower(compressType) {
+	case tls.CompressLz4:
+		size, parseErr := strconv.Atoi(rawSize)
+		if parseErr != nil {
+			return nil, fmt.Errorf("invalid x-tls-bodyrawsize %q", rawSize)
+		}
+		raw := make([]byte, size)
+		var n int
+		n, err = lz4.UncompressBlock(body, raw)
+		body = raw[:n]
+	case tls.CompressZstd:
+		var decoder *zstd.Decoder
+		decoder, err = zstd.NewReader(nil)
+		if err == nil {
+			body, err = decoder.DecodeAll(body, nil)
+			decoder.Close()
+		}
+	case tls.CompressSnappy:
+		body, err = snappy.Decode(nil, body)
+	case tls.CompressGz:
+		// The SDK sends PutLogs bodies of CompressGz uncompressed.
+		if len(body) > 1 && body[0] == 0x1f && body[1] == 0x8b {
+			var reader *gzip.Reader
+			reader, err = gzip.NewReader(bytes.NewReader(body))
+			if err == nil {
+				body, err = ioutil.ReadAll(reader)
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	list := &pb.LogGroupList{}
+	if err := proto.Unmarshal(body, list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (s *Server) describeShards(w http.ResponseWriter, r *http.Request) {
+	t := s.topic(w, r.URL.Query().Get("TopicId"))
+	if t == nil {
+		return
+	}
+
+	pageNumber, pageSize := page(r, 20)
+	resp := &tls.DescribeShardsResponse{Total: len(t.shards)}
+	for i := (pageNumber - 1) * pageSize; i < len(t.shards) && i < pageNumber*pageSize; i++ {
+		sh := t.shards[i]
+		resp.Shards = append(resp.Shards, &tls.ShardInfo{
+			TopicID:           t.id,
+			ShardID:           int32(sh.id),
+			InclusiveBeginKey: sh.begin,
+			ExclusiveEndKey:   sh.end,
+			Status:            sh.status(),
+		})
+	}
+
+	writeJSON(w, resp)
+}
+
+func (sh *shard) status() string {
+	if sh.readOnly {
+		return "readonly"
+	}
+
+	return "readwrite"
+}
+
+// page returns the PageNumber and PageSize parameters of r.
+func page(r *http.Request, defaultSize int) (int, int) {
+	pageNumber, err := strconv.Atoi(r.URL.Query().Get("PageNumber"))
+	if err != nil || pageNumber <= 0 {
+		pageNumber = 1
+	}
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("PageSize"))
+	if err != nil || pageSize <= 0 {
+		pageSize = defaultSize
+	}
+
+	return pageNumber, pageSize
+}
+
+func (s *Server) describeCursor(w http.ResponseWriter, r *http.Request) {
+	sh := s.shard(w, r)
+	if sh == nil {
+		return
+	}
+
+	var req struct {
+		From string
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeInvalidArgument(w, err.Error())
+		return
+	}
+	cursor, err := sh.cursorFrom(req.From)
+	if err != nil {
+		writeInvalidArgument(w, err.Error())
+		return
+	}
+
+	writeJSON(w, &tls.DescribeCursorResponse{Cursor: cursor})
+}
+
+// cursorFrom returns the cursor of "begin", "end" or the first log group
+// received at or after a unix timestamp in seconds.
+func (sh *shard) cursorFrom(from string) (string, error) {
+	switch from {
+	case "begin":
+		return "0", nil
+	case "end":
+		return strconv.Itoa(len(sh.groups)), nil
+	}
+
+	ts, err := strconv.ParseInt(from, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid From %q", from)
+	}
+	for i, stored := range sh.groups {
+		if stored.receiveTime.Unix() >= ts {
+			return strconv.Itoa(i), nil
+		}
+	}
+
+	return strconv.Itoa(len(sh.groups)), nil
+}
+
+func (sh *shard) parseCursor(cursor string) (int, error) {
+	i, err := strconv.Atoi(cursor)
+	if err != nil || i < 0 || i > len(sh.groups) {
+		return 0, fmt.Errorf("invalid cursor %q", cursor)
+	}
+
+	return i, nil
+}
+
+func (s *Server) consumeLogs(w http.ResponseWriter, r *http.Request) {
+	sh := s.shard(w, r)
+	if sh == nil {
+		return
+	}
+
+	if group := s.consumingGroup(r.Header.Get("ConsumerGroupName"), r.URL.Query().Get("TopicId")); group != nil {
+		if !group.alive(r.Header.Get("ConsumerName"), time.Now()) {
+			writeError(w, http.StatusBadRequest, tls.ErrConsumerHeartbeatExpired, "heartbeat of consumer "+r.Header.Get("ConsumerName")+" expired")
+			return
+		}
+	}
+
+	var req struct {
+		Cursor        string
+		EndCursor     *string
+		LogGroupCount *int
+		Compression   *string
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeInvalidArgument(w, err.Error())
+		return
+	}
+	begin, err := sh.parseCursor(req.Cursor)
+	if err != nil {
+		writeInvalidArgument(w, err.Error())
+		return
+	}
+	end := len(sh.groups)
+	if req.EndCursor != nil {
+		if end, err = sh.parseCursor(*req.EndCursor); err != nil {
+			writeInvalidArgument(w, err.Error())
+			return
+		}
+	}
+	count := defaultLogGroupCount
+	if req.LogGroupCount != nil {
+		count = *req.LogGroupCount
+	}
+	if count <= 0 || count > maxLogGroupCount {
+		writeInvalidArgument(w, "invalid LogGroupCount "+strconv.Itoa(count))
+		return
+	}
+	if end > begin+count {
+		end = begin + count
+	}
+
+	list := &pb.LogGroupList{}
+	for i := begin; i < end; i++ {
+		list.LogGroups = append(list.LogGroups, sh.groups[i].group)
+	}
+	compression := ""
+	if req.Compression != nil {
+		compression = *req.Compression
+	}
+	body, rawSize, err := tls.GetPutLogsBody(compression, list)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, tls.ErrInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("x-tls-bodyrawsize", strconv.Itoa(rawSize))
+	w.Header().Set("x-tls-count", strconv.Itoa(len(list.LogGroups)))
+	w.Header().Set("x-tls-cursor", strconv.Itoa(end))
+	w.Write(body)
+}
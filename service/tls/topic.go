@@ -1,6 +1,7 @@
 package tls
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"io/ioutil"
@@ -10,6 +11,10 @@ import (
 
 // CreateTopic 创建日志主题
 func (c *LsClient) CreateTopic(request *CreateTopicRequest) (r *CreateTopicResponse, e error) {
+	return c.CreateTopicCtx(context.Background(), request)
+}
+
+func (c *LsClient) CreateTopicCtx(ctx context.Context, request *CreateTopicRequest) (r *CreateTopicResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -33,7 +38,7 @@ func (c *LsClient) CreateTopic(request *CreateTopicRequest) (r *CreateTopicRespo
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPost, PathCreateTopic, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPost, PathCreateTopic, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -56,6 +61,10 @@ func (c *LsClient) CreateTopic(request *CreateTopicRequest) (r *CreateTopicRespo
 
 // DeleteTopic 删除日志主题
 func (c *LsClient) DeleteTopic(request *DeleteTopicRequest) (r *CommonResponse, e error) {
+	return c.DeleteTopicCtx(context.Background(), request)
+}
+
+func (c *LsClient) DeleteTopicCtx(ctx context.Context, request *DeleteTopicRequest) (r *CommonResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -73,7 +82,7 @@ func (c *LsClient) DeleteTopic(request *DeleteTopicRequest) (r *CommonResponse,
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodDelete, PathDeleteTopic, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodDelete, PathDeleteTopic, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -95,6 +104,10 @@ func (c *LsClient) DeleteTopic(request *DeleteTopicRequest) (r *CommonResponse,
 
 // ModifyTopic 更新日志主题信息
 func (c *LsClient) ModifyTopic(request *ModifyTopicRequest) (r *CommonResponse, e error) {
+	return c.ModifyTopicCtx(context.Background(), request)
+}
+
+func (c *LsClient) ModifyTopicCtx(ctx context.Context, request *ModifyTopicRequest) (r *CommonResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -108,7 +121,7 @@ func (c *LsClient) ModifyTopic(request *ModifyTopicRequest) (r *CommonResponse,
 		return nil, err
 	}
 
-	rawResponse, err := c.Request(http.MethodPut, PathModifyTopic, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPut, PathModifyTopic, nil, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -129,6 +142,10 @@ func (c *LsClient) ModifyTopic(request *ModifyTopicRequest) (r *CommonResponse,
 
 // DescribeTopic 获取一个日志主题的信息
 func (c *LsClient) DescribeTopic(request *DescribeTopicRequest) (r *DescribeTopicResponse, e error) {
+	return c.DescribeTopicCtx(context.Background(), request)
+}
+
+func (c *LsClient) DescribeTopicCtx(ctx context.Context, request *DescribeTopicRequest) (r *DescribeTopicResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -144,7 +161,7 @@ func (c *LsClient) DescribeTopic(request *DescribeTopicRequest) (r *DescribeTopi
 	body := map[string]string{}
 	bytesBody, err := json.Marshal(body)
 
-	rawResponse, err := c.Request(http.MethodGet, PathDescribeTopic, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodGet, PathDescribeTopic, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -167,6 +184,10 @@ func (c *LsClient) DescribeTopic(request *DescribeTopicRequest) (r *DescribeTopi
 
 // DescribeTopics 获取日志主题列表
 func (c *LsClient) DescribeTopics(request *DescribeTopicsRequest) (r *DescribeTopicsResponse, e error) {
+	return c.DescribeTopicsCtx(context.Background(), request)
+}
+
+func (c *LsClient) DescribeTopicsCtx(ctx context.Context, request *DescribeTopicsRequest) (r *DescribeTopicsResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -214,7 +235,7 @@ func (c *LsClient) DescribeTopics(request *DescribeTopicsRequest) (r *DescribeTo
 	body := map[string]string{}
 	bytesBody, err := json.Marshal(body)
 
-	rawResponse, err := c.Request(http.MethodGet, PathDescribeTopics, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodGet, PathDescribeTopics, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
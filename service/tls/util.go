@@ -4,8 +4,11 @@ import (
 	"encoding/json"
 	"runtime"
 	"strings"
+	"sync"
 
 	"github.com/gogo/protobuf/proto"
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
 	"github.com/pierrec/lz4"
 	"github.com/volcengine/volc-sdk-golang/service/tls/pb"
 )
@@ -45,6 +48,34 @@ func CopyIncompressible(src, dst []byte) (int, error) {
 	return di, nil
 }
 
+var (
+	zstdEncoderOnce sync.Once
+	zstdEncoder     *zstd.Encoder
+	zstdEncoderErr  error
+
+	zstdDecoderOnce sync.Once
+	zstdDecoder     *zstd.Decoder
+	zstdDecoderErr  error
+)
+
+// getZstdEncoder returns the shared zstd encoder, EncodeAll is safe for
+// concurrent use.
+func getZstdEncoder() (*zstd.Encoder, error) {
+	zstdEncoderOnce.Do(func() {
+		zstdEncoder, zstdEncoderErr = zstd.NewWriter(nil)
+	})
+	return zstdEncoder, zstdEncoderErr
+}
+
+// getZstdDecoder returns the shared zstd decoder, DecodeAll is safe for
+// concurrent use.
+func getZstdDecoder() (*zstd.Decoder, error) {
+	zstdDecoderOnce.Do(func() {
+		zstdDecoder, zstdDecoderErr = zstd.NewReader(nil)
+	})
+	return zstdDecoder, zstdDecoderErr
+}
+
 func GetPutLogsBody(compressType string, logGroupList *pb.LogGroupList) ([]byte, int, error) {
 	var (
 		out       []byte
@@ -72,6 +103,16 @@ func GetPutLogsBody(compressType string, logGroupList *pb.LogGroupList) ([]byte,
 		}
 		outLen = n
 		break
+	case CompressZstd:
+		encoder, err := getZstdEncoder()
+		if err != nil {
+			return nil, -1, err
+		}
+		out = encoder.EncodeAll(body, make([]byte, 0, len(body)))
+		outLen = len(out)
+	case CompressSnappy:
+		out = snappy.Encode(nil, body)
+		outLen = len(out)
 	default:
 		out = body
 		outLen = len(out)
@@ -1,11 +1,16 @@
 package tls
 
 import (
+	"context"
 	"net/http"
 	"strconv"
 )
 
 func (c *LsClient) WebTracks(request *WebTracksRequest) (r *WebTracksResponse, e error) {
+	return c.WebTracksCtx(context.Background(), request)
+}
+
+func (c *LsClient) WebTracksCtx(ctx context.Context, request *WebTracksRequest) (r *WebTracksResponse, e error) {
 	if err := request.CheckValidation(); err != nil {
 		return nil, NewClientError(err)
 	}
@@ -30,7 +35,7 @@ func (c *LsClient) WebTracks(request *WebTracksRequest) (r *WebTracksResponse, e
 	}
 	reqHeaders[rawBodySizeHeader] = strconv.Itoa(rawBodyLength)
 
-	rawResponse, err := c.Request(http.MethodPost, PathWebTracks, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
+	rawResponse, err := c.RequestCtx(ctx, http.MethodPost, PathWebTracks, params, c.assembleHeader(request.CommonRequest, reqHeaders), bytesBody)
 	if err != nil {
 		return nil, err
 	}
@@ -44,7 +44,7 @@ func NewInstance(ak, sk string) *Veen {
 
 // GetServiceInfo interface
 func (v *Veen) GetServiceInfo(env string) *base.ServiceInfo {
-	return v.Client.ServiceInfo
+	return v.Client.GetServiceInfo()
 }
 
 // GetAPIInfo interface
@@ -57,15 +57,15 @@ func (v *Veen) GetAPIInfo(api string) *base.ApiInfo {
 
 // SetRegion
 func (v *Veen) SetRegion(env, region string) {
-	v.Client.ServiceInfo.Credentials.Region = region
+	v.Client.SetRegion(region)
 }
 
 // SetHost .
 func (v *Veen) SetHost(host string) {
-	v.Client.ServiceInfo.Host = host
+	v.Client.SetHost(host)
 }
 
 // SetSchema .
 func (v *Veen) SetSchema(schema string) {
-	v.Client.ServiceInfo.Scheme = schema
+	v.Client.SetScheme(schema)
 }
@@ -28,8 +28,10 @@ func packError(resp *ResponseMetadata) error {
 func NewVerenderInstance() *Verender {
     v := Verender{}
     v.Client = base.NewClient(ServiceInfo, APIInfoList)
-    v.Client.ServiceInfo.Credentials.Service = ServiceName
-    v.Client.ServiceInfo.Credentials.Region = DefaultRegion
+    v.Client.UpdateServiceInfo(func(info *base.ServiceInfo) {
+        info.Credentials.Service = ServiceName
+        info.Credentials.Region = DefaultRegion
+    })
 
     return &v
 }
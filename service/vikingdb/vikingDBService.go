@@ -35,7 +35,7 @@ func NewVikingDBService(host string, region string, ak string, sk string, scheme
 
 }
 func (vikingDBService *VikingDBService) SetConnectionTimeout(connectionTimeout int64) {
-	vikingDBService.Client.ServiceInfo.Timeout = time.Duration(connectionTimeout) * time.Second
+	vikingDBService.Client.SetTimeout(time.Duration(connectionTimeout) * time.Second)
 }
 func getApiInfo() map[string]*base.ApiInfo {
 	apiInfos := map[string]*base.ApiInfo{
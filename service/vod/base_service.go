@@ -99,9 +99,13 @@ func (p *Vod) createHlsDrmAuthToken(authAlgorithm string, expireSeconds int64) (
 	if expireSeconds == 0 {
 		return "", errors.New("invalid expire")
 	}
+	cred, err := p.Credentials()
+	if err != nil {
+		return "", err
+	}
 
-	token, err := createAuth(authAlgorithm, Version2, p.ServiceInfo.Credentials.AccessKeyID,
-		p.ServiceInfo.Credentials.SecretAccessKey, p.ServiceInfo.Credentials.Region, expireSeconds)
+	token, err := createAuth(authAlgorithm, Version2, cred.AccessKeyID,
+		cred.SecretAccessKey, cred.Region, expireSeconds)
 	if err != nil {
 		return "", err
 	}
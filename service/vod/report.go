@@ -271,8 +271,8 @@ func (p *Vod) buildDefaultUploadReport(spaceName string, cost int64, statusCode,
 		r.Tags = append(r.Tags, &tag{true, tagErrorMsg, errMsg})
 	}
 	if p != nil {
-		if p.ServiceInfo.Host != "" {
-			r.Tags = append(r.Tags, &tag{true, tagFromHost, p.ServiceInfo.Host})
+		if host := p.GetServiceInfo().Host; host != "" {
+			r.Tags = append(r.Tags, &tag{true, tagFromHost, host})
 		}
 	}
 	return r